@@ -0,0 +1,91 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+// TestDataDirectory is the directory, relative to the package of a test,
+// under which the TestNameDirectory, TestNameFile, TestStepDirectory and
+// TestStepFile functions resolve paths.
+const TestDataDirectory = "testdata"
+
+// TestStepConfigRequest is the information about a TestStep passed to a
+// TestStepConfigFunc.
+type TestStepConfigRequest struct {
+	// StepNumber is the 1-based number of the TestStep.
+	StepNumber int
+
+	// TestName is the name of the test, as returned by the Name method of
+	// the testing.T of the test. The names of subtests contain slashes.
+	TestName string
+}
+
+// TestStepConfigFunc returns the path of the configuration directory or file
+// of a TestStep. Relative paths are relative to the package of the test.
+type TestStepConfigFunc func(TestStepConfigRequest) string
+
+// Exec returns the path returned by the function, or an empty string if the
+// function is nil.
+func (f TestStepConfigFunc) Exec(req TestStepConfigRequest) string {
+	if f == nil {
+		return ""
+	}
+
+	return f(req)
+}
+
+// StaticDirectory returns a TestStepConfigFunc which returns the given
+// directory for every TestStep.
+func StaticDirectory(directory string) TestStepConfigFunc {
+	return func(_ TestStepConfigRequest) string {
+		return directory
+	}
+}
+
+// TestNameDirectory returns a TestStepConfigFunc which returns the
+// testdata/<TestName> directory, such as testdata/TestAccThing_basic.
+func TestNameDirectory() TestStepConfigFunc {
+	return func(req TestStepConfigRequest) string {
+		return filepath.Join(TestDataDirectory, req.TestName)
+	}
+}
+
+// TestStepDirectory returns a TestStepConfigFunc which returns the
+// testdata/<TestName>/<StepNumber> directory, such as
+// testdata/TestAccThing_basic/1, so that every TestStep has its own
+// configuration.
+func TestStepDirectory() TestStepConfigFunc {
+	return func(req TestStepConfigRequest) string {
+		return filepath.Join(TestDataDirectory, req.TestName, strconv.Itoa(req.StepNumber))
+	}
+}
+
+// StaticFile returns a TestStepConfigFunc which returns the given file for
+// every TestStep.
+func StaticFile(file string) TestStepConfigFunc {
+	return func(_ TestStepConfigRequest) string {
+		return file
+	}
+}
+
+// TestNameFile returns a TestStepConfigFunc which returns the given file
+// within the testdata/<TestName> directory, such as
+// testdata/TestAccThing_basic/main.tf.
+func TestNameFile(file string) TestStepConfigFunc {
+	return func(req TestStepConfigRequest) string {
+		return filepath.Join(TestDataDirectory, req.TestName, file)
+	}
+}
+
+// TestStepFile returns a TestStepConfigFunc which returns the given file
+// within the testdata/<TestName>/<StepNumber> directory, such as
+// testdata/TestAccThing_basic/1/main.tf.
+func TestStepFile(file string) TestStepConfigFunc {
+	return func(req TestStepConfigRequest) string {
+		return filepath.Join(TestDataDirectory, req.TestName, strconv.Itoa(req.StepNumber), file)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTestStepConfigFunc_Exec(t *testing.T) {
+	t.Parallel()
+
+	req := TestStepConfigRequest{
+		StepNumber: 2,
+		TestName:   "TestAccThing_basic",
+	}
+
+	cases := map[string]struct {
+		Func     TestStepConfigFunc
+		Expected string
+	}{
+		"nil": {
+			Func:     nil,
+			Expected: "",
+		},
+		"StaticDirectory": {
+			Func:     StaticDirectory("fixtures/thing"),
+			Expected: "fixtures/thing",
+		},
+		"TestNameDirectory": {
+			Func:     TestNameDirectory(),
+			Expected: filepath.Join("testdata", "TestAccThing_basic"),
+		},
+		"TestStepDirectory": {
+			Func:     TestStepDirectory(),
+			Expected: filepath.Join("testdata", "TestAccThing_basic", "2"),
+		},
+		"StaticFile": {
+			Func:     StaticFile("fixtures/thing.tf"),
+			Expected: "fixtures/thing.tf",
+		},
+		"TestNameFile": {
+			Func:     TestNameFile("main.tf"),
+			Expected: filepath.Join("testdata", "TestAccThing_basic", "main.tf"),
+		},
+		"TestStepFile": {
+			Func:     TestStepFile("main.tf"),
+			Expected: filepath.Join("testdata", "TestAccThing_basic", "2", "main.tf"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tc.Func.Exec(req)
+
+			if got != tc.Expected {
+				t.Errorf("expected %q, got %q", tc.Expected, got)
+			}
+		})
+	}
+}
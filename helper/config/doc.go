@@ -0,0 +1,27 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package config contains the types for configuring a resource.TestStep
+// from files rather than an inline string, and for setting the values of the
+// input variables of its configuration.
+//
+// The ConfigDirectory and ConfigFile fields of a TestStep are set to a
+// TestStepConfigFunc, which returns a path for each step. The TestNameDirectory
+// and TestStepDirectory functions resolve paths relative to the name of the
+// test, so that fixtures can be kept in the testdata directory of the
+// package, for example:
+//
+//	// testdata/TestAccThing_basic/main.tf declares a variable "name".
+//	func TestAccThing_basic(t *testing.T) {
+//		resource.Test(t, resource.TestCase{
+//			Steps: []resource.TestStep{
+//				{
+//					ConfigDirectory: config.TestNameDirectory(),
+//					ConfigVariables: config.Variables{
+//						"name": config.StringVariable("test"),
+//					},
+//				},
+//			},
+//		})
+//	}
+package config
@@ -0,0 +1,242 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+)
+
+// Variables are the values of the root module input variables of the
+// configuration of a TestStep, keyed by variable name. They are written to a
+// variable definitions file in the JSON syntax, so Terraform converts each
+// value to the type declared by its variable block.
+type Variables map[string]Variable
+
+// MarshalJSON returns the content of the variable definitions file of the
+// variables. It returns an error if any of the variables is invalid.
+func (v Variables) MarshalJSON() ([]byte, error) {
+	names := make([]string, 0, len(v))
+
+	for name := range v {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	result := make(map[string]json.RawMessage, len(v))
+
+	for _, name := range names {
+		b, err := v[name].marshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("variable %q: %w", name, err)
+		}
+
+		result[name] = b
+	}
+
+	return json.Marshal(result)
+}
+
+// Variable is the value of an input variable, which is created with the
+// functions of this package.
+type Variable struct {
+	value cty.Value
+	err   error
+}
+
+// Value returns the cty value of the variable, or an error if the variable
+// is invalid, such as a list of elements of different types.
+func (v Variable) Value() (cty.Value, error) {
+	if v.err != nil {
+		return cty.NilVal, v.err
+	}
+
+	if v.value == cty.NilVal {
+		return cty.NilVal, fmt.Errorf("variable has no value")
+	}
+
+	return v.value, nil
+}
+
+func (v Variable) marshalJSON() ([]byte, error) {
+	val, err := v.Value()
+	if err != nil {
+		return nil, err
+	}
+
+	if !val.IsWhollyKnown() {
+		return nil, fmt.Errorf("variable value must be known")
+	}
+
+	return ctyjson.Marshal(val, val.Type())
+}
+
+// CtyVariable returns a variable with the given cty value, which must be
+// wholly known.
+func CtyVariable(value cty.Value) Variable {
+	return Variable{value: value}
+}
+
+// BoolVariable returns a variable with the given bool value.
+func BoolVariable(value bool) Variable {
+	return Variable{value: cty.BoolVal(value)}
+}
+
+// FloatVariable returns a variable with the given number value.
+func FloatVariable(value float64) Variable {
+	return Variable{value: cty.NumberFloatVal(value)}
+}
+
+// IntegerVariable returns a variable with the given number value.
+func IntegerVariable(value int64) Variable {
+	return Variable{value: cty.NumberIntVal(value)}
+}
+
+// StringVariable returns a variable with the given string value.
+func StringVariable(value string) Variable {
+	return Variable{value: cty.StringVal(value)}
+}
+
+// ListVariable returns a variable with a list of the given elements, which
+// must all be of the same type.
+func ListVariable(elems ...Variable) Variable {
+	vals, ty, err := elementValues(elems)
+	if err != nil {
+		return Variable{err: fmt.Errorf("list %w", err)}
+	}
+
+	if len(vals) == 0 {
+		return Variable{value: cty.ListValEmpty(ty)}
+	}
+
+	return Variable{value: cty.ListVal(vals)}
+}
+
+// SetVariable returns a variable with a set of the given elements, which
+// must all be of the same type.
+func SetVariable(elems ...Variable) Variable {
+	vals, ty, err := elementValues(elems)
+	if err != nil {
+		return Variable{err: fmt.Errorf("set %w", err)}
+	}
+
+	if len(vals) == 0 {
+		return Variable{value: cty.SetValEmpty(ty)}
+	}
+
+	return Variable{value: cty.SetVal(vals)}
+}
+
+// TupleVariable returns a variable with a tuple of the given elements, which
+// may be of different types.
+func TupleVariable(elems ...Variable) Variable {
+	vals := make([]cty.Value, 0, len(elems))
+
+	for i, elem := range elems {
+		val, err := elem.Value()
+		if err != nil {
+			return Variable{err: fmt.Errorf("tuple element %d: %w", i, err)}
+		}
+
+		vals = append(vals, val)
+	}
+
+	return Variable{value: cty.TupleVal(vals)}
+}
+
+// MapVariable returns a variable with a map of the given elements, which
+// must all be of the same type.
+func MapVariable(elems map[string]Variable) Variable {
+	vals, err := attributeValues(elems)
+	if err != nil {
+		return Variable{err: fmt.Errorf("map %w", err)}
+	}
+
+	if len(vals) == 0 {
+		return Variable{value: cty.MapValEmpty(cty.DynamicPseudoType)}
+	}
+
+	var ty cty.Type
+
+	for _, key := range sortedVariableKeys(elems) {
+		switch {
+		case ty == cty.NilType:
+			ty = vals[key].Type()
+		case !vals[key].Type().Equals(ty):
+			return Variable{err: fmt.Errorf("map element %q: expected type %s, got: %s", key, ty.FriendlyName(), vals[key].Type().FriendlyName())}
+		}
+	}
+
+	return Variable{value: cty.MapVal(vals)}
+}
+
+// ObjectVariable returns a variable with an object of the given attributes,
+// which may be of different types.
+func ObjectVariable(attrs map[string]Variable) Variable {
+	vals, err := attributeValues(attrs)
+	if err != nil {
+		return Variable{err: fmt.Errorf("object %w", err)}
+	}
+
+	return Variable{value: cty.ObjectVal(vals)}
+}
+
+// elementValues returns the values of the given list or set elements and
+// their type, or an error if the elements are invalid or of different types.
+func elementValues(elems []Variable) ([]cty.Value, cty.Type, error) {
+	vals := make([]cty.Value, 0, len(elems))
+	ty := cty.DynamicPseudoType
+
+	for i, elem := range elems {
+		val, err := elem.Value()
+		if err != nil {
+			return nil, cty.NilType, fmt.Errorf("element %d: %w", i, err)
+		}
+
+		switch {
+		case i == 0:
+			ty = val.Type()
+		case !val.Type().Equals(ty):
+			return nil, cty.NilType, fmt.Errorf("element %d: expected type %s, got: %s", i, ty.FriendlyName(), val.Type().FriendlyName())
+		}
+
+		vals = append(vals, val)
+	}
+
+	return vals, ty, nil
+}
+
+// attributeValues returns the values of the given map elements or object
+// attributes, or an error if any of them are invalid.
+func attributeValues(elems map[string]Variable) (map[string]cty.Value, error) {
+	vals := make(map[string]cty.Value, len(elems))
+
+	for _, key := range sortedVariableKeys(elems) {
+		val, err := elems[key].Value()
+		if err != nil {
+			return nil, fmt.Errorf("element %q: %w", key, err)
+		}
+
+		vals[key] = val
+	}
+
+	return vals, nil
+}
+
+func sortedVariableKeys(elems map[string]Variable) []string {
+	keys := make([]string, 0, len(elems))
+
+	for key := range elems {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
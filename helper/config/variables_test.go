@@ -0,0 +1,118 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestVariables_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Variables     Variables
+		Expected      string
+		ExpectedError string
+	}{
+		"empty": {
+			Variables: Variables{},
+			Expected:  `{}`,
+		},
+		"primitives": {
+			Variables: Variables{
+				"bool":    BoolVariable(true),
+				"float":   FloatVariable(1.5),
+				"integer": IntegerVariable(42),
+				"string":  StringVariable("test"),
+			},
+			Expected: `{"bool":true,"float":1.5,"integer":42,"string":"test"}`,
+		},
+		"collections": {
+			Variables: Variables{
+				"list":  ListVariable(StringVariable("b"), StringVariable("a")),
+				"set":   SetVariable(StringVariable("b"), StringVariable("a")),
+				"map":   MapVariable(map[string]Variable{"env": StringVariable("prod")}),
+				"empty": ListVariable(),
+			},
+			Expected: `{"empty":[],"list":["b","a"],"map":{"env":"prod"},"set":["a","b"]}`,
+		},
+		"structural": {
+			Variables: Variables{
+				"object": ObjectVariable(map[string]Variable{
+					"name":  StringVariable("test"),
+					"ports": ListVariable(IntegerVariable(80), IntegerVariable(443)),
+				}),
+				"tuple": TupleVariable(StringVariable("a"), BoolVariable(false)),
+			},
+			Expected: `{"object":{"name":"test","ports":[80,443]},"tuple":["a",false]}`,
+		},
+		"cty": {
+			Variables: Variables{
+				"null": CtyVariable(cty.NullVal(cty.String)),
+			},
+			Expected: `{"null":null}`,
+		},
+		"list-mixed-types": {
+			Variables: Variables{
+				"list": ListVariable(StringVariable("a"), IntegerVariable(1)),
+			},
+			ExpectedError: `variable "list": list element 1: expected type string, got: number`,
+		},
+		"map-mixed-types": {
+			Variables: Variables{
+				"map": MapVariable(map[string]Variable{
+					"a": StringVariable("a"),
+					"b": BoolVariable(true),
+				}),
+			},
+			ExpectedError: `variable "map": map element "b": expected type string, got: bool`,
+		},
+		"nested-error": {
+			Variables: Variables{
+				"object": ObjectVariable(map[string]Variable{
+					"set": SetVariable(BoolVariable(true), StringVariable("a")),
+				}),
+			},
+			ExpectedError: `variable "object": object element "set": set element 1: expected type bool, got: string`,
+		},
+		"unknown": {
+			Variables: Variables{
+				"unknown": CtyVariable(cty.UnknownVal(cty.String)),
+			},
+			ExpectedError: `variable "unknown": variable value must be known`,
+		},
+		"zero-value": {
+			Variables: Variables{
+				"zero": {},
+			},
+			ExpectedError: `variable "zero": variable has no value`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tc.Variables.MarshalJSON()
+
+			if tc.ExpectedError != "" {
+				if err == nil || err.Error() != tc.ExpectedError {
+					t.Fatalf("expected error %q, got: %v", tc.ExpectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if string(got) != tc.Expected {
+				t.Errorf("expected %s, got %s", tc.Expected, got)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -74,3 +75,69 @@ func Sequence(funcs ...schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
 		return nil
 	}
 }
+
+// AllWithDiagnostics returns a CustomizeDiffWithDiagnosticsFunc that runs all
+// of the given CustomizeDiffWithDiagnosticsFuncs and returns all of the
+// diagnostics produced.
+//
+// If one function produces an error diagnostic, functions after it are still
+// run. If this is not desirable, use function SequenceWithDiagnostics
+// instead.
+//
+// CustomizeDiffFuncs, such as those returned by All, Sequence, ForceNewIf or
+// ValidateChange, can be composed with WithDiagnostics. For example:
+//
+//	&schema.Resource{
+//	    // ...
+//	    CustomizeDiffWithDiagnostics: customdiff.AllWithDiagnostics(
+//	        customdiff.ValidateChangeWithDiagnostics("size", func (ctx context.Context, old, new, meta interface{}) diag.Diagnostics {
+//	            if new.(int) > 100 {
+//	                return diag.Diagnostics{{
+//	                    Severity: diag.Warning,
+//	                    Summary:  "Large size",
+//	                    Detail:   "A size greater than 100 may take a long time to provision.",
+//	                }}
+//	            }
+//	            return nil
+//	        }),
+//	        customdiff.WithDiagnostics(customdiff.ForceNewIfChange("size", func (ctx context.Context, old, new, meta interface{}) bool {
+//	            return new.(int) < old.(int)
+//	        })),
+//	    ),
+//	}
+func AllWithDiagnostics(funcs ...schema.CustomizeDiffWithDiagnosticsFunc) schema.CustomizeDiffWithDiagnosticsFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+		var diags diag.Diagnostics
+		for _, f := range funcs {
+			diags = append(diags, f(ctx, d, meta)...)
+		}
+		return diags
+	}
+}
+
+// SequenceWithDiagnostics returns a CustomizeDiffWithDiagnosticsFunc that
+// runs all of the given CustomizeDiffWithDiagnosticsFuncs in sequence,
+// stopping at the first one that returns an error diagnostic and returning
+// the diagnostics produced so far, including warnings.
+func SequenceWithDiagnostics(funcs ...schema.CustomizeDiffWithDiagnosticsFunc) schema.CustomizeDiffWithDiagnosticsFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+		var diags diag.Diagnostics
+		for _, f := range funcs {
+			diags = append(diags, f(ctx, d, meta)...)
+			if diags.HasError() {
+				return diags
+			}
+		}
+		return diags
+	}
+}
+
+// WithDiagnostics returns a CustomizeDiffWithDiagnosticsFunc that runs the
+// given CustomizeDiffFunc and returns its error as an error diagnostic, so
+// that it can be composed using AllWithDiagnostics and
+// SequenceWithDiagnostics.
+func WithDiagnostics(f schema.CustomizeDiffFunc) schema.CustomizeDiffWithDiagnosticsFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+		return diag.FromErr(f(ctx, d, meta))
+	}
+}
@@ -6,8 +6,10 @@ package customdiff
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -113,3 +115,98 @@ func TestSequence(t *testing.T) {
 		t.Error("customize callback C was called (should not have been)")
 	}
 }
+
+func TestAllWithDiagnostics(t *testing.T) {
+	var aCalled, bCalled, cCalled bool
+
+	diags := testDiffWithDiagnostics(
+		map[string]*schema.Schema{},
+		AllWithDiagnostics(
+			func(_ context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+				aCalled = true
+				return diag.Errorf("A bad")
+			},
+			func(_ context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+				bCalled = true
+				return diag.Diagnostics{{Severity: diag.Warning, Summary: "B warning"}}
+			},
+			WithDiagnostics(func(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+				cCalled = true
+				return errors.New("C bad")
+			}),
+		),
+		map[string]string{
+			"foo": "bar",
+		},
+		map[string]string{
+			"foo": "baz",
+		},
+	)
+
+	expected := diag.Diagnostics{
+		{Severity: diag.Error, Summary: "A bad"},
+		{Severity: diag.Warning, Summary: "B warning"},
+		{Severity: diag.Error, Summary: "C bad"},
+	}
+
+	if !reflect.DeepEqual(diags, expected) {
+		t.Errorf("wrong diagnostics %#v; want %#v", diags, expected)
+	}
+
+	if !aCalled {
+		t.Error("customize callback A was not called")
+	}
+	if !bCalled {
+		t.Error("customize callback B was not called")
+	}
+	if !cCalled {
+		t.Error("customize callback C was not called")
+	}
+}
+
+func TestSequenceWithDiagnostics(t *testing.T) {
+	var aCalled, bCalled, cCalled bool
+
+	diags := testDiffWithDiagnostics(
+		map[string]*schema.Schema{},
+		SequenceWithDiagnostics(
+			func(_ context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+				aCalled = true
+				return diag.Diagnostics{{Severity: diag.Warning, Summary: "A warning"}}
+			},
+			func(_ context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+				bCalled = true
+				return diag.Errorf("B bad")
+			},
+			func(_ context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+				cCalled = true
+				return diag.Errorf("C bad")
+			},
+		),
+		map[string]string{
+			"foo": "bar",
+		},
+		map[string]string{
+			"foo": "baz",
+		},
+	)
+
+	expected := diag.Diagnostics{
+		{Severity: diag.Warning, Summary: "A warning"},
+		{Severity: diag.Error, Summary: "B bad"},
+	}
+
+	if !reflect.DeepEqual(diags, expected) {
+		t.Errorf("wrong diagnostics %#v; want %#v", diags, expected)
+	}
+
+	if !aCalled {
+		t.Error("customize callback A was not called")
+	}
+	if !bCalled {
+		t.Error("customize callback B was not called")
+	}
+	if cCalled {
+		t.Error("customize callback C was called (should not have been)")
+	}
+}
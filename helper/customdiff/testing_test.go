@@ -6,6 +6,7 @@ package customdiff
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -38,3 +39,23 @@ func testDiff(provider *schema.Provider, oldValue, newValue map[string]string) (
 		provider.Meta(),
 	)
 }
+
+func testDiffWithDiagnostics(s map[string]*schema.Schema, cd schema.CustomizeDiffWithDiagnosticsFunc, oldValue, newValue map[string]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	provider := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"test": {
+				Schema: s,
+				CustomizeDiffWithDiagnostics: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+					diags = cd(ctx, d, meta)
+					return diags
+				},
+			},
+		},
+	}
+
+	_, _ = testDiff(provider, oldValue, newValue)
+
+	return diags
+}
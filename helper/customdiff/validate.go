@@ -5,7 +5,12 @@ package customdiff
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -18,6 +23,16 @@ type ValueChangeValidationFunc func(ctx context.Context, oldValue, newValue, met
 // returning an error if the value is invalid.
 type ValueValidationFunc func(ctx context.Context, value, meta interface{}) error
 
+// ValueChangeValidationWithDiagnosticsFunc is a function type that validates
+// the difference (or lack thereof) between two values, returning diagnostics
+// if the change is invalid or deserves a warning.
+type ValueChangeValidationWithDiagnosticsFunc func(ctx context.Context, oldValue, newValue, meta interface{}) diag.Diagnostics
+
+// ValueValidationWithDiagnosticsFunc is a function type that validates a
+// particular value, returning diagnostics if the value is invalid or deserves
+// a warning.
+type ValueValidationWithDiagnosticsFunc func(ctx context.Context, value, meta interface{}) diag.Diagnostics
+
 // ValidateChange returns a CustomizeDiffFunc that applies the given validation
 // function to the change for the given key, returning any error produced.
 func ValidateChange(key string, f ValueChangeValidationFunc) schema.CustomizeDiffFunc {
@@ -41,3 +56,83 @@ func ValidateValue(key string, f ValueValidationFunc) schema.CustomizeDiffFunc {
 		return f(ctx, val, meta)
 	}
 }
+
+// ValidateChangeWithDiagnostics returns a CustomizeDiffWithDiagnosticsFunc
+// that applies the given validation function to the change for the given key,
+// returning any diagnostics produced. Diagnostics without an AttributePath
+// are returned with the path of the key.
+func ValidateChangeWithDiagnostics(key string, f ValueChangeValidationWithDiagnosticsFunc) schema.CustomizeDiffWithDiagnosticsFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+		oldValue, newValue := d.GetChange(key)
+		return withKeyPath(key, d.GetRawConfig(), f(ctx, oldValue, newValue, meta))
+	}
+}
+
+// ValidateValueWithDiagnostics returns a CustomizeDiffWithDiagnosticsFunc that
+// applies the given validation function to value of the given key, returning
+// any diagnostics produced. Diagnostics without an AttributePath are returned
+// with the path of the key.
+//
+// As with ValidateValue, this should generally not be used in favor of a
+// validation function applied directly to the schema attribute in question.
+func ValidateValueWithDiagnostics(key string, f ValueValidationWithDiagnosticsFunc) schema.CustomizeDiffWithDiagnosticsFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) diag.Diagnostics {
+		val := d.Get(key)
+		return withKeyPath(key, d.GetRawConfig(), f(ctx, val, meta))
+	}
+}
+
+// withKeyPath sets the AttributePath of the given diagnostics without one to
+// the path of the given key, using the given configuration value to tell
+// map keys apart from attribute names.
+func withKeyPath(key string, config cty.Value, diags diag.Diagnostics) diag.Diagnostics {
+	path := keyPath(key, config.Type())
+
+	for i := range diags {
+		if diags[i].AttributePath == nil {
+			diags[i].AttributePath = path
+		}
+	}
+
+	return diags
+}
+
+// keyPath returns the path of the given key, whose parts are separated by
+// dots, within a value of the given type. Names of object attributes are
+// attribute steps, while list indexes and map keys are index steps. Set
+// elements cannot be addressed by their flatmap keys, so the path of a key
+// within a set ends at the set. When the type is not known, such as without
+// a raw configuration, numeric parts are assumed to be list indexes and any
+// other parts attribute names.
+func keyPath(key string, ty cty.Type) cty.Path {
+	var path cty.Path
+
+	for _, part := range strings.Split(key, ".") {
+		switch {
+		case ty.IsObjectType() && ty.HasAttribute(part):
+			path = path.GetAttr(part)
+			ty = ty.AttributeType(part)
+		case ty.IsMapType():
+			path = path.IndexString(part)
+			ty = ty.ElementType()
+		case ty.IsSetType():
+			return path
+		default:
+			index, err := strconv.Atoi(part)
+
+			if err != nil {
+				path = path.GetAttr(part)
+			} else {
+				path = path.IndexInt(index)
+			}
+
+			if ty.IsListType() {
+				ty = ty.ElementType()
+			} else {
+				ty = cty.DynamicPseudoType
+			}
+		}
+	}
+
+	return path
+}
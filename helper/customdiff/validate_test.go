@@ -6,8 +6,12 @@ package customdiff
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -100,3 +104,135 @@ func TestValidateValue(t *testing.T) {
 		t.Errorf("wrong value %q; want %q", got, want)
 	}
 }
+
+func TestValidateChangeWithDiagnostics(t *testing.T) {
+	var gotOld, gotNew string
+
+	diags := testDiffWithDiagnostics(
+		map[string]*schema.Schema{
+			"foo": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+		ValidateChangeWithDiagnostics("foo", func(_ context.Context, oldValue, newValue, meta interface{}) diag.Diagnostics {
+			gotOld = oldValue.(string)
+			gotNew = newValue.(string)
+			return diag.Diagnostics{
+				{Severity: diag.Warning, Summary: "warning"},
+				{Severity: diag.Error, Summary: "bad", AttributePath: cty.GetAttrPath("bar")},
+			}
+		}),
+		map[string]string{
+			"foo": "bar",
+		},
+		map[string]string{
+			"foo": "baz",
+		},
+	)
+
+	expected := diag.Diagnostics{
+		{Severity: diag.Warning, Summary: "warning", AttributePath: cty.GetAttrPath("foo")},
+		{Severity: diag.Error, Summary: "bad", AttributePath: cty.GetAttrPath("bar")},
+	}
+
+	if !reflect.DeepEqual(diags, expected) {
+		t.Errorf("wrong diagnostics %#v; want %#v", diags, expected)
+	}
+
+	if got, want := gotOld, "bar"; got != want {
+		t.Errorf("wrong old value %q; want %q", got, want)
+	}
+	if got, want := gotNew, "baz"; got != want {
+		t.Errorf("wrong new value %q; want %q", got, want)
+	}
+}
+
+func TestValidateValueWithDiagnostics_nestedKey(t *testing.T) {
+	diags := testDiffWithDiagnostics(
+		map[string]*schema.Schema{
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"port": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+		ValidateValueWithDiagnostics("rule.0.port", func(_ context.Context, value, meta interface{}) diag.Diagnostics {
+			return diag.Diagnostics{
+				{Severity: diag.Error, Summary: "bad"},
+			}
+		}),
+		map[string]string{},
+		map[string]string{},
+	)
+
+	expected := diag.Diagnostics{
+		{Severity: diag.Error, Summary: "bad", AttributePath: cty.GetAttrPath("rule").IndexInt(0).GetAttr("port")},
+	}
+
+	if !reflect.DeepEqual(diags, expected) {
+		t.Errorf("wrong diagnostics %#v; want %#v", diags, expected)
+	}
+}
+
+func TestKeyPath(t *testing.T) {
+	ty := cty.Object(map[string]cty.Type{
+		"name": cty.String,
+		"rule": cty.List(cty.Object(map[string]cty.Type{
+			"port": cty.Number,
+		})),
+		"tags": cty.Map(cty.String),
+		"zone": cty.Set(cty.Object(map[string]cty.Type{
+			"name": cty.String,
+		})),
+	})
+
+	cases := map[string]struct {
+		Key      string
+		Type     cty.Type
+		Expected cty.Path
+	}{
+		"attribute": {
+			Key:      "name",
+			Type:     ty,
+			Expected: cty.GetAttrPath("name"),
+		},
+		"list": {
+			Key:      "rule.0.port",
+			Type:     ty,
+			Expected: cty.GetAttrPath("rule").IndexInt(0).GetAttr("port"),
+		},
+		"map": {
+			Key:      "tags.0",
+			Type:     ty,
+			Expected: cty.GetAttrPath("tags").IndexString("0"),
+		},
+		"set": {
+			Key:      "zone.1234.name",
+			Type:     ty,
+			Expected: cty.GetAttrPath("zone"),
+		},
+		"unknown type": {
+			Key:      "rule.0.port",
+			Type:     cty.NilType,
+			Expected: cty.GetAttrPath("rule").IndexInt(0).GetAttr("port"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := keyPath(tc.Key, tc.Type)
+
+			if !got.Equals(tc.Expected) {
+				t.Errorf("wrong path %#v; want %#v", got, tc.Expected)
+			}
+		})
+	}
+}
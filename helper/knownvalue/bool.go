@@ -0,0 +1,42 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"fmt"
+	"strconv"
+)
+
+var _ Check = boolExact{}
+
+type boolExact struct {
+	value bool
+}
+
+// CheckValue implements the Check interface.
+func (v boolExact) CheckValue(other interface{}) error {
+	b, ok := other.(bool)
+	if !ok {
+		return fmt.Errorf("expected bool value for Bool check, got: %s", describe(other))
+	}
+
+	if b != v.value {
+		return fmt.Errorf("expected value %s for Bool check, got: %t", v, b)
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v boolExact) String() string {
+	return strconv.FormatBool(v.value)
+}
+
+// Bool returns a Check for asserting equality between the given bool and a
+// value.
+func Bool(value bool) Check {
+	return boolExact{
+		value: value,
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// Check defines a check of a known value decoded from the JSON plan or state
+// formats of Terraform. Strings are decoded as string, numbers as
+// json.Number or float64, bools as bool, lists, sets and tuples as
+// []interface{}, maps and objects as map[string]interface{}, and null as
+// nil.
+type Check interface {
+	// CheckValue returns an error if the value does not pass the check.
+	CheckValue(value interface{}) error
+
+	// String returns a description of the expected value, which is used in
+	// the errors of checks containing this check.
+	String() string
+}
+
+// number returns the given number value, decoded as a json.Number or a
+// float64, as a big.Float.
+func number(value interface{}) (*big.Float, error) {
+	switch v := value.(type) {
+	case json.Number:
+		f, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q: %w", v, err)
+		}
+
+		return f, nil
+	case float64:
+		return big.NewFloat(v), nil
+	default:
+		return nil, fmt.Errorf("expected number value, got: %s", describe(value))
+	}
+}
+
+// describe returns the given value formatted for errors.
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case json.Number:
+		return v.String()
+	case []interface{}, map[string]interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+
+		return string(b)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
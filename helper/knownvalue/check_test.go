@@ -0,0 +1,38 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"testing"
+)
+
+type checkValueTestCase struct {
+	Check         Check
+	Value         interface{}
+	ExpectedError string
+}
+
+func runCheckValueTests(t *testing.T, cases map[string]checkValueTestCase) {
+	t.Helper()
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.Check.CheckValue(tc.Value)
+
+			if tc.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tc.ExpectedError {
+				t.Fatalf("expected error %q, got: %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
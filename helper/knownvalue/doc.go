@@ -0,0 +1,19 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package knownvalue contains the Check interface for matching known values
+// in the JSON plan and state formats of Terraform, and the built-in checks
+// which implement it.
+//
+// Checks are typed, unlike the flatmap strings compared by functions such as
+// resource.TestCheckResourceAttr, and are used with the checks of the
+// statecheck and plancheck packages. For example:
+//
+//	statecheck.ExpectKnownValue(
+//		"examplecloud_thing.test",
+//		cty.GetAttrPath("tags"),
+//		knownvalue.MapExact(map[string]knownvalue.Check{
+//			"env": knownvalue.StringExact("prod"),
+//		}),
+//	)
+package knownvalue
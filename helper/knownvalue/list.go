@@ -0,0 +1,152 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var _ Check = listExact{}
+
+type listExact struct {
+	value []Check
+}
+
+// CheckValue implements the Check interface.
+func (v listExact) CheckValue(other interface{}) error {
+	elems, ok := other.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected list value for ListExact check, got: %s", describe(other))
+	}
+
+	if len(elems) != len(v.value) {
+		return fmt.Errorf("expected %d elements for ListExact check, got %d elements", len(v.value), len(elems))
+	}
+
+	for i, check := range v.value {
+		if err := check.CheckValue(elems[i]); err != nil {
+			return fmt.Errorf("list element index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v listExact) String() string {
+	return checksString(v.value)
+}
+
+// ListExact returns a Check for asserting that a list, or tuple, value has
+// exactly the elements matched by the given checks, in order.
+func ListExact(value []Check) Check {
+	return listExact{
+		value: value,
+	}
+}
+
+var _ Check = listPartial{}
+
+type listPartial struct {
+	value map[int]Check
+}
+
+// CheckValue implements the Check interface.
+func (v listPartial) CheckValue(other interface{}) error {
+	elems, ok := other.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected list value for ListPartial check, got: %s", describe(other))
+	}
+
+	for _, i := range v.indexes() {
+		if i < 0 || i >= len(elems) {
+			return fmt.Errorf("missing element index %d for ListPartial check", i)
+		}
+
+		if err := v.value[i].CheckValue(elems[i]); err != nil {
+			return fmt.Errorf("list element index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v listPartial) String() string {
+	parts := make([]string, 0, len(v.value))
+
+	for _, i := range v.indexes() {
+		parts = append(parts, strconv.Itoa(i)+":"+v.value[i].String())
+	}
+
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+func (v listPartial) indexes() []int {
+	indexes := make([]int, 0, len(v.value))
+
+	for i := range v.value {
+		indexes = append(indexes, i)
+	}
+
+	sort.Ints(indexes)
+
+	return indexes
+}
+
+// ListPartial returns a Check for asserting that the elements of a list, or
+// tuple, value at the given indexes are matched by the given checks. Other
+// elements are ignored.
+func ListPartial(value map[int]Check) Check {
+	return listPartial{
+		value: value,
+	}
+}
+
+var _ Check = listSizeExact{}
+
+type listSizeExact struct {
+	size int
+}
+
+// CheckValue implements the Check interface.
+func (v listSizeExact) CheckValue(other interface{}) error {
+	elems, ok := other.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected list value for ListSizeExact check, got: %s", describe(other))
+	}
+
+	if len(elems) != v.size {
+		return fmt.Errorf("expected %d elements for ListSizeExact check, got %d elements", v.size, len(elems))
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v listSizeExact) String() string {
+	return strconv.Itoa(v.size)
+}
+
+// ListSizeExact returns a Check for asserting that a list, or tuple, value
+// has the given number of elements.
+func ListSizeExact(size int) Check {
+	return listSizeExact{
+		size: size,
+	}
+}
+
+// checksString returns the descriptions of the given checks as a list.
+func checksString(checks []Check) string {
+	parts := make([]string, 0, len(checks))
+
+	for _, check := range checks {
+		parts = append(parts, check.String())
+	}
+
+	return "[" + strings.Join(parts, " ") + "]"
+}
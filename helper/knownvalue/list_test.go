@@ -0,0 +1,81 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestListExact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"equal": {
+			Check: ListExact([]Check{StringExact("a"), Int64Exact(1)}),
+			Value: []interface{}{"a", json.Number("1")},
+		},
+		"empty": {
+			Check: ListExact([]Check{}),
+			Value: []interface{}{},
+		},
+		"element-not-equal": {
+			Check:         ListExact([]Check{StringExact("a"), StringExact("b")}),
+			Value:         []interface{}{"a", "c"},
+			ExpectedError: `list element index 1: expected value "b" for StringExact check, got: "c"`,
+		},
+		"order": {
+			Check:         ListExact([]Check{StringExact("a"), StringExact("b")}),
+			Value:         []interface{}{"b", "a"},
+			ExpectedError: `list element index 0: expected value "a" for StringExact check, got: "b"`,
+		},
+		"length": {
+			Check:         ListExact([]Check{StringExact("a")}),
+			Value:         []interface{}{"a", "b"},
+			ExpectedError: "expected 1 elements for ListExact check, got 2 elements",
+		},
+		"wrong-type": {
+			Check:         ListExact([]Check{StringExact("a")}),
+			Value:         map[string]interface{}{"a": "a"},
+			ExpectedError: `expected list value for ListExact check, got: {"a":"a"}`,
+		},
+	})
+}
+
+func TestListPartial(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"match": {
+			Check: ListPartial(map[int]Check{1: StringExact("b")}),
+			Value: []interface{}{"a", "b", "c"},
+		},
+		"not-equal": {
+			Check:         ListPartial(map[int]Check{2: StringExact("b")}),
+			Value:         []interface{}{"a", "b", "c"},
+			ExpectedError: `list element index 2: expected value "b" for StringExact check, got: "c"`,
+		},
+		"missing-index": {
+			Check:         ListPartial(map[int]Check{3: StringExact("d")}),
+			Value:         []interface{}{"a", "b", "c"},
+			ExpectedError: "missing element index 3 for ListPartial check",
+		},
+	})
+}
+
+func TestListSizeExact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"equal": {
+			Check: ListSizeExact(2),
+			Value: []interface{}{"a", "b"},
+		},
+		"not-equal": {
+			Check:         ListSizeExact(1),
+			Value:         []interface{}{"a", "b"},
+			ExpectedError: "expected 1 elements for ListSizeExact check, got 2 elements",
+		},
+	})
+}
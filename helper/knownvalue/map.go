@@ -0,0 +1,199 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var _ Check = mapExact{}
+
+type mapExact struct {
+	value map[string]Check
+}
+
+// CheckValue implements the Check interface.
+func (v mapExact) CheckValue(other interface{}) error {
+	return checkAttributes(other, v.value, "map", "MapExact", true)
+}
+
+// String implements the Check interface.
+func (v mapExact) String() string {
+	return checksMapString(v.value)
+}
+
+// MapExact returns a Check for asserting that a map value has exactly the
+// keys of the given checks, with elements matched by the checks.
+func MapExact(value map[string]Check) Check {
+	return mapExact{
+		value: value,
+	}
+}
+
+var _ Check = mapPartial{}
+
+type mapPartial struct {
+	value map[string]Check
+}
+
+// CheckValue implements the Check interface.
+func (v mapPartial) CheckValue(other interface{}) error {
+	return checkAttributes(other, v.value, "map", "MapPartial", false)
+}
+
+// String implements the Check interface.
+func (v mapPartial) String() string {
+	return checksMapString(v.value)
+}
+
+// MapPartial returns a Check for asserting that a map value has the keys of
+// the given checks, with elements matched by the checks. Other elements are
+// ignored.
+func MapPartial(value map[string]Check) Check {
+	return mapPartial{
+		value: value,
+	}
+}
+
+var _ Check = mapKeysExact{}
+
+type mapKeysExact struct {
+	keys []string
+}
+
+// CheckValue implements the Check interface.
+func (v mapKeysExact) CheckValue(other interface{}) error {
+	m, ok := other.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected map value for MapKeysExact check, got: %s", describe(other))
+	}
+
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	if strings.Join(keys, "\x00") != strings.Join(v.keys, "\x00") {
+		return fmt.Errorf("expected keys %s for MapKeysExact check, got: %q", v, keys)
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v mapKeysExact) String() string {
+	return fmt.Sprintf("%q", v.keys)
+}
+
+// MapKeysExact returns a Check for asserting that a map value has exactly
+// the given keys, regardless of its elements.
+func MapKeysExact(keys ...string) Check {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+
+	return mapKeysExact{
+		keys: sorted,
+	}
+}
+
+var _ Check = mapSizeExact{}
+
+type mapSizeExact struct {
+	size int
+}
+
+// CheckValue implements the Check interface.
+func (v mapSizeExact) CheckValue(other interface{}) error {
+	m, ok := other.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected map value for MapSizeExact check, got: %s", describe(other))
+	}
+
+	if len(m) != v.size {
+		return fmt.Errorf("expected %d elements for MapSizeExact check, got %d elements", v.size, len(m))
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v mapSizeExact) String() string {
+	return strconv.Itoa(v.size)
+}
+
+// MapSizeExact returns a Check for asserting that a map value has the given
+// number of elements.
+func MapSizeExact(size int) Check {
+	return mapSizeExact{
+		size: size,
+	}
+}
+
+// checkAttributes checks the elements of the given map or object value with
+// the checks of the same keys. If exact is true, the value must not have
+// other keys.
+func checkAttributes(other interface{}, checks map[string]Check, kind, name string, exact bool) error {
+	m, ok := other.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected %s value for %s check, got: %s", kind, name, describe(other))
+	}
+
+	if exact && len(m) != len(checks) {
+		var unexpected []string
+
+		for k := range m {
+			if _, ok := checks[k]; !ok {
+				unexpected = append(unexpected, k)
+			}
+		}
+
+		sort.Strings(unexpected)
+
+		if len(unexpected) > 0 {
+			return fmt.Errorf("unexpected keys %q for %s check", unexpected, name)
+		}
+	}
+
+	for _, k := range sortedKeys(checks) {
+		v, ok := m[k]
+		if !ok {
+			return fmt.Errorf("missing key %q for %s check", k, name)
+		}
+
+		if err := checks[k].CheckValue(v); err != nil {
+			return fmt.Errorf("%s key %q: %w", kind, k, err)
+		}
+	}
+
+	return nil
+}
+
+// checksMapString returns the descriptions of the given checks as a map.
+func checksMapString(checks map[string]Check) string {
+	parts := make([]string, 0, len(checks))
+
+	for _, k := range sortedKeys(checks) {
+		parts = append(parts, k+":"+checks[k].String())
+	}
+
+	return "map[" + strings.Join(parts, " ") + "]"
+}
+
+func sortedKeys(checks map[string]Check) []string {
+	keys := make([]string, 0, len(checks))
+
+	for k := range checks {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
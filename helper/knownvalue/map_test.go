@@ -0,0 +1,108 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"testing"
+)
+
+func TestMapExact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"equal": {
+			Check: MapExact(map[string]Check{"env": StringExact("prod")}),
+			Value: map[string]interface{}{"env": "prod"},
+		},
+		"element-not-equal": {
+			Check:         MapExact(map[string]Check{"env": StringExact("prod")}),
+			Value:         map[string]interface{}{"env": "dev"},
+			ExpectedError: `map key "env": expected value "prod" for StringExact check, got: "dev"`,
+		},
+		"missing-key": {
+			Check:         MapExact(map[string]Check{"env": StringExact("prod")}),
+			Value:         map[string]interface{}{"name": "prod"},
+			ExpectedError: `missing key "env" for MapExact check`,
+		},
+		"extra-key": {
+			Check:         MapExact(map[string]Check{"env": StringExact("prod")}),
+			Value:         map[string]interface{}{"env": "prod", "name": "test"},
+			ExpectedError: `unexpected keys ["name"] for MapExact check`,
+		},
+		"wrong-type": {
+			Check:         MapExact(map[string]Check{}),
+			Value:         nil,
+			ExpectedError: "expected map value for MapExact check, got: null",
+		},
+	})
+}
+
+func TestMapPartial(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"subset": {
+			Check: MapPartial(map[string]Check{"env": StringExact("prod")}),
+			Value: map[string]interface{}{"env": "prod", "name": "test"},
+		},
+		"missing-key": {
+			Check:         MapPartial(map[string]Check{"owner": NotNull()}),
+			Value:         map[string]interface{}{"env": "prod"},
+			ExpectedError: `missing key "owner" for MapPartial check`,
+		},
+	})
+}
+
+func TestMapKeysExact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"equal": {
+			Check: MapKeysExact("name", "env"),
+			Value: map[string]interface{}{"env": "prod", "name": "test"},
+		},
+		"not-equal": {
+			Check:         MapKeysExact("env"),
+			Value:         map[string]interface{}{"env": "prod", "name": "test"},
+			ExpectedError: `expected keys ["env"] for MapKeysExact check, got: ["env" "name"]`,
+		},
+	})
+}
+
+func TestMapSizeExact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"equal": {
+			Check: MapSizeExact(1),
+			Value: map[string]interface{}{"env": "prod"},
+		},
+		"not-equal": {
+			Check:         MapSizeExact(2),
+			Value:         map[string]interface{}{"env": "prod"},
+			ExpectedError: "expected 2 elements for MapSizeExact check, got 1 elements",
+		},
+	})
+}
+
+func TestObjectExact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"equal": {
+			Check: ObjectExact(map[string]Check{"port": Int64Exact(80), "cidr": Null()}),
+			Value: map[string]interface{}{"port": float64(80), "cidr": nil},
+		},
+		"extra-attribute": {
+			Check:         ObjectExact(map[string]Check{"port": Int64Exact(80)}),
+			Value:         map[string]interface{}{"port": float64(80), "cidr": nil},
+			ExpectedError: `unexpected keys ["cidr"] for ObjectExact check`,
+		},
+		"attribute-not-equal": {
+			Check:         ObjectPartial(map[string]Check{"port": Int64Exact(80)}),
+			Value:         map[string]interface{}{"port": float64(443), "cidr": nil},
+			ExpectedError: `object key "port": expected value 80 for Int64Exact check, got: 443`,
+		},
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"fmt"
+)
+
+var _ Check = null{}
+
+type null struct{}
+
+// CheckValue implements the Check interface.
+func (v null) CheckValue(other interface{}) error {
+	if other != nil {
+		return fmt.Errorf("expected value null for Null check, got: %s", describe(other))
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v null) String() string {
+	return "null"
+}
+
+// Null returns a Check for asserting that a value is null.
+func Null() Check {
+	return null{}
+}
+
+var _ Check = notNull{}
+
+type notNull struct{}
+
+// CheckValue implements the Check interface.
+func (v notNull) CheckValue(other interface{}) error {
+	if other == nil {
+		return fmt.Errorf("expected non-null value for NotNull check, got: null")
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v notNull) String() string {
+	return "not null"
+}
+
+// NotNull returns a Check for asserting that a value is not null.
+func NotNull() Check {
+	return notNull{}
+}
@@ -0,0 +1,61 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"testing"
+)
+
+func TestBool(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"equal": {
+			Check: Bool(true),
+			Value: true,
+		},
+		"not-equal": {
+			Check:         Bool(true),
+			Value:         false,
+			ExpectedError: "expected value true for Bool check, got: false",
+		},
+		"wrong-type": {
+			Check:         Bool(true),
+			Value:         "true",
+			ExpectedError: `expected bool value for Bool check, got: "true"`,
+		},
+	})
+}
+
+func TestNull(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"null": {
+			Check: Null(),
+			Value: nil,
+		},
+		"not-null": {
+			Check:         Null(),
+			Value:         []interface{}{},
+			ExpectedError: "expected value null for Null check, got: []",
+		},
+	})
+}
+
+func TestNotNull(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"not-null": {
+			Check: NotNull(),
+			Value: "",
+		},
+		"null": {
+			Check:         NotNull(),
+			Value:         nil,
+			ExpectedError: "expected non-null value for NotNull check, got: null",
+		},
+	})
+}
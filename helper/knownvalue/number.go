@@ -0,0 +1,126 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+var _ Check = int64Exact{}
+
+type int64Exact struct {
+	value int64
+}
+
+// CheckValue implements the Check interface.
+func (v int64Exact) CheckValue(other interface{}) error {
+	n, err := int64Value(other, "Int64Exact")
+	if err != nil {
+		return err
+	}
+
+	if n != v.value {
+		return fmt.Errorf("expected value %s for Int64Exact check, got: %d", v, n)
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v int64Exact) String() string {
+	return strconv.FormatInt(v.value, 10)
+}
+
+// Int64Exact returns a Check for asserting equality between the given int64
+// and a number value.
+func Int64Exact(value int64) Check {
+	return int64Exact{
+		value: value,
+	}
+}
+
+var _ Check = int64Between{}
+
+type int64Between struct {
+	minimum, maximum int64
+}
+
+// CheckValue implements the Check interface.
+func (v int64Between) CheckValue(other interface{}) error {
+	n, err := int64Value(other, "Int64Between")
+	if err != nil {
+		return err
+	}
+
+	if n < v.minimum || n > v.maximum {
+		return fmt.Errorf("expected value %s for Int64Between check, got: %d", v, n)
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v int64Between) String() string {
+	return fmt.Sprintf("between %d and %d", v.minimum, v.maximum)
+}
+
+// Int64Between returns a Check for asserting that a number value is an
+// integer between the given minimum and maximum, inclusive.
+func Int64Between(minimum, maximum int64) Check {
+	return int64Between{
+		minimum: minimum,
+		maximum: maximum,
+	}
+}
+
+var _ Check = float64Exact{}
+
+type float64Exact struct {
+	value float64
+}
+
+// CheckValue implements the Check interface.
+func (v float64Exact) CheckValue(other interface{}) error {
+	f, err := number(other)
+	if err != nil {
+		return fmt.Errorf("%w for Float64Exact check", err)
+	}
+
+	if f.Cmp(big.NewFloat(v.value)) != 0 {
+		return fmt.Errorf("expected value %s for Float64Exact check, got: %s", v, f.Text('g', -1))
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v float64Exact) String() string {
+	return strconv.FormatFloat(v.value, 'g', -1, 64)
+}
+
+// Float64Exact returns a Check for asserting equality between the given
+// float64 and a number value.
+func Float64Exact(value float64) Check {
+	return float64Exact{
+		value: value,
+	}
+}
+
+// int64Value returns the given number value as an int64, or an error naming
+// the given check if it is not an integer which fits an int64.
+func int64Value(value interface{}, check string) (int64, error) {
+	f, err := number(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w for %s check", err, check)
+	}
+
+	n, accuracy := f.Int64()
+	if accuracy != big.Exact {
+		return 0, fmt.Errorf("expected int64 value for %s check, got: %s", check, f.Text('g', -1))
+	}
+
+	return n, nil
+}
@@ -0,0 +1,88 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInt64Exact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"json-number": {
+			Check: Int64Exact(123),
+			Value: json.Number("123"),
+		},
+		"float64": {
+			Check: Int64Exact(123),
+			Value: float64(123),
+		},
+		"large": {
+			Check: Int64Exact(9223372036854775807),
+			Value: json.Number("9223372036854775807"),
+		},
+		"not-equal": {
+			Check:         Int64Exact(123),
+			Value:         json.Number("456"),
+			ExpectedError: "expected value 123 for Int64Exact check, got: 456",
+		},
+		"not-integer": {
+			Check:         Int64Exact(1),
+			Value:         json.Number("1.5"),
+			ExpectedError: "expected int64 value for Int64Exact check, got: 1.5",
+		},
+		"wrong-type": {
+			Check:         Int64Exact(1),
+			Value:         "1",
+			ExpectedError: `expected number value, got: "1" for Int64Exact check`,
+		},
+	})
+}
+
+func TestInt64Between(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"minimum": {
+			Check: Int64Between(1, 10),
+			Value: json.Number("1"),
+		},
+		"maximum": {
+			Check: Int64Between(1, 10),
+			Value: json.Number("10"),
+		},
+		"below": {
+			Check:         Int64Between(1, 10),
+			Value:         json.Number("0"),
+			ExpectedError: "expected value between 1 and 10 for Int64Between check, got: 0",
+		},
+		"above": {
+			Check:         Int64Between(1, 10),
+			Value:         float64(11),
+			ExpectedError: "expected value between 1 and 10 for Int64Between check, got: 11",
+		},
+	})
+}
+
+func TestFloat64Exact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"json-number": {
+			Check: Float64Exact(1.5),
+			Value: json.Number("1.5"),
+		},
+		"float64": {
+			Check: Float64Exact(1.5),
+			Value: float64(1.5),
+		},
+		"not-equal": {
+			Check:         Float64Exact(1.5),
+			Value:         json.Number("2.5"),
+			ExpectedError: "expected value 1.5 for Float64Exact check, got: 2.5",
+		},
+	})
+}
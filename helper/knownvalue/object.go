@@ -0,0 +1,54 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+var _ Check = objectExact{}
+
+type objectExact struct {
+	value map[string]Check
+}
+
+// CheckValue implements the Check interface.
+func (v objectExact) CheckValue(other interface{}) error {
+	return checkAttributes(other, v.value, "object", "ObjectExact", true)
+}
+
+// String implements the Check interface.
+func (v objectExact) String() string {
+	return checksMapString(v.value)
+}
+
+// ObjectExact returns a Check for asserting that an object value, such as a
+// nested block, has exactly the attributes of the given checks, with values
+// matched by the checks.
+func ObjectExact(value map[string]Check) Check {
+	return objectExact{
+		value: value,
+	}
+}
+
+var _ Check = objectPartial{}
+
+type objectPartial struct {
+	value map[string]Check
+}
+
+// CheckValue implements the Check interface.
+func (v objectPartial) CheckValue(other interface{}) error {
+	return checkAttributes(other, v.value, "object", "ObjectPartial", false)
+}
+
+// String implements the Check interface.
+func (v objectPartial) String() string {
+	return checksMapString(v.value)
+}
+
+// ObjectPartial returns a Check for asserting that an object value, such as
+// a nested block, has the attributes of the given checks, with values
+// matched by the checks. Other attributes are ignored.
+func ObjectPartial(value map[string]Check) Check {
+	return objectPartial{
+		value: value,
+	}
+}
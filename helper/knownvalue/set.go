@@ -0,0 +1,134 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"fmt"
+	"strconv"
+)
+
+var _ Check = setExact{}
+
+type setExact struct {
+	value []Check
+}
+
+// CheckValue implements the Check interface.
+func (v setExact) CheckValue(other interface{}) error {
+	elems, ok := other.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected set value for SetExact check, got: %s", describe(other))
+	}
+
+	if len(elems) != len(v.value) {
+		return fmt.Errorf("expected %d elements for SetExact check, got %d elements", len(v.value), len(elems))
+	}
+
+	return matchSetElements(elems, v.value, "SetExact")
+}
+
+// String implements the Check interface.
+func (v setExact) String() string {
+	return checksString(v.value)
+}
+
+// SetExact returns a Check for asserting that a set value has exactly the
+// elements matched by the given checks, in any order.
+func SetExact(value []Check) Check {
+	return setExact{
+		value: value,
+	}
+}
+
+var _ Check = setPartial{}
+
+type setPartial struct {
+	value []Check
+}
+
+// CheckValue implements the Check interface.
+func (v setPartial) CheckValue(other interface{}) error {
+	elems, ok := other.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected set value for SetPartial check, got: %s", describe(other))
+	}
+
+	return matchSetElements(elems, v.value, "SetPartial")
+}
+
+// String implements the Check interface.
+func (v setPartial) String() string {
+	return checksString(v.value)
+}
+
+// SetPartial returns a Check for asserting that a set value contains
+// elements matched by the given checks, in any order. Other elements are
+// ignored.
+func SetPartial(value []Check) Check {
+	return setPartial{
+		value: value,
+	}
+}
+
+var _ Check = setSizeExact{}
+
+type setSizeExact struct {
+	size int
+}
+
+// CheckValue implements the Check interface.
+func (v setSizeExact) CheckValue(other interface{}) error {
+	elems, ok := other.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected set value for SetSizeExact check, got: %s", describe(other))
+	}
+
+	if len(elems) != v.size {
+		return fmt.Errorf("expected %d elements for SetSizeExact check, got %d elements", v.size, len(elems))
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v setSizeExact) String() string {
+	return strconv.Itoa(v.size)
+}
+
+// SetSizeExact returns a Check for asserting that a set value has the given
+// number of elements.
+func SetSizeExact(size int) Check {
+	return setSizeExact{
+		size: size,
+	}
+}
+
+// matchSetElements returns an error unless every check matches a different
+// element of the given set elements.
+func matchSetElements(elems []interface{}, checks []Check, name string) error {
+	matched := make([]bool, len(elems))
+
+	for _, check := range checks {
+		found := false
+
+		for i, elem := range elems {
+			if matched[i] {
+				continue
+			}
+
+			if check.CheckValue(elem) == nil {
+				matched[i] = true
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("missing value %s for %s check", check, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"testing"
+)
+
+func TestSetExact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"any-order": {
+			Check: SetExact([]Check{StringExact("b"), StringExact("a")}),
+			Value: []interface{}{"a", "b"},
+		},
+		"objects": {
+			Check: SetExact([]Check{
+				ObjectPartial(map[string]Check{"port": Int64Exact(443)}),
+				ObjectPartial(map[string]Check{"port": Int64Exact(80)}),
+			}),
+			Value: []interface{}{
+				map[string]interface{}{"port": float64(80), "protocol": "tcp"},
+				map[string]interface{}{"port": float64(443), "protocol": "tcp"},
+			},
+		},
+		"missing": {
+			Check:         SetExact([]Check{StringExact("a"), StringExact("c")}),
+			Value:         []interface{}{"a", "b"},
+			ExpectedError: `missing value "c" for SetExact check`,
+		},
+		"duplicate-check": {
+			Check:         SetExact([]Check{StringExact("a"), StringExact("a")}),
+			Value:         []interface{}{"a", "b"},
+			ExpectedError: `missing value "a" for SetExact check`,
+		},
+		"length": {
+			Check:         SetExact([]Check{StringExact("a")}),
+			Value:         []interface{}{"a", "b"},
+			ExpectedError: "expected 1 elements for SetExact check, got 2 elements",
+		},
+	})
+}
+
+func TestSetPartial(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"subset": {
+			Check: SetPartial([]Check{StringExact("c")}),
+			Value: []interface{}{"a", "b", "c"},
+		},
+		"missing": {
+			Check:         SetPartial([]Check{StringExact("d")}),
+			Value:         []interface{}{"a", "b", "c"},
+			ExpectedError: `missing value "d" for SetPartial check`,
+		},
+	})
+}
+
+func TestSetSizeExact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"equal": {
+			Check: SetSizeExact(0),
+			Value: []interface{}{},
+		},
+		"not-equal": {
+			Check:         SetSizeExact(0),
+			Value:         []interface{}{"a"},
+			ExpectedError: "expected 0 elements for SetSizeExact check, got 1 elements",
+		},
+	})
+}
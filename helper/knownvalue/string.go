@@ -0,0 +1,75 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var _ Check = stringExact{}
+
+type stringExact struct {
+	value string
+}
+
+// CheckValue implements the Check interface.
+func (v stringExact) CheckValue(other interface{}) error {
+	s, ok := other.(string)
+	if !ok {
+		return fmt.Errorf("expected string value for StringExact check, got: %s", describe(other))
+	}
+
+	if s != v.value {
+		return fmt.Errorf("expected value %s for StringExact check, got: %q", v, s)
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v stringExact) String() string {
+	return fmt.Sprintf("%q", v.value)
+}
+
+// StringExact returns a Check for asserting equality between the given
+// string and a value.
+func StringExact(value string) Check {
+	return stringExact{
+		value: value,
+	}
+}
+
+var _ Check = stringRegexp{}
+
+type stringRegexp struct {
+	regex *regexp.Regexp
+}
+
+// CheckValue implements the Check interface.
+func (v stringRegexp) CheckValue(other interface{}) error {
+	s, ok := other.(string)
+	if !ok {
+		return fmt.Errorf("expected string value for StringRegexp check, got: %s", describe(other))
+	}
+
+	if !v.regex.MatchString(s) {
+		return fmt.Errorf("expected regex match %s for StringRegexp check, got: %q", v, s)
+	}
+
+	return nil
+}
+
+// String implements the Check interface.
+func (v stringRegexp) String() string {
+	return v.regex.String()
+}
+
+// StringRegexp returns a Check for asserting that a string value matches the
+// given regular expression.
+func StringRegexp(regex *regexp.Regexp) Check {
+	return stringRegexp{
+		regex: regex,
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package knownvalue
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+)
+
+func TestStringExact(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"equal": {
+			Check: StringExact("test"),
+			Value: "test",
+		},
+		"not-equal": {
+			Check:         StringExact("test"),
+			Value:         "other",
+			ExpectedError: `expected value "test" for StringExact check, got: "other"`,
+		},
+		"wrong-type": {
+			Check:         StringExact("1"),
+			Value:         json.Number("1"),
+			ExpectedError: "expected string value for StringExact check, got: 1",
+		},
+		"null": {
+			Check:         StringExact("test"),
+			Value:         nil,
+			ExpectedError: "expected string value for StringExact check, got: null",
+		},
+	})
+}
+
+func TestStringRegexp(t *testing.T) {
+	t.Parallel()
+
+	runCheckValueTests(t, map[string]checkValueTestCase{
+		"match": {
+			Check: StringRegexp(regexp.MustCompile(`^arn:`)),
+			Value: "arn:example:thing",
+		},
+		"no-match": {
+			Check:         StringRegexp(regexp.MustCompile(`^arn:`)),
+			Value:         "thing",
+			ExpectedError: `expected regex match ^arn: for StringRegexp check, got: "thing"`,
+		},
+		"wrong-type": {
+			Check:         StringRegexp(regexp.MustCompile(`.*`)),
+			Value:         true,
+			ExpectedError: "expected string value for StringRegexp check, got: true",
+		},
+	})
+}
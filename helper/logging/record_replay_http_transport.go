@@ -0,0 +1,443 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// These are the environment variables that determine whether the transports
+// of NewRecordReplayHTTPTransport record or replay HTTP interactions.
+const (
+	// EnvAccRecord records the HTTP interactions of acceptance tests to
+	// cassettes when set to any value.
+	EnvAccRecord = "TF_ACC_RECORD"
+
+	// EnvAccReplay replays the HTTP interactions of acceptance tests from
+	// cassettes, instead of sending requests, when set to any value.
+	EnvAccReplay = "TF_ACC_REPLAY"
+)
+
+// DefaultCassetteDir is the directory of the cassette files of
+// NewRecordReplayHTTPTransport, relative to the package directory of the
+// tests, unless RecordReplayOptions.Dir is set.
+const DefaultCassetteDir = "testdata/cassettes"
+
+// CassetteRedacted is the value which replaces secrets in cassettes.
+const CassetteRedacted = "REDACTED"
+
+// DefaultRedactedHeaders are the headers whose values are always replaced
+// with CassetteRedacted in cassettes.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+// Cassette is a recording of HTTP interactions, which is stored as a JSON
+// file.
+type Cassette struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// CassetteInteraction is a recorded HTTP request and its response.
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is a recorded HTTP request.
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// CassetteResponse is a recorded HTTP response.
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// CassetteMatcher returns true if a request being replayed matches a
+// recorded request. Both requests are redacted before they are matched.
+type CassetteMatcher func(req CassetteRequest, recorded CassetteRequest) bool
+
+// MatchMethod is a CassetteMatcher which requires the request methods to
+// be equal.
+func MatchMethod(req CassetteRequest, recorded CassetteRequest) bool {
+	return req.Method == recorded.Method
+}
+
+// MatchURL is a CassetteMatcher which requires the request URLs, including
+// their query strings, to be equal.
+func MatchURL(req CassetteRequest, recorded CassetteRequest) bool {
+	return req.URL == recorded.URL
+}
+
+// MatchBody is a CassetteMatcher which requires the request bodies to be
+// equal.
+func MatchBody(req CassetteRequest, recorded CassetteRequest) bool {
+	return req.Body == recorded.Body
+}
+
+// RecordReplayOptions are the options of NewRecordReplayHTTPTransport.
+type RecordReplayOptions struct {
+	// Dir is the directory of the cassette files. Defaults to
+	// DefaultCassetteDir.
+	Dir string
+
+	// Matchers are the CassetteMatcher functions that must all match for a
+	// recorded interaction to be replayed. Defaults to MatchMethod, MatchURL
+	// and MatchBody.
+	Matchers []CassetteMatcher
+
+	// RedactHeaders are the headers whose values are replaced with
+	// CassetteRedacted, in addition to DefaultRedactedHeaders.
+	RedactHeaders []string
+
+	// RedactValues are secrets, such as credentials and account
+	// identifiers, which are replaced with CassetteRedacted wherever they
+	// appear in the URLs, headers and bodies of the interactions.
+	RedactValues []string
+}
+
+// NewRecordReplayHTTPTransport creates a wrapper around an
+// *http.RoundTripper, designed to be used for the `Transport` field of
+// http.Client, which records or replays HTTP interactions in the named
+// cassette, so that acceptance tests can run without access to the API.
+//
+// When the TF_ACC_RECORD environment variable is set, the requests are
+// sent with the wrapped transport, and each interaction is written to the
+// cassette file, which replaces any prior recording. When the TF_ACC_REPLAY
+// environment variable is set, the requests are instead answered with the
+// first matching interaction of the cassette which has not been replayed
+// yet, or the last matching interaction if all of them have, and an error
+// is returned for requests without a matching interaction. Otherwise,
+// requests are sent with the wrapped transport.
+//
+// The transports of a cassette within a process share its recording, as the
+// provider is configured again for each Terraform command of an acceptance
+// test. The cassette file is replaced by the first recorded interaction of
+// the process, and the following interactions are appended to it, while
+// replayed interactions are not replayed again by later transports.
+//
+// The cassette file is the name, with any slashes replaced by double
+// underscores, and the .json extension in RecordReplayOptions.Dir. Use a
+// distinct cassette for each provider configuration of the tests, such as
+// one named after each acceptance test.
+//
+// This transport can be combined with NewLoggingHTTPTransport, for example:
+//
+//	transport := logging.NewLoggingHTTPTransport(
+//		logging.NewRecordReplayHTTPTransport(name, http.DefaultTransport, logging.RecordReplayOptions{}),
+//	)
+func NewRecordReplayHTTPTransport(name string, t http.RoundTripper, opts RecordReplayOptions) *recordReplayHttpTransport {
+	if opts.Dir == "" {
+		opts.Dir = DefaultCassetteDir
+	}
+
+	if len(opts.Matchers) == 0 {
+		opts.Matchers = []CassetteMatcher{MatchMethod, MatchURL, MatchBody}
+	}
+
+	return &recordReplayHttpTransport{
+		path:      filepath.Join(opts.Dir, strings.ReplaceAll(name, "/", "__")+".json"),
+		record:    os.Getenv(EnvAccRecord) != "",
+		replay:    os.Getenv(EnvAccReplay) != "",
+		opts:      opts,
+		transport: t,
+	}
+}
+
+type recordReplayHttpTransport struct {
+	path      string
+	record    bool
+	replay    bool
+	opts      RecordReplayOptions
+	transport http.RoundTripper
+}
+
+// cassettes are the cassettes recorded or replayed by the transports of
+// this process, keyed by file path.
+var (
+	cassettesMu sync.Mutex
+	cassettes   = make(map[string]*cassetteState)
+)
+
+// cassetteState is a cassette recorded or replayed by this process. The
+// replayed interactions are nil while the cassette is recorded.
+type cassetteState struct {
+	cassette *Cassette
+	replayed []bool
+}
+
+func (t *recordReplayHttpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch {
+	case t.record && t.replay:
+		return nil, fmt.Errorf("%s and %s cannot both be set", EnvAccRecord, EnvAccReplay)
+	case t.replay:
+		return t.replayRoundTrip(req)
+	case t.record:
+		return t.recordRoundTrip(req)
+	default:
+		return t.transport.RoundTrip(req)
+	}
+}
+
+func (t *recordReplayHttpTransport) recordRoundTrip(req *http.Request) (*http.Response, error) {
+	recordedReq, err := t.cassetteRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wrap the bytes from the response body back into an io.ReadCloser, as
+	// done when logging the response
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewBuffer(resBody))
+
+	interaction := CassetteInteraction{
+		Request: recordedReq,
+		Response: CassetteResponse{
+			StatusCode: res.StatusCode,
+			Header:     t.redactHeader(res.Header),
+			Body:       t.redact(string(resBody)),
+		},
+	}
+
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+
+	// The first recording of the process replaces the cassette
+	state, ok := cassettes[t.path]
+	if !ok || state.replayed != nil {
+		state = &cassetteState{
+			cassette: &Cassette{},
+		}
+		cassettes[t.path] = state
+	}
+
+	state.cassette.Interactions = append(state.cassette.Interactions, interaction)
+
+	// The cassette is written after every interaction, as the tests may
+	// exit without closing the transport
+	if err := writeCassette(t.path, state.cassette); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (t *recordReplayHttpTransport) replayRoundTrip(req *http.Request) (*http.Response, error) {
+	replayReq, err := t.cassetteRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+
+	state, ok := cassettes[t.path]
+	if !ok || state.replayed == nil {
+		cassette, err := readCassette(t.path)
+		if err != nil {
+			return nil, err
+		}
+
+		state = &cassetteState{
+			cassette: cassette,
+			replayed: make([]bool, len(cassette.Interactions)),
+		}
+		cassettes[t.path] = state
+	}
+
+	match := -1
+
+	for i, interaction := range state.cassette.Interactions {
+		if !t.matches(replayReq, interaction.Request) {
+			continue
+		}
+
+		match = i
+
+		if !state.replayed[i] {
+			break
+		}
+	}
+
+	if match == -1 {
+		return nil, fmt.Errorf("no interaction of cassette %s matches the request %s %s", t.path, replayReq.Method, replayReq.URL)
+	}
+
+	state.replayed[match] = true
+
+	recordedRes := state.cassette.Interactions[match].Response
+
+	header := recordedRes.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	// The recorded body may have been redacted or edited since
+	if header.Get("Content-Length") != "" {
+		header.Set("Content-Length", strconv.Itoa(len(recordedRes.Body)))
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recordedRes.StatusCode, http.StatusText(recordedRes.StatusCode)),
+		StatusCode:    recordedRes.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recordedRes.Body)),
+		ContentLength: int64(len(recordedRes.Body)),
+		Request:       req,
+	}, nil
+}
+
+// cassetteRequest returns the redacted form of a request, restoring its
+// body so that it can still be sent.
+func (t *recordReplayHttpTransport) cassetteRequest(req *http.Request) (CassetteRequest, error) {
+	var body []byte
+
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return CassetteRequest{}, err
+		}
+
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewBuffer(body))
+	}
+
+	return CassetteRequest{
+		Method: req.Method,
+		URL:    t.redact(req.URL.String()),
+		Header: t.redactHeader(req.Header),
+		Body:   t.redact(string(body)),
+	}, nil
+}
+
+func (t *recordReplayHttpTransport) matches(req CassetteRequest, recorded CassetteRequest) bool {
+	for _, matcher := range t.opts.Matchers {
+		if !matcher(req, recorded) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// redactHeader returns a copy of the given header, with the values of the
+// redacted headers replaced and the redacted values replaced in the rest.
+func (t *recordReplayHttpTransport) redactHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	redacted := make(http.Header, len(header))
+
+	for k, values := range header {
+		redactHeader := headerNamed(k, DefaultRedactedHeaders) || headerNamed(k, t.opts.RedactHeaders)
+
+		for _, v := range values {
+			if redactHeader {
+				redacted[k] = append(redacted[k], CassetteRedacted)
+			} else {
+				redacted[k] = append(redacted[k], t.redact(v))
+			}
+		}
+	}
+
+	return redacted
+}
+
+// headerNamed returns true if the given header key is one of the given
+// header names, which are case-insensitive.
+func headerNamed(key string, names []string) bool {
+	for _, name := range names {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redact replaces the redacted values within the given string.
+func (t *recordReplayHttpTransport) redact(s string) string {
+	for _, v := range t.opts.RedactValues {
+		if v == "" {
+			continue
+		}
+
+		s = strings.ReplaceAll(s, v, CassetteRedacted)
+	}
+
+	return s
+}
+
+func readCassette(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("cassette %s not found, record it with %s set: %w", path, EnvAccRecord, err)
+		}
+
+		return nil, fmt.Errorf("unable to read cassette %s: %w", path, err)
+	}
+
+	var cassette Cassette
+
+	if err := json.Unmarshal(b, &cassette); err != nil {
+		return nil, fmt.Errorf("unable to decode cassette %s: %w", path, err)
+	}
+
+	return &cassette, nil
+}
+
+func writeCassette(path string, cassette *Cassette) error {
+	b, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode cassette %s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("unable to create cassette directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return fmt.Errorf("unable to write cassette %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package logging_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+)
+
+func TestNewRecordReplayHTTPTransport(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Set-Cookie", "session=secret-session")
+		w.Header().Set("X-Request-Id", fmt.Sprintf("request-%d", requests))
+		fmt.Fprintf(w, "%s %s %s response %d for account secret-account", r.Method, r.URL.Path, body, requests)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	opts := logging.RecordReplayOptions{
+		Dir:          dir,
+		RedactValues: []string{"secret-account"},
+	}
+
+	do := func(t *testing.T, transport http.RoundTripper, method string, path string, body string) string {
+		t.Helper()
+
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer secret-token")
+
+		res, err := (&http.Client{Transport: transport}).Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		defer res.Body.Close()
+
+		resBody, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		return string(resBody)
+	}
+
+	t.Setenv(logging.EnvAccRecord, "1")
+
+	transport := logging.NewRecordReplayHTTPTransport("TestThing/basic", http.DefaultTransport, opts)
+
+	recorded := []string{
+		do(t, transport, http.MethodGet, "/thing", ""),
+		do(t, transport, http.MethodPost, "/thing", "one"),
+		do(t, transport, http.MethodGet, "/thing", ""),
+	}
+
+	expectedRecorded := []string{
+		"GET /thing  response 1 for account secret-account",
+		"POST /thing one response 2 for account secret-account",
+		"GET /thing  response 3 for account secret-account",
+	}
+
+	if diff := cmp.Diff(expectedRecorded, recorded); diff != "" {
+		t.Fatalf("unexpected recorded difference: %s", diff)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "TestThing__basic.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, secret := range []string{"secret-account", "secret-session", "secret-token"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("expected cassette to not contain %s, got:\n%s", secret, b)
+		}
+	}
+
+	t.Setenv(logging.EnvAccRecord, "")
+	t.Setenv(logging.EnvAccReplay, "1")
+
+	transport = logging.NewRecordReplayHTTPTransport("TestThing/basic", http.DefaultTransport, opts)
+
+	// Requests are matched on method, URL and body, in the recorded order
+	// for identical requests, with the last interaction replayed again.
+	replayed := []string{
+		do(t, transport, http.MethodPost, "/thing", "one"),
+		do(t, transport, http.MethodGet, "/thing", ""),
+		do(t, transport, http.MethodGet, "/thing", ""),
+		do(t, transport, http.MethodGet, "/thing", ""),
+	}
+
+	expectedReplayed := []string{
+		"POST /thing one response 2 for account REDACTED",
+		"GET /thing  response 1 for account REDACTED",
+		"GET /thing  response 3 for account REDACTED",
+		"GET /thing  response 3 for account REDACTED",
+	}
+
+	if diff := cmp.Diff(expectedReplayed, replayed); diff != "" {
+		t.Errorf("unexpected replayed difference: %s", diff)
+	}
+
+	if requests != 3 {
+		t.Errorf("expected no requests to be sent in replay, got %d requests", requests-3)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/thing", strings.NewReader("two"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = (&http.Client{Transport: transport}).Do(req)
+	if err == nil || !regexp.MustCompile(`no interaction of cassette .* matches the request POST`).MatchString(err.Error()) {
+		t.Errorf("expected no matching interaction error, got: %v", err)
+	}
+}
+
+func TestNewRecordReplayHTTPTransport_Matchers(t *testing.T) {
+	dir := t.TempDir()
+
+	cassette := `{
+  "interactions": [
+    {
+      "request": {
+        "method": "GET",
+        "url": "https://example.com/thing?page=1"
+      },
+      "response": {
+        "status_code": 404,
+        "header": {
+          "Content-Type": ["text/plain"]
+        },
+        "body": "not found"
+      }
+    }
+  ]
+}`
+
+	if err := os.WriteFile(filepath.Join(dir, "matchers.json"), []byte(cassette), 0o644); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	t.Setenv(logging.EnvAccReplay, "1")
+
+	transport := logging.NewRecordReplayHTTPTransport("matchers", nil, logging.RecordReplayOptions{
+		Dir: dir,
+		Matchers: []logging.CassetteMatcher{
+			logging.MatchMethod,
+			func(req logging.CassetteRequest, recorded logging.CassetteRequest) bool {
+				return strings.Split(req.URL, "?")[0] == strings.Split(recorded.URL, "?")[0]
+			},
+		},
+	})
+
+	res, err := (&http.Client{Transport: transport}).Get("https://example.com/thing?page=2")
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if res.StatusCode != http.StatusNotFound || res.Header.Get("Content-Type") != "text/plain" || string(body) != "not found" {
+		t.Errorf("unexpected response: %d %v %q", res.StatusCode, res.Header, body)
+	}
+}
+
+func TestNewRecordReplayHTTPTransport_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Setenv(logging.EnvAccReplay, "1")
+
+	transport := logging.NewRecordReplayHTTPTransport("missing", nil, logging.RecordReplayOptions{Dir: dir})
+
+	_, err := (&http.Client{Transport: transport}).Get("https://example.com/")
+	if err == nil || !strings.Contains(err.Error(), "not found, record it with TF_ACC_RECORD set") {
+		t.Errorf("expected missing cassette error, got: %v", err)
+	}
+
+	t.Setenv(logging.EnvAccRecord, "1")
+
+	transport = logging.NewRecordReplayHTTPTransport("missing", nil, logging.RecordReplayOptions{Dir: dir})
+
+	_, err = (&http.Client{Transport: transport}).Get("https://example.com/")
+	if err == nil || !strings.Contains(err.Error(), "TF_ACC_RECORD and TF_ACC_REPLAY cannot both be set") {
+		t.Errorf("expected conflicting environment variables error, got: %v", err)
+	}
+}
+
+func TestNewRecordReplayHTTPTransport_Transports(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		fmt.Fprintf(w, "%s %s response %d for account secret-account", r.Method, r.URL.Path, requests)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	opts := logging.RecordReplayOptions{
+		Dir:          dir,
+		RedactValues: []string{"secret-account"},
+	}
+
+	do := func(t *testing.T, method string) (string, string) {
+		t.Helper()
+
+		// Each request uses a new transport, as a provider configured for
+		// every Terraform command would.
+		transport := logging.NewRecordReplayHTTPTransport("TestThing/transports", http.DefaultTransport, opts)
+
+		req, err := http.NewRequest(method, server.URL+"/thing", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		res, err := (&http.Client{Transport: transport}).Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		defer res.Body.Close()
+
+		resBody, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		return string(resBody), res.Header.Get("Content-Length")
+	}
+
+	t.Setenv(logging.EnvAccRecord, "1")
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodGet} {
+		do(t, method)
+	}
+
+	t.Setenv(logging.EnvAccRecord, "")
+	t.Setenv(logging.EnvAccReplay, "1")
+
+	var replayed []string
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodGet} {
+		body, contentLength := do(t, method)
+
+		if expected := fmt.Sprint(len(body)); contentLength != expected {
+			t.Errorf("expected Content-Length %s, got %s", expected, contentLength)
+		}
+
+		replayed = append(replayed, body)
+	}
+
+	expectedReplayed := []string{
+		"GET /thing response 1 for account REDACTED",
+		"POST /thing response 2 for account REDACTED",
+		"GET /thing response 3 for account REDACTED",
+	}
+
+	if diff := cmp.Diff(expectedReplayed, replayed); diff != "" {
+		t.Errorf("unexpected replayed difference: %s", diff)
+	}
+
+	if requests != 3 {
+		t.Errorf("expected no requests to be sent in replay, got %d requests", requests-3)
+	}
+}
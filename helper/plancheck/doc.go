@@ -0,0 +1,23 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package plancheck contains the PlanCheck interface for checking the
+// Terraform plans created during acceptance tests, and the built-in checks
+// which implement it.
+//
+// Plan checks are run by setting the ConfigPlanChecks field of a
+// resource.TestStep, for example:
+//
+//	resource.TestStep{
+//		Config: `...`,
+//		ConfigPlanChecks: resource.ConfigPlanChecks{
+//			PreApply: []plancheck.PlanCheck{
+//				plancheck.ExpectResourceAction("examplecloud_thing.test", plancheck.ResourceActionCreate),
+//				plancheck.ExpectUnknownValue("examplecloud_thing.test", cty.GetAttrPath("id")),
+//			},
+//			PostApplyPostRefresh: []plancheck.PlanCheck{
+//				plancheck.ExpectEmptyPlan(),
+//			},
+//		},
+//	}
+package plancheck
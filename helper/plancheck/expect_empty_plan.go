@@ -0,0 +1,50 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plancheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/planrender"
+)
+
+var _ PlanCheck = expectEmptyPlan{}
+
+type expectEmptyPlan struct{}
+
+// CheckPlan implements the PlanCheck interface.
+func (e expectEmptyPlan) CheckPlan(_ context.Context, plan *tfjson.Plan) error {
+	var addrs []string
+
+	for _, rc := range plan.ResourceChanges {
+		if !rc.Change.Actions.NoOp() {
+			addrs = append(addrs, rc.Address)
+		}
+	}
+
+	if len(addrs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("expected empty plan, but %s %s planned changes:\n\n%s", strings.Join(addrs, ", "), pluralHas(len(addrs)), planrender.Plan(plan))
+}
+
+// ExpectEmptyPlan returns a plan check that asserts that no resource
+// changes are planned. The error lists the resources with planned changes,
+// and a summary of the changed attributes.
+func ExpectEmptyPlan() PlanCheck {
+	return expectEmptyPlan{}
+}
+
+func pluralHas(n int) string {
+	if n == 1 {
+		return "has"
+	}
+
+	return "have"
+}
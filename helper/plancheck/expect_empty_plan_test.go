@@ -0,0 +1,51 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plancheck
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestExpectEmptyPlan(t *testing.T) {
+	t.Parallel()
+
+	err := ExpectEmptyPlan().CheckPlan(context.Background(), testPlan(tfjson.Actions{tfjson.ActionNoop}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	plan := testPlan(tfjson.Actions{tfjson.ActionNoop}, tfjson.Actions{tfjson.ActionUpdate})
+	plan.ResourceChanges[1].Change.Before = map[string]interface{}{
+		"id":   "1",
+		"name": "old",
+	}
+	plan.ResourceChanges[1].Change.After = map[string]interface{}{
+		"id":   "1",
+		"name": "new",
+	}
+
+	err = ExpectEmptyPlan().CheckPlan(context.Background(), plan)
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	for _, expected := range []string{
+		"expected empty plan, but examplecloud_thing.testx has planned changes:",
+		"# examplecloud_thing.testx will be updated in-place",
+		`name: "old" -> "new"`,
+		"Plan: 0 to add, 1 to change, 0 to destroy.",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q, got:\n%s", expected, err)
+		}
+	}
+
+	if strings.Contains(err.Error(), "id:") {
+		t.Errorf("expected unchanged attributes to be omitted, got:\n%s", err)
+	}
+}
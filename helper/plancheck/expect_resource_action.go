@@ -0,0 +1,84 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plancheck
+
+import (
+	"context"
+	"fmt"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// ResourceActionType is the action planned for a resource instance.
+type ResourceActionType string
+
+const (
+	// ResourceActionNoop is planned when no change is required.
+	ResourceActionNoop ResourceActionType = "NoOp"
+
+	// ResourceActionCreate is planned when a new object is created.
+	ResourceActionCreate ResourceActionType = "Create"
+
+	// ResourceActionUpdate is planned when the existing object is updated
+	// in-place.
+	ResourceActionUpdate ResourceActionType = "Update"
+
+	// ResourceActionReplace is planned when the existing object is replaced
+	// with a new object, either before or after it is destroyed.
+	ResourceActionReplace ResourceActionType = "Replace"
+
+	// ResourceActionDestroy is planned when the existing object is destroyed
+	// without being replaced.
+	ResourceActionDestroy ResourceActionType = "Destroy"
+)
+
+var _ PlanCheck = expectResourceAction{}
+
+type expectResourceAction struct {
+	addr   string
+	action ResourceActionType
+}
+
+// CheckPlan implements the PlanCheck interface.
+func (e expectResourceAction) CheckPlan(_ context.Context, plan *tfjson.Plan) error {
+	rc, err := resourceChange(plan, e.addr)
+	if err != nil {
+		return err
+	}
+
+	actions := rc.Change.Actions
+
+	var matches bool
+
+	switch e.action {
+	case ResourceActionNoop:
+		matches = actions.NoOp()
+	case ResourceActionCreate:
+		matches = actions.Create()
+	case ResourceActionUpdate:
+		matches = actions.Update()
+	case ResourceActionReplace:
+		matches = actions.Replace()
+	case ResourceActionDestroy:
+		matches = actions.Delete()
+	default:
+		return fmt.Errorf("%s - unexpected ResourceActionType: %s", e.addr, e.action)
+	}
+
+	if !matches {
+		return fmt.Errorf("%s - expected %s, got action(s): %v", e.addr, e.action, actions)
+	}
+
+	return nil
+}
+
+// ExpectResourceAction returns a plan check that asserts that the resource
+// instance with the given address, such as examplecloud_thing.test, has the
+// given planned action.
+func ExpectResourceAction(addr string, action ResourceActionType) PlanCheck {
+	return expectResourceAction{
+		addr:   addr,
+		action: action,
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plancheck
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func testPlan(actions ...tfjson.Actions) *tfjson.Plan {
+	plan := &tfjson.Plan{}
+
+	for i, a := range actions {
+		plan.ResourceChanges = append(plan.ResourceChanges, &tfjson.ResourceChange{
+			Address: "examplecloud_thing.test" + strings.Repeat("x", i),
+			Mode:    tfjson.ManagedResourceMode,
+			Type:    "examplecloud_thing",
+			Name:    "test" + strings.Repeat("x", i),
+			Change: &tfjson.Change{
+				Actions: a,
+			},
+		})
+	}
+
+	return plan
+}
+
+func TestExpectResourceAction(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Actions       tfjson.Actions
+		Action        ResourceActionType
+		ExpectedError string
+	}{
+		"noop": {
+			Actions: tfjson.Actions{tfjson.ActionNoop},
+			Action:  ResourceActionNoop,
+		},
+		"create": {
+			Actions: tfjson.Actions{tfjson.ActionCreate},
+			Action:  ResourceActionCreate,
+		},
+		"update": {
+			Actions: tfjson.Actions{tfjson.ActionUpdate},
+			Action:  ResourceActionUpdate,
+		},
+		"replace-destroy-before-create": {
+			Actions: tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate},
+			Action:  ResourceActionReplace,
+		},
+		"replace-create-before-destroy": {
+			Actions: tfjson.Actions{tfjson.ActionCreate, tfjson.ActionDelete},
+			Action:  ResourceActionReplace,
+		},
+		"destroy": {
+			Actions: tfjson.Actions{tfjson.ActionDelete},
+			Action:  ResourceActionDestroy,
+		},
+		"replace-is-not-destroy": {
+			Actions:       tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate},
+			Action:        ResourceActionDestroy,
+			ExpectedError: "examplecloud_thing.test - expected Destroy, got action(s): [delete create]",
+		},
+		"update-is-not-create": {
+			Actions:       tfjson.Actions{tfjson.ActionUpdate},
+			Action:        ResourceActionCreate,
+			ExpectedError: "examplecloud_thing.test - expected Create, got action(s): [update]",
+		},
+		"invalid-action": {
+			Actions:       tfjson.Actions{tfjson.ActionUpdate},
+			Action:        "Invalid",
+			ExpectedError: "examplecloud_thing.test - unexpected ResourceActionType: Invalid",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ExpectResourceAction("examplecloud_thing.test", tc.Action).CheckPlan(context.Background(), testPlan(tc.Actions))
+
+			if tc.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tc.ExpectedError {
+				t.Fatalf("expected error %q, got: %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestExpectResourceAction_notFound(t *testing.T) {
+	t.Parallel()
+
+	err := ExpectResourceAction("examplecloud_thing.other", ResourceActionCreate).CheckPlan(context.Background(), testPlan(tfjson.Actions{tfjson.ActionCreate}))
+
+	expected := "examplecloud_thing.other - Resource not found in plan ResourceChanges"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %q, got: %v", expected, err)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plancheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/tfjsonpath"
+)
+
+var _ PlanCheck = expectSensitiveValue{}
+
+type expectSensitiveValue struct {
+	addr string
+	path cty.Path
+}
+
+// CheckPlan implements the PlanCheck interface.
+func (e expectSensitiveValue) CheckPlan(_ context.Context, plan *tfjson.Plan) error {
+	rc, err := resourceChange(plan, e.addr)
+	if err != nil {
+		return err
+	}
+
+	if markedAtPath(rc.Change.AfterSensitive, e.path) {
+		return nil
+	}
+
+	if _, err := tfjsonpath.Traverse(rc.Change.After, e.path); err != nil && !markedAtPath(rc.Change.AfterUnknown, e.path) {
+		return fmt.Errorf("%s - %w", e.addr, err)
+	}
+
+	return fmt.Errorf("%s - attribute at path %s is not sensitive", e.addr, tfjsonpath.String(e.path))
+}
+
+// ExpectSensitiveValue returns a plan check that asserts that the value at
+// the given path of the planned new state of the resource instance with the
+// given address is sensitive, such as an attribute with the Sensitive schema
+// field enabled.
+func ExpectSensitiveValue(addr string, path cty.Path) PlanCheck {
+	return expectSensitiveValue{
+		addr: addr,
+		path: path,
+	}
+}
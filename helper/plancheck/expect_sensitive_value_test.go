@@ -0,0 +1,83 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plancheck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestExpectSensitiveValue(t *testing.T) {
+	t.Parallel()
+
+	plan := testPlan(tfjson.Actions{tfjson.ActionUpdate})
+	plan.ResourceChanges[0].Change.After = map[string]interface{}{
+		"name":     "test",
+		"password": "secret",
+		"token":    nil,
+		"rule": []interface{}{
+			map[string]interface{}{
+				"key": "secret",
+			},
+		},
+	}
+	plan.ResourceChanges[0].Change.AfterUnknown = map[string]interface{}{
+		"token": true,
+	}
+	plan.ResourceChanges[0].Change.AfterSensitive = map[string]interface{}{
+		"password": true,
+		"rule": []interface{}{
+			map[string]interface{}{
+				"key": true,
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		Path          cty.Path
+		ExpectedError string
+	}{
+		"sensitive": {
+			Path: cty.GetAttrPath("password"),
+		},
+		"sensitive-nested": {
+			Path: cty.GetAttrPath("rule").IndexInt(0).GetAttr("key"),
+		},
+		"not-sensitive": {
+			Path:          cty.GetAttrPath("name"),
+			ExpectedError: "examplecloud_thing.test - attribute at path name is not sensitive",
+		},
+		"unknown-not-sensitive": {
+			Path:          cty.GetAttrPath("token"),
+			ExpectedError: "examplecloud_thing.test - attribute at path token is not sensitive",
+		},
+		"not-found": {
+			Path:          cty.GetAttrPath("missing"),
+			ExpectedError: `examplecloud_thing.test - path missing: "missing" not found`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ExpectSensitiveValue("examplecloud_thing.test", tc.Path).CheckPlan(context.Background(), plan)
+
+			if tc.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tc.ExpectedError {
+				t.Fatalf("expected error %q, got: %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plancheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/tfjsonpath"
+)
+
+var _ PlanCheck = expectUnknownValue{}
+
+type expectUnknownValue struct {
+	addr string
+	path cty.Path
+}
+
+// CheckPlan implements the PlanCheck interface.
+func (e expectUnknownValue) CheckPlan(_ context.Context, plan *tfjson.Plan) error {
+	rc, err := resourceChange(plan, e.addr)
+	if err != nil {
+		return err
+	}
+
+	if markedAtPath(rc.Change.AfterUnknown, e.path) {
+		return nil
+	}
+
+	// Values which are neither unknown nor planned are reported as not
+	// found rather than known.
+	if _, err := tfjsonpath.Traverse(rc.Change.After, e.path); err != nil {
+		return fmt.Errorf("%s - %w", e.addr, err)
+	}
+
+	return fmt.Errorf("%s - attribute at path %s is known", e.addr, tfjsonpath.String(e.path))
+}
+
+// ExpectUnknownValue returns a plan check that asserts that the value at the
+// given path of the planned new state of the resource instance with the given
+// address is unknown, which is shown as "(known after apply)" in plans.
+func ExpectUnknownValue(addr string, path cty.Path) PlanCheck {
+	return expectUnknownValue{
+		addr: addr,
+		path: path,
+	}
+}
+
+// markedAtPath returns whether the given after_unknown or sensitive values
+// structure of a planned change is true at the given path or any of its
+// parents, as a whole unknown or sensitive object or collection is
+// represented by a single true value.
+func markedAtPath(marks interface{}, path cty.Path) bool {
+	for i := 0; i <= len(path); i++ {
+		v, err := tfjsonpath.Traverse(marks, path[:i])
+		if err != nil {
+			return false
+		}
+
+		if v == true {
+			return true
+		}
+	}
+
+	return false
+}
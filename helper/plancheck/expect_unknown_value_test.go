@@ -0,0 +1,76 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plancheck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestExpectUnknownValue(t *testing.T) {
+	t.Parallel()
+
+	plan := testPlan(tfjson.Actions{tfjson.ActionCreate})
+	plan.ResourceChanges[0].Change.After = map[string]interface{}{
+		"name": "test",
+		"id":   nil,
+		"rule": nil,
+		"tags": map[string]interface{}{
+			"env": nil,
+		},
+	}
+	plan.ResourceChanges[0].Change.AfterUnknown = map[string]interface{}{
+		"id":   true,
+		"rule": true,
+		"tags": map[string]interface{}{
+			"env": true,
+		},
+	}
+
+	cases := map[string]struct {
+		Path          cty.Path
+		ExpectedError string
+	}{
+		"unknown": {
+			Path: cty.GetAttrPath("id"),
+		},
+		"unknown-map-element": {
+			Path: cty.GetAttrPath("tags").IndexString("env"),
+		},
+		"unknown-parent": {
+			Path: cty.GetAttrPath("rule").IndexInt(0).GetAttr("port"),
+		},
+		"known": {
+			Path:          cty.GetAttrPath("name"),
+			ExpectedError: "examplecloud_thing.test - attribute at path name is known",
+		},
+		"not-found": {
+			Path:          cty.GetAttrPath("missing"),
+			ExpectedError: `examplecloud_thing.test - path missing: "missing" not found`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ExpectUnknownValue("examplecloud_thing.test", tc.Path).CheckPlan(context.Background(), plan)
+
+			if tc.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tc.ExpectedError {
+				t.Fatalf("expected error %q, got: %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plancheck
+
+import (
+	"context"
+	"fmt"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// PlanCheck defines a check of a Terraform plan, in the format of the
+// terraform show -json command.
+type PlanCheck interface {
+	// CheckPlan returns an error if the plan does not pass the check.
+	CheckPlan(ctx context.Context, plan *tfjson.Plan) error
+}
+
+// resourceChange returns the planned change of the resource instance with
+// the given address, such as examplecloud_thing.test or
+// examplecloud_thing.test[0].
+func resourceChange(plan *tfjson.Plan, addr string) (*tfjson.ResourceChange, error) {
+	for _, rc := range plan.ResourceChanges {
+		if rc.Address == addr {
+			return rc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s - Resource not found in plan ResourceChanges", addr)
+}
@@ -112,7 +112,7 @@ type providerFactories struct {
 	protov6 protov6ProviderFactories
 }
 
-func runProviderCommand(ctx context.Context, t testing.T, f func() error, wd *plugintest.WorkingDir, factories *providerFactories) error {
+func runProviderCommand(ctx context.Context, t testing.T, f func() error, wd workingDir, factories *providerFactories) error {
 	// don't point to this as a test failure location
 	// point to whatever called it
 	t.Helper()
@@ -124,6 +124,14 @@ func runProviderCommand(ctx context.Context, t testing.T, f func() error, wd *pl
 		return err
 	}
 
+	// The in-process Terraform core calls the provider servers directly,
+	// without the reattach behavior of the Terraform CLI.
+	if wd, ok := wd.(*inProcessWorkingDir); ok {
+		return wd.runProviderCommand(ctx, f, factories)
+	}
+
+	cliWd := wd.(*plugintest.WorkingDir)
+
 	// Run the providers in the same process as the test runner using the
 	// reattach behavior in Terraform. This ensures we get test coverage
 	// and enables the use of delve as a debugger.
@@ -427,7 +435,7 @@ func runProviderCommand(ctx context.Context, t testing.T, f func() error, wd *pl
 
 	// set the working directory reattach info that will tell Terraform how to
 	// connect to our various running servers.
-	wd.SetReattachInfo(ctx, reattachInfo)
+	cliWd.SetReattachInfo(ctx, reattachInfo)
 
 	logging.HelperResourceTrace(ctx, "Calling wrapped Terraform CLI command")
 
@@ -469,7 +477,7 @@ func runProviderCommand(ctx context.Context, t testing.T, f func() error, wd *pl
 	// longer valid. In theory it should be overwritten in the next call,
 	// but just to avoid any confusing bug reports, let's just unset the
 	// environment variable altogether.
-	wd.UnsetReattachInfo()
+	cliWd.UnsetReattachInfo()
 
 	// return any error returned from the orchestration code running
 	// Terraform commands
@@ -41,6 +41,9 @@ func (c TestCase) hasProviders(_ context.Context) bool {
 //
 //   - No overlapping ExternalProviders and Providers entries
 //   - No overlapping ExternalProviders and ProviderFactories entries
+//   - No ExternalProviders, ProtoV6ProviderFactories, or IDRefreshName with
+//     InProcessCore
+//   - UpgradeFrom settings and TestSteps which are supported with it
 //   - TestStep validations performed by the (TestStep).validate() method.
 func (c TestCase) validate(ctx context.Context) error {
 	logging.HelperResourceTrace(ctx, "Validating TestCase")
@@ -65,6 +68,20 @@ func (c TestCase) validate(ctx context.Context) error {
 		}
 	}
 
+	if c.InProcessCore {
+		if err := c.validateInProcessCore(); err != nil {
+			logging.HelperResourceError(ctx, "TestCase validation error", map[string]interface{}{logging.KeyError: err})
+			return err
+		}
+	}
+
+	if c.UpgradeFrom != nil {
+		if err := c.validateUpgradeFrom(ctx); err != nil {
+			logging.HelperResourceError(ctx, "TestCase validation error", map[string]interface{}{logging.KeyError: err})
+			return err
+		}
+	}
+
 	testCaseHasProviders := c.hasProviders(ctx)
 
 	for stepIndex, step := range c.Steps {
@@ -85,3 +102,68 @@ func (c TestCase) validate(ctx context.Context) error {
 
 	return nil
 }
+
+// validateInProcessCore ensures the TestCase and its TestSteps only use
+// providers and features supported by the in-process Terraform core.
+func (c TestCase) validateInProcessCore() error {
+	if len(c.ExternalProviders) > 0 {
+		return fmt.Errorf("TestCase ExternalProviders are not supported with InProcessCore")
+	}
+
+	if len(c.ProtoV6ProviderFactories) > 0 {
+		return fmt.Errorf("TestCase ProtoV6ProviderFactories are not supported with InProcessCore")
+	}
+
+	if c.IDRefreshName != "" {
+		return fmt.Errorf("TestCase IDRefreshName is not supported with InProcessCore")
+	}
+
+	for stepIndex, step := range c.Steps {
+		stepNumber := stepIndex + 1 // Use 1-based index for humans
+
+		if len(step.ExternalProviders) > 0 {
+			return fmt.Errorf("TestStep %d/%d ExternalProviders are not supported with InProcessCore", stepNumber, len(c.Steps))
+		}
+
+		if len(step.ProtoV6ProviderFactories) > 0 {
+			return fmt.Errorf("TestStep %d/%d ProtoV6ProviderFactories are not supported with InProcessCore", stepNumber, len(c.Steps))
+		}
+	}
+
+	return nil
+}
+
+// validateUpgradeFrom ensures the UpgradeFrom of the TestCase is complete
+// and its TestSteps only use features supported with it.
+func (c TestCase) validateUpgradeFrom(ctx context.Context) error {
+	if c.InProcessCore {
+		return fmt.Errorf("TestCase UpgradeFrom is not supported with InProcessCore")
+	}
+
+	if c.UpgradeFrom.ProviderName == "" || c.UpgradeFrom.Version == "" || c.UpgradeFrom.MirrorDir == "" {
+		return fmt.Errorf("TestCase UpgradeFrom must specify ProviderName, Version and MirrorDir")
+	}
+
+	name := c.UpgradeFrom.ProviderName
+	_, legacy := c.ProviderFactories[name]
+	_, protov5 := c.ProtoV5ProviderFactories[name]
+	_, protov6 := c.ProtoV6ProviderFactories[name]
+
+	if !legacy && !protov5 && !protov6 {
+		return fmt.Errorf("TestCase UpgradeFrom provider %q must be set in ProviderFactories, ProtoV5ProviderFactories or ProtoV6ProviderFactories", name)
+	}
+
+	for stepIndex, step := range c.Steps {
+		stepNumber := stepIndex + 1 // Use 1-based index for humans
+
+		if step.ImportState || step.RefreshState || step.ExpectError != nil {
+			return fmt.Errorf("TestStep %d/%d ImportState, RefreshState and ExpectError are not supported with UpgradeFrom", stepNumber, len(c.Steps))
+		}
+
+		if step.hasProviders(ctx) {
+			return fmt.Errorf("TestStep %d/%d providers are not supported with UpgradeFrom", stepNumber, len(c.Steps))
+		}
+	}
+
+	return nil
+}
@@ -132,10 +132,181 @@ func TestTestCaseValidate(t *testing.T) {
 			},
 			expectedError: fmt.Errorf("TestCase provider \"test\" set in both ExternalProviders and ProviderFactories"),
 		},
+		"inprocesscore-externalproviders": {
+			testCase: TestCase{
+				InProcessCore: true,
+				ExternalProviders: map[string]ExternalProvider{
+					"other": {}, // does not need to be real
+				},
+				ProviderFactories: map[string]func() (*schema.Provider, error){
+					"test": nil, // does not need to be real
+				},
+				Steps: []TestStep{
+					{
+						Config: "# not empty",
+					},
+				},
+			},
+			expectedError: fmt.Errorf("TestCase ExternalProviders are not supported with InProcessCore"),
+		},
+		"inprocesscore-protov6providerfactories": {
+			testCase: TestCase{
+				InProcessCore: true,
+				ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+					"test": nil, // does not need to be real
+				},
+				Steps: []TestStep{
+					{
+						Config: "# not empty",
+					},
+				},
+			},
+			expectedError: fmt.Errorf("TestCase ProtoV6ProviderFactories are not supported with InProcessCore"),
+		},
+		"inprocesscore-step-protov6providerfactories": {
+			testCase: TestCase{
+				InProcessCore: true,
+				Steps: []TestStep{
+					{
+						Config: "# not empty",
+						ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+							"test": nil, // does not need to be real
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("TestStep 1/1 ProtoV6ProviderFactories are not supported with InProcessCore"),
+		},
+		"inprocesscore-valid": {
+			testCase: TestCase{
+				InProcessCore: true,
+				ProtoV5ProviderFactories: map[string]func() (tfprotov5.ProviderServer, error){
+					"test": nil, // does not need to be real
+				},
+				Steps: []TestStep{
+					{
+						Config: "# not empty",
+					},
+				},
+			},
+		},
 		"steps-missing": {
 			testCase:      TestCase{},
 			expectedError: fmt.Errorf("TestCase missing Steps"),
 		},
+		"upgradefrom": {
+			testCase: TestCase{
+				ProviderFactories: map[string]func() (*schema.Provider, error){
+					"test": nil, // does not need to be real
+				},
+				UpgradeFrom: &UpgradeFrom{
+					ProviderName: "test",
+					Version:      "1.2.0",
+					MirrorDir:    "testdata/mirror",
+				},
+				Steps: []TestStep{
+					{
+						Config: "# not empty",
+					},
+				},
+			},
+		},
+		"upgradefrom-incomplete": {
+			testCase: TestCase{
+				ProviderFactories: map[string]func() (*schema.Provider, error){
+					"test": nil, // does not need to be real
+				},
+				UpgradeFrom: &UpgradeFrom{
+					ProviderName: "test",
+				},
+				Steps: []TestStep{
+					{
+						Config: "# not empty",
+					},
+				},
+			},
+			expectedError: fmt.Errorf("TestCase UpgradeFrom must specify ProviderName, Version and MirrorDir"),
+		},
+		"upgradefrom-provider-missing": {
+			testCase: TestCase{
+				ProviderFactories: map[string]func() (*schema.Provider, error){
+					"test": nil, // does not need to be real
+				},
+				UpgradeFrom: &UpgradeFrom{
+					ProviderName: "other",
+					Version:      "1.2.0",
+					MirrorDir:    "testdata/mirror",
+				},
+				Steps: []TestStep{
+					{
+						Config: "# not empty",
+					},
+				},
+			},
+			expectedError: fmt.Errorf(`TestCase UpgradeFrom provider "other" must be set in ProviderFactories, ProtoV5ProviderFactories or ProtoV6ProviderFactories`),
+		},
+		"upgradefrom-inprocesscore": {
+			testCase: TestCase{
+				InProcessCore: true,
+				ProviderFactories: map[string]func() (*schema.Provider, error){
+					"test": nil, // does not need to be real
+				},
+				UpgradeFrom: &UpgradeFrom{
+					ProviderName: "test",
+					Version:      "1.2.0",
+					MirrorDir:    "testdata/mirror",
+				},
+				Steps: []TestStep{
+					{
+						Config: "# not empty",
+					},
+				},
+			},
+			expectedError: fmt.Errorf("TestCase UpgradeFrom is not supported with InProcessCore"),
+		},
+		"upgradefrom-importstate": {
+			testCase: TestCase{
+				ProviderFactories: map[string]func() (*schema.Provider, error){
+					"test": nil, // does not need to be real
+				},
+				UpgradeFrom: &UpgradeFrom{
+					ProviderName: "test",
+					Version:      "1.2.0",
+					MirrorDir:    "testdata/mirror",
+				},
+				Steps: []TestStep{
+					{
+						Config: "# not empty",
+					},
+					{
+						ImportState:  true,
+						ResourceName: "test_resource.test",
+					},
+				},
+			},
+			expectedError: fmt.Errorf("TestStep 2/2 ImportState, RefreshState and ExpectError are not supported with UpgradeFrom"),
+		},
+		"upgradefrom-step-providers": {
+			testCase: TestCase{
+				UpgradeFrom: &UpgradeFrom{
+					ProviderName: "test",
+					Version:      "1.2.0",
+					MirrorDir:    "testdata/mirror",
+				},
+				ProviderFactories: map[string]func() (*schema.Provider, error){
+					"test": nil, // does not need to be real
+				},
+				Steps: []TestStep{
+					{
+						Config: "# not empty",
+						ExternalProviders: map[string]ExternalProvider{
+							"other": {}, // does not need to be real
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("TestStep 1/1 providers are not supported with UpgradeFrom"),
+		},
 		"steps-validate-error": {
 			testCase: TestCase{
 				Steps: []TestStep{
@@ -0,0 +1,3 @@
+resource "examplecloud_thing" "test" {
+  name = var.name
+}
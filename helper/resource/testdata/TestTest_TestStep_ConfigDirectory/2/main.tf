@@ -0,0 +1,14 @@
+variable "thing" {
+  type = object({
+    names = list(string)
+    index = number
+  })
+}
+
+resource "examplecloud_thing" "test" {
+  name = var.thing.names[var.thing.index]
+}
+
+output "name" {
+  value = examplecloud_thing.test.name
+}
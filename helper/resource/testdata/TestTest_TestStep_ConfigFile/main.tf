@@ -0,0 +1,8 @@
+variable "name" {
+  type    = string
+  default = "default"
+}
+
+resource "examplecloud_thing" "test" {
+  name = var.name
+}
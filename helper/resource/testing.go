@@ -13,14 +13,16 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/mitchellh/go-testing-interface"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/plancheck"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/statecheck"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/addrs"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugintest"
@@ -37,10 +39,9 @@ import (
 // Adding Sweeper methods with AddTestSweepers will
 // construct a list of sweeper funcs to be called here. We iterate through
 // regions provided by the sweep flag, and for each region we iterate through the
-// tests, and exit on any errors. At time of writing, sweepers are ran
-// sequentially, however they can list dependencies to be ran first. We track
-// the sweepers that have been ran, so as to not run a sweeper twice for a given
-// region.
+// tests, and exit on any errors. Sweepers of a region are ran concurrently,
+// however they can list dependencies to be ran first. We track the sweepers
+// that have been ran, so as to not run a sweeper twice for a given region.
 //
 // WARNING:
 // Sweepers are designed to be destructive. You should not use the -sweep flag
@@ -50,6 +51,10 @@ import (
 var flagSweep = flag.String("sweep", "", "List of Regions to run available Sweepers")
 var flagSweepAllowFailures = flag.Bool("sweep-allow-failures", false, "Enable to allow Sweeper Tests to continue after failures")
 var flagSweepRun = flag.String("sweep-run", "", "Comma separated list of Sweeper Tests to run")
+var flagSweepDryRun = flag.Bool("sweep-dry-run", false, "Enable to report the resources Sweepers would delete without deleting them")
+var flagSweepParallelism = flag.Int("sweep-parallelism", 10, "Maximum number of Sweepers to run concurrently in a region")
+var flagSweepReport = flag.String("sweep-report", "", "Path of a JSON, or JUnit XML with the .xml extension, report of the Sweeper results")
+var flagSweepTimeout = flag.Duration("sweep-timeout", 0, "Maximum duration of the Sweepers, overriding the go test timeout, after which no further Sweepers are started and their context is cancelled")
 var sweeperFuncs map[string]*Sweeper
 
 // SweeperFunc is a signature for a function that acts as a sweeper. It
@@ -69,6 +74,23 @@ type Sweeper struct {
 	// Sweeper function that when invoked sweeps the Provider of specific
 	// resources
 	F SweeperFunc
+
+	// ContextF is a sweeper function which supports dry runs and
+	// cancellation, used instead of F when set. Sweepers without ContextF
+	// are skipped with the -sweep-dry-run flag.
+	ContextF SweeperContextFunc
+
+	// ProviderFactory returns the provider whose meta, as returned by its
+	// ConfigureContextFunc, is passed to ContextF, so that the sweeper can
+	// reuse the clients of the provider. The provider is configured for
+	// each region with ProviderConfig.
+	ProviderFactory func() (*schema.Provider, error)
+
+	// ProviderConfig returns the provider configuration for the given
+	// region, such as its region and credential arguments, keyed by
+	// attribute name. Arguments not set fall back to their provider schema
+	// defaults, including those of DefaultFunc environment variables.
+	ProviderConfig func(r string) map[string]interface{}
 }
 
 func init() {
@@ -106,6 +128,18 @@ func AddTestSweepers(name string, s *Sweeper) {
 //	-sweep-allow-failures: Enable to allow other sweepers to run after failures.
 //	-sweep-run: Comma-separated list of resource type sweepers to run. Defaults
 //	        to all sweepers.
+//	-sweep-dry-run: Enable to run the sweepers with a ContextF, which report
+//	        the resources they would delete with SweepWouldDelete, without
+//	        deleting any resources. Other sweepers are skipped.
+//	-sweep-parallelism: Maximum number of sweepers to run concurrently in a
+//	        region. Sweepers always run after their dependencies. Defaults
+//	        to 10.
+//	-sweep-report: Path of a file to write a report of the results and
+//	        durations of each sweeper in each region to, in the JUnit XML
+//	        format if the path has the .xml extension and JSON otherwise.
+//
+// Sweepers with a ContextF are cancelled on an interrupt and once the go test
+// -timeout passes.
 //
 // Refer to the Env prefixed constants for environment variables that further
 // control testing functionality.
@@ -120,67 +154,30 @@ func TestMain(m interface {
 		// get filtered list of sweepers to run based on sweep-run flag
 		sweepers := filterSweepers(*flagSweepRun, sweeperFuncs)
 
-		if _, err := runSweepers(regions, sweepers, *flagSweepAllowFailures); err != nil {
-			os.Exit(1)
+		opts := sweepOptions{
+			allowFailures: *flagSweepAllowFailures,
+			dryRun:        *flagSweepDryRun,
+			parallelism:   *flagSweepParallelism,
 		}
-	} else {
-		exitCode := m.Run()
-		os.Exit(exitCode)
-	}
-}
-
-func runSweepers(regions []string, sweepers map[string]*Sweeper, allowFailures bool) (map[string]map[string]error, error) {
-	var sweeperErrorFound bool
-	sweeperRunList := make(map[string]map[string]error)
-
-	for _, region := range regions {
-		region = strings.TrimSpace(region)
-
-		var regionSweeperErrorFound bool
-		regionSweeperRunList := make(map[string]error)
 
-		start := time.Now()
-		log.Printf("[DEBUG] Running Sweepers for region (%s):\n", region)
-		for _, sweeper := range sweepers {
-			if err := runSweeperWithRegion(region, sweeper, sweepers, regionSweeperRunList, allowFailures); err != nil {
-				if allowFailures {
-					continue
-				}
+		ctx, cancel := sweepContext(sweepTimeout(*flagSweepTimeout))
+		report, err := runSweepers(ctx, regions, sweepers, opts)
+		cancel()
 
-				sweeperRunList[region] = regionSweeperRunList
-				return sweeperRunList, fmt.Errorf("sweeper (%s) for region (%s) failed: %s", sweeper.Name, region, err)
-			}
-		}
-		elapsed := time.Since(start)
-		log.Printf("Completed Sweepers for region (%s) in %s", region, elapsed)
-
-		log.Printf("Sweeper Tests for region (%s) ran successfully:\n", region)
-		for sweeper, sweeperErr := range regionSweeperRunList {
-			if sweeperErr == nil {
-				fmt.Printf("\t- %s\n", sweeper)
-			} else {
-				regionSweeperErrorFound = true
+		if *flagSweepReport != "" {
+			if err := writeSweepReport(*flagSweepReport, report); err != nil {
+				log.Printf("[ERROR] %s", err)
+				os.Exit(1)
 			}
 		}
 
-		if regionSweeperErrorFound {
-			sweeperErrorFound = true
-			log.Printf("Sweeper Tests for region (%s) ran unsuccessfully:\n", region)
-			for sweeper, sweeperErr := range regionSweeperRunList {
-				if sweeperErr != nil {
-					fmt.Printf("\t- %s: %s\n", sweeper, sweeperErr)
-				}
-			}
+		if err != nil {
+			os.Exit(1)
 		}
-
-		sweeperRunList[region] = regionSweeperRunList
-	}
-
-	if sweeperErrorFound {
-		return sweeperRunList, errors.New("at least one sweeper failed")
+	} else {
+		exitCode := m.Run()
+		os.Exit(exitCode)
 	}
-
-	return sweeperRunList, nil
 }
 
 // filterSweepers takes a comma separated string listing the names of sweepers
@@ -230,54 +227,6 @@ func filterSweeperWithDependencies(name string, source map[string]*Sweeper) map[
 	return result
 }
 
-// runSweeperWithRegion receives a sweeper and a region, and recursively calls
-// itself with that region for every dependency found for that sweeper. If there
-// are no dependencies, invoke the contained sweeper fun with the region, and
-// add the success/fail status to the sweeperRunList.
-func runSweeperWithRegion(region string, s *Sweeper, sweepers map[string]*Sweeper, sweeperRunList map[string]error, allowFailures bool) error {
-	for _, dep := range s.Dependencies {
-		depSweeper, ok := sweepers[dep]
-
-		if !ok {
-			log.Printf("[ERROR] Sweeper (%s) has dependency (%s), but that sweeper was not found", s.Name, dep)
-			return fmt.Errorf("sweeper (%s) has dependency (%s), but that sweeper was not found", s.Name, dep)
-		}
-
-		log.Printf("[DEBUG] Sweeper (%s) has dependency (%s), running..", s.Name, dep)
-		err := runSweeperWithRegion(region, depSweeper, sweepers, sweeperRunList, allowFailures)
-
-		if err != nil {
-			if allowFailures {
-				log.Printf("[ERROR] Error running Sweeper (%s) in region (%s): %s", depSweeper.Name, region, err)
-				continue
-			}
-
-			return err
-		}
-	}
-
-	if _, ok := sweeperRunList[s.Name]; ok {
-		log.Printf("[DEBUG] Sweeper (%s) already ran in region (%s)", s.Name, region)
-		return nil
-	}
-
-	log.Printf("[DEBUG] Running Sweeper (%s) in region (%s)", s.Name, region)
-
-	start := time.Now()
-	runE := s.F(region)
-	elapsed := time.Since(start)
-
-	log.Printf("[DEBUG] Completed Sweeper (%s) in region (%s) in %s", s.Name, region, elapsed)
-
-	sweeperRunList[s.Name] = runE
-
-	if runE != nil {
-		log.Printf("[ERROR] Error running Sweeper (%s) in region (%s): %s", s.Name, region, runE)
-	}
-
-	return runE
-}
-
 // Deprecated: Use EnvTfAcc instead.
 const TestEnvVar = EnvTfAcc
 
@@ -294,6 +243,26 @@ type ImportStateCheckFunc func([]*terraform.InstanceState) error
 // generation for ImportState tests.
 type ImportStateIdFunc func(*terraform.State) (string, error)
 
+// ImportStateKind is the method used to import a resource in ImportState
+// tests.
+type ImportStateKind byte
+
+const (
+	// ImportCommandWithID imports the resource with the terraform import
+	// command and an import identifier. This is the default.
+	ImportCommandWithID ImportStateKind = iota
+
+	// ImportBlockWithID imports the resource with an import block and an
+	// import identifier, by planning the import without applying it.
+	ImportBlockWithID
+
+	// ImportBlockWithResourceIdentity imports the resource with an import
+	// block and the identity of the resource in the prior state, by planning
+	// the import without applying it. The resource must support resource
+	// identity.
+	ImportBlockWithResourceIdentity
+)
+
 // ErrorCheckFunc is a function providers can use to handle errors.
 type ErrorCheckFunc func(error) error
 
@@ -415,6 +384,47 @@ type TestCase struct {
 	// IDRefreshIgnore is a list of configuration keys that will be ignored
 	// during ID-only refresh testing.
 	IDRefreshIgnore []string
+
+	// CheckImmutableIdentity fails Config and RefreshState mode TestSteps if
+	// the identity of a resource changes from the prior TestStep, unless the
+	// resource is created or replaced by the TestStep. Only the resources of
+	// ProviderFactories and Providers which do not set the MutableIdentity
+	// resource behavior are checked, as the behavior of other providers is
+	// not known.
+	CheckImmutableIdentity bool
+
+	// InProcessCore runs the TestSteps with a minimal Terraform core built
+	// into the testing framework, which calls the RPCs of the provider
+	// servers directly, instead of running the Terraform CLI. The Terraform
+	// CLI is neither installed nor run, and no network access is required
+	// beyond what the providers themselves need.
+	//
+	// TestStep and Check semantics are unchanged, however the configuration
+	// of every TestStep is limited to provider, resource, data, variable,
+	// locals and output blocks in a single module, with the count,
+	// provider and depends_on meta-arguments and the Terraform built-in
+	// functions which do not access the filesystem. Modules, for_each,
+	// provider aliases, lifecycle blocks and provisioners are not
+	// supported. Plans for non-empty plan errors are rendered by the
+	// testing framework rather than the Terraform CLI.
+	//
+	// Only ProviderFactories, ProtoV5ProviderFactories and Providers are
+	// supported. Setting ProtoV6ProviderFactories, ExternalProviders or
+	// IDRefreshName will raise an error.
+	InProcessCore bool
+
+	// UpgradeFrom tests upgrading the provider under test from a previous
+	// version, which is installed from a local filesystem mirror. Each
+	// Config mode TestStep is applied with the previous version, without
+	// running its Check, ConfigPlanChecks or ConfigStateChecks, and the
+	// configuration is then planned with the in-tree provider, which must
+	// upgrade the state without any differences. The upgrade plan is not
+	// applied, so the following TestSteps continue with the state of the
+	// previous version.
+	//
+	// ImportState, RefreshState, ExpectError and TestStep providers are not
+	// supported with UpgradeFrom.
+	UpgradeFrom *UpgradeFrom
 }
 
 // ExternalProvider holds information about third-party providers that should
@@ -474,8 +484,36 @@ type TestStep struct {
 	//
 	// JSON Configuration Syntax can be used and is assumed whenever Config
 	// contains valid JSON.
+	//
+	// Only one of Config, ConfigDirectory and ConfigFile can be set.
 	Config string
 
+	// ConfigDirectory is a function returning the path of a directory
+	// containing the configuration to give to Terraform, such as
+	// config.TestNameDirectory or config.TestStepDirectory, which resolve
+	// directories within testdata from the name of the test. Every file and
+	// subdirectory of the directory is copied into the working directory, so
+	// it can contain multiple .tf files and local modules.
+	//
+	// Unlike Config, the configuration is not merged with the terraform
+	// configuration block of any ExternalProviders, so it must declare their
+	// required_providers itself.
+	ConfigDirectory config.TestStepConfigFunc
+
+	// ConfigFile is a function returning the path of a single file
+	// containing the configuration to give to Terraform, such as
+	// config.TestNameFile or config.TestStepFile. As with ConfigDirectory,
+	// the configuration is not merged with the terraform configuration block
+	// of any ExternalProviders.
+	ConfigFile config.TestStepConfigFunc
+
+	// ConfigVariables are the values of the input variables of the
+	// configuration set by Config, ConfigDirectory or ConfigFile. They are
+	// written to a variable definitions file which Terraform loads
+	// automatically, and converted to the types declared by the variable
+	// blocks of the configuration.
+	ConfigVariables config.Variables
+
 	// Check is called after the Config is applied. Use this step to
 	// make your own API calls to check the status of things, and to
 	// inspect the format of the ResourceState itself.
@@ -486,6 +524,23 @@ type TestStep struct {
 	// If this is nil, no check is done on this step.
 	Check TestCheckFunc
 
+	// ConfigPlanChecks allow assertions to be made against the plans created
+	// during the Config of this TestStep, using the checks of the plancheck
+	// package or custom checks implementing the plancheck.PlanCheck
+	// interface.
+	ConfigPlanChecks ConfigPlanChecks
+
+	// ConfigStateChecks allow assertions to be made against the state after
+	// the Config of this TestStep is applied, using the checks of the
+	// statecheck package or custom checks implementing the
+	// statecheck.StateCheck interface. They are run after Check, against
+	// the state prior to the destroy for Destroy steps.
+	//
+	// Unlike TestCheckFunc functions, state checks compare typed values
+	// addressed by paths rather than flatmap strings, which allows set
+	// elements and nested values to be checked reliably.
+	ConfigStateChecks []statecheck.StateCheck
+
 	// Destroy will create a destroy plan if set to true.
 	Destroy bool
 
@@ -582,6 +637,22 @@ type TestStep struct {
 	// at the end of the test step that is verifying import behavior.
 	ImportStatePersist bool
 
+	// ImportStateKind is the method used to import the resource, which is
+	// the terraform import command by default.
+	//
+	// With ImportBlockWithID and ImportBlockWithResourceIdentity, an import
+	// block for ResourceName is added to the configuration and the import is
+	// planned, but not applied. The plan must only import the resource,
+	// unless ExpectNonEmptyPlan is set. These kinds cannot be used with
+	// ImportStatePersist, and ImportBlockWithResourceIdentity cannot be used
+	// with ImportStateId, ImportStateIdFunc, or ImportStateIdPrefix.
+	ImportStateKind ImportStateKind
+
+	// ImportStateIdentityVerify, if true, will check that the identity of
+	// the imported ResourceName resource, as returned by the provider,
+	// matches its identity in the prior state.
+	ImportStateIdentityVerify bool
+
 	//---------------------------------------------------------------
 	// RefreshState testing
 	//---------------------------------------------------------------
@@ -667,6 +738,26 @@ type TestStep struct {
 	ExternalProviders map[string]ExternalProvider
 }
 
+// ConfigPlanChecks defines the plan checks run at different points of a
+// Config TestStep. Any failing check fails the TestStep, after every check
+// of the same point has run.
+type ConfigPlanChecks struct {
+	// PreApply checks are run against the plan created before the apply,
+	// or the destroy plan for Destroy steps. They cannot be used with
+	// PlanOnly.
+	PreApply []plancheck.PlanCheck
+
+	// PostApplyPreRefresh checks are run against the plan created after the
+	// apply, before the refresh. They are run before the plan is checked to
+	// be empty, unless ExpectNonEmptyPlan is set.
+	PostApplyPreRefresh []plancheck.PlanCheck
+
+	// PostApplyPostRefresh checks are run against the plan created after the
+	// apply and the refresh. They are run before the plan is checked to be
+	// empty, unless ExpectNonEmptyPlan is set.
+	PostApplyPostRefresh []plancheck.PlanCheck
+}
+
 // ParallelTest performs an acceptance test on a resource, allowing concurrency
 // with other ParallelTest. The number of concurrent tests is controlled by the
 // "go test" command -parallel flag.
@@ -763,6 +854,16 @@ func Test(t testing.T, c TestCase) {
 		logging.HelperResourceDebug(ctx, "Called TestCase PreCheck")
 	}
 
+	if c.InProcessCore {
+		logging.HelperResourceTrace(ctx, "TestCase is InProcessCore mode")
+
+		runInProcessTest(ctx, t, c)
+
+		logging.HelperResourceDebug(ctx, "Finished TestCase")
+
+		return
+	}
+
 	sourceDir, err := os.Getwd()
 	if err != nil {
 		t.Fatalf("Error getting working dir: %s", err)
@@ -801,6 +902,18 @@ func testResource(c TestStep, state *terraform.State) (*terraform.ResourceState,
 		}
 	}
 
+	// ResourceName may also be in the format of Terraform JSON output, such
+	// as examplecloud_thing.test[0], rather than the shimmed state key.
+	address := resourceInstanceAddress(c.ResourceName)
+
+	for _, m := range state.Modules {
+		for k, v := range m.Resources {
+			if resourceInstanceAddress(k) == address {
+				return v, nil
+			}
+		}
+	}
+
 	return nil, fmt.Errorf(
 		"Resource specified by ResourceName couldn't be found: %s", c.ResourceName)
 }
@@ -8,10 +8,9 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugintest"
 )
 
-func testStepTaint(ctx context.Context, step TestStep, wd *plugintest.WorkingDir) error {
+func testStepTaint(ctx context.Context, step TestStep, wd workingDir) error {
 	if len(step.Taint) == 0 {
 		return nil
 	}
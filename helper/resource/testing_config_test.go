@@ -0,0 +1,110 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/knownvalue"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/statecheck"
+)
+
+func TestTest_TestStep_ConfigDirectory(t *testing.T) {
+	t.Parallel()
+
+	things := make(map[string]string)
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(things),
+		Steps: []TestStep{
+			{
+				ConfigDirectory: config.TestStepDirectory(),
+				ConfigVariables: config.Variables{
+					"name": config.StringVariable("one"),
+				},
+				Check: TestCheckResourceAttr("examplecloud_thing.test", "name", "one"),
+			},
+			{
+				ConfigDirectory: config.TestStepDirectory(),
+				ConfigVariables: config.Variables{
+					"thing": config.ObjectVariable(map[string]config.Variable{
+						"names": config.ListVariable(config.StringVariable("one"), config.StringVariable("two")),
+						"index": config.IntegerVariable(1),
+					}),
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("examplecloud_thing.test", cty.GetAttrPath("name"), knownvalue.StringExact("two")),
+					statecheck.ExpectKnownOutputValue("name", knownvalue.StringExact("two")),
+				},
+			},
+			// The prior directory and variables are used for the import.
+			{
+				ResourceName:      "examplecloud_thing.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestTest_TestStep_ConfigFile(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(make(map[string]string)),
+		Steps: []TestStep{
+			{
+				ConfigFile: config.TestNameFile("main.tf"),
+				Check:      TestCheckResourceAttr("examplecloud_thing.test", "name", "default"),
+			},
+			{
+				ConfigFile: config.TestNameFile("main.tf"),
+				ConfigVariables: config.Variables{
+					"name": config.StringVariable("set"),
+				},
+				Check: TestCheckResourceAttr("examplecloud_thing.test", "name", "set"),
+			},
+			{
+				Config: `
+					variable "name" {
+						type = string
+					}
+
+					resource "examplecloud_thing" "test" {
+						name = var.name
+					}
+				`,
+				ConfigVariables: config.Variables{
+					"name": config.StringVariable("inline"),
+				},
+				Check: TestCheckResourceAttr("examplecloud_thing.test", "name", "inline"),
+			},
+		},
+	})
+}
+
+func TestTest_TestStep_ConfigDirectory_Error(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(make(map[string]string)),
+		Steps: []TestStep{
+			{
+				ConfigDirectory: config.TestNameDirectory(),
+				ExpectError:     regexp.MustCompile(`unable to read configuration directory`),
+			},
+			{
+				ConfigDirectory: config.StaticDirectory("testdata/TestTest_TestStep_ConfigDirectory/1"),
+				ExpectError:     regexp.MustCompile(`input variable "name" is not set`),
+			},
+		},
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// immutableIdentityTypes returns the managed resource types of the SDK
+// providers which support resource identity without the MutableIdentity
+// resource behavior. The resource behavior of other providers is not known,
+// so the identities of their resources are not checked between TestSteps.
+func (f *providerFactories) immutableIdentityTypes() (map[string]bool, error) {
+	types := make(map[string]bool)
+
+	if f == nil {
+		return types, nil
+	}
+
+	for name, factory := range f.legacy {
+		if factory == nil {
+			continue
+		}
+
+		p, err := factory()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create provider %q: %w", name, err)
+		}
+
+		for typeName, r := range p.ResourcesMap {
+			if r.Identity != nil && !r.ResourceBehavior.MutableIdentity {
+				types[typeName] = true
+			}
+		}
+	}
+
+	return types, nil
+}
+
+// createdInstances returns the addresses of the managed resource instances
+// which are created or replaced by the given plan.
+func createdInstances(plan *tfjson.Plan) map[string]bool {
+	created := make(map[string]bool)
+
+	if plan == nil {
+		return created
+	}
+
+	for _, rc := range plan.ResourceChanges {
+		if rc.Change == nil {
+			continue
+		}
+
+		if rc.Change.Actions.Create() || rc.Change.Actions.Replace() {
+			created[rc.Address] = true
+		}
+	}
+
+	return created
+}
+
+// checkIdentityUnchanged returns an error if the identity of a managed
+// resource instance of one of the given resource types differs between the
+// prior and the new state, unless the instance is one of the given created
+// instances. Instances without an identity in either state are ignored.
+func checkIdentityUnchanged(prior, state *tfjson.State, types, created map[string]bool) error {
+	priorResources := stateManagedResources(prior)
+
+	var errs []error
+
+	for addr, r := range stateManagedResources(state) {
+		if !types[r.Type] || created[addr] || len(r.IdentityValues) == 0 {
+			continue
+		}
+
+		priorResource, ok := priorResources[addr]
+		if !ok || len(priorResource.IdentityValues) == 0 {
+			continue
+		}
+
+		expected, err := normalizeIdentity(priorResource.IdentityValues)
+		if err != nil {
+			return err
+		}
+
+		actual, err := normalizeIdentity(r.IdentityValues)
+		if err != nil {
+			return err
+		}
+
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			errs = append(errs, fmt.Errorf("%s - identity changed, but resource type %s does not have the MutableIdentity resource behavior. Difference is shown below. The - symbol indicates the prior identity.\n\n%s", addr, r.Type, diff))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// stateManagedResources returns the managed resource instances of all the
+// modules of the given state, keyed by address.
+func stateManagedResources(state *tfjson.State) map[string]*tfjson.StateResource {
+	resources := make(map[string]*tfjson.StateResource)
+
+	if state == nil || state.Values == nil {
+		return resources
+	}
+
+	var addModule func(*tfjson.StateModule)
+
+	addModule = func(module *tfjson.StateModule) {
+		if module == nil {
+			return
+		}
+
+		for _, r := range module.Resources {
+			if r.Mode == tfjson.ManagedResourceMode {
+				resources[r.Address] = r
+			}
+		}
+
+		for _, child := range module.ChildModules {
+			addModule(child)
+		}
+	}
+
+	addModule(state.Values.RootModule)
+
+	return resources
+}
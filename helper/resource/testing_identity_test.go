@@ -0,0 +1,245 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/knownvalue"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/statecheck"
+)
+
+func TestProviderFactoriesImmutableIdentityTypes(t *testing.T) {
+	t.Parallel()
+
+	identity := &schema.ResourceIdentity{
+		SchemaFunc: func() map[string]*schema.Schema {
+			return map[string]*schema.Schema{
+				"id": {
+					Type:              schema.TypeString,
+					RequiredForImport: true,
+				},
+			}
+		},
+	}
+
+	factories := &providerFactories{
+		legacy: map[string]func() (*schema.Provider, error){
+			"examplecloud": func() (*schema.Provider, error) { //nolint:unparam // required signature
+				return &schema.Provider{
+					ResourcesMap: map[string]*schema.Resource{
+						"examplecloud_immutable": {
+							Identity: identity,
+						},
+						"examplecloud_mutable": {
+							Identity: identity,
+							ResourceBehavior: schema.ResourceBehavior{
+								MutableIdentity: true,
+							},
+						},
+						"examplecloud_none": {},
+					},
+				}, nil
+			},
+		},
+	}
+
+	got, err := factories.immutableIdentityTypes()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff(map[string]bool{"examplecloud_immutable": true}, got); diff != "" {
+		t.Errorf("unexpected difference: %s", diff)
+	}
+}
+
+func TestCreatedInstances(t *testing.T) {
+	t.Parallel()
+
+	plan := &tfjson.Plan{
+		ResourceChanges: []*tfjson.ResourceChange{
+			{
+				Address: "examplecloud_thing.created",
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionCreate}},
+			},
+			{
+				Address: "examplecloud_thing.replaced",
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}},
+			},
+			{
+				Address: "examplecloud_thing.updated",
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionUpdate}},
+			},
+		},
+	}
+
+	expected := map[string]bool{
+		"examplecloud_thing.created":  true,
+		"examplecloud_thing.replaced": true,
+	}
+
+	if diff := cmp.Diff(expected, createdInstances(plan)); diff != "" {
+		t.Errorf("unexpected difference: %s", diff)
+	}
+}
+
+func TestCheckIdentityUnchanged(t *testing.T) {
+	t.Parallel()
+
+	testState := func(identities map[string]map[string]interface{}) *tfjson.State {
+		state := &tfjson.State{
+			Values: &tfjson.StateValues{
+				RootModule: &tfjson.StateModule{},
+			},
+		}
+
+		for addr, identity := range identities {
+			typeName, _, _ := strings.Cut(addr, ".")
+
+			state.Values.RootModule.Resources = append(state.Values.RootModule.Resources, &tfjson.StateResource{
+				Address:        addr,
+				Mode:           tfjson.ManagedResourceMode,
+				Type:           typeName,
+				IdentityValues: identity,
+			})
+		}
+
+		return state
+	}
+
+	prior := testState(map[string]map[string]interface{}{
+		"examplecloud_thing.test":      {"id": "one", "number": json.Number("1")},
+		"examplecloud_thing.created":   {"id": "one"},
+		"examplecloud_thing.new":       nil,
+		"examplecloud_mutable.test":    {"id": "one"},
+		"examplecloud_thing.unchanged": {"id": "one", "number": json.Number("1")},
+	})
+
+	testCases := map[string]struct {
+		state         *tfjson.State
+		expectedError string
+	}{
+		"unchanged": {
+			state: testState(map[string]map[string]interface{}{
+				"examplecloud_thing.test":      {"id": "one", "number": float64(1)},
+				"examplecloud_thing.created":   {"id": "two"},
+				"examplecloud_thing.new":       {"id": "two"},
+				"examplecloud_mutable.test":    {"id": "two"},
+				"examplecloud_thing.unchanged": {"id": "one", "number": json.Number("1")},
+				"examplecloud_thing.other":     {"id": "two"},
+			}),
+		},
+		"changed": {
+			state: testState(map[string]map[string]interface{}{
+				"examplecloud_thing.test": {"id": "two", "number": json.Number("1")},
+			}),
+			expectedError: "examplecloud_thing.test - identity changed, but resource type examplecloud_thing does not have the MutableIdentity resource behavior",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkIdentityUnchanged(prior, testCase.state, map[string]bool{"examplecloud_thing": true}, map[string]bool{"examplecloud_thing.created": true})
+
+			if testCase.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+				t.Fatalf("expected error containing %q, got: %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestTest_InProcessCore_IdentityReplace(t *testing.T) {
+	t.Parallel()
+
+	var nextID int
+
+	UnitTest(t, TestCase{
+		InProcessCore:          true,
+		CheckImmutableIdentity: true,
+		ProviderFactories: map[string]func() (*schema.Provider, error){
+			"examplecloud": func() (*schema.Provider, error) { //nolint:unparam // required signature
+				return &schema.Provider{
+					ResourcesMap: map[string]*schema.Resource{
+						"examplecloud_thing": {
+							CreateContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+								nextID++
+								d.SetId(fmt.Sprintf("%s-%d", d.Get("name"), nextID))
+
+								identity, err := d.Identity()
+								if err != nil {
+									return diag.FromErr(err)
+								}
+
+								return diag.FromErr(identity.Set("id", d.Id()))
+							},
+							DeleteContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+								return nil
+							},
+							ReadContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+								return nil
+							},
+							Schema: map[string]*schema.Schema{
+								"name": {
+									Required: true,
+									ForceNew: true,
+									Type:     schema.TypeString,
+								},
+							},
+							Identity: &schema.ResourceIdentity{
+								SchemaFunc: func() map[string]*schema.Schema {
+									return map[string]*schema.Schema{
+										"id": {
+											Type:              schema.TypeString,
+											RequiredForImport: true,
+										},
+									}
+								},
+							},
+						},
+					},
+				}, nil
+			},
+		},
+		Steps: []TestStep{
+			{
+				Config: `resource "examplecloud_thing" "test" { name = "one" }`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("examplecloud_thing.test", map[string]knownvalue.Check{
+						"id": knownvalue.StringExact("one-1"),
+					}),
+				},
+			},
+			{
+				Config: `resource "examplecloud_thing" "test" { name = "two" }`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentityValue("examplecloud_thing.test", cty.GetAttrPath("id"), knownvalue.StringExact("two-2")),
+				},
+			},
+			{
+				RefreshState: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,427 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/mitchellh/go-testing-interface"
+	zctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/inprocess"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/planrender"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugintest"
+)
+
+// runInProcessTest runs the TestSteps of the given TestCase with the
+// in-process Terraform core instead of the Terraform CLI.
+func runInProcessTest(ctx context.Context, t testing.T, c TestCase) {
+	t.Helper()
+
+	runTestSteps(ctx, t, c, requireNewInProcessWorkingDir(ctx, t), func(ctx context.Context) workingDir {
+		return requireNewInProcessWorkingDir(ctx, t)
+	})
+}
+
+// inProcessProviderServers returns the protocol version 5 provider servers
+// created by the given factories, keyed by provider name, and a function
+// which stops the servers of SDK providers.
+func inProcessProviderServers(ctx context.Context, factories *providerFactories) (map[string]tfprotov5.ProviderServer, func(), error) {
+	servers := make(map[string]tfprotov5.ProviderServer, len(factories.legacy)+len(factories.protov5))
+
+	// schema.Provider have a global stop context that is created outside
+	// the server context and have their own associated goroutine, which
+	// are ended by the StopProvider RPC.
+	var legacyProviderServers []*schema.GRPCProviderServer
+
+	stop := func() {
+		for _, server := range legacyProviderServers {
+			server.StopProvider(ctx, nil) //nolint:errcheck // does not return errors
+		}
+	}
+
+	for providerName, factory := range factories.legacy {
+		// providerName may be returned as terraform-provider-foo, and
+		// we need just foo. So let's fix that.
+		providerName = strings.TrimPrefix(providerName, "terraform-provider-")
+
+		logging.HelperResourceDebug(ctx, "Creating sdkv2 provider instance", map[string]interface{}{logging.KeyProviderAddress: getProviderAddr(providerName)})
+
+		provider, err := factory()
+		if err != nil {
+			stop()
+
+			return nil, nil, fmt.Errorf("unable to create provider %q from factory: %w", providerName, err)
+		}
+
+		server := schema.NewGRPCProviderServer(provider)
+		legacyProviderServers = append(legacyProviderServers, server)
+		servers[providerName] = server
+	}
+
+	for providerName, factory := range factories.protov5 {
+		// providerName may be returned as terraform-provider-foo, and
+		// we need just foo. So let's fix that.
+		providerName = strings.TrimPrefix(providerName, "terraform-provider-")
+
+		if _, ok := servers[providerName]; ok {
+			stop()
+
+			return nil, nil, fmt.Errorf("Provider %s registered in both TestCase.ProviderFactories and TestCase.ProtoV5ProviderFactories: please use one or the other, or supply a muxed provider to TestCase.ProtoV5ProviderFactories.", providerName)
+		}
+
+		logging.HelperResourceDebug(ctx, "Creating tfprotov5 provider instance", map[string]interface{}{logging.KeyProviderAddress: getProviderAddr(providerName)})
+
+		provider, err := factory()
+		if err != nil {
+			stop()
+
+			return nil, nil, fmt.Errorf("unable to create provider %q from factory: %w", providerName, err)
+		}
+
+		servers[providerName] = provider
+	}
+
+	return servers, stop, nil
+}
+
+// inProcessWorkingDir is a workingDir which runs the in-process Terraform
+// core instead of the Terraform CLI. The saved plan is kept in memory and
+// the providers of the core are replaced for every provider command.
+type inProcessWorkingDir struct {
+	core *inprocess.Core
+
+	// importBlock is the import block of the configuration, if any, which
+	// the core does not support, so its import is emulated when planning.
+	importBlock *inProcessImportBlock
+
+	// plan is the saved plan, if any, which is a destroy plan when
+	// destroyPlan is true.
+	plan        *tfjson.Plan
+	destroyPlan bool
+}
+
+// inProcessImportBlock is the import block of a WorkingDirConfig.
+type inProcessImportBlock struct {
+	to       string
+	id       string
+	identity map[string]interface{}
+}
+
+// requireNewInProcessWorkingDir returns a new in-process working directory
+// with an empty configuration and state, failing the test if it cannot be
+// created.
+func requireNewInProcessWorkingDir(ctx context.Context, t testing.T) *inProcessWorkingDir {
+	t.Helper()
+
+	core, err := inprocess.NewCore(ctx, nil)
+	if err != nil {
+		logging.HelperResourceError(ctx,
+			"TestCase error creating in-process core",
+			map[string]interface{}{logging.KeyError: err},
+		)
+		t.Fatalf("TestCase error creating in-process core: %s", err)
+	}
+
+	return &inProcessWorkingDir{
+		core: core,
+	}
+}
+
+// runProviderCommand runs f with new provider servers created by the given
+// factories, as the Terraform CLI starts new provider instances for every
+// command.
+func (wd *inProcessWorkingDir) runProviderCommand(ctx context.Context, f func() error, factories *providerFactories) error {
+	servers, stop, err := inProcessProviderServers(ctx, factories)
+	if err != nil {
+		return err
+	}
+
+	defer stop()
+
+	if err := wd.core.SetProviders(ctx, servers); err != nil {
+		return err
+	}
+
+	return f()
+}
+
+// Close does nothing, as the in-process core keeps no files.
+func (wd *inProcessWorkingDir) Close() error {
+	return nil
+}
+
+// SetConfig sets the configuration and variables of the core and clears the
+// saved plan. Only the files at the top level of configuration directories
+// are used, as local modules are not supported by the in-process core.
+func (wd *inProcessWorkingDir) SetConfig(ctx context.Context, cfg plugintest.WorkingDirConfig) error {
+	logging.HelperResourceTrace(ctx, "Setting in-process configuration", map[string]any{
+		logging.KeyTestTerraformConfiguration:          cfg.Raw,
+		logging.KeyTestTerraformConfigurationDirectory: cfg.Directory,
+		logging.KeyTestTerraformConfigurationFile:      cfg.File,
+	})
+
+	files, err := cfg.Files()
+	if err != nil {
+		return err
+	}
+
+	for name := range files {
+		if filepath.Dir(name) != "." {
+			delete(files, name)
+		}
+	}
+
+	importBlock, err := parseInProcessImportBlock(cfg.ImportBlock)
+	if err != nil {
+		return err
+	}
+
+	if err := wd.core.SetConfigFiles(files); err != nil {
+		return err
+	}
+
+	if err := wd.core.SetVariables(cfg.Variables); err != nil {
+		return err
+	}
+
+	wd.importBlock = importBlock
+	wd.plan = nil
+
+	return nil
+}
+
+// Init does nothing, as the in-process core uses the provider servers of the
+// TestCase and does not support modules.
+func (wd *inProcessWorkingDir) Init(_ context.Context) error {
+	return nil
+}
+
+// CreatePlan saves a plan of the changes of the configuration. When the
+// configuration has an import block, the resource is imported into a copy of
+// the state before planning, and its change is marked as importing.
+func (wd *inProcessWorkingDir) CreatePlan(ctx context.Context) error {
+	wd.plan = nil
+
+	core := wd.core
+
+	if wd.importBlock != nil {
+		core = wd.core.Copy()
+
+		var err error
+
+		if wd.importBlock.identity != nil {
+			err = core.ImportIdentity(ctx, wd.importBlock.to, wd.importBlock.identity)
+		} else {
+			err = core.Import(ctx, wd.importBlock.to, wd.importBlock.id)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	plan, err := core.Plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	if wd.importBlock != nil {
+		if err := wd.importBlock.markImporting(core, plan); err != nil {
+			return err
+		}
+	}
+
+	wd.plan = plan
+	wd.destroyPlan = false
+
+	return nil
+}
+
+// CreateDestroyPlan saves a plan destroying every managed resource.
+func (wd *inProcessWorkingDir) CreateDestroyPlan(ctx context.Context) error {
+	wd.plan = nil
+
+	plan, err := wd.core.DestroyPlan(ctx)
+	if err != nil {
+		return err
+	}
+
+	wd.plan = plan
+	wd.destroyPlan = true
+
+	return nil
+}
+
+// Apply applies the configuration, or destroys every managed resource when
+// the saved plan is a destroy plan. The in-process core plans again when
+// applying, so the changes are those of the saved plan as long as the remote
+// objects did not change in the meantime.
+func (wd *inProcessWorkingDir) Apply(ctx context.Context) error {
+	if wd.plan != nil && wd.destroyPlan {
+		return wd.core.Destroy(ctx)
+	}
+
+	return wd.core.Apply(ctx)
+}
+
+// Destroy destroys every managed resource. It does not consider or modify
+// any saved plan.
+func (wd *inProcessWorkingDir) Destroy(ctx context.Context) error {
+	return wd.core.Destroy(ctx)
+}
+
+// SavedPlan returns the saved plan, or an error if there is none.
+func (wd *inProcessWorkingDir) SavedPlan(_ context.Context) (*tfjson.Plan, error) {
+	if wd.plan == nil {
+		return nil, fmt.Errorf("there is no current saved plan")
+	}
+
+	return wd.plan, nil
+}
+
+// SavedPlanRawStdout returns the saved plan rendered by the testing
+// framework, or an error if there is none.
+func (wd *inProcessWorkingDir) SavedPlanRawStdout(ctx context.Context) (string, error) {
+	plan, err := wd.SavedPlan(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return planrender.Plan(plan), nil
+}
+
+// State returns the current state.
+func (wd *inProcessWorkingDir) State(_ context.Context) (*tfjson.State, error) {
+	return wd.core.State()
+}
+
+// Import imports the remote object with the given import identifier into the
+// state as the given resource instance address.
+func (wd *inProcessWorkingDir) Import(ctx context.Context, resource, id string) error {
+	return wd.core.Import(ctx, resource, id)
+}
+
+// Taint marks the given managed resource instance as tainted.
+func (wd *inProcessWorkingDir) Taint(_ context.Context, address string) error {
+	return wd.core.Taint(address)
+}
+
+// Refresh updates the state with the remote objects.
+func (wd *inProcessWorkingDir) Refresh(ctx context.Context) error {
+	return wd.core.Refresh(ctx)
+}
+
+// parseInProcessImportBlock parses the given ImportBlock of a
+// WorkingDirConfig, as written by importBlockConfig, returning nil if it is
+// empty.
+func parseInProcessImportBlock(src string) (*inProcessImportBlock, error) {
+	if src == "" {
+		return nil, nil
+	}
+
+	file, diags := hclsyntax.ParseConfig([]byte(src), plugintest.ImportBlockFileName, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	content, diags := file.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "import"},
+		},
+	})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if len(content.Blocks) != 1 {
+		return nil, fmt.Errorf("expected one import block, got %d", len(content.Blocks))
+	}
+
+	attrs, diags := content.Blocks[0].Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	to, ok := attrs["to"]
+	if !ok {
+		return nil, fmt.Errorf("import block has no to argument")
+	}
+
+	block := &inProcessImportBlock{
+		to: string(to.Expr.Range().SliceBytes(file.Bytes)),
+	}
+
+	if attr, ok := attrs["id"]; ok {
+		val, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		block.id = val.AsString()
+	}
+
+	if attr, ok := attrs["identity"]; ok {
+		val, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		src, err := zctyjson.Marshal(val, val.Type())
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(src, &block.identity); err != nil {
+			return nil, err
+		}
+	}
+
+	return block, nil
+}
+
+// markImporting marks the change of the imported resource in the given plan
+// of the given core as importing, with the identity of the resource in its
+// state, as Terraform does when planning an import block.
+func (b *inProcessImportBlock) markImporting(core *inprocess.Core, plan *tfjson.Plan) error {
+	state, err := core.State()
+	if err != nil {
+		return err
+	}
+
+	identity, err := stateResourceIdentity(state, b.to)
+	if err != nil {
+		return err
+	}
+
+	address := resourceInstanceAddress(b.to)
+
+	for _, rc := range plan.ResourceChanges {
+		if resourceInstanceAddress(rc.Address) != address || rc.Change == nil {
+			continue
+		}
+
+		rc.Change.Importing = &tfjson.Importing{
+			ID: b.id,
+		}
+
+		if b.identity != nil {
+			rc.Change.Importing.Identity = b.identity
+		}
+
+		rc.Change.AfterIdentity = identity
+	}
+
+	return nil
+}
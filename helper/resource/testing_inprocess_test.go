@@ -0,0 +1,434 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// testExamplecloudProviderFactories returns the factories of a provider
+// whose examplecloud_thing objects are stored in the given map of names,
+// keyed by ID. Only the name of a thing and its size, which is assigned on
+// creation, are read, so that its other attributes keep the values of its
+// configuration.
+func testExamplecloudProviderFactories(things map[string]string) map[string]func() (*schema.Provider, error) {
+	// The sizes of the things, keyed by ID, are shared by every provider
+	// instance, as are the things.
+	sizes := make(map[string]int)
+
+	return map[string]func() (*schema.Provider, error){
+		"examplecloud": func() (*schema.Provider, error) { //nolint:unparam // required signature
+			read := func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+				name, ok := things[d.Id()]
+				if !ok {
+					d.SetId("")
+
+					return nil
+				}
+
+				identity, err := d.Identity()
+				if err != nil {
+					return diag.FromErr(err)
+				}
+
+				if err := identity.Set("id", d.Id()); err != nil {
+					return diag.FromErr(err)
+				}
+
+				if err := d.Set("size", sizes[d.Id()]); err != nil {
+					return diag.FromErr(err)
+				}
+
+				return diag.FromErr(d.Set("name", name))
+			}
+
+			return &schema.Provider{
+				DataSourcesMap: map[string]*schema.Resource{
+					"examplecloud_thing": {
+						ReadContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+							id := d.Get("id").(string)
+
+							name, ok := things[id]
+							if !ok {
+								return diag.Errorf("thing %s not found", id)
+							}
+
+							d.SetId(id)
+
+							return diag.FromErr(d.Set("name", name))
+						},
+						Schema: map[string]*schema.Schema{
+							"id": {
+								Required: true,
+								Type:     schema.TypeString,
+							},
+							"name": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+						},
+					},
+				},
+				ResourcesMap: map[string]*schema.Resource{
+					"examplecloud_thing": {
+						CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+							d.SetId(fmt.Sprintf("thing-%d", len(things)+1))
+							things[d.Id()] = d.Get("name").(string)
+							sizes[d.Id()] = 3
+
+							return read(ctx, d, meta)
+						},
+						DeleteContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+							delete(things, d.Id())
+							delete(sizes, d.Id())
+
+							return nil
+						},
+						ReadContext: read,
+						UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+							things[d.Id()] = d.Get("name").(string)
+
+							return read(ctx, d, meta)
+						},
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Optional: true,
+								Type:     schema.TypeString,
+							},
+							"secret": {
+								Optional:  true,
+								Sensitive: true,
+								Type:      schema.TypeString,
+							},
+							"size": {
+								Computed: true,
+								Type:     schema.TypeInt,
+							},
+							"zone": {
+								ForceNew: true,
+								Optional: true,
+								Type:     schema.TypeString,
+							},
+							"rule": {
+								ForceNew: true,
+								Optional: true,
+								Type:     schema.TypeSet,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"port": {
+											Required: true,
+											Type:     schema.TypeInt,
+										},
+										"protocol": {
+											Optional: true,
+											Type:     schema.TypeString,
+										},
+									},
+								},
+							},
+							"tags": {
+								ForceNew: true,
+								Optional: true,
+								Type:     schema.TypeMap,
+								Elem: &schema.Schema{
+									Type: schema.TypeString,
+								},
+							},
+						},
+						Importer: &schema.ResourceImporter{
+							StateContext: schema.ImportStatePassthroughWithIdentity("id"),
+						},
+						Identity: &schema.ResourceIdentity{
+							SchemaFunc: func() map[string]*schema.Schema {
+								return map[string]*schema.Schema{
+									"id": {
+										Type:              schema.TypeString,
+										RequiredForImport: true,
+									},
+								}
+							},
+						},
+					},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestTest_InProcessCore(t *testing.T) {
+	t.Parallel()
+
+	things := make(map[string]string)
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(things),
+		CheckDestroy: func(s *terraform.State) error {
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type != "examplecloud_thing" || rs.Primary == nil {
+					continue
+				}
+
+				if _, ok := things[rs.Primary.ID]; ok {
+					return fmt.Errorf("thing %s still exists", rs.Primary.ID)
+				}
+			}
+
+			return nil
+		},
+		Steps: []TestStep{
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name = "one"
+					}
+
+					data "examplecloud_thing" "test" {
+						id = examplecloud_thing.test.id
+					}
+
+					output "name" {
+						value = data.examplecloud_thing.test.name
+					}
+				`,
+				Check: ComposeAggregateTestCheckFunc(
+					TestCheckResourceAttr("examplecloud_thing.test", "name", "one"),
+					TestCheckResourceAttrPair("examplecloud_thing.test", "id", "data.examplecloud_thing.test", "id"),
+					TestCheckOutput("name", "one"),
+				),
+			},
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name = "two"
+					}
+				`,
+				Check: ComposeAggregateTestCheckFunc(
+					TestCheckResourceAttr("examplecloud_thing.test", "id", "thing-1"),
+					TestCheckResourceAttr("examplecloud_thing.test", "name", "two"),
+					func(s *terraform.State) error {
+						if _, ok := s.RootModule().Resources["data.examplecloud_thing.test"]; ok {
+							return fmt.Errorf("expected data.examplecloud_thing.test to be removed from state")
+						}
+
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "examplecloud_thing.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				PreConfig: func() {
+					things["thing-1"] = "changed"
+				},
+				RefreshState: true,
+				Check:        TestCheckResourceAttr("examplecloud_thing.test", "name", "changed"),
+				// The configuration still sets the name to two.
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+
+	if len(things) != 0 {
+		t.Fatalf("expected no things after the test, got: %v", things)
+	}
+}
+
+func TestTest_InProcessCore_ExpectError(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(make(map[string]string)),
+		Steps: []TestStep{
+			{
+				Config: `
+					data "examplecloud_thing" "test" {
+						id = "missing"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`thing missing not found`),
+			},
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						for_each = toset(["one"])
+						name     = each.key
+					}
+				`,
+				ExpectError: regexp.MustCompile(`for_each meta-arguments are not supported`),
+			},
+		},
+	})
+}
+
+func TestTest_InProcessCore_NonEmptyPlan(t *testing.T) {
+	t.Parallel()
+
+	things := make(map[string]string)
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(things),
+		Steps: []TestStep{
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name = "one"
+					}
+				`,
+				Check: func(*terraform.State) error {
+					things["thing-1"] = "changed"
+
+					return nil
+				},
+				ExpectError: regexp.MustCompile(`(?s)the plan was not empty.*# examplecloud_thing.test will be updated in-place.*name: "changed" -> "one"`),
+			},
+		},
+	})
+}
+
+func TestTest_InProcessCore_ImportStateKind(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(make(map[string]string)),
+		Steps: []TestStep{
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name = "one"
+					}
+				`,
+			},
+			{
+				ResourceName:              "examplecloud_thing.test",
+				ImportState:               true,
+				ImportStateIdentityVerify: true,
+				ImportStateVerify:         true,
+			},
+			{
+				ResourceName:              "examplecloud_thing.test",
+				ImportState:               true,
+				ImportStateKind:           ImportBlockWithID,
+				ImportStateIdentityVerify: true,
+				ImportStateVerify:         true,
+			},
+			{
+				ResourceName:              "examplecloud_thing.test",
+				ImportState:               true,
+				ImportStateKind:           ImportBlockWithResourceIdentity,
+				ImportStateIdentityVerify: true,
+				ImportStateVerify:         true,
+			},
+		},
+	})
+}
+
+func TestTest_InProcessCore_ImportState_Count(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(make(map[string]string)),
+		Steps: []TestStep{
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						count = 2
+
+						name = "thing-${count.index}"
+					}
+				`,
+			},
+			{
+				ResourceName:              "examplecloud_thing.test.0",
+				ImportState:               true,
+				ImportStateIdentityVerify: true,
+				ImportStateVerify:         true,
+			},
+			{
+				ResourceName:              "examplecloud_thing.test[1]",
+				ImportState:               true,
+				ImportStateIdentityVerify: true,
+				ImportStateVerify:         true,
+			},
+			{
+				ResourceName:      "examplecloud_thing.test.1",
+				ImportState:       true,
+				ImportStateKind:   ImportBlockWithID,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:    "examplecloud_thing.test[0]",
+				ImportState:     true,
+				ImportStateKind: ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
+func TestTest_InProcessCore_ImportStateKind_Errors(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(make(map[string]string)),
+		Steps: []TestStep{
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name = "one"
+					}
+
+					resource "examplecloud_thing" "other" {
+						name = "two"
+					}
+				`,
+			},
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name = "changed"
+					}
+				`,
+				ResourceName:    "examplecloud_thing.test",
+				ImportState:     true,
+				ImportStateKind: ImportBlockWithResourceIdentity,
+				ExpectError:     regexp.MustCompile(`expected a no-op import operation, got \["update"\] action`),
+			},
+			{
+				ResourceName:              "examplecloud_thing.test",
+				ImportState:               true,
+				ImportStateIdFunc:         testStateResourceIdFunc("examplecloud_thing.other"),
+				ImportStateIdentityVerify: true,
+				ExpectError:               regexp.MustCompile(`(?s)ImportStateIdentityVerify identity not equivalent.*"id": string`),
+			},
+		},
+	})
+}
+
+// testStateResourceIdFunc returns an ImportStateIdFunc which returns the ID
+// of the given resource in the state.
+func testStateResourceIdFunc(address string) ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[address]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", address)
+		}
+
+		return rs.Primary.ID, nil
+	}
+}
@@ -13,12 +13,34 @@ import (
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/mitchellh/go-testing-interface"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugintest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
-func runPostTestDestroy(ctx context.Context, t testing.T, c TestCase, wd *plugintest.WorkingDir, providers *providerFactories, statePreDestroy *terraform.State) error {
+// workingDir is the Terraform working directory in which the TestSteps of a
+// TestCase are run. It is implemented by *plugintest.WorkingDir, which runs
+// the Terraform CLI, and by *inProcessWorkingDir, which runs the in-process
+// Terraform core. Operations calling providers must be run with
+// runProviderCommand.
+type workingDir interface {
+	Close() error
+	SetConfig(ctx context.Context, cfg plugintest.WorkingDirConfig) error
+	Init(ctx context.Context) error
+	CreatePlan(ctx context.Context) error
+	CreateDestroyPlan(ctx context.Context) error
+	Apply(ctx context.Context) error
+	Destroy(ctx context.Context) error
+	SavedPlan(ctx context.Context) (*tfjson.Plan, error)
+	SavedPlanRawStdout(ctx context.Context) (string, error)
+	State(ctx context.Context) (*tfjson.State, error)
+	Import(ctx context.Context, resource, id string) error
+	Taint(ctx context.Context, address string) error
+	Refresh(ctx context.Context) error
+}
+
+func runPostTestDestroy(ctx context.Context, t testing.T, c TestCase, wd workingDir, providers *providerFactories, statePreDestroy *terraform.State) error {
 	t.Helper()
 
 	err := runProviderCommand(ctx, t, func() error {
@@ -50,6 +72,18 @@ func runNewTest(ctx context.Context, t testing.T, c TestCase, helper *plugintest
 	ctx = logging.TestTerraformPathContext(ctx, wd.GetHelper().TerraformExecPath())
 	ctx = logging.TestWorkingDirectoryContext(ctx, wd.GetHelper().WorkingDirectory())
 
+	runTestSteps(ctx, t, c, wd, func(ctx context.Context) workingDir {
+		return helper.RequireNewWorkingDir(ctx, t)
+	})
+}
+
+// runTestSteps runs the TestSteps of the given TestCase in the given working
+// directory, then destroys any remaining resources. The newWorkingDir
+// function returns an additional working directory of the same kind, which
+// ImportState TestSteps use when the imported state is not persisted.
+func runTestSteps(ctx context.Context, t testing.T, c TestCase, wd workingDir, newWorkingDir func(context.Context) workingDir) {
+	t.Helper()
+
 	providers := &providerFactories{
 		legacy:  c.ProviderFactories,
 		protov5: c.ProtoV5ProviderFactories,
@@ -90,7 +124,7 @@ func runNewTest(ctx context.Context, t testing.T, c TestCase, helper *plugintest
 	}()
 
 	if c.hasProviders(ctx) {
-		err := wd.SetConfig(ctx, c.providerConfig(ctx, false))
+		err := wd.SetConfig(ctx, plugintest.WorkingDirConfig{Raw: c.providerConfig(ctx, false)})
 
 		if err != nil {
 			logging.HelperResourceError(ctx,
@@ -117,7 +151,7 @@ func runNewTest(ctx context.Context, t testing.T, c TestCase, helper *plugintest
 
 	// use this to track last step successfully applied
 	// acts as default for import tests
-	var appliedCfg string
+	var appliedCfg plugintest.WorkingDirConfig
 
 	for stepIndex, step := range c.Steps {
 		stepNumber := stepIndex + 1 // 1-based indexing for humans
@@ -152,7 +186,25 @@ func runNewTest(ctx context.Context, t testing.T, c TestCase, helper *plugintest
 			}
 		}
 
-		if step.Config != "" && !step.Destroy && len(step.Taint) > 0 {
+		var stepCfg plugintest.WorkingDirConfig
+
+		if step.hasConfig() {
+			var err error
+
+			stepCfg, err = step.config(ctx, c, config.TestStepConfigRequest{
+				StepNumber: stepNumber,
+				TestName:   t.Name(),
+			})
+			if err != nil {
+				logging.HelperResourceError(ctx,
+					"TestStep error resolving configuration",
+					map[string]interface{}{logging.KeyError: err},
+				)
+				t.Fatalf("TestStep %d/%d error resolving configuration: %s", stepNumber, len(c.Steps), err)
+			}
+		}
+
+		if step.hasConfig() && !step.Destroy && len(step.Taint) > 0 {
 			err := testStepTaint(ctx, step, wd)
 
 			if err != nil {
@@ -173,7 +225,7 @@ func runNewTest(ctx context.Context, t testing.T, c TestCase, helper *plugintest
 
 			providerCfg := step.providerConfig(ctx, step.configHasProviderBlock(ctx))
 
-			err := wd.SetConfig(ctx, providerCfg)
+			err := wd.SetConfig(ctx, plugintest.WorkingDirConfig{Raw: providerCfg})
 
 			if err != nil {
 				logging.HelperResourceError(ctx,
@@ -206,7 +258,13 @@ func runNewTest(ctx context.Context, t testing.T, c TestCase, helper *plugintest
 		if step.ImportState {
 			logging.HelperResourceTrace(ctx, "TestStep is ImportState mode")
 
-			err := testStepNewImportState(ctx, t, helper, wd, step, appliedCfg, providers)
+			importCfg := appliedCfg
+
+			if step.hasConfig() {
+				importCfg = stepCfg
+			}
+
+			err := testStepNewImportState(ctx, t, newWorkingDir, wd, step, importCfg, providers)
 			if step.ExpectError != nil {
 				logging.HelperResourceDebug(ctx, "Checking TestStep ExpectError")
 				if err == nil {
@@ -245,7 +303,7 @@ func runNewTest(ctx context.Context, t testing.T, c TestCase, helper *plugintest
 		if step.RefreshState {
 			logging.HelperResourceTrace(ctx, "TestStep is RefreshState mode")
 
-			err := testStepNewRefreshState(ctx, t, wd, step, providers)
+			err := testStepNewRefreshState(ctx, t, c, wd, step, providers)
 			if step.ExpectError != nil {
 				logging.HelperResourceDebug(ctx, "Checking TestStep ExpectError")
 				if err == nil {
@@ -281,10 +339,19 @@ func runNewTest(ctx context.Context, t testing.T, c TestCase, helper *plugintest
 			continue
 		}
 
-		if step.Config != "" {
+		if step.hasConfig() {
 			logging.HelperResourceTrace(ctx, "TestStep is Config mode")
 
-			err := testStepNewConfig(ctx, t, c, wd, step, providers)
+			var err error
+			if c.UpgradeFrom != nil {
+				logging.HelperResourceTrace(ctx, "Using TestCase UpgradeFrom")
+
+				// UpgradeFrom is not supported with InProcessCore, so
+				// this is always a Terraform CLI working directory.
+				err = testStepNewUpgrade(ctx, t, c, wd.(*plugintest.WorkingDir), step, stepCfg, providers)
+			} else {
+				err = testStepNewConfig(ctx, t, c, wd, step, stepCfg, providers)
+			}
 			if step.ExpectError != nil {
 				logging.HelperResourceDebug(ctx, "Checking TestStep ExpectError")
 
@@ -318,7 +385,7 @@ func runNewTest(ctx context.Context, t testing.T, c TestCase, helper *plugintest
 				}
 			}
 
-			appliedCfg = step.mergedConfig(ctx, c)
+			appliedCfg = stepCfg
 
 			logging.HelperResourceDebug(ctx, "Finished TestStep")
 
@@ -329,7 +396,7 @@ func runNewTest(ctx context.Context, t testing.T, c TestCase, helper *plugintest
 	}
 }
 
-func getState(ctx context.Context, t testing.T, wd *plugintest.WorkingDir) (*terraform.State, error) {
+func getState(ctx context.Context, t testing.T, wd workingDir) (*terraform.State, error) {
 	t.Helper()
 
 	jsonState, err := wd.State(ctx)
@@ -358,7 +425,7 @@ func planIsEmpty(plan *tfjson.Plan) bool {
 	return true
 }
 
-func testIDRefresh(ctx context.Context, t testing.T, c TestCase, wd *plugintest.WorkingDir, step TestStep, r *terraform.ResourceState, providers *providerFactories) error {
+func testIDRefresh(ctx context.Context, t testing.T, c TestCase, wd workingDir, step TestStep, cfg plugintest.WorkingDirConfig, r *terraform.ResourceState, providers *providerFactories) error {
 	t.Helper()
 
 	// Build the state. The state is just the resource with an ID. There
@@ -369,12 +436,12 @@ func testIDRefresh(ctx context.Context, t testing.T, c TestCase, wd *plugintest.
 
 	// Temporarily set the config to a minimal provider config for the refresh
 	// test. After the refresh we can reset it.
-	err := wd.SetConfig(ctx, c.providerConfig(ctx, step.configHasProviderBlock(ctx)))
+	err := wd.SetConfig(ctx, plugintest.WorkingDirConfig{Raw: c.providerConfig(ctx, step.configHasProviderBlock(ctx))})
 	if err != nil {
 		t.Fatalf("Error setting import test config: %s", err)
 	}
 	defer func() {
-		err = wd.SetConfig(ctx, step.Config)
+		err = wd.SetConfig(ctx, cfg)
 		if err != nil {
 			t.Fatalf("Error resetting test config: %s", err)
 		}
@@ -16,14 +16,39 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
-func testStepNewConfig(ctx context.Context, t testing.T, c TestCase, wd *plugintest.WorkingDir, step TestStep, providers *providerFactories) error {
+func testStepNewConfig(ctx context.Context, t testing.T, c TestCase, wd workingDir, step TestStep, cfg plugintest.WorkingDirConfig, providers *providerFactories) error {
 	t.Helper()
 
-	err := wd.SetConfig(ctx, step.mergedConfig(ctx, c))
+	err := wd.SetConfig(ctx, cfg)
 	if err != nil {
 		return fmt.Errorf("Error setting config: %w", err)
 	}
 
+	var identityTypes map[string]bool
+	if c.CheckImmutableIdentity {
+		identityTypes, err = providers.immutableIdentityTypes()
+		if err != nil {
+			return fmt.Errorf("Error getting resource identity behaviors: %w", err)
+		}
+	}
+
+	// Keep the state of the prior TestStep to check that resource
+	// identities do not change
+	var priorState *tfjson.State
+	if len(identityTypes) > 0 {
+		err = runProviderCommand(ctx, t, func() error {
+			var err error
+			priorState, err = wd.State(ctx)
+			return err
+		}, wd, providers)
+		if err != nil {
+			return fmt.Errorf("Error retrieving prior state: %w", err)
+		}
+	}
+
+	// Resources created or replaced by the apply have a new identity
+	var created map[string]bool
+
 	// require a refresh before applying
 	// failing to do this will result in data sources not being updated
 	err = runProviderCommand(ctx, t, func() error {
@@ -50,6 +75,29 @@ func testStepNewConfig(ctx context.Context, t testing.T, c TestCase, wd *plugint
 			return fmt.Errorf("Error running pre-apply plan: %w", err)
 		}
 
+		var plan *tfjson.Plan
+		if len(step.ConfigPlanChecks.PreApply) > 0 || len(identityTypes) > 0 {
+			err = runProviderCommand(ctx, t, func() error {
+				var err error
+				plan, err = wd.SavedPlan(ctx)
+				return err
+			}, wd, providers)
+			if err != nil {
+				return fmt.Errorf("Error retrieving pre-apply plan: %w", err)
+			}
+		}
+
+		created = createdInstances(plan)
+
+		// Run pre-apply plan checks
+		if len(step.ConfigPlanChecks.PreApply) > 0 {
+			logging.HelperResourceTrace(ctx, "Using TestStep ConfigPlanChecks.PreApply")
+
+			if err := runPlanChecks(ctx, plan, step.ConfigPlanChecks.PreApply); err != nil {
+				return fmt.Errorf("Pre-apply plan check(s) failed:\n%w", err)
+			}
+		}
+
 		// We need to keep a copy of the state prior to destroying such
 		// that the destroy steps can verify their behavior in the
 		// check function
@@ -65,6 +113,20 @@ func testStepNewConfig(ctx context.Context, t testing.T, c TestCase, wd *plugint
 			return fmt.Errorf("Error retrieving pre-apply state: %w", err)
 		}
 
+		// Destroy steps run their state checks against the state prior to
+		// destroying, as they do their Check function
+		var jsonState *tfjson.State
+		if step.Destroy && len(step.ConfigStateChecks) > 0 {
+			err = runProviderCommand(ctx, t, func() error {
+				var err error
+				jsonState, err = wd.State(ctx)
+				return err
+			}, wd, providers)
+			if err != nil {
+				return fmt.Errorf("Error retrieving pre-apply state: %w", err)
+			}
+		}
+
 		// Apply the diff, creating real resources
 		err = runProviderCommand(ctx, t, func() error {
 			return wd.Apply(ctx)
@@ -104,6 +166,26 @@ func testStepNewConfig(ctx context.Context, t testing.T, c TestCase, wd *plugint
 				}
 			}
 		}
+
+		// Run any configured state checks
+		if len(step.ConfigStateChecks) > 0 {
+			logging.HelperResourceTrace(ctx, "Using TestStep ConfigStateChecks")
+
+			if !step.Destroy {
+				err = runProviderCommand(ctx, t, func() error {
+					var err error
+					jsonState, err = wd.State(ctx)
+					return err
+				}, wd, providers)
+				if err != nil {
+					return fmt.Errorf("Error retrieving state after apply: %w", err)
+				}
+			}
+
+			if err := runStateChecks(ctx, jsonState, step.ConfigStateChecks); err != nil {
+				return fmt.Errorf("Post-apply state check(s) failed:\n%w", err)
+			}
+		}
 	}
 
 	// Test for perpetual diffs by performing a plan, a refresh, and another plan
@@ -130,6 +212,15 @@ func testStepNewConfig(ctx context.Context, t testing.T, c TestCase, wd *plugint
 		return fmt.Errorf("Error retrieving post-apply plan: %w", err)
 	}
 
+	// Run post-apply, pre-refresh plan checks
+	if len(step.ConfigPlanChecks.PostApplyPreRefresh) > 0 {
+		logging.HelperResourceTrace(ctx, "Using TestStep ConfigPlanChecks.PostApplyPreRefresh")
+
+		if err := runPlanChecks(ctx, plan, step.ConfigPlanChecks.PostApplyPreRefresh); err != nil {
+			return fmt.Errorf("Post-apply pre-refresh plan check(s) failed:\n%w", err)
+		}
+	}
+
 	if !planIsEmpty(plan) && !step.ExpectNonEmptyPlan {
 		var stdout string
 		err = runProviderCommand(ctx, t, func() error {
@@ -173,6 +264,15 @@ func testStepNewConfig(ctx context.Context, t testing.T, c TestCase, wd *plugint
 		return fmt.Errorf("Error retrieving second post-apply plan: %w", err)
 	}
 
+	// Run post-apply, post-refresh plan checks
+	if len(step.ConfigPlanChecks.PostApplyPostRefresh) > 0 {
+		logging.HelperResourceTrace(ctx, "Using TestStep ConfigPlanChecks.PostApplyPostRefresh")
+
+		if err := runPlanChecks(ctx, plan, step.ConfigPlanChecks.PostApplyPostRefresh); err != nil {
+			return fmt.Errorf("Post-apply post-refresh plan check(s) failed:\n%w", err)
+		}
+	}
+
 	// check if plan is empty
 	if !planIsEmpty(plan) && !step.ExpectNonEmptyPlan {
 		var stdout string
@@ -189,6 +289,22 @@ func testStepNewConfig(ctx context.Context, t testing.T, c TestCase, wd *plugint
 		return errors.New("Expected a non-empty plan, but got an empty plan")
 	}
 
+	if len(identityTypes) > 0 {
+		var jsonState *tfjson.State
+		err = runProviderCommand(ctx, t, func() error {
+			var err error
+			jsonState, err = wd.State(ctx)
+			return err
+		}, wd, providers)
+		if err != nil {
+			return fmt.Errorf("Error retrieving state: %w", err)
+		}
+
+		if err := checkIdentityUnchanged(priorState, jsonState, identityTypes, created); err != nil {
+			return fmt.Errorf("Resource identity check(s) failed:\n%w", err)
+		}
+	}
+
 	// ID-ONLY REFRESH
 	// If we've never checked an id-only refresh and our state isn't
 	// empty, find the first resource and test it.
@@ -233,7 +349,7 @@ func testStepNewConfig(ctx context.Context, t testing.T, c TestCase, wd *plugint
 		// this fails. If refresh isn't read-only, then this will have
 		// caught a different bug.
 		if idRefreshCheck != nil {
-			if err := testIDRefresh(ctx, t, c, wd, step, idRefreshCheck, providers); err != nil {
+			if err := testIDRefresh(ctx, t, c, wd, step, cfg, idRefreshCheck, providers); err != nil {
 				return fmt.Errorf(
 					"[ERROR] Test: ID-only test failed: %s", err)
 			}
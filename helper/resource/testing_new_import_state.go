@@ -4,20 +4,29 @@
 package resource
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/mitchellh/go-testing-interface"
+	zcty "github.com/zclconf/go-cty/cty"
+	zctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugintest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
-func testStepNewImportState(ctx context.Context, t testing.T, helper *plugintest.Helper, wd *plugintest.WorkingDir, step TestStep, cfg string, providers *providerFactories) error {
+func testStepNewImportState(ctx context.Context, t testing.T, newWorkingDir func(context.Context) workingDir, wd workingDir, step TestStep, cfg plugintest.WorkingDirConfig, providers *providerFactories) error {
 	t.Helper()
 
 	if step.ResourceName == "" {
@@ -25,10 +34,15 @@ func testStepNewImportState(ctx context.Context, t testing.T, helper *plugintest
 	}
 
 	// get state from check sequence
+	var jsonState *tfjson.State
 	var state *terraform.State
 	var err error
 	err = runProviderCommand(ctx, t, func() error {
-		state, err = getState(ctx, t, wd)
+		jsonState, err = wd.State(ctx)
+		if err != nil {
+			return err
+		}
+		state, err = shimStateFromJson(jsonState)
 		if err != nil {
 			return err
 		}
@@ -38,73 +52,51 @@ func testStepNewImportState(ctx context.Context, t testing.T, helper *plugintest
 		t.Fatalf("Error getting state: %s", err)
 	}
 
-	// Determine the ID to import
 	var importId string
-	switch {
-	case step.ImportStateIdFunc != nil:
-		logging.HelperResourceTrace(ctx, "Using TestStep ImportStateIdFunc for import identifier")
-
-		var err error
-
-		logging.HelperResourceDebug(ctx, "Calling TestStep ImportStateIdFunc")
-
-		importId, err = step.ImportStateIdFunc(state)
-
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		logging.HelperResourceDebug(ctx, "Called TestStep ImportStateIdFunc")
-	case step.ImportStateId != "":
-		logging.HelperResourceTrace(ctx, "Using TestStep ImportStateId for import identifier")
-
-		importId = step.ImportStateId
-	default:
-		logging.HelperResourceTrace(ctx, "Using resource identifier for import identifier")
-
-		resource, err := testResource(step, state)
-		if err != nil {
-			t.Fatal(err)
-		}
-		importId = resource.Primary.ID
-	}
+	var identity map[string]interface{}
 
-	if step.ImportStateIdPrefix != "" {
-		logging.HelperResourceTrace(ctx, "Prepending TestStep ImportStateIdPrefix for import identifier")
+	if step.ImportStateKind == ImportBlockWithResourceIdentity {
+		identity = testStepImportStateIdentity(ctx, t, step, jsonState)
+	} else {
+		importId = testStepImportStateId(ctx, t, step, state)
 
-		importId = step.ImportStateIdPrefix + importId
+		logging.HelperResourceTrace(ctx, fmt.Sprintf("Using import identifier: %s", importId))
 	}
 
-	logging.HelperResourceTrace(ctx, fmt.Sprintf("Using import identifier: %s", importId))
-
 	// Create working directory for import tests
-	if step.Config == "" {
+	if !step.hasConfig() {
 		logging.HelperResourceTrace(ctx, "Using prior TestStep Config for import")
+	}
 
-		step.Config = cfg
-		if step.Config == "" {
-			t.Fatal("Cannot import state with no specified config")
+	if cfg.IsEmpty() {
+		t.Fatal("Cannot import state with no specified config")
+	}
+
+	if step.ImportStateKind != ImportCommandWithID {
+		cfg.ImportBlock, err = importBlockConfig(resourceInstanceAddress(step.ResourceName), importId, identity)
+		if err != nil {
+			t.Fatalf("Error creating import block: %s", err)
 		}
 	}
 
-	var importWd *plugintest.WorkingDir
+	var importWd workingDir
 
 	// Use the same working directory to persist the state from import
 	if step.ImportStatePersist {
 		importWd = wd
 	} else {
-		importWd = helper.RequireNewWorkingDir(ctx, t)
+		importWd = newWorkingDir(ctx)
 		defer importWd.Close()
 	}
 
-	err = importWd.SetConfig(ctx, step.Config)
+	err = importWd.SetConfig(ctx, cfg)
 	if err != nil {
 		t.Fatalf("Error setting test config: %s", err)
 	}
 
-	logging.HelperResourceDebug(ctx, "Running Terraform CLI init and import")
-
 	if !step.ImportStatePersist {
+		logging.HelperResourceDebug(ctx, "Running Terraform CLI init")
+
 		err = runProviderCommand(ctx, t, func() error {
 			return importWd.Init(ctx)
 		}, importWd, providers)
@@ -113,26 +105,301 @@ func testStepNewImportState(ctx context.Context, t testing.T, helper *plugintest
 		}
 	}
 
-	err = runProviderCommand(ctx, t, func() error {
-		return importWd.Import(ctx, step.ResourceName, importId)
-	}, importWd, providers)
-	if err != nil {
+	var importState *terraform.State
+	var importedIdentity map[string]interface{}
+
+	if step.ImportStateKind != ImportCommandWithID {
+		importState, importedIdentity, err = testStepNewImportBlock(ctx, t, importWd, step, providers)
+		if err != nil {
+			return err
+		}
+	} else {
+		logging.HelperResourceDebug(ctx, "Running Terraform CLI import")
+
+		err = runProviderCommand(ctx, t, func() error {
+			return importWd.Import(ctx, step.ResourceName, importId)
+		}, importWd, providers)
+		if err != nil {
+			return err
+		}
+
+		err = runProviderCommand(ctx, t, func() error {
+			importJsonState, err := importWd.State(ctx)
+			if err != nil {
+				return err
+			}
+			importState, err = shimStateFromJson(importJsonState)
+			if err != nil {
+				return err
+			}
+			if step.ImportStateIdentityVerify {
+				importedIdentity, err = stateResourceIdentity(importJsonState, step.ResourceName)
+			}
+			return err
+		}, importWd, providers)
+		if err != nil {
+			t.Fatalf("Error getting state: %s", err)
+		}
+	}
+
+	testStepImportStateCheck(ctx, t, step, importState)
+
+	if err := testStepImportStateVerify(ctx, t, step, state, importState); err != nil {
 		return err
 	}
 
-	var importState *terraform.State
-	err = runProviderCommand(ctx, t, func() error {
-		importState, err = getState(ctx, t, importWd)
+	return testStepImportStateIdentityVerify(ctx, step, jsonState, importedIdentity)
+}
+
+// testStepNewImportBlock plans the import of the import block in the
+// configuration of the given working directory. It returns the state of the
+// imported resource, as planned, and its identity.
+func testStepNewImportBlock(ctx context.Context, t testing.T, wd workingDir, step TestStep, providers *providerFactories) (*terraform.State, map[string]interface{}, error) {
+	t.Helper()
+
+	logging.HelperResourceDebug(ctx, "Running Terraform CLI plan with import block")
+
+	var plan *tfjson.Plan
+	var stdout string
+	err := runProviderCommand(ctx, t, func() error {
+		var err error
+		if err = wd.CreatePlan(ctx); err != nil {
+			return err
+		}
+		plan, err = wd.SavedPlan(ctx)
 		if err != nil {
 			return err
 		}
+		stdout, err = wd.SavedPlanRawStdout(ctx)
+		return err
+	}, wd, providers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rc *tfjson.ResourceChange
+
+	address := resourceInstanceAddress(step.ResourceName)
+
+	for _, c := range plan.ResourceChanges {
+		if resourceInstanceAddress(c.Address) == address && c.Change != nil {
+			rc = c
+			break
+		}
+	}
+
+	if rc == nil || rc.Change.Importing == nil {
+		return nil, nil, fmt.Errorf("importing resource %s: expected an import operation in the plan\nstdout:\n\n%s", step.ResourceName, stdout)
+	}
+
+	if !rc.Change.Actions.NoOp() && !step.ExpectNonEmptyPlan {
+		return nil, nil, fmt.Errorf("importing resource %s: expected a no-op import operation, got %q action with plan\nstdout:\n\n%s", step.ResourceName, rc.Change.Actions, stdout)
+	}
+
+	importState, err := shimStateFromJson(plannedImportState(plan, rc))
+	if err != nil {
+		t.Fatalf("Error getting planned import state: %s", err)
+	}
+
+	identity, _ := rc.Change.AfterIdentity.(map[string]interface{})
+
+	return importState, identity, nil
+}
+
+// plannedImportState returns a state containing only the given planned
+// resource change of the given plan, with its planned values.
+func plannedImportState(plan *tfjson.Plan, rc *tfjson.ResourceChange) *tfjson.State {
+	after, _ := rc.Change.After.(map[string]interface{})
+
+	return &tfjson.State{
+		FormatVersion:    plan.FormatVersion,
+		TerraformVersion: plan.TerraformVersion,
+		Values: &tfjson.StateValues{
+			RootModule: &tfjson.StateModule{
+				Resources: []*tfjson.StateResource{
+					{
+						Address:         rc.Address,
+						Mode:            rc.Mode,
+						Type:            rc.Type,
+						Name:            rc.Name,
+						Index:           rc.Index,
+						ProviderName:    rc.ProviderName,
+						AttributeValues: after,
+					},
+				},
+			},
+		},
+	}
+}
+
+// testStepImportStateIdentity returns the identity of the ResourceName
+// resource of the given ImportState TestStep in the prior state.
+func testStepImportStateIdentity(ctx context.Context, t testing.T, step TestStep, state *tfjson.State) map[string]interface{} {
+	t.Helper()
+
+	logging.HelperResourceTrace(ctx, "Using resource identity for import")
+
+	identity, err := stateResourceIdentity(state, step.ResourceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if identity == nil {
+		t.Fatalf("Resource %s has no identity in state, which is required by ImportBlockWithResourceIdentity", step.ResourceName)
+	}
+
+	return identity
+}
+
+// stateResourceIdentity returns the identity of the resource instance with
+// the given address in the given state, or nil if it has no identity.
+func stateResourceIdentity(state *tfjson.State, address string) (map[string]interface{}, error) {
+	if state.Values != nil {
+		if r := moduleStateResource(state.Values.RootModule, resourceInstanceAddress(address)); r != nil {
+			return r.IdentityValues, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: resource not found in state", address)
+}
+
+// moduleStateResource returns the resource instance with the given address,
+// as returned by resourceInstanceAddress, in the given module or its child
+// modules, or nil if it does not exist.
+func moduleStateResource(module *tfjson.StateModule, address string) *tfjson.StateResource {
+	if module == nil {
 		return nil
-	}, importWd, providers)
+	}
+
+	for _, r := range module.Resources {
+		if resourceInstanceAddress(r.Address) == address {
+			return r
+		}
+	}
+
+	for _, child := range module.ChildModules {
+		if r := moduleStateResource(child, address); r != nil {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// resourceInstanceAddress returns the given resource instance address in the
+// format of Terraform JSON output, such as examplecloud_thing.test[0],
+// converting the legacy index format of the shimmed state, such as
+// examplecloud_thing.test.0, which ResourceName may also use.
+func resourceInstanceAddress(address string) string {
+	var b strings.Builder
+
+	for i, part := range strings.Split(address, ".") {
+		if _, err := strconv.ParseUint(part, 10, 64); err == nil && i > 0 {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+
+		if i > 0 {
+			b.WriteString(".")
+		}
+
+		b.WriteString(part)
+	}
+
+	return b.String()
+}
+
+// importBlockConfig returns a configuration containing an import block for
+// the given resource address, with either the given import identifier or
+// the given resource identity.
+func importBlockConfig(address, id string, identity map[string]interface{}) (string, error) {
+	to, diags := hclsyntax.ParseTraversalAbs([]byte(address), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", fmt.Errorf("invalid resource address %q: %s", address, diags.Error())
+	}
+
+	f := hclwrite.NewEmptyFile()
+	body := f.Body().AppendNewBlock("import", nil).Body()
+
+	body.SetAttributeTraversal("to", to)
+
+	if identity == nil {
+		body.SetAttributeValue("id", zcty.StringVal(id))
+
+		return string(f.Bytes()), nil
+	}
+
+	src, err := json.Marshal(identity)
 	if err != nil {
-		t.Fatalf("Error getting state: %s", err)
+		return "", err
+	}
+
+	ty, err := zctyjson.ImpliedType(src)
+	if err != nil {
+		return "", err
+	}
+
+	val, err := zctyjson.Unmarshal(src, ty)
+	if err != nil {
+		return "", err
+	}
+
+	body.SetAttributeValue("identity", val)
+
+	return string(f.Bytes()), nil
+}
+
+// testStepImportStateId returns the import identifier of the given
+// ImportState TestStep, determined from the prior state when not set
+// explicitly.
+func testStepImportStateId(ctx context.Context, t testing.T, step TestStep, state *terraform.State) string {
+	t.Helper()
+
+	var importId string
+
+	switch {
+	case step.ImportStateIdFunc != nil:
+		logging.HelperResourceTrace(ctx, "Using TestStep ImportStateIdFunc for import identifier")
+
+		var err error
+
+		logging.HelperResourceDebug(ctx, "Calling TestStep ImportStateIdFunc")
+
+		importId, err = step.ImportStateIdFunc(state)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logging.HelperResourceDebug(ctx, "Called TestStep ImportStateIdFunc")
+	case step.ImportStateId != "":
+		logging.HelperResourceTrace(ctx, "Using TestStep ImportStateId for import identifier")
+
+		importId = step.ImportStateId
+	default:
+		logging.HelperResourceTrace(ctx, "Using resource identifier for import identifier")
+
+		resource, err := testResource(step, state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		importId = resource.Primary.ID
 	}
 
-	// Go through the imported state and verify
+	if step.ImportStateIdPrefix != "" {
+		logging.HelperResourceTrace(ctx, "Prepending TestStep ImportStateIdPrefix for import identifier")
+
+		importId = step.ImportStateIdPrefix + importId
+	}
+
+	return importId
+}
+
+// testStepImportStateCheck calls the ImportStateCheck of the given TestStep,
+// if any, with the instances of the managed resources in the imported state.
+func testStepImportStateCheck(ctx context.Context, t testing.T, step TestStep, importState *terraform.State) {
+	t.Helper()
+
 	if step.ImportStateCheck != nil {
 		logging.HelperResourceTrace(ctx, "Using TestStep ImportStateCheck")
 
@@ -159,8 +426,14 @@ func testStepNewImportState(ctx context.Context, t testing.T, helper *plugintest
 
 		logging.HelperResourceDebug(ctx, "Called TestStep ImportStateCheck")
 	}
+}
+
+// testStepImportStateVerify returns an error if ImportStateVerify is enabled
+// for the given TestStep and the attributes of the imported managed resources
+// differ from the attributes of the same resources in the prior state.
+func testStepImportStateVerify(ctx context.Context, t testing.T, step TestStep, state, importState *terraform.State) error {
+	t.Helper()
 
-	// Verify that all the states match
 	if step.ImportStateVerify {
 		logging.HelperResourceTrace(ctx, "Using TestStep ImportStateVerify")
 
@@ -279,3 +552,64 @@ func testStepNewImportState(ctx context.Context, t testing.T, helper *plugintest
 
 	return nil
 }
+
+// testStepImportStateIdentityVerify returns an error if
+// ImportStateIdentityVerify is enabled for the given TestStep and the given
+// identity of the imported ResourceName resource differs from its identity in
+// the prior state.
+func testStepImportStateIdentityVerify(ctx context.Context, step TestStep, state *tfjson.State, importedIdentity map[string]interface{}) error {
+	if !step.ImportStateIdentityVerify {
+		return nil
+	}
+
+	logging.HelperResourceTrace(ctx, "Using TestStep ImportStateIdentityVerify")
+
+	identity, err := stateResourceIdentity(state, step.ResourceName)
+	if err != nil {
+		return fmt.Errorf("ImportStateIdentityVerify: %w", err)
+	}
+
+	if identity == nil {
+		return fmt.Errorf("ImportStateIdentityVerify: resource %s has no identity in the prior state", step.ResourceName)
+	}
+
+	if importedIdentity == nil {
+		return fmt.Errorf("ImportStateIdentityVerify: resource %s has no identity after import", step.ResourceName)
+	}
+
+	// Numbers may be decoded differently depending on the source of the
+	// identity, so both are normalized through JSON before comparing.
+	expected, err := normalizeIdentity(identity)
+	if err != nil {
+		return fmt.Errorf("ImportStateIdentityVerify: %w", err)
+	}
+
+	actual, err := normalizeIdentity(importedIdentity)
+	if err != nil {
+		return fmt.Errorf("ImportStateIdentityVerify: %w", err)
+	}
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		return fmt.Errorf("ImportStateIdentityVerify identity not equivalent. Difference is shown below. The - symbol indicates identity attributes missing after import.\n\n%s", diff)
+	}
+
+	return nil
+}
+
+func normalizeIdentity(identity map[string]interface{}) (map[string]interface{}, error) {
+	src, err := json.Marshal(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+
+	if err := dec.Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
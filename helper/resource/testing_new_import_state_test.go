@@ -5,10 +5,14 @@ package resource
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -198,6 +202,61 @@ func TestTest_TestStep_ImportStateVerifyIgnore(t *testing.T) {
 	})
 }
 
+func TestTest_TestStep_ImportState_Count(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		ProviderFactories: map[string]func() (*schema.Provider, error){
+			"examplecloud": func() (*schema.Provider, error) { //nolint:unparam // required signature
+				return &schema.Provider{
+					ResourcesMap: map[string]*schema.Resource{
+						"examplecloud_thing": {
+							CreateContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+								d.SetId("resource-test")
+
+								return nil
+							},
+							DeleteContext: func(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+								return nil
+							},
+							ReadContext: func(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+								return nil
+							},
+							Schema: map[string]*schema.Schema{
+								"id": {
+									Computed: true,
+									Type:     schema.TypeString,
+								},
+							},
+							Importer: &schema.ResourceImporter{
+								StateContext: schema.ImportStatePassthroughContext,
+							},
+						},
+					},
+				}, nil
+			},
+		},
+		Steps: []TestStep{
+			{
+				Config: `resource "examplecloud_thing" "test" {
+					count = 1
+				}`,
+			},
+			{
+				ResourceName:      "examplecloud_thing.test.0",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "examplecloud_thing.test.0",
+				ImportState:       true,
+				ImportStateKind:   ImportBlockWithID,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestTest_TestStep_ExpectError_ImportState(t *testing.T) {
 	t.Parallel()
 
@@ -219,3 +278,152 @@ func TestTest_TestStep_ExpectError_ImportState(t *testing.T) {
 		},
 	})
 }
+
+func TestImportBlockConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		address  string
+		id       string
+		identity map[string]interface{}
+		expected string
+	}{
+		"id": {
+			address: "examplecloud_thing.test",
+			id:      "thing-${1}",
+			expected: `import {
+  to = examplecloud_thing.test
+  id = "thing-$${1}"
+}
+`,
+		},
+		"identity": {
+			address: "examplecloud_thing.test[1]",
+			identity: map[string]interface{}{
+				"id":     "thing-1",
+				"number": json.Number("2"),
+				"zones":  []interface{}{"a", "b"},
+			},
+			expected: `import {
+  to = examplecloud_thing.test[1]
+  identity = {
+    id     = "thing-1"
+    number = 2
+    zones  = ["a", "b"]
+  }
+}
+`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := importBlockConfig(testCase.address, testCase.id, testCase.identity)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(testCase.expected, got); diff != "" {
+				t.Errorf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestResourceInstanceAddress(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"examplecloud_thing.test":                "examplecloud_thing.test",
+		"examplecloud_thing.test.0":              "examplecloud_thing.test[0]",
+		"examplecloud_thing.test[0]":             "examplecloud_thing.test[0]",
+		"data.examplecloud_thing.test.1":         "data.examplecloud_thing.test[1]",
+		"module.child.examplecloud_thing.test.2": "module.child.examplecloud_thing.test[2]",
+	}
+
+	for address, expected := range testCases {
+		t.Run(address, func(t *testing.T) {
+			t.Parallel()
+
+			if got := resourceInstanceAddress(address); got != expected {
+				t.Errorf("expected %q, got %q", expected, got)
+			}
+		})
+	}
+}
+
+func TestStateResourceIdentity(t *testing.T) {
+	t.Parallel()
+
+	state := &tfjson.State{
+		Values: &tfjson.StateValues{
+			RootModule: &tfjson.StateModule{
+				Resources: []*tfjson.StateResource{
+					{
+						Address:        "examplecloud_thing.test[0]",
+						IdentityValues: map[string]interface{}{"id": "root"},
+					},
+				},
+				ChildModules: []*tfjson.StateModule{
+					{
+						Address: "module.child",
+						Resources: []*tfjson.StateResource{
+							{
+								Address:        "module.child.examplecloud_thing.test[0]",
+								IdentityValues: map[string]interface{}{"id": "child"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		address     string
+		expected    map[string]interface{}
+		expectError bool
+	}{
+		"json-address": {
+			address:  "examplecloud_thing.test[0]",
+			expected: map[string]interface{}{"id": "root"},
+		},
+		"shimmed-address": {
+			address:  "examplecloud_thing.test.0",
+			expected: map[string]interface{}{"id": "root"},
+		},
+		"module": {
+			address:  "module.child.examplecloud_thing.test.0",
+			expected: map[string]interface{}{"id": "child"},
+		},
+		"not-found": {
+			address:     "examplecloud_thing.test.1",
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := stateResourceIdentity(state, testCase.address)
+			if err != nil {
+				if !testCase.expectError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectError {
+				t.Fatal("expected error, got none")
+			}
+
+			if diff := cmp.Diff(testCase.expected, got); diff != "" {
+				t.Errorf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
@@ -11,11 +11,10 @@ import (
 	"github.com/mitchellh/go-testing-interface"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugintest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
-func testStepNewRefreshState(ctx context.Context, t testing.T, wd *plugintest.WorkingDir, step TestStep, providers *providerFactories) error {
+func testStepNewRefreshState(ctx context.Context, t testing.T, c TestCase, wd workingDir, step TestStep, providers *providerFactories) error {
 	t.Helper()
 
 	var err error
@@ -31,6 +30,28 @@ func testStepNewRefreshState(ctx context.Context, t testing.T, wd *plugintest.Wo
 		t.Fatalf("Error getting state: %s", err)
 	}
 
+	var identityTypes map[string]bool
+	if c.CheckImmutableIdentity {
+		identityTypes, err = providers.immutableIdentityTypes()
+		if err != nil {
+			return fmt.Errorf("Error getting resource identity behaviors: %w", err)
+		}
+	}
+
+	// Keep the state of the prior TestStep to check that resource
+	// identities do not change
+	var priorState *tfjson.State
+	if len(identityTypes) > 0 {
+		err = runProviderCommand(ctx, t, func() error {
+			var err error
+			priorState, err = wd.State(ctx)
+			return err
+		}, wd, providers)
+		if err != nil {
+			t.Fatalf("Error getting state: %s", err)
+		}
+	}
+
 	err = runProviderCommand(ctx, t, func() error {
 		return wd.Refresh(ctx)
 	}, wd, providers)
@@ -38,6 +59,22 @@ func testStepNewRefreshState(ctx context.Context, t testing.T, wd *plugintest.Wo
 		return err
 	}
 
+	if len(identityTypes) > 0 {
+		var jsonState *tfjson.State
+		err = runProviderCommand(ctx, t, func() error {
+			var err error
+			jsonState, err = wd.State(ctx)
+			return err
+		}, wd, providers)
+		if err != nil {
+			t.Fatalf("Error getting state: %s", err)
+		}
+
+		if err := checkIdentityUnchanged(priorState, jsonState, identityTypes, nil); err != nil {
+			return fmt.Errorf("Resource identity check(s) failed:\n%w", err)
+		}
+	}
+
 	var refreshState *terraform.State
 	err = runProviderCommand(ctx, t, func() error {
 		refreshState, err = getState(ctx, t, wd)
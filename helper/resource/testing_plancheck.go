@@ -0,0 +1,37 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"errors"
+
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/plancheck"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
+)
+
+// runPlanChecks runs every given plan check against the plan and returns the
+// errors of the failing checks combined.
+func runPlanChecks(ctx context.Context, plan *tfjson.Plan, planChecks []plancheck.PlanCheck) error {
+	var result []error
+
+	for _, planCheck := range planChecks {
+		logging.HelperResourceDebug(ctx, "Calling TestStep plan check")
+
+		if err := planCheck.CheckPlan(ctx, plan); err != nil {
+			result = append(result, err)
+		}
+
+		logging.HelperResourceDebug(ctx, "Called TestStep plan check")
+	}
+
+	return errors.Join(result...)
+}
+
+// hasChecks returns true if any of the plan checks are set.
+func (c ConfigPlanChecks) hasChecks() bool {
+	return len(c.PreApply) > 0 || len(c.PostApplyPreRefresh) > 0 || len(c.PostApplyPostRefresh) > 0
+}
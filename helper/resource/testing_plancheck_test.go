@@ -0,0 +1,114 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/plancheck"
+)
+
+func TestTest_TestStep_ConfigPlanChecks(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(make(map[string]string)),
+		Steps: []TestStep{
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name   = "one"
+						secret = "hunter2"
+						zone   = "a"
+					}
+				`,
+				ConfigPlanChecks: ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("examplecloud_thing.test", plancheck.ResourceActionCreate),
+						plancheck.ExpectUnknownValue("examplecloud_thing.test", cty.GetAttrPath("id")),
+						plancheck.ExpectSensitiveValue("examplecloud_thing.test", cty.GetAttrPath("secret")),
+					},
+					PostApplyPreRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("examplecloud_thing.test", plancheck.ResourceActionNoop),
+					},
+				},
+			},
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name   = "two"
+						secret = "hunter2"
+						zone   = "a"
+					}
+				`,
+				ConfigPlanChecks: ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("examplecloud_thing.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name   = "two"
+						secret = "hunter2"
+						zone   = "b"
+					}
+				`,
+				ConfigPlanChecks: ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("examplecloud_thing.test", plancheck.ResourceActionReplace),
+					},
+				},
+			},
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name   = "two"
+						secret = "hunter2"
+						zone   = "b"
+					}
+				`,
+				Destroy: true,
+				ConfigPlanChecks: ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("examplecloud_thing.test", plancheck.ResourceActionDestroy),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestTest_TestStep_ConfigPlanChecks_Error(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(make(map[string]string)),
+		Steps: []TestStep{
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						name = "one"
+					}
+				`,
+				ConfigPlanChecks: ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+						plancheck.ExpectResourceAction("examplecloud_thing.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ExpectError: regexp.MustCompile(`(?s)Pre-apply plan check\(s\) failed:\nexpected empty plan, but examplecloud_thing.test has planned changes:.*# examplecloud_thing.test will be created.*expected Update, got action\(s\): \[create\]`),
+			},
+		},
+	})
+}
@@ -0,0 +1,32 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"errors"
+
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/statecheck"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
+)
+
+// runStateChecks runs every given state check against the state and returns
+// the errors of the failing checks combined.
+func runStateChecks(ctx context.Context, state *tfjson.State, stateChecks []statecheck.StateCheck) error {
+	var result []error
+
+	for _, stateCheck := range stateChecks {
+		logging.HelperResourceDebug(ctx, "Calling TestStep state check")
+
+		if err := stateCheck.CheckState(ctx, state); err != nil {
+			result = append(result, err)
+		}
+
+		logging.HelperResourceDebug(ctx, "Called TestStep state check")
+	}
+
+	return errors.Join(result...)
+}
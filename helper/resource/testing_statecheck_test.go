@@ -0,0 +1,103 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/knownvalue"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/statecheck"
+)
+
+func TestTest_TestStep_ConfigStateChecks(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(make(map[string]string)),
+		Steps: []TestStep{
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						rule {
+							port     = 443
+							protocol = "tcp"
+						}
+
+						rule {
+							port = 80
+						}
+
+						tags = {
+							env  = "prod"
+							name = "test"
+						}
+					}
+
+					output "tags" {
+						value = examplecloud_thing.test.tags
+					}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("examplecloud_thing.test", cty.GetAttrPath("size"), knownvalue.Int64Between(1, 5)),
+					statecheck.ExpectKnownValue("examplecloud_thing.test", cty.GetAttrPath("rule"), knownvalue.SetExact([]knownvalue.Check{
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"port":     knownvalue.Int64Exact(80),
+							"protocol": knownvalue.StringExact(""),
+						}),
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{
+							"port": knownvalue.Int64Exact(443),
+						}),
+					})),
+					statecheck.ExpectKnownValue("examplecloud_thing.test", cty.GetAttrPath("tags"), knownvalue.MapKeysExact("env", "name")),
+					statecheck.ExpectKnownOutputValue("tags", knownvalue.MapPartial(map[string]knownvalue.Check{
+						"env": knownvalue.StringRegexp(regexp.MustCompile(`^prod$`)),
+					})),
+					statecheck.ExpectKnownOutputValueAtPath("tags", cty.GetAttrPath("name"), knownvalue.StringExact("test")),
+				},
+			},
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						rule {
+							port = 80
+						}
+					}
+				`,
+				Destroy: true,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("examplecloud_thing.test", cty.GetAttrPath("rule"), knownvalue.SetSizeExact(2)),
+				},
+			},
+		},
+	})
+}
+
+func TestTest_TestStep_ConfigStateChecks_Error(t *testing.T) {
+	t.Parallel()
+
+	UnitTest(t, TestCase{
+		InProcessCore:     true,
+		ProviderFactories: testExamplecloudProviderFactories(make(map[string]string)),
+		Steps: []TestStep{
+			{
+				Config: `
+					resource "examplecloud_thing" "test" {
+						tags = {
+							env = "prod"
+						}
+					}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("examplecloud_thing.test", cty.GetAttrPath("size"), knownvalue.Int64Exact(4)),
+					statecheck.ExpectKnownValue("examplecloud_thing.test", cty.GetAttrPath("tags").IndexString("env"), knownvalue.Null()),
+				},
+				ExpectError: regexp.MustCompile(`(?s)Post-apply state check\(s\) failed:.*expected value 4 for Int64Exact check, got: 3.*expected value null for Null check, got: "prod"`),
+			},
+		},
+	})
+}
@@ -0,0 +1,601 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/diagutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// SweeperContextFunc is a signature for a function that acts as a sweeper
+// and supports the -sweep-dry-run flag. It accepts a context, which is
+// cancelled on an interrupt or once the go test timeout passes and reports
+// whether the sweeper is running as a dry run with SweepDryRun, a string for
+// the region that the sweeper is to be ran in, and the meta of the
+// provider of the Sweeper configured for that region, or nil if the Sweeper
+// has no ProviderFactory.
+type SweeperContextFunc func(ctx context.Context, r string, meta interface{}) diag.Diagnostics
+
+// Sweeper statuses of a sweep report.
+const (
+	sweeperStatusPassed  = "passed"
+	sweeperStatusFailed  = "failed"
+	sweeperStatusSkipped = "skipped"
+)
+
+// sweepOptions are the options of a run of the sweepers, which are set with
+// the sweeper flags of TestMain.
+type sweepOptions struct {
+	// allowFailures continues to run the sweepers after a sweeper fails.
+	allowFailures bool
+
+	// dryRun runs the sweepers without deleting any resources.
+	dryRun bool
+
+	// parallelism is the maximum number of sweepers which run concurrently
+	// in a region. Values less than one run the sweepers sequentially.
+	parallelism int
+}
+
+// sweepReport is the report of a run of the sweepers, which is written
+// with the -sweep-report flag.
+type sweepReport struct {
+	DryRun  bool                `json:"dry_run"`
+	Regions []sweepRegionReport `json:"regions"`
+}
+
+// sweepRegionReport is the report of the sweepers of a region.
+type sweepRegionReport struct {
+	Region          string               `json:"region"`
+	DurationSeconds float64              `json:"duration_seconds"`
+	Sweepers        []sweepSweeperReport `json:"sweepers"`
+}
+
+// sweepSweeperReport is the report of a sweeper in a region.
+type sweepSweeperReport struct {
+	Name            string   `json:"name"`
+	Status          string   `json:"status"`
+	Error           string   `json:"error,omitempty"`
+	DurationSeconds float64  `json:"duration_seconds"`
+	WouldDelete     []string `json:"would_delete,omitempty"`
+
+	err error
+}
+
+// sweepDryRunKey is the context key of the sweepDryRun of a sweeper.
+type sweepDryRunKey struct{}
+
+// sweepDryRun records the resources which a sweeper would delete.
+type sweepDryRun struct {
+	mu        sync.Mutex
+	resources []string
+}
+
+// SweepDryRun returns true if the sweeper of the given context is running
+// with the -sweep-dry-run flag. Sweepers must then not delete any
+// resources, and should report each resource they would delete with
+// SweepWouldDelete instead.
+func SweepDryRun(ctx context.Context) bool {
+	_, ok := ctx.Value(sweepDryRunKey{}).(*sweepDryRun)
+
+	return ok
+}
+
+// SweepWouldDelete reports that the sweeper of the given context would
+// delete the given resource, such as its type and identifier, if it was not
+// running with the -sweep-dry-run flag. The resources are logged and
+// included in the sweep report. It does nothing outside of dry runs.
+func SweepWouldDelete(ctx context.Context, resource string) {
+	d, ok := ctx.Value(sweepDryRunKey{}).(*sweepDryRun)
+	if !ok {
+		return
+	}
+
+	log.Printf("[INFO] Sweeper would delete %s", resource)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.resources = append(d.resources, resource)
+}
+
+// sweepTimeout returns the maximum duration of a run of the sweepers, which
+// is the given override if it is greater than zero, or the timeout of the
+// go test command otherwise.
+func sweepTimeout(override time.Duration) time.Duration {
+	if override > 0 {
+		return override
+	}
+
+	f := flag.Lookup("test.timeout")
+	if f == nil {
+		return 0
+	}
+
+	timeout, ok := f.Value.(flag.Getter).Get().(time.Duration)
+	if !ok {
+		return 0
+	}
+
+	return timeout
+}
+
+// sweepContext returns the context of a run of the sweepers, which is
+// cancelled on an interrupt and once the given timeout passes, if it is
+// greater than zero. Only the first interrupt is captured, so that a second
+// interrupt stops the process.
+func sweepContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if timeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// runSweepers runs the given sweepers in each of the given regions and
+// returns a report of their results. Regions are swept one after the other,
+// while the sweepers of a region run concurrently once all of their
+// dependencies have run. Unless failures are allowed, no further sweepers
+// are started after a sweeper fails.
+func runSweepers(ctx context.Context, regions []string, sweepers map[string]*Sweeper, opts sweepOptions) (*sweepReport, error) {
+	var sweeperErrorFound bool
+	report := &sweepReport{
+		DryRun: opts.dryRun,
+	}
+
+	for _, region := range regions {
+		region = strings.TrimSpace(region)
+
+		log.Printf("[DEBUG] Running Sweepers for region (%s):\n", region)
+
+		regionReport, err := runRegionSweepers(ctx, region, sweepers, opts)
+
+		report.Regions = append(report.Regions, regionReport)
+
+		if err != nil {
+			return report, err
+		}
+
+		log.Printf("Completed Sweepers for region (%s) in %s", region, time.Duration(regionReport.DurationSeconds*float64(time.Second)))
+
+		var regionSweeperErrorFound bool
+
+		log.Printf("Sweeper Tests for region (%s) ran successfully:\n", region)
+		for _, sweeper := range regionReport.Sweepers {
+			switch sweeper.Status {
+			case sweeperStatusPassed:
+				fmt.Printf("\t- %s\n", sweeper.Name)
+			case sweeperStatusFailed:
+				regionSweeperErrorFound = true
+			}
+		}
+
+		if regionSweeperErrorFound {
+			sweeperErrorFound = true
+			log.Printf("Sweeper Tests for region (%s) ran unsuccessfully:\n", region)
+			for _, sweeper := range regionReport.Sweepers {
+				if sweeper.Status == sweeperStatusFailed {
+					fmt.Printf("\t- %s: %s\n", sweeper.Name, sweeper.Error)
+				}
+			}
+		}
+	}
+
+	if sweeperErrorFound {
+		return report, errors.New("at least one sweeper failed")
+	}
+
+	return report, nil
+}
+
+// runRegionSweepers runs the given sweepers in a region. A sweeper is
+// started once all of its dependencies have run, with at most
+// opts.parallelism sweepers running at a time. Sweepers which are not run,
+// because an earlier sweeper failed or the given context is done, are
+// reported as skipped.
+func runRegionSweepers(ctx context.Context, region string, sweepers map[string]*Sweeper, opts sweepOptions) (sweepRegionReport, error) {
+	parallelism := opts.parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	// Start sweepers in a stable order
+	names := make([]string, 0, len(sweepers))
+	for name := range sweepers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make(map[string]sweepSweeperReport, len(sweepers))
+	started := make(map[string]bool, len(sweepers))
+	done := make(chan sweepSweeperReport)
+
+	var running int
+	var failed error
+
+	// interrupted is the error of sweepers which were not started after
+	// the context was done.
+	var interrupted error
+
+	// fail records a failure, which stops further sweepers from being
+	// started unless failures are allowed.
+	fail := func(result sweepSweeperReport) {
+		results[result.Name] = result
+
+		if failed == nil && !opts.allowFailures {
+			failed = fmt.Errorf("sweeper (%s) for region (%s) failed: %w", result.Name, region, result.err)
+		}
+	}
+
+	start := time.Now()
+
+	for {
+		// Sweepers which fail without running may allow their dependents
+		// to start, so the sweepers are scanned again afterwards.
+		var progressed bool
+
+		for _, name := range names {
+			if failed != nil || running >= parallelism {
+				break
+			}
+
+			if err := ctx.Err(); err != nil {
+				if interrupted == nil {
+					log.Printf("[ERROR] Sweepers for region (%s) interrupted: %s", region, err)
+
+					interrupted = fmt.Errorf("sweepers for region (%s) interrupted: %w", region, err)
+				}
+
+				break
+			}
+
+			if started[name] {
+				continue
+			}
+
+			s := sweepers[name]
+
+			ready, err := sweeperDependenciesRan(s, sweepers, results)
+			if err != nil {
+				log.Printf("[ERROR] %s", err)
+
+				started[name] = true
+				fail(sweepSweeperReport{
+					Name:   name,
+					Status: sweeperStatusFailed,
+					Error:  err.Error(),
+					err:    err,
+				})
+				progressed = true
+
+				continue
+			}
+
+			if !ready {
+				continue
+			}
+
+			started[name] = true
+			running++
+
+			go func(name string, s *Sweeper) {
+				done <- runSweeper(ctx, region, name, s, opts.dryRun)
+			}(name, s)
+		}
+
+		if running == 0 {
+			if progressed && failed == nil {
+				continue
+			}
+
+			break
+		}
+
+		result := <-done
+		running--
+
+		if result.Status == sweeperStatusFailed {
+			fail(result)
+		} else {
+			results[result.Name] = result
+		}
+	}
+
+	report := sweepRegionReport{
+		Region:          region,
+		DurationSeconds: time.Since(start).Seconds(),
+	}
+
+	// Whether the remaining sweepers were not started due to a failure or an
+	// interruption, rather than a dependency cycle
+	notStarted := failed != nil || interrupted != nil
+
+	for _, name := range names {
+		result, ok := results[name]
+
+		switch {
+		case ok:
+		case notStarted:
+			result = sweepSweeperReport{
+				Name:   name,
+				Status: sweeperStatusSkipped,
+				Error:  "not run after an earlier sweeper failed or the sweepers were interrupted",
+			}
+		default:
+			// Every sweeper whose dependencies ran has been started, so the
+			// remaining sweepers depend on each other.
+			err := fmt.Errorf("sweeper (%s) has dependencies which were never run, which may be caused by a dependency cycle", name)
+
+			log.Printf("[ERROR] %s", err)
+
+			result = sweepSweeperReport{
+				Name:   name,
+				Status: sweeperStatusFailed,
+				Error:  err.Error(),
+				err:    err,
+			}
+
+			if !opts.allowFailures && failed == nil {
+				failed = fmt.Errorf("sweeper (%s) for region (%s) failed: %w", name, region, err)
+			}
+		}
+
+		report.Sweepers = append(report.Sweepers, result)
+	}
+
+	if failed == nil {
+		failed = interrupted
+	}
+
+	return report, failed
+}
+
+// sweeperDependenciesRan returns true if all of the dependencies of the
+// given sweeper have run, or an error if one of them does not exist.
+func sweeperDependenciesRan(s *Sweeper, sweepers map[string]*Sweeper, results map[string]sweepSweeperReport) (bool, error) {
+	for _, dep := range s.Dependencies {
+		if _, ok := sweepers[dep]; !ok {
+			return false, fmt.Errorf("sweeper (%s) has dependency (%s), but that sweeper was not found", s.Name, dep)
+		}
+
+		if _, ok := results[dep]; !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// runSweeper runs a sweeper in a region and returns its result. Sweepers
+// without a ContextF are skipped in dry runs, as they cannot report the
+// resources they would delete.
+func runSweeper(ctx context.Context, region string, name string, s *Sweeper, dryRun bool) sweepSweeperReport {
+	result := sweepSweeperReport{
+		Name: name,
+	}
+
+	if dryRun && s.ContextF == nil {
+		log.Printf("[WARN] Sweeper (%s) does not support dry runs, skipping", name)
+
+		result.Status = sweeperStatusSkipped
+		result.Error = "sweeper does not support dry runs"
+
+		return result
+	}
+
+	var d *sweepDryRun
+	if dryRun {
+		d = &sweepDryRun{}
+		ctx = context.WithValue(ctx, sweepDryRunKey{}, d)
+	}
+
+	log.Printf("[DEBUG] Running Sweeper (%s) in region (%s)", name, region)
+
+	start := time.Now()
+
+	var err error
+	if s.ContextF != nil {
+		err = runSweeperContextFunc(ctx, region, name, s)
+	} else {
+		err = s.F(region)
+	}
+
+	elapsed := time.Since(start)
+
+	log.Printf("[DEBUG] Completed Sweeper (%s) in region (%s) in %s", name, region, elapsed)
+
+	result.DurationSeconds = elapsed.Seconds()
+
+	if d != nil {
+		result.WouldDelete = d.resources
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] Error running Sweeper (%s) in region (%s): %s", name, region, err)
+
+		result.Status = sweeperStatusFailed
+		result.Error = err.Error()
+		result.err = err
+
+		return result
+	}
+
+	result.Status = sweeperStatusPassed
+
+	return result
+}
+
+// runSweeperContextFunc configures the provider of a sweeper for a region,
+// if it has one, and runs its ContextF with the provider meta. Warning
+// diagnostics are logged, while error diagnostics are returned as an error.
+func runSweeperContextFunc(ctx context.Context, region string, name string, s *Sweeper) error {
+	meta, diags := sweeperMeta(ctx, region, s)
+
+	if !diags.HasError() {
+		diags = append(diags, s.ContextF(ctx, region, meta)...)
+	}
+
+	for _, warning := range diagutils.WarningDiags(diags).Warnings() {
+		log.Printf("[WARN] Sweeper (%s) in region (%s): %s", name, region, warning)
+	}
+
+	if diags.HasError() {
+		return diagutils.ErrorDiags(diags)
+	}
+
+	return nil
+}
+
+// sweeperMeta returns the meta of the provider of a sweeper, configured with
+// its ProviderConfig for the given region, or nil if it has no
+// ProviderFactory.
+func sweeperMeta(ctx context.Context, region string, s *Sweeper) (interface{}, diag.Diagnostics) {
+	if s.ProviderFactory == nil {
+		return nil, nil
+	}
+
+	p, err := s.ProviderFactory()
+	if err != nil {
+		return nil, diag.Errorf("unable to create provider: %s", err)
+	}
+
+	config := make(map[string]interface{})
+	if s.ProviderConfig != nil {
+		config = s.ProviderConfig(region)
+	}
+
+	diags := p.Configure(ctx, terraform.NewResourceConfigRaw(config))
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	return p.Meta(), diags
+}
+
+// writeSweepReport writes the given report to a file, in the JUnit XML
+// format if the file has the .xml extension and in JSON otherwise.
+func writeSweepReport(path string, report *sweepReport) error {
+	var b []byte
+	var err error
+
+	if strings.EqualFold(filepath.Ext(path), ".xml") {
+		b, err = xml.MarshalIndent(report.junit(), "", "  ")
+		b = append([]byte(xml.Header), b...)
+	} else {
+		b, err = json.MarshalIndent(report, "", "  ")
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to encode sweep report: %w", err)
+	}
+
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return fmt.Errorf("unable to write sweep report: %w", err)
+	}
+
+	return nil
+}
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a JUnit XML test suite, which is the report of the
+// sweepers of a region.
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a JUnit XML test case, which is the report of a sweeper
+// in a region.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage is the failure or skipped element of a JUnit XML test case.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// junit returns the report in the JUnit XML format, with a test suite for
+// each region and a test case for each sweeper. The resources which would
+// be deleted in a dry run are written to the system output of the test case.
+func (r *sweepReport) junit() junitTestSuites {
+	var suites junitTestSuites
+
+	for _, region := range r.Regions {
+		suite := junitTestSuite{
+			Name:  region.Region,
+			Tests: len(region.Sweepers),
+			Time:  fmt.Sprintf("%.3f", region.DurationSeconds),
+		}
+
+		for _, sweeper := range region.Sweepers {
+			testCase := junitTestCase{
+				Name:      sweeper.Name,
+				ClassName: region.Region,
+				Time:      fmt.Sprintf("%.3f", sweeper.DurationSeconds),
+			}
+
+			switch sweeper.Status {
+			case sweeperStatusFailed:
+				suite.Failures++
+				testCase.Failure = &junitMessage{Message: sweeper.Error}
+			case sweeperStatusSkipped:
+				suite.Skipped++
+				testCase.Skipped = &junitMessage{Message: sweeper.Error}
+			}
+
+			if len(sweeper.WouldDelete) > 0 {
+				testCase.SystemOut = "Would delete:\n" + strings.Join(sweeper.WouldDelete, "\n")
+			}
+
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		suites.TestSuites = append(suites.TestSuites, suite)
+	}
+
+	return suites
+}
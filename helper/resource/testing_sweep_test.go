@@ -0,0 +1,539 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRunSweepers_Parallel(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var order []string
+
+	// Both independent sweepers must be running at the same time for either
+	// of them to complete.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	independent := func(name string) SweeperFunc {
+		return func(string) error {
+			wg.Done()
+			wg.Wait()
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, name)
+
+			return nil
+		}
+	}
+
+	sweepers := map[string]*Sweeper{
+		"aws_one": {
+			Name: "aws_one",
+			F:    independent("aws_one"),
+		},
+		"aws_two": {
+			Name: "aws_two",
+			F:    independent("aws_two"),
+		},
+		"aws_top": {
+			Name:         "aws_top",
+			Dependencies: []string{"aws_one", "aws_two"},
+			F: func(string) error {
+				mu.Lock()
+				defer mu.Unlock()
+
+				order = append(order, "aws_top")
+
+				return nil
+			},
+		},
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if _, err := runSweepers(context.Background(), []string{"test"}, sweepers, sweepOptions{parallelism: 2}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for independent sweepers to run concurrently")
+	}
+
+	if len(order) != 3 || order[2] != "aws_top" {
+		t.Fatalf("expected aws_top to run after its dependencies, got: %v", order)
+	}
+}
+
+func TestRunSweepers_Errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		sweepers        map[string]*Sweeper
+		allowFailures   bool
+		expectedStatus  map[string]string
+		expectedErrText string
+	}{
+		"missing dependency": {
+			sweepers: map[string]*Sweeper{
+				"aws_top": {
+					Name:         "aws_top",
+					Dependencies: []string{"aws_missing"},
+					F:            mockSweeperFunc,
+				},
+			},
+			expectedStatus: map[string]string{
+				"aws_top": sweeperStatusFailed,
+			},
+			expectedErrText: "sweeper (aws_top) has dependency (aws_missing), but that sweeper was not found",
+		},
+		"missing dependency allow failures": {
+			sweepers: map[string]*Sweeper{
+				"aws_sub": {
+					Name:         "aws_sub",
+					Dependencies: []string{"aws_missing"},
+					F:            mockSweeperFunc,
+				},
+				"aws_top": {
+					Name:         "aws_top",
+					Dependencies: []string{"aws_sub"},
+					F:            mockSweeperFunc,
+				},
+			},
+			allowFailures: true,
+			expectedStatus: map[string]string{
+				"aws_sub": sweeperStatusFailed,
+				"aws_top": sweeperStatusPassed,
+			},
+			expectedErrText: "at least one sweeper failed",
+		},
+		"dependency cycle": {
+			sweepers: map[string]*Sweeper{
+				"aws_one": {
+					Name:         "aws_one",
+					Dependencies: []string{"aws_two"},
+					F:            mockSweeperFunc,
+				},
+				"aws_two": {
+					Name:         "aws_two",
+					Dependencies: []string{"aws_one"},
+					F:            mockSweeperFunc,
+				},
+			},
+			expectedStatus: map[string]string{
+				"aws_one": sweeperStatusFailed,
+				"aws_two": sweeperStatusFailed,
+			},
+			expectedErrText: "sweeper (aws_one) has dependencies which were never run",
+		},
+		"failure skips remaining": {
+			sweepers: map[string]*Sweeper{
+				"aws_one": {
+					Name: "aws_one",
+					F:    mockFailingSweeperFunc,
+				},
+				"aws_two": {
+					Name: "aws_two",
+					F:    mockSweeperFunc,
+				},
+			},
+			expectedStatus: map[string]string{
+				"aws_one": sweeperStatusFailed,
+				"aws_two": sweeperStatusSkipped,
+			},
+			expectedErrText: "sweeper (aws_one) for region (test) failed: failing sweeper",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := runSweepers(context.Background(), []string{"test"}, testCase.sweepers, sweepOptions{allowFailures: testCase.allowFailures})
+
+			if err == nil || !strings.Contains(err.Error(), testCase.expectedErrText) {
+				t.Fatalf("expected error containing %q, got: %v", testCase.expectedErrText, err)
+			}
+
+			status := make(map[string]string)
+			for _, sweeper := range report.Regions[0].Sweepers {
+				status[sweeper.Name] = sweeper.Status
+			}
+
+			if diff := cmp.Diff(testCase.expectedStatus, status); diff != "" {
+				t.Errorf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestRunSweepers_DryRun(t *testing.T) {
+	t.Parallel()
+
+	var deleted []string
+
+	sweepers := map[string]*Sweeper{
+		"aws_context": {
+			Name: "aws_context",
+			ContextF: func(ctx context.Context, region string, _ interface{}) diag.Diagnostics {
+				for _, id := range []string{"one", "two"} {
+					if SweepDryRun(ctx) {
+						SweepWouldDelete(ctx, "aws_thing "+region+"/"+id)
+
+						continue
+					}
+
+					deleted = append(deleted, id)
+				}
+
+				return nil
+			},
+		},
+		"aws_legacy": {
+			Name: "aws_legacy",
+			F: func(string) error {
+				return errors.New("legacy sweeper should not run in a dry run")
+			},
+		},
+	}
+
+	report, err := runSweepers(context.Background(), []string{"test"}, sweepers, sweepOptions{dryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(deleted) != 0 {
+		t.Fatalf("expected no deletions in a dry run, got: %v", deleted)
+	}
+
+	expected := []sweepSweeperReport{
+		{
+			Name:        "aws_context",
+			Status:      sweeperStatusPassed,
+			WouldDelete: []string{"aws_thing test/one", "aws_thing test/two"},
+		},
+		{
+			Name:   "aws_legacy",
+			Status: sweeperStatusSkipped,
+			Error:  "sweeper does not support dry runs",
+		},
+	}
+
+	got := report.Regions[0].Sweepers
+	for i := range got {
+		got[i].DurationSeconds = 0
+	}
+
+	if diff := cmp.Diff(expected, got, cmp.AllowUnexported(sweepSweeperReport{})); diff != "" {
+		t.Errorf("unexpected difference: %s", diff)
+	}
+
+	if !report.DryRun {
+		t.Error("expected the report to be a dry run")
+	}
+
+	_, err = runSweepers(context.Background(), []string{"test"}, sweepers, sweepOptions{})
+	if err == nil {
+		t.Fatal("expected the legacy sweeper to run and fail outside of a dry run")
+	}
+
+	if diff := cmp.Diff([]string{"one", "two"}, deleted); diff != "" {
+		t.Errorf("unexpected difference: %s", diff)
+	}
+}
+
+func TestRunSweepers_ContextF(t *testing.T) {
+	t.Parallel()
+
+	type client struct {
+		region string
+	}
+
+	providerFactory := func() (*schema.Provider, error) {
+		return &schema.Provider{
+			Schema: map[string]*schema.Schema{
+				"region": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+			ConfigureContextFunc: func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+				region := d.Get("region").(string)
+
+				if region == "invalid" {
+					return nil, diag.Errorf("invalid region")
+				}
+
+				return &client{region: region}, nil
+			},
+		}, nil
+	}
+
+	var mu sync.Mutex
+	regions := make(map[string]string)
+
+	sweepers := map[string]*Sweeper{
+		"examplecloud_thing": {
+			Name: "examplecloud_thing",
+			ContextF: func(_ context.Context, region string, meta interface{}) diag.Diagnostics {
+				mu.Lock()
+				defer mu.Unlock()
+
+				regions[region] = meta.(*client).region
+
+				return diag.Diagnostics{
+					{
+						Severity: diag.Warning,
+						Summary:  "thing not swept",
+					},
+				}
+			},
+			ProviderFactory: providerFactory,
+			ProviderConfig: func(region string) map[string]interface{} {
+				return map[string]interface{}{
+					"region": region,
+				}
+			},
+		},
+	}
+
+	if _, err := runSweepers(context.Background(), []string{"us-east-1", "us-west-2"}, sweepers, sweepOptions{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"us-east-1": "us-east-1",
+		"us-west-2": "us-west-2",
+	}
+
+	if diff := cmp.Diff(expected, regions); diff != "" {
+		t.Errorf("unexpected difference: %s", diff)
+	}
+
+	report, err := runSweepers(context.Background(), []string{"invalid"}, sweepers, sweepOptions{})
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	if got, expected := report.Regions[0].Sweepers[0].Error, "Error: invalid region"; got != expected {
+		t.Errorf("expected error %q, got %q", expected, got)
+	}
+
+	if _, ok := regions["invalid"]; ok {
+		t.Error("expected the sweeper not to run when the provider cannot be configured")
+	}
+}
+
+func TestRunSweepers_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sweepers := map[string]*Sweeper{
+		"aws_sub": {
+			Name: "aws_sub",
+			ContextF: func(ctx context.Context, _ string, _ interface{}) diag.Diagnostics {
+				cancel()
+
+				<-ctx.Done()
+
+				return diag.FromErr(ctx.Err())
+			},
+		},
+		"aws_dependent": {
+			Name:         "aws_dependent",
+			Dependencies: []string{"aws_sub"},
+			ContextF: func(_ context.Context, _ string, _ interface{}) diag.Diagnostics {
+				return nil
+			},
+		},
+		"aws_top": {
+			Name:         "aws_top",
+			Dependencies: []string{"aws_sub"},
+			F:            mockSweeperFunc,
+		},
+	}
+
+	report, err := runSweepers(ctx, []string{"test"}, sweepers, sweepOptions{allowFailures: true})
+	if err == nil || !strings.Contains(err.Error(), "sweepers for region (test) interrupted: context canceled") {
+		t.Fatalf("expected interrupted error, got: %v", err)
+	}
+
+	status := make(map[string]string)
+	for _, sweeper := range report.Regions[0].Sweepers {
+		status[sweeper.Name] = sweeper.Status
+	}
+
+	expected := map[string]string{
+		"aws_dependent": sweeperStatusSkipped,
+		"aws_sub":       sweeperStatusFailed,
+		"aws_top":       sweeperStatusSkipped,
+	}
+
+	if diff := cmp.Diff(expected, status); diff != "" {
+		t.Errorf("unexpected difference: %s", diff)
+	}
+}
+
+func TestSweepContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := sweepContext(0)
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("expected no deadline without a timeout")
+	}
+
+	ctx, cancel = sweepContext(time.Hour)
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("expected a deadline with a timeout")
+	}
+}
+
+func TestSweepTimeout(t *testing.T) {
+	t.Parallel()
+
+	if got := sweepTimeout(time.Hour); got != time.Hour {
+		t.Errorf("expected the override, got %s", got)
+	}
+
+	expected := flag.Lookup("test.timeout").Value.(flag.Getter).Get().(time.Duration)
+
+	if got := sweepTimeout(0); got != expected {
+		t.Errorf("expected the go test timeout %s, got %s", expected, got)
+	}
+}
+
+func TestWriteSweepReport(t *testing.T) {
+	t.Parallel()
+
+	report := &sweepReport{
+		Regions: []sweepRegionReport{
+			{
+				Region:          "us-east-1",
+				DurationSeconds: 1.5,
+				Sweepers: []sweepSweeperReport{
+					{
+						Name:            "aws_one",
+						Status:          sweeperStatusPassed,
+						DurationSeconds: 1,
+						WouldDelete:     []string{"aws_thing one"},
+					},
+					{
+						Name:            "aws_two",
+						Status:          sweeperStatusFailed,
+						Error:           "failing sweeper",
+						DurationSeconds: 0.5,
+					},
+					{
+						Name:   "aws_three",
+						Status: sweeperStatusSkipped,
+						Error:  "not run after an earlier sweeper failed or the sweepers were interrupted",
+					},
+				},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "report.json")
+	if err := writeSweepReport(jsonPath, report); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	b, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var gotJSON sweepReport
+	if err := json.Unmarshal(b, &gotJSON); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff(report, &gotJSON, cmp.AllowUnexported(sweepSweeperReport{})); diff != "" {
+		t.Errorf("unexpected JSON difference: %s", diff)
+	}
+
+	xmlPath := filepath.Join(dir, "report.xml")
+	if err := writeSweepReport(xmlPath, report); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	b, err = os.ReadFile(xmlPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var gotXML junitTestSuites
+	if err := xml.Unmarshal(b, &gotXML); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedXML := junitTestSuites{
+		XMLName: xml.Name{Local: "testsuites"},
+		TestSuites: []junitTestSuite{
+			{
+				Name:     "us-east-1",
+				Tests:    3,
+				Failures: 1,
+				Skipped:  1,
+				Time:     "1.500",
+				TestCases: []junitTestCase{
+					{
+						Name:      "aws_one",
+						ClassName: "us-east-1",
+						Time:      "1.000",
+						SystemOut: "Would delete:\naws_thing one",
+					},
+					{
+						Name:      "aws_two",
+						ClassName: "us-east-1",
+						Time:      "0.500",
+						Failure:   &junitMessage{Message: "failing sweeper"},
+					},
+					{
+						Name:      "aws_three",
+						ClassName: "us-east-1",
+						Time:      "0.000",
+						Skipped:   &junitMessage{Message: "not run after an earlier sweeper failed or the sweepers were interrupted"},
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expectedXML, gotXML); diff != "" {
+		t.Errorf("unexpected XML difference: %s", diff)
+	}
+}
@@ -4,6 +4,7 @@
 package resource
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -832,8 +833,8 @@ func TestRunSweepers(t *testing.T) {
 		sweeperFuncs = map[string]*Sweeper{}
 
 		t.Run(tc.Name, func(t *testing.T) {
-			sweeperRunList, err := runSweepers([]string{"test"}, tc.Sweepers, tc.AllowFailures)
-			fmt.Printf("sweeperRunList: %#v\n", sweeperRunList)
+			report, err := runSweepers(context.Background(), []string{"test"}, tc.Sweepers, sweepOptions{allowFailures: tc.AllowFailures})
+			fmt.Printf("report: %#v\n", report)
 
 			if err == nil && tc.ExpectError {
 				t.Fatalf("expected error, did not receive error")
@@ -843,10 +844,12 @@ func TestRunSweepers(t *testing.T) {
 				t.Fatalf("did not expect error, received error: %s", err)
 			}
 
-			// get list of tests ran from sweeperRunList keys
+			// get list of tests ran from the region report
 			var keys []string
-			for k := range sweeperRunList["test"] {
-				keys = append(keys, k)
+			for _, sweeper := range report.Regions[0].Sweepers {
+				if sweeper.Status != sweeperStatusSkipped {
+					keys = append(keys, sweeper.Name)
+				}
 			}
 
 			sort.Strings(keys)
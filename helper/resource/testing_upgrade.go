@@ -0,0 +1,237 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/mitchellh/go-testing-interface"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugintest"
+)
+
+// UpgradeFrom is a previous version of a provider under test, which is
+// installed from a local filesystem mirror to test that resources created
+// with it are upgraded by the in-tree provider without any differences.
+type UpgradeFrom struct {
+	// ProviderName is the name of the provider under test in the
+	// ProviderFactories, ProtoV5ProviderFactories or
+	// ProtoV6ProviderFactories of the TestCase, such as examplecloud.
+	ProviderName string
+
+	// Source is the source address of the provider under test, which must
+	// match the configurations of the TestSteps. Defaults to
+	// registry.terraform.io/hashicorp/ followed by ProviderName.
+	Source string
+
+	// Version is the exact previous version of the provider, such as 1.2.0.
+	Version string
+
+	// MirrorDir is the path of a local filesystem mirror directory which
+	// contains the package of the previous version of the provider, in the
+	// packed or unpacked layout written by the terraform providers mirror
+	// command. No other version of the provider is installed from it. An
+	// existing Terraform CLI configuration file must not configure provider
+	// installation, and other providers are installed from their origin
+	// registries.
+	MirrorDir string
+
+	// ExpectStateUpgrade requires the in-tree provider to upgrade the state
+	// of at least one resource created with the previous version, which is
+	// the case when its schema version is greater, so that its
+	// StateUpgraders are run.
+	ExpectStateUpgrade bool
+}
+
+// source returns the source address of the provider.
+func (u UpgradeFrom) source() string {
+	if u.Source != "" {
+		return u.Source
+	}
+
+	return "registry.terraform.io/hashicorp/" + u.ProviderName
+}
+
+// without returns the provider factories except the given provider.
+func (f *providerFactories) without(name string) *providerFactories {
+	result := &providerFactories{
+		legacy:  make(sdkProviderFactories),
+		protov5: make(protov5ProviderFactories),
+		protov6: make(protov6ProviderFactories),
+	}
+
+	for n, factory := range f.legacy {
+		if n != name {
+			result.legacy[n] = factory
+		}
+	}
+
+	for n, factory := range f.protov5 {
+		if n != name {
+			result.protov5[n] = factory
+		}
+	}
+
+	for n, factory := range f.protov6 {
+		if n != name {
+			result.protov6[n] = factory
+		}
+	}
+
+	return result
+}
+
+// testStepNewUpgrade runs a Config mode TestStep with the previous version
+// of the provider of the TestCase UpgradeFrom, without its checks, as they
+// are written for the in-tree provider. Unless the TestStep destroys the
+// resources, it then plans the configuration with the in-tree provider,
+// which upgrades the state, and requires the plan to be empty.
+//
+// The plan is not applied, so the previous version of the provider can run
+// the following TestSteps on the state it created.
+func testStepNewUpgrade(ctx context.Context, t testing.T, c TestCase, wd *plugintest.WorkingDir, step TestStep, cfg plugintest.WorkingDirConfig, providers *providerFactories) error {
+	t.Helper()
+
+	upgrade := c.UpgradeFrom
+	previousProviders := providers.without(upgrade.ProviderName)
+
+	logging.HelperResourceDebug(ctx, fmt.Sprintf("Running TestStep with provider %s version %s", upgrade.ProviderName, upgrade.Version))
+
+	err := wd.SetConfig(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("Error setting config: %w", err)
+	}
+
+	err = wd.SetProviderMirror(ctx, upgrade.MirrorDir, upgrade.source(), upgrade.Version)
+	if err != nil {
+		return fmt.Errorf("Error setting provider mirror: %w", err)
+	}
+
+	// Always install the in-tree provider again afterwards, including for
+	// the post-test destroy after errors
+	defer func() {
+		if err := wd.UnsetProviderMirror(ctx); err != nil {
+			logging.HelperResourceError(ctx, "Error unsetting provider mirror", map[string]interface{}{logging.KeyError: err})
+		}
+	}()
+
+	err = runProviderCommand(ctx, t, func() error {
+		return wd.Init(ctx)
+	}, wd, previousProviders)
+	if err != nil {
+		return fmt.Errorf("Error running init with provider %s version %s: %w", upgrade.ProviderName, upgrade.Version, err)
+	}
+
+	previousCase := c
+	previousCase.IDRefreshName = ""
+
+	previousStep := step
+	previousStep.Check = nil
+	previousStep.ConfigPlanChecks = ConfigPlanChecks{}
+	previousStep.ConfigStateChecks = nil
+
+	err = testStepNewConfig(ctx, t, previousCase, wd, previousStep, cfg, previousProviders)
+	if err != nil {
+		return fmt.Errorf("Error running TestStep with provider %s version %s: %w", upgrade.ProviderName, upgrade.Version, err)
+	}
+
+	var previousState *tfjson.State
+	err = runProviderCommand(ctx, t, func() error {
+		var err error
+		previousState, err = wd.State(ctx)
+		return err
+	}, wd, previousProviders)
+	if err != nil {
+		return fmt.Errorf("Error retrieving state of provider %s version %s: %w", upgrade.ProviderName, upgrade.Version, err)
+	}
+
+	err = wd.UnsetProviderMirror(ctx)
+	if err != nil {
+		return fmt.Errorf("Error unsetting provider mirror: %w", err)
+	}
+
+	err = runProviderCommand(ctx, t, func() error {
+		return wd.Init(ctx)
+	}, wd, providers)
+	if err != nil {
+		return fmt.Errorf("Error running init with in-tree provider %s: %w", upgrade.ProviderName, err)
+	}
+
+	if step.Destroy {
+		return nil
+	}
+
+	logging.HelperResourceDebug(ctx, fmt.Sprintf("Running Terraform CLI plan with in-tree provider %s", upgrade.ProviderName))
+
+	err = runProviderCommand(ctx, t, func() error {
+		return wd.CreatePlan(ctx)
+	}, wd, providers)
+	if err != nil {
+		return fmt.Errorf("Error running upgrade plan: %w", err)
+	}
+
+	var plan *tfjson.Plan
+	err = runProviderCommand(ctx, t, func() error {
+		var err error
+		plan, err = wd.SavedPlan(ctx)
+		return err
+	}, wd, providers)
+	if err != nil {
+		return fmt.Errorf("Error retrieving upgrade plan: %w", err)
+	}
+
+	if !planIsEmpty(plan) && !step.ExpectNonEmptyPlan {
+		var stdout string
+		err = runProviderCommand(ctx, t, func() error {
+			var err error
+			stdout, err = wd.SavedPlanRawStdout(ctx)
+			return err
+		}, wd, providers)
+		if err != nil {
+			return fmt.Errorf("Error retrieving formatted upgrade plan output: %w", err)
+		}
+		return fmt.Errorf("After upgrading provider %s from version %s, the plan was not empty.\nstdout:\n\n%s", upgrade.ProviderName, upgrade.Version, stdout)
+	}
+
+	upgraded := upgradedResources(previousState, plan.PriorState)
+
+	if len(upgraded) > 0 {
+		logging.HelperResourceDebug(ctx, fmt.Sprintf("Upgraded state of resources: %s", strings.Join(upgraded, ", ")))
+	}
+
+	if upgrade.ExpectStateUpgrade && len(upgraded) == 0 {
+		return fmt.Errorf("Expected the in-tree provider %s to upgrade the state of at least one resource created with version %s, but no resource schema versions changed", upgrade.ProviderName, upgrade.Version)
+	}
+
+	return nil
+}
+
+// upgradedResources returns the addresses of the managed resource instances
+// whose schema version is greater in the upgraded state than in the prior
+// state.
+func upgradedResources(prior, upgraded *tfjson.State) []string {
+	priorResources := stateManagedResources(prior)
+
+	var result []string
+
+	for addr, r := range stateManagedResources(upgraded) {
+		priorResource, ok := priorResources[addr]
+		if !ok {
+			continue
+		}
+
+		if r.SchemaVersion > priorResource.SchemaVersion {
+			result = append(result, addr)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
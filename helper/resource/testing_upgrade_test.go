@@ -0,0 +1,87 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestUpgradedResources(t *testing.T) {
+	t.Parallel()
+
+	state := func(schemaVersions map[string]uint64) *tfjson.State {
+		var resources []*tfjson.StateResource
+
+		for addr, schemaVersion := range schemaVersions {
+			resources = append(resources, &tfjson.StateResource{
+				Address:       addr,
+				Mode:          tfjson.ManagedResourceMode,
+				SchemaVersion: schemaVersion,
+			})
+		}
+
+		return &tfjson.State{
+			Values: &tfjson.StateValues{
+				RootModule: &tfjson.StateModule{
+					Resources: resources,
+				},
+			},
+		}
+	}
+
+	prior := state(map[string]uint64{
+		"examplecloud_thing.one":   0,
+		"examplecloud_thing.two":   1,
+		"examplecloud_thing.three": 1,
+	})
+
+	upgraded := state(map[string]uint64{
+		"examplecloud_thing.one":   2,
+		"examplecloud_thing.two":   1,
+		"examplecloud_thing.three": 2,
+		"examplecloud_thing.four":  2,
+	})
+
+	got := upgradedResources(prior, upgraded)
+	expected := []string{"examplecloud_thing.one", "examplecloud_thing.three"}
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected difference: %s", diff)
+	}
+
+	if got := upgradedResources(nil, upgraded); len(got) != 0 {
+		t.Errorf("expected no upgraded resources without a prior state, got: %v", got)
+	}
+}
+
+func TestProviderFactoriesWithout(t *testing.T) {
+	t.Parallel()
+
+	providers := &providerFactories{
+		legacy: sdkProviderFactories{
+			"examplecloud": nil, // does not need to be real
+			"other":        nil, // does not need to be real
+		},
+		protov5: protov5ProviderFactories{
+			"examplecloud": nil, // does not need to be real
+		},
+	}
+
+	got := providers.without("examplecloud")
+
+	if _, ok := got.legacy["other"]; !ok || len(got.legacy) != 1 {
+		t.Errorf("expected only the other legacy provider, got: %v", got.legacy)
+	}
+
+	if len(got.protov5) != 0 || len(got.protov6) != 0 {
+		t.Errorf("expected no protocol providers, got: %v, %v", got.protov5, got.protov6)
+	}
+
+	if len(providers.legacy) != 2 {
+		t.Error("expected the provider factories to be unchanged")
+	}
+}
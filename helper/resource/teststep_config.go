@@ -0,0 +1,54 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugintest"
+)
+
+// hasConfig returns true if the TestStep has a configuration, set with any
+// of the Config, ConfigDirectory or ConfigFile fields.
+func (s TestStep) hasConfig() bool {
+	return s.Config != "" || s.ConfigDirectory != nil || s.ConfigFile != nil
+}
+
+// config returns the configuration of the TestStep, which is either the
+// Config merged with any necessary terraform configuration blocks, or the
+// directory or file returned by ConfigDirectory or ConfigFile, along with the
+// content of the variable definitions file of any ConfigVariables.
+func (s TestStep) config(ctx context.Context, testCase TestCase, req config.TestStepConfigRequest) (plugintest.WorkingDirConfig, error) {
+	var cfg plugintest.WorkingDirConfig
+
+	switch {
+	case s.ConfigDirectory != nil:
+		cfg.Directory = s.ConfigDirectory.Exec(req)
+
+		if cfg.Directory == "" {
+			return cfg, fmt.Errorf("TestStep ConfigDirectory returned an empty directory")
+		}
+	case s.ConfigFile != nil:
+		cfg.File = s.ConfigFile.Exec(req)
+
+		if cfg.File == "" {
+			return cfg, fmt.Errorf("TestStep ConfigFile returned an empty file")
+		}
+	default:
+		cfg.Raw = s.mergedConfig(ctx, testCase)
+	}
+
+	if s.ConfigVariables != nil {
+		variables, err := s.ConfigVariables.MarshalJSON()
+		if err != nil {
+			return cfg, fmt.Errorf("TestStep ConfigVariables: %w", err)
+		}
+
+		cfg.Variables = variables
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,105 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugintest"
+)
+
+func TestTestStepConfig(t *testing.T) {
+	t.Parallel()
+
+	testCase := TestCase{
+		ProviderFactories: map[string]func() (*schema.Provider, error){
+			"test": nil, // does not need to be real
+		},
+	}
+
+	req := config.TestStepConfigRequest{
+		StepNumber: 1,
+		TestName:   "TestAccThing_basic",
+	}
+
+	tests := map[string]struct {
+		testStep      TestStep
+		expected      plugintest.WorkingDirConfig
+		expectedError string
+	}{
+		"config": {
+			testStep: TestStep{
+				Config: `resource "test_thing" "test" {}`,
+			},
+			expected: plugintest.WorkingDirConfig{
+				Raw: `resource "test_thing" "test" {}`,
+			},
+		},
+		"configdirectory": {
+			testStep: TestStep{
+				ConfigDirectory: config.TestNameDirectory(),
+				ConfigVariables: config.Variables{
+					"name": config.StringVariable("test"),
+				},
+			},
+			expected: plugintest.WorkingDirConfig{
+				Directory: filepath.Join("testdata", "TestAccThing_basic"),
+				Variables: []byte(`{"name":"test"}`),
+			},
+		},
+		"configfile": {
+			testStep: TestStep{
+				ConfigFile: config.StaticFile("main.tf"),
+			},
+			expected: plugintest.WorkingDirConfig{
+				File: "main.tf",
+			},
+		},
+		"configdirectory-empty": {
+			testStep: TestStep{
+				ConfigDirectory: config.StaticDirectory(""),
+			},
+			expectedError: "TestStep ConfigDirectory returned an empty directory",
+		},
+		"configvariables-error": {
+			testStep: TestStep{
+				Config: "# not empty",
+				ConfigVariables: config.Variables{
+					"list": config.ListVariable(config.StringVariable("a"), config.BoolVariable(true)),
+				},
+			},
+			expectedError: `TestStep ConfigVariables: variable "list": list element 1: expected type string, got: bool`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.testStep.config(context.Background(), testCase, req)
+
+			if test.expectedError != "" {
+				if err == nil || err.Error() != test.expectedError {
+					t.Fatalf("expected error %q, got: %v", test.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
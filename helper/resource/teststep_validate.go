@@ -46,8 +46,13 @@ func (s TestStep) hasProviders(_ context.Context) bool {
 
 // validate ensures the TestStep is valid based on the following criteria:
 //
-//   - Config or ImportState or RefreshState is set.
-//   - Config and RefreshState are not both set.
+//   - Config, ConfigDirectory, ConfigFile, ImportState or RefreshState is
+//     set.
+//   - Only one of Config, ConfigDirectory and ConfigFile is set.
+//   - ConfigVariables are only set with Config, ConfigDirectory or
+//     ConfigFile.
+//   - Config, ConfigDirectory or ConfigFile and RefreshState are not both
+//     set.
 //   - RefreshState and Destroy are not both set.
 //   - RefreshState is not the first TestStep.
 //   - Providers are not specified (ExternalProviders,
@@ -59,18 +64,47 @@ func (s TestStep) hasProviders(_ context.Context) bool {
 //   - No overlapping ExternalProviders and ProviderFactories entries
 //   - ResourceName is not empty when ImportState is true, ImportStateIdFunc
 //     is not set, and ImportStateId is not set.
+//   - ConfigPlanChecks are only set with Config.
+//   - ConfigPlanChecks.PreApply is not set with PlanOnly.
+//   - ConfigStateChecks are only set with Config, and not with PlanOnly.
+//   - ImportStateKind and ImportStateIdentityVerify are only set with
+//     ImportState.
+//   - Import blocks are not used with ImportStatePersist.
+//   - ImportBlockWithResourceIdentity is not used with ImportStateId,
+//     ImportStateIdFunc, or ImportStateIdPrefix, and is used with
+//     ResourceName, as is ImportStateIdentityVerify.
 func (s TestStep) validate(ctx context.Context, req testStepValidateRequest) error {
 	ctx = logging.TestStepNumberContext(ctx, req.StepNumber)
 
 	logging.HelperResourceTrace(ctx, "Validating TestStep")
 
-	if s.Config == "" && !s.ImportState && !s.RefreshState {
+	if !s.hasConfig() && !s.ImportState && !s.RefreshState {
 		err := fmt.Errorf("TestStep missing Config or ImportState or RefreshState")
 		logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
 		return err
 	}
 
-	if s.Config != "" && s.RefreshState {
+	configCount := 0
+
+	for _, isSet := range []bool{s.Config != "", s.ConfigDirectory != nil, s.ConfigFile != nil} {
+		if isSet {
+			configCount++
+		}
+	}
+
+	if configCount > 1 {
+		err := fmt.Errorf("TestStep must only specify one of Config, ConfigDirectory, or ConfigFile")
+		logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
+		return err
+	}
+
+	if s.ConfigVariables != nil && !s.hasConfig() {
+		err := fmt.Errorf("TestStep ConfigVariables must only be specified with Config, ConfigDirectory, or ConfigFile")
+		logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
+		return err
+	}
+
+	if s.hasConfig() && s.RefreshState {
 		err := fmt.Errorf("TestStep cannot have Config and RefreshState")
 		logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
 		return err
@@ -116,12 +150,60 @@ func (s TestStep) validate(ctx context.Context, req testStepValidateRequest) err
 		return err
 	}
 
+	if s.ConfigPlanChecks.hasChecks() && (!s.hasConfig() || s.ImportState) {
+		err := fmt.Errorf("TestStep ConfigPlanChecks must only be specified with Config")
+		logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
+		return err
+	}
+
+	if len(s.ConfigPlanChecks.PreApply) > 0 && s.PlanOnly {
+		err := fmt.Errorf("TestStep ConfigPlanChecks.PreApply cannot be run with PlanOnly")
+		logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
+		return err
+	}
+
+	if len(s.ConfigStateChecks) > 0 && (!s.hasConfig() || s.ImportState) {
+		err := fmt.Errorf("TestStep ConfigStateChecks must only be specified with Config")
+		logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
+		return err
+	}
+
+	if len(s.ConfigStateChecks) > 0 && s.PlanOnly {
+		err := fmt.Errorf("TestStep ConfigStateChecks cannot be run with PlanOnly")
+		logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
+		return err
+	}
+
+	if !s.ImportState && (s.ImportStateKind != ImportCommandWithID || s.ImportStateIdentityVerify) {
+		err := fmt.Errorf("TestStep ImportStateKind and ImportStateIdentityVerify must only be specified with ImportState")
+		logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
+		return err
+	}
+
 	if s.ImportState {
 		if s.ImportStateId == "" && s.ImportStateIdFunc == nil && s.ResourceName == "" {
 			err := fmt.Errorf("TestStep ImportState must be specified with ImportStateId, ImportStateIdFunc, or ResourceName")
 			logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
 			return err
 		}
+
+		if s.ImportStateKind != ImportCommandWithID && s.ImportStatePersist {
+			err := fmt.Errorf("TestStep ImportStatePersist cannot be used with import blocks")
+			logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
+			return err
+		}
+
+		if s.ImportStateKind == ImportBlockWithResourceIdentity && (s.ImportStateId != "" || s.ImportStateIdFunc != nil || s.ImportStateIdPrefix != "") {
+			err := fmt.Errorf("TestStep ImportBlockWithResourceIdentity cannot be used with ImportStateId, ImportStateIdFunc, or ImportStateIdPrefix")
+			logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
+			return err
+		}
+
+		if (s.ImportStateKind == ImportBlockWithResourceIdentity || s.ImportStateIdentityVerify) && s.ResourceName == "" {
+			err := fmt.Errorf("TestStep ImportBlockWithResourceIdentity and ImportStateIdentityVerify must be specified with ResourceName")
+			logging.HelperResourceError(ctx, "TestStep validation error", map[string]interface{}{logging.KeyError: err})
+			return err
+		}
 	}
 
 	return nil
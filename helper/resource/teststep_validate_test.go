@@ -12,7 +12,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/knownvalue"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/plancheck"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/statecheck"
 )
 
 func TestTestStepHasProviders(t *testing.T) {
@@ -110,6 +114,117 @@ func TestTestStepValidate(t *testing.T) {
 			testStepValidateRequest: testStepValidateRequest{},
 			expectedError:           fmt.Errorf("TestStep cannot have ImportState and RefreshState in same step"),
 		},
+		"configplanchecks-without-config": {
+			testStep: TestStep{
+				RefreshState: true,
+				ConfigPlanChecks: ConfigPlanChecks{
+					PostApplyPreRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				StepNumber:           2,
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep ConfigPlanChecks must only be specified with Config"),
+		},
+		"configplanchecks-preapply-planonly": {
+			testStep: TestStep{
+				Config:   "# not empty",
+				PlanOnly: true,
+				ConfigPlanChecks: ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep ConfigPlanChecks.PreApply cannot be run with PlanOnly"),
+		},
+		"config-and-configdirectory": {
+			testStep: TestStep{
+				Config:          "# not empty",
+				ConfigDirectory: config.TestNameDirectory(),
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep must only specify one of Config, ConfigDirectory, or ConfigFile"),
+		},
+		"configdirectory-and-configfile": {
+			testStep: TestStep{
+				ConfigDirectory: config.TestNameDirectory(),
+				ConfigFile:      config.TestNameFile("main.tf"),
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep must only specify one of Config, ConfigDirectory, or ConfigFile"),
+		},
+		"configdirectory-and-refreshstate": {
+			testStep: TestStep{
+				ConfigDirectory: config.TestNameDirectory(),
+				RefreshState:    true,
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				StepNumber:           2,
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep cannot have Config and RefreshState"),
+		},
+		"configfile-with-configvariables": {
+			testStep: TestStep{
+				ConfigFile: config.TestNameFile("main.tf"),
+				ConfigVariables: config.Variables{
+					"name": config.StringVariable("test"),
+				},
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+		},
+		"configvariables-without-config": {
+			testStep: TestStep{
+				ImportState:  true,
+				ResourceName: "test_resource.test",
+				ConfigVariables: config.Variables{
+					"name": config.StringVariable("test"),
+				},
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep ConfigVariables must only be specified with Config, ConfigDirectory, or ConfigFile"),
+		},
+		"configstatechecks-without-config": {
+			testStep: TestStep{
+				RefreshState: true,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.NotNull()),
+				},
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				StepNumber:           2,
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep ConfigStateChecks must only be specified with Config"),
+		},
+		"configstatechecks-planonly": {
+			testStep: TestStep{
+				Config:   "# not empty",
+				PlanOnly: true,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.NotNull()),
+				},
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep ConfigStateChecks cannot be run with PlanOnly"),
+		},
 		"destroy-and-refreshstate-both-true": {
 			testStep: TestStep{
 				Destroy:      true,
@@ -152,6 +267,72 @@ func TestTestStepValidate(t *testing.T) {
 			},
 			expectedError: fmt.Errorf("TestStep ImportState must be specified with ImportStateId, ImportStateIdFunc, or ResourceName"),
 		},
+		"importstatekind-missing-importstate": {
+			testStep: TestStep{
+				Config:          "# not empty",
+				ImportStateKind: ImportBlockWithID,
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep ImportStateKind and ImportStateIdentityVerify must only be specified with ImportState"),
+		},
+		"importstateidentityverify-missing-importstate": {
+			testStep: TestStep{
+				Config:                    "# not empty",
+				ImportStateIdentityVerify: true,
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep ImportStateKind and ImportStateIdentityVerify must only be specified with ImportState"),
+		},
+		"importstatekind-importblock-importstatepersist": {
+			testStep: TestStep{
+				ImportState:        true,
+				ImportStateKind:    ImportBlockWithID,
+				ImportStatePersist: true,
+				ResourceName:       "test_resource.test",
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep ImportStatePersist cannot be used with import blocks"),
+		},
+		"importstatekind-identity-importstateid": {
+			testStep: TestStep{
+				ImportState:     true,
+				ImportStateId:   "test",
+				ImportStateKind: ImportBlockWithResourceIdentity,
+				ResourceName:    "test_resource.test",
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep ImportBlockWithResourceIdentity cannot be used with ImportStateId, ImportStateIdFunc, or ImportStateIdPrefix"),
+		},
+		"importstateidentityverify-missing-resourcename": {
+			testStep: TestStep{
+				ImportState:               true,
+				ImportStateId:             "test",
+				ImportStateIdentityVerify: true,
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+			expectedError: fmt.Errorf("TestStep ImportBlockWithResourceIdentity and ImportStateIdentityVerify must be specified with ResourceName"),
+		},
+		"importstatekind-valid": {
+			testStep: TestStep{
+				ImportState:               true,
+				ImportStateKind:           ImportBlockWithResourceIdentity,
+				ImportStateIdentityVerify: true,
+				ResourceName:              "test_resource.test",
+			},
+			testStepValidateRequest: testStepValidateRequest{
+				TestCaseHasProviders: true,
+			},
+		},
 		"protov5providerfactories-testcase-providers": {
 			testStep: TestStep{
 				Config: "# not empty",
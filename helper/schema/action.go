@@ -0,0 +1,199 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Action represents a provider action in Terraform. Actions are day-2
+// operations, such as rotating a key or rebooting an instance, which
+// practitioners declare in configuration and Terraform invokes during
+// apply. Actions have no state.
+type Action struct {
+	// Schema is the structure and type information for the configuration
+	// of this action. This field, or SchemaFunc, is required.
+	Schema map[string]*Schema
+
+	// SchemaFunc is the structure and type information for the
+	// configuration of this action. This field, or Schema, is required.
+	// Use this field instead of Schema to prevent storing all schema
+	// information in memory for the lifecycle of a provider.
+	SchemaFunc func() map[string]*Schema
+
+	// Description is used as the description for docs, the language server
+	// and other user facing usage. It can be plain-text or markdown
+	// depending on the global DescriptionKind setting.
+	Description string
+
+	// DeprecationMessage, when non-empty, marks the action as deprecated
+	// and is returned as a warning during validation.
+	DeprecationMessage string
+
+	// LinkedResources declares the managed resource types of this provider
+	// which the action operates on, such as the instance type for a
+	// "reboot instance" action. Provider.InternalValidate verifies that
+	// each type exists in the ResourcesMap.
+	//
+	// The declarations are documentation for practitioners and tooling.
+	// The current version of the plugin protocol used by this SDK does not
+	// send them to Terraform.
+	LinkedResources []string
+
+	// Plan is called when Terraform plans the action, which is the
+	// opportunity to validate the configuration against the remote system
+	// before any changes are made. This field is optional.
+	Plan PlanActionFunc
+
+	// Invoke is called when Terraform invokes the action during apply.
+	// This field is required.
+	Invoke InvokeActionFunc
+}
+
+// PlanActionFunc is the function used to plan an Action.
+type PlanActionFunc func(context.Context, PlanActionRequest, *PlanActionResponse)
+
+// InvokeActionFunc is the function used to invoke an Action.
+type InvokeActionFunc func(context.Context, InvokeActionRequest, *InvokeActionResponse)
+
+type PlanActionRequest struct {
+	// ResourceData is used to query the configuration of the action. Values
+	// may be unknown during plan.
+	ResourceData *ResourceData
+
+	// Meta is the value returned by the provider configuration function,
+	// conventionally used to store API clients.
+	Meta interface{}
+
+	// DeferralAllowed indicates whether the Terraform request planning the
+	// action allows a deferred response.
+	//
+	// NOTE: This functionality is related to deferred action support, which is currently experimental and is subject
+	// to change or break without warning. It is not protected by version compatibility guarantees.
+	DeferralAllowed bool
+}
+
+type PlanActionResponse struct {
+	// Diagnostics report errors or warnings related to planning the
+	// action.
+	Diagnostics diag.Diagnostics
+
+	// Deferred indicates that Terraform should defer the action. This field
+	// can only be set if `(schema.PlanActionRequest).DeferralAllowed` is
+	// true.
+	//
+	// NOTE: This functionality is related to deferred action support, which is currently experimental and is subject
+	// to change or break without warning. It is not protected by version compatibility guarantees.
+	Deferred *Deferred
+}
+
+type InvokeActionRequest struct {
+	// ResourceData is used to query the configuration of the action.
+	ResourceData *ResourceData
+
+	// Meta is the value returned by the provider configuration function,
+	// conventionally used to store API clients.
+	Meta interface{}
+
+	// SendProgress sends a progress message to Terraform, which is shown
+	// to the practitioner while the action is running. It can be called
+	// any number of times before Invoke returns.
+	SendProgress func(message string)
+}
+
+type InvokeActionResponse struct {
+	// Diagnostics report errors or warnings related to invoking the
+	// action.
+	Diagnostics diag.Diagnostics
+}
+
+// SchemaMap returns the schema information for this action whether it is
+// defined via the SchemaFunc field or Schema field. The SchemaFunc field, if
+// defined, takes precedence over the Schema field.
+func (a *Action) SchemaMap() map[string]*Schema {
+	if a.SchemaFunc != nil {
+		return a.SchemaFunc()
+	}
+
+	return a.Schema
+}
+
+// CoreConfigSchema lowers the action schema to the schema model expected by
+// Terraform core. Unlike managed resources and data sources, no implicit
+// "id" attribute is added.
+func (a *Action) CoreConfigSchema() *configschema.Block {
+	block := schemaMap(a.SchemaMap()).CoreConfigSchema()
+
+	desc := a.Description
+	descKind := configschema.StringKind(DescriptionKind)
+	if desc == "" {
+		// fallback to plain text if empty
+		descKind = configschema.StringPlain
+	}
+
+	block.Description = desc
+	block.DescriptionKind = descKind
+	block.Deprecated = a.DeprecationMessage != ""
+	block.DeprecationMessage = a.DeprecationMessage
+
+	return block
+}
+
+// Validate validates the action configuration against the schema.
+func (a *Action) Validate(c *terraform.ResourceConfig) diag.Diagnostics {
+	diags := schemaMap(a.SchemaMap()).Validate(c)
+
+	if a.DeprecationMessage != "" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Deprecated Action",
+			Detail:   a.DeprecationMessage,
+		})
+	}
+
+	return diags
+}
+
+// InternalValidate should be called to validate the structure of the action.
+//
+// Provider.InternalValidate() will automatically call this for all of the
+// actions it manages, so you don't need to call this manually if it is part
+// of a Provider.
+func (a *Action) InternalValidate() error {
+	if a == nil {
+		return errors.New("action is nil")
+	}
+
+	if a.SchemaFunc != nil && a.Schema != nil {
+		return errors.New("SchemaFunc and Schema should not both be set")
+	}
+
+	if a.Invoke == nil {
+		return errors.New("Invoke must be implemented")
+	}
+
+	sm := schemaMap(a.SchemaMap())
+
+	if sm.hasWriteOnly() {
+		return errors.New("cannot contain write-only attributes")
+	}
+
+	for k, s := range sm {
+		if s.ForceNew {
+			return fmt.Errorf("%s: ForceNew is not supported for actions", k)
+		}
+
+		if s.Computed {
+			return fmt.Errorf("%s: Computed is not supported for actions", k)
+		}
+	}
+
+	return sm.InternalValidate(sm)
+}
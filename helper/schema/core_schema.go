@@ -98,6 +98,8 @@ func (m schemaMap) CoreConfigSchema() *configschema.Block {
 			ret.Attributes[name] = schema.coreConfigSchemaAttribute()
 		case SchemaConfigModeBlock:
 			ret.BlockTypes[name] = schema.coreConfigSchemaBlock()
+		case SchemaConfigModeNestedAttr:
+			ret.Attributes[name] = schema.coreConfigSchemaNestedAttribute()
 		default: // SchemaConfigModeAuto, or any other invalid value
 			if schema.Computed && !schema.Optional {
 				// Computed-only schemas are always handled as attributes,
@@ -178,6 +180,34 @@ func (s *Schema) coreConfigSchemaAttribute() *configschema.Attribute {
 	}
 }
 
+// coreConfigSchemaNestedAttribute prepares a configschema.Attribute
+// representation of a schema with a nested type. This is appropriate only for
+// collections whose Elem is an instance of Resource, and will panic
+// otherwise.
+func (s *Schema) coreConfigSchemaNestedAttribute() *configschema.Attribute {
+	ret := s.coreConfigSchemaAttribute()
+
+	ret.NestedType = &configschema.Object{
+		Attributes: s.Elem.(*Resource).coreConfigSchema().Attributes,
+	}
+
+	switch {
+	case s.Type == TypeList && s.MaxItems == 1:
+		ret.NestedType.Nesting = configschema.NestingSingle
+	case s.Type == TypeList:
+		ret.NestedType.Nesting = configschema.NestingList
+	case s.Type == TypeSet:
+		ret.NestedType.Nesting = configschema.NestingSet
+	default:
+		// Should never happen for a valid schema
+		panic(fmt.Errorf("invalid s.Type %s for nested attribute", s.Type))
+	}
+
+	ret.Type = ret.NestedType.ImpliedType()
+
+	return ret
+}
+
 // coreConfigSchemaBlock prepares a configschema.NestedBlock representation of
 // a schema. This is appropriate only for collections whose Elem is an instance
 // of Resource, and will panic otherwise.
@@ -242,6 +272,8 @@ func (s *Schema) coreConfigSchemaType() cty.Type {
 	switch s.Type {
 	case TypeString:
 		return cty.String
+	case TypeDynamic:
+		return cty.DynamicPseudoType
 	case TypeBool:
 		return cty.Bool
 	case TypeInt, TypeFloat:
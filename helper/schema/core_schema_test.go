@@ -365,6 +365,105 @@ func TestSchemaMapCoreConfigSchema(t *testing.T) {
 				},
 			}),
 		},
+		"nested attribute types": {
+			map[string]*Schema{
+				"list": {
+					Type:       TypeList,
+					Optional:   true,
+					ConfigMode: SchemaConfigModeNestedAttr,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"name": {
+								Type:     TypeString,
+								Required: true,
+							},
+							"single": {
+								Type:       TypeList,
+								Optional:   true,
+								MaxItems:   1,
+								ConfigMode: SchemaConfigModeNestedAttr,
+								Elem: &Resource{
+									Schema: map[string]*Schema{
+										"value": {
+											Type:     TypeInt,
+											Computed: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"set": {
+					Type:       TypeSet,
+					Required:   true,
+					Sensitive:  true,
+					ConfigMode: SchemaConfigModeNestedAttr,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"name": {
+								Type:     TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+			testResource(&configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"list": {
+						Type: cty.List(cty.Object(map[string]cty.Type{
+							"name": cty.String,
+							"single": cty.Object(map[string]cty.Type{
+								"value": cty.Number,
+							}),
+						})),
+						NestedType: &configschema.Object{
+							Attributes: map[string]*configschema.Attribute{
+								"name": {
+									Type:     cty.String,
+									Required: true,
+								},
+								"single": {
+									Type: cty.Object(map[string]cty.Type{
+										"value": cty.Number,
+									}),
+									NestedType: &configschema.Object{
+										Attributes: map[string]*configschema.Attribute{
+											"value": {
+												Type:     cty.Number,
+												Computed: true,
+											},
+										},
+										Nesting: configschema.NestingSingle,
+									},
+									Optional: true,
+								},
+							},
+							Nesting: configschema.NestingList,
+						},
+						Optional: true,
+					},
+					"set": {
+						Type: cty.Set(cty.Object(map[string]cty.Type{
+							"name": cty.String,
+						})),
+						NestedType: &configschema.Object{
+							Attributes: map[string]*configschema.Attribute{
+								"name": {
+									Type:     cty.String,
+									Optional: true,
+								},
+							},
+							Nesting: configschema.NestingSet,
+						},
+						Required:  true,
+						Sensitive: true,
+					},
+				},
+				BlockTypes: map[string]*configschema.NestedBlock{},
+			}),
+		},
 		"sensitive": {
 			map[string]*Schema{
 				"string": {
@@ -3,17 +3,24 @@
 
 package schema
 
-// MAINTAINER NOTE: Only PROVIDER_CONFIG_UNKNOWN (enum value 2 in the plugin-protocol) is relevant
-// for SDKv2. Since (Deferred).Reason is mapped directly to the plugin-protocol,
-// the other enum values are intentionally omitted here.
+// MAINTAINER NOTE: (Deferred).Reason is mapped directly to the plugin-protocol,
+// so the enum values must match those of the plugin-protocol.
 const (
 	// DeferredReasonUnknown is used to indicate an invalid `DeferredReason`.
 	// Provider developers should not use it.
 	DeferredReasonUnknown DeferredReason = 0
 
+	// DeferredReasonResourceConfigUnknown represents a deferred reason caused
+	// by unknown resource configuration.
+	DeferredReasonResourceConfigUnknown DeferredReason = 1
+
 	// DeferredReasonProviderConfigUnknown represents a deferred reason caused
 	// by unknown provider configuration.
 	DeferredReasonProviderConfigUnknown DeferredReason = 2
+
+	// DeferredReasonAbsentPrereq represents a deferred reason caused by a
+	// hard dependency that has not been created yet.
+	DeferredReasonAbsentPrereq DeferredReason = 3
 )
 
 // Deferred is used to indicate to Terraform that a resource or data source is not able
@@ -38,8 +45,12 @@ func (d DeferredReason) String() string {
 	switch d {
 	case 0:
 		return "Unknown"
+	case 1:
+		return "Resource Config Unknown"
 	case 2:
 		return "Provider Config Unknown"
+	case 3:
+		return "Absent Prerequisite"
 	}
 	return "Unknown"
 }
@@ -0,0 +1,301 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// EphemeralResource represents an ephemeral resource in Terraform. Ephemeral
+// resources produce short-lived data, such as credentials or tokens, which
+// Terraform never persists in plan or state.
+//
+// Terraform opens an ephemeral resource each time its data is needed during
+// a plan or apply, optionally renews it while the run is in progress, and
+// closes it before the run finishes.
+type EphemeralResource struct {
+	// Schema is the structure and type information for this ephemeral
+	// resource. This field, or SchemaFunc, is required.
+	//
+	// Attributes which are set by the provider during Open must be marked
+	// as Computed.
+	Schema map[string]*Schema
+
+	// SchemaFunc is the structure and type information for this ephemeral
+	// resource. This field, or Schema, is required. Use this field instead
+	// of Schema to prevent storing all schema information in memory for the
+	// lifecycle of a provider.
+	SchemaFunc func() map[string]*Schema
+
+	// Description is used as the description for docs, the language server
+	// and other user facing usage. It can be plain-text or markdown
+	// depending on the global DescriptionKind setting.
+	Description string
+
+	// DeprecationMessage, when non-empty, marks the ephemeral resource as
+	// deprecated and is returned as a warning during validation.
+	DeprecationMessage string
+
+	// Open is called when Terraform needs the data of the ephemeral
+	// resource. The configuration can be read with the ResourceData in the
+	// request, and the result is set with the ResourceData Set method.
+	// This field is required.
+	Open OpenEphemeralResourceFunc
+
+	// Renew is called when the RenewAt time returned by Open or a previous
+	// Renew has passed and Terraform still needs the ephemeral resource.
+	// This field is optional.
+	Renew RenewEphemeralResourceFunc
+
+	// Close is called when Terraform no longer needs the ephemeral
+	// resource, for example to revoke a credential. This field is
+	// optional.
+	Close CloseEphemeralResourceFunc
+}
+
+// OpenEphemeralResourceFunc is the function used to open an
+// EphemeralResource.
+type OpenEphemeralResourceFunc func(context.Context, OpenEphemeralResourceRequest, *OpenEphemeralResourceResponse)
+
+// RenewEphemeralResourceFunc is the function used to renew an
+// EphemeralResource.
+type RenewEphemeralResourceFunc func(context.Context, RenewEphemeralResourceRequest, *RenewEphemeralResourceResponse)
+
+// CloseEphemeralResourceFunc is the function used to close an
+// EphemeralResource.
+type CloseEphemeralResourceFunc func(context.Context, CloseEphemeralResourceRequest, *CloseEphemeralResourceResponse)
+
+type OpenEphemeralResourceRequest struct {
+	// ResourceData is used to query the configuration and to set the
+	// result of the ephemeral resource.
+	ResourceData *ResourceData
+
+	// Meta is the value returned by the provider configuration function,
+	// conventionally used to store API clients.
+	Meta interface{}
+
+	// DeferralAllowed indicates whether the Terraform request opening the
+	// ephemeral resource allows a deferred response.
+	//
+	// NOTE: This functionality is related to deferred action support, which is currently experimental and is subject
+	// to change or break without warning. It is not protected by version compatibility guarantees.
+	DeferralAllowed bool
+}
+
+type OpenEphemeralResourceResponse struct {
+	// Private is provider-defined data which Terraform sends back in the
+	// Renew and Close requests, such as a lease identifier. Values must be
+	// JSON encodable. Private data is never persisted.
+	Private map[string]interface{}
+
+	// RenewAt, if set, is the time after which Terraform calls Renew if
+	// the ephemeral resource is still in use.
+	RenewAt time.Time
+
+	// Diagnostics report errors or warnings related to opening the
+	// ephemeral resource.
+	Diagnostics diag.Diagnostics
+
+	// Deferred indicates that Terraform should defer opening the ephemeral
+	// resource. This field can only be set if
+	// `(schema.OpenEphemeralResourceRequest).DeferralAllowed` is true.
+	//
+	// NOTE: This functionality is related to deferred action support, which is currently experimental and is subject
+	// to change or break without warning. It is not protected by version compatibility guarantees.
+	Deferred *Deferred
+}
+
+type RenewEphemeralResourceRequest struct {
+	// Private is the private data returned by Open or the previous Renew.
+	Private map[string]interface{}
+
+	// Meta is the value returned by the provider configuration function,
+	// conventionally used to store API clients.
+	Meta interface{}
+}
+
+type RenewEphemeralResourceResponse struct {
+	// Private replaces the private data sent to later Renew and Close
+	// requests. If nil, the request private data is kept.
+	Private map[string]interface{}
+
+	// RenewAt, if set, is the time after which Terraform calls Renew again
+	// if the ephemeral resource is still in use.
+	RenewAt time.Time
+
+	// Diagnostics report errors or warnings related to renewing the
+	// ephemeral resource.
+	Diagnostics diag.Diagnostics
+}
+
+type CloseEphemeralResourceRequest struct {
+	// Private is the private data returned by Open or the latest Renew.
+	Private map[string]interface{}
+
+	// Meta is the value returned by the provider configuration function,
+	// conventionally used to store API clients.
+	Meta interface{}
+}
+
+type CloseEphemeralResourceResponse struct {
+	// Diagnostics report errors or warnings related to closing the
+	// ephemeral resource.
+	Diagnostics diag.Diagnostics
+}
+
+// SchemaMap returns the schema information for this ephemeral resource
+// whether it is defined via the SchemaFunc field or Schema field. The
+// SchemaFunc field, if defined, takes precedence over the Schema field.
+func (r *EphemeralResource) SchemaMap() map[string]*Schema {
+	if r.SchemaFunc != nil {
+		return r.SchemaFunc()
+	}
+
+	return r.Schema
+}
+
+// CoreConfigSchema lowers the ephemeral resource schema to the schema model
+// expected by Terraform core. Unlike managed resources and data sources, no
+// implicit "id" attribute is added.
+func (r *EphemeralResource) CoreConfigSchema() *configschema.Block {
+	block := schemaMap(r.SchemaMap()).CoreConfigSchema()
+
+	desc := r.Description
+	descKind := configschema.StringKind(DescriptionKind)
+	if desc == "" {
+		// fallback to plain text if empty
+		descKind = configschema.StringPlain
+	}
+
+	block.Description = desc
+	block.DescriptionKind = descKind
+	block.Deprecated = r.DeprecationMessage != ""
+	block.DeprecationMessage = r.DeprecationMessage
+
+	return block
+}
+
+// Validate validates the ephemeral resource configuration against the
+// schema.
+func (r *EphemeralResource) Validate(c *terraform.ResourceConfig) diag.Diagnostics {
+	diags := schemaMap(r.SchemaMap()).Validate(c)
+
+	if r.DeprecationMessage != "" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Deprecated Ephemeral Resource",
+			Detail:   r.DeprecationMessage,
+		})
+	}
+
+	return diags
+}
+
+// InternalValidate should be called to validate the structure of the
+// ephemeral resource.
+//
+// Provider.InternalValidate() will automatically call this for all of the
+// ephemeral resources it manages, so you don't need to call this manually
+// if it is part of a Provider.
+func (r *EphemeralResource) InternalValidate() error {
+	if r == nil {
+		return errors.New("ephemeral resource is nil")
+	}
+
+	if r.SchemaFunc != nil && r.Schema != nil {
+		return errors.New("SchemaFunc and Schema should not both be set")
+	}
+
+	if r.Open == nil {
+		return errors.New("Open must be implemented")
+	}
+
+	sm := schemaMap(r.SchemaMap())
+
+	if sm.hasWriteOnly() {
+		return errors.New("cannot contain write-only attributes")
+	}
+
+	for k, s := range sm {
+		if s.ForceNew {
+			return fmt.Errorf("%s: ForceNew is not supported for ephemeral resources", k)
+		}
+	}
+
+	return sm.InternalValidate(sm)
+}
+
+// open builds the ResourceData for the configuration, calls the Open
+// function and returns the result value along with the response.
+func (r *EphemeralResource) open(ctx context.Context, configVal cty.Value, deferralAllowed bool, meta interface{}) (cty.Value, *OpenEphemeralResourceResponse, error) {
+	sm := schemaMap(r.SchemaMap())
+	schemaBlock := sm.CoreConfigSchema()
+
+	data, err := sm.configData(ctx, configVal, meta)
+	if err != nil {
+		return cty.NilVal, nil, err
+	}
+
+	req := OpenEphemeralResourceRequest{
+		ResourceData:    data,
+		Meta:            meta,
+		DeferralAllowed: deferralAllowed,
+	}
+	resp := &OpenEphemeralResourceResponse{}
+
+	r.Open(ctx, req, resp)
+
+	if resp.Diagnostics.HasError() || resp.Deferred != nil {
+		return cty.NullVal(schemaBlock.ImpliedType()), resp, nil
+	}
+
+	// Ephemeral resources have no identifier, but ResourceData only
+	// produces a state when one is present.
+	if data.Id() == "" {
+		data.SetId("-")
+	}
+
+	resultVal, err := data.State().AttrsAsObjectValueBlock(schemaBlock)
+	if err != nil {
+		return cty.NilVal, resp, err
+	}
+
+	return resultVal, resp, nil
+}
+
+// marshalEphemeralPrivate encodes ephemeral resource private data for the
+// protocol. Empty private data is encoded as nil.
+func marshalEphemeralPrivate(private map[string]interface{}) ([]byte, error) {
+	if len(private) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(private)
+}
+
+// unmarshalEphemeralPrivate decodes ephemeral resource private data from
+// the protocol.
+func unmarshalEphemeralPrivate(b []byte) (map[string]interface{}, error) {
+	private := make(map[string]interface{})
+
+	if len(b) == 0 {
+		return private, nil
+	}
+
+	if err := json.Unmarshal(b, &private); err != nil {
+		return nil, err
+	}
+
+	return private, nil
+}
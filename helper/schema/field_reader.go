@@ -73,7 +73,7 @@ func addrToSchema(addr []string, schemaMap map[string]*Schema) []*Schema {
 		}
 
 		switch t := current.Type; t {
-		case TypeBool, TypeInt, TypeFloat, TypeString:
+		case TypeBool, TypeInt, TypeFloat, TypeString, TypeDynamic:
 			if len(addr) > 0 {
 				return nil
 			}
@@ -328,7 +328,7 @@ func stringToPrimitive(
 		}
 
 		returnVal = int(v)
-	case TypeString:
+	case TypeString, TypeDynamic:
 		returnVal = value
 	default:
 		panic(fmt.Sprintf("Unknown type: %s", schema.Type))
@@ -40,6 +40,10 @@ func (r *ConfigFieldReader) readField(
 	}
 
 	if !nested {
+		// copy address to ensure we don't modify the argument, which is
+		// read by the other levels of a MultiLevelFieldReader afterwards
+		address = append([]string(nil), address...)
+
 		// If we have a set anywhere in the address, then we need to
 		// read that set out in order and actually replace that part of
 		// the address with the real list index. i.e. set.50 might actually
@@ -100,7 +104,7 @@ func (r *ConfigFieldReader) readField(
 	}
 
 	switch schema.Type {
-	case TypeBool, TypeFloat, TypeInt, TypeString:
+	case TypeBool, TypeFloat, TypeInt, TypeString, TypeDynamic:
 		return r.readPrimitive(k, schema)
 	case TypeList:
 		return readListField(&nestedConfigFieldReader{r}, address)
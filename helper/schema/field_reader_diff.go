@@ -67,7 +67,7 @@ func (r *DiffFieldReader) ReadField(address []string) (FieldReadResult, error) {
 
 	schema := schemaList[len(schemaList)-1]
 	switch schema.Type {
-	case TypeBool, TypeInt, TypeFloat, TypeString:
+	case TypeBool, TypeInt, TypeFloat, TypeString, TypeDynamic:
 		res, err = r.readPrimitive(address, schema)
 	case TypeList:
 		res, err = readListField(r, address)
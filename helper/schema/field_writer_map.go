@@ -10,7 +10,10 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/hcl2shim"
 )
 
 // MapFieldWriter writes data into a single map[string]string structure.
@@ -92,6 +95,19 @@ func (w *MapFieldWriter) WriteField(addr []string, value interface{}) error {
 	return w.set(addr, value)
 }
 
+// writeNestedField functions like WriteField, except that it also allows
+// setting fields nested within list, set and map elements. It is used by
+// ResourceDiff, which writes single nested values of the new diff.
+func (w *MapFieldWriter) writeNestedField(addr []string, value interface{}) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.result == nil {
+		w.result = make(map[string]string)
+	}
+
+	return w.set(addr, value)
+}
+
 func (w *MapFieldWriter) set(addr []string, value interface{}) error {
 	schemaList := addrToSchema(addr, w.Schema)
 	if len(schemaList) == 0 {
@@ -100,7 +116,7 @@ func (w *MapFieldWriter) set(addr []string, value interface{}) error {
 
 	schema := schemaList[len(schemaList)-1]
 	switch schema.Type {
-	case TypeBool, TypeInt, TypeFloat, TypeString:
+	case TypeBool, TypeInt, TypeFloat, TypeString, TypeDynamic:
 		return w.setPrimitive(addr, value, schema)
 	case TypeList:
 		return w.setList(addr, value)
@@ -274,6 +290,35 @@ func (w *MapFieldWriter) setPrimitive(
 			return fmt.Errorf("%s: %s", k, err)
 		}
 		set = strconv.FormatFloat(n, 'G', -1, 64)
+	case TypeDynamic:
+		switch v := v.(type) {
+		case cty.Value:
+			if v.IsNull() {
+				// The empty string here means the value is removed.
+				w.result[k] = ""
+				return nil
+			}
+
+			if !v.IsKnown() {
+				return fmt.Errorf("%s: value must be known", k)
+			}
+
+			var err error
+			set, err = hcl2shim.EncodeDynamicValue(v)
+			if err != nil {
+				return fmt.Errorf("%s: %s", k, err)
+			}
+		case string:
+			// The value is already encoded, such as when it is returned
+			// from ResourceData.Get.
+			if _, err := hcl2shim.DecodeDynamicValue(v); err != nil {
+				return fmt.Errorf("%s: %s", k, err)
+			}
+
+			set = v
+		default:
+			return fmt.Errorf("%s: TypeDynamic value must be a cty.Value, got %T", k, v)
+		}
 	default:
 		return fmt.Errorf("Unknown type: %#v", schema.Type)
 	}
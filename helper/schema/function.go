@@ -0,0 +1,407 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/gocty"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugin/convert"
+)
+
+// Function represents a provider-defined function, which practitioners can
+// call in configuration with the provider::<name>::<function> syntax.
+//
+// Functions are intended to be pure computations over their arguments, such
+// as parsing or formatting identifiers. Terraform can call functions before
+// the provider is configured, so the function implementation does not
+// receive the provider meta value.
+type Function struct {
+	// Summary is a short description of the function, used by tooling such
+	// as language servers.
+	Summary string
+
+	// Description is a longer description of the function. The format of
+	// the text is controlled by the package level DescriptionKind variable.
+	Description string
+
+	// DeprecationMessage, when non-empty, marks the function as deprecated
+	// and is shown to practitioners calling the function.
+	DeprecationMessage string
+
+	// Parameters are the positional parameters of the function, in order.
+	Parameters []*FunctionParameter
+
+	// VariadicParameter is an optional final parameter which accepts zero
+	// or more arguments of the same type. The arguments are passed to Run
+	// as a trailing []interface{} element of the argument slice.
+	VariadicParameter *FunctionParameter
+
+	// Return defines the type of the value returned by Run. It is required.
+	Return *FunctionReturn
+
+	// Run is called with the decoded arguments, in parameter order, and
+	// returns the function result. It is required.
+	//
+	// Argument values use the same Go types as ResourceData.Get:
+	//
+	//   - TypeBool: bool
+	//   - TypeInt: int
+	//   - TypeFloat: float64
+	//   - TypeString: string
+	//   - TypeList: []interface{}
+	//   - TypeMap: map[string]interface{}
+	//   - TypeSet: *Set
+	//
+	// Null arguments, which are only possible when the parameter sets
+	// AllowNullValue, are passed as nil.
+	//
+	// The returned value must be convertible to the Return type. Errors
+	// are returned to Terraform as a function error. Use
+	// NewFunctionArgumentError to associate an error with a specific
+	// argument.
+	Run FunctionRunFunc
+}
+
+// FunctionRunFunc is the implementation of a provider-defined function.
+type FunctionRunFunc func(ctx context.Context, args []interface{}) (interface{}, error)
+
+// FunctionParameter describes a single parameter of a Function.
+type FunctionParameter struct {
+	// Name is the name of the parameter, used in documentation and
+	// error messages. It is required.
+	Name string
+
+	// Description is a description of the parameter.
+	Description string
+
+	// Type is the type of the parameter. It may be any ValueType other than
+	// TypeInvalid.
+	Type ValueType
+
+	// Elem is the element type of TypeList, TypeSet and TypeMap parameters,
+	// following the same rules as Schema.Elem. A *Resource Elem is decoded
+	// as an object with the attributes of the resource schema.
+	Elem interface{}
+
+	// AllowNullValue permits practitioners to pass null as the argument.
+	// Otherwise Terraform raises an error before calling the function.
+	AllowNullValue bool
+}
+
+// FunctionReturn describes the return value of a Function.
+type FunctionReturn struct {
+	// Type is the type of the returned value.
+	Type ValueType
+
+	// Elem is the element type of TypeList, TypeSet and TypeMap return
+	// values, following the same rules as Schema.Elem.
+	Elem interface{}
+}
+
+// FunctionArgumentError is an error associated with a specific function
+// argument. It is returned to Terraform as a function error referencing the
+// argument, so the practitioner can see which argument was invalid.
+type FunctionArgumentError struct {
+	// Index is the zero-based position of the argument. Arguments of a
+	// variadic parameter continue counting from the last positional
+	// parameter.
+	Index int
+
+	// Err is the underlying error.
+	Err error
+}
+
+// NewFunctionArgumentError returns an error associated with the function
+// argument at the given zero-based index.
+func NewFunctionArgumentError(index int, err error) error {
+	return &FunctionArgumentError{
+		Index: index,
+		Err:   err,
+	}
+}
+
+func (e *FunctionArgumentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FunctionArgumentError) Unwrap() error {
+	return e.Err
+}
+
+func (p *FunctionParameter) schema() *Schema {
+	return &Schema{Type: p.Type, Elem: p.Elem}
+}
+
+func (r *FunctionReturn) schema() *Schema {
+	return &Schema{Type: r.Type, Elem: r.Elem}
+}
+
+// InternalValidate should be called to validate the structure of the
+// function.
+func (f *Function) InternalValidate() error {
+	if f == nil {
+		return errors.New("function is nil")
+	}
+
+	if f.Run == nil {
+		return errors.New("Run must be set")
+	}
+
+	if f.Return == nil {
+		return errors.New("Return must be set")
+	}
+
+	if err := validateFunctionType(f.Return.Type, f.Return.Elem); err != nil {
+		return fmt.Errorf("return: %w", err)
+	}
+
+	names := make(map[string]struct{}, len(f.Parameters))
+	params := f.Parameters
+
+	if f.VariadicParameter != nil {
+		params = append(params[:len(params):len(params)], f.VariadicParameter)
+	}
+
+	for i, p := range params {
+		if p == nil {
+			return fmt.Errorf("parameter %d is nil", i)
+		}
+
+		if p.Name == "" {
+			return fmt.Errorf("parameter %d: Name must be set", i)
+		}
+
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("parameter %d: duplicate name %q", i, p.Name)
+		}
+
+		names[p.Name] = struct{}{}
+
+		if err := validateFunctionType(p.Type, p.Elem); err != nil {
+			return fmt.Errorf("parameter %q: %w", p.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func validateFunctionType(t ValueType, elem interface{}) error {
+	switch t {
+	case TypeBool, TypeInt, TypeFloat, TypeString:
+		if elem != nil {
+			return fmt.Errorf("Elem must not be set for %s", t)
+		}
+	case TypeList, TypeSet, TypeMap:
+		switch e := elem.(type) {
+		case nil, ValueType:
+		case *Schema:
+			return validateFunctionType(e.Type, e.Elem)
+		case *Resource:
+			if t == TypeMap {
+				return errors.New("TypeMap Elem must be a *Schema or ValueType")
+			}
+		default:
+			return fmt.Errorf("invalid Elem %#v", elem)
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", t)
+	}
+
+	return nil
+}
+
+// ProtoFunction returns the protocol representation of the function.
+func (f *Function) ProtoFunction() (*tfprotov5.Function, error) {
+	descKind := tfprotov5.StringKindPlain
+	if DescriptionKind == StringMarkdown && f.Description != "" {
+		descKind = tfprotov5.StringKindMarkdown
+	}
+
+	ret := &tfprotov5.Function{
+		Summary:            f.Summary,
+		Description:        f.Description,
+		DescriptionKind:    descKind,
+		DeprecationMessage: f.DeprecationMessage,
+		Parameters:         make([]*tfprotov5.FunctionParameter, 0, len(f.Parameters)),
+	}
+
+	for _, p := range f.Parameters {
+		param, err := p.protoParameter()
+		if err != nil {
+			return nil, err
+		}
+
+		ret.Parameters = append(ret.Parameters, param)
+	}
+
+	if f.VariadicParameter != nil {
+		param, err := f.VariadicParameter.protoParameter()
+		if err != nil {
+			return nil, err
+		}
+
+		ret.VariadicParameter = param
+	}
+
+	returnType, err := convert.CtyTypeToTFType(f.Return.schema().coreConfigSchemaType())
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Return = &tfprotov5.FunctionReturn{
+		Type: returnType,
+	}
+
+	return ret, nil
+}
+
+func (p *FunctionParameter) protoParameter() (*tfprotov5.FunctionParameter, error) {
+	typ, err := convert.CtyTypeToTFType(p.schema().coreConfigSchemaType())
+	if err != nil {
+		return nil, fmt.Errorf("parameter %q: %w", p.Name, err)
+	}
+
+	descKind := tfprotov5.StringKindPlain
+	if DescriptionKind == StringMarkdown && p.Description != "" {
+		descKind = tfprotov5.StringKindMarkdown
+	}
+
+	return &tfprotov5.FunctionParameter{
+		Name:            p.Name,
+		Description:     p.Description,
+		DescriptionKind: descKind,
+		Type:            typ,
+		AllowNullValue:  p.AllowNullValue,
+	}, nil
+}
+
+// functionValueToGo converts an argument value into the Go type documented on
+// Function.Run for the given schema.
+func functionValueToGo(val cty.Value, s *Schema) (interface{}, error) {
+	if val.IsNull() {
+		return nil, nil
+	}
+
+	if !val.IsKnown() {
+		return nil, errors.New("value must be known")
+	}
+
+	switch s.Type {
+	case TypeBool:
+		return val.True(), nil
+	case TypeInt:
+		i, acc := val.AsBigFloat().Int64()
+		if acc != big.Exact {
+			return nil, fmt.Errorf("value must be a whole number, got %s", val.AsBigFloat().Text('f', -1))
+		}
+		return int(i), nil
+	case TypeFloat:
+		f, _ := val.AsBigFloat().Float64()
+		return f, nil
+	case TypeString:
+		return val.AsString(), nil
+	case typeObject:
+		res := s.Elem.(*Resource)
+		m := make(map[string]interface{}, len(res.SchemaMap()))
+		for k, attrSchema := range res.SchemaMap() {
+			if !val.Type().HasAttribute(k) {
+				continue
+			}
+
+			v, err := functionValueToGo(val.GetAttr(k), attrSchema)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			m[k] = v
+		}
+		return m, nil
+	case TypeList, TypeSet:
+		elemSchema := functionElemSchema(s)
+		l := make([]interface{}, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			v, err := functionValueToGo(ev, elemSchema)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, v)
+		}
+
+		if s.Type == TypeSet {
+			if res, ok := s.Elem.(*Resource); ok {
+				return NewSet(HashResource(res), l), nil
+			}
+			return NewSet(HashSchema(elemSchema), l), nil
+		}
+		return l, nil
+	case TypeMap:
+		elemSchema := functionElemSchema(s)
+		m := make(map[string]interface{}, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			ek, ev := it.Element()
+			v, err := functionValueToGo(ev, elemSchema)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", ek.AsString(), err)
+			}
+			m[ek.AsString()] = v
+		}
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", s.Type)
+}
+
+// functionElemSchema returns the schema of the elements of a collection
+// schema, mirroring the Elem handling of coreConfigSchemaType.
+func functionElemSchema(s *Schema) *Schema {
+	switch e := s.Elem.(type) {
+	case *Schema:
+		return e
+	case ValueType:
+		return &Schema{Type: e}
+	case *Resource:
+		return &Schema{Type: typeObject, Elem: e}
+	default:
+		return &Schema{Type: TypeString}
+	}
+}
+
+// functionValueFromGo converts the value returned by Function.Run into a
+// cty.Value of the given type.
+func functionValueFromGo(v interface{}, ty cty.Type) (cty.Value, error) {
+	return gocty.ToCtyValue(functionNormalizeSets(v), ty)
+}
+
+// functionNormalizeSets replaces any *Set values with their list of elements,
+// so the value can be converted with gocty.
+func functionNormalizeSets(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *Set:
+		if v == nil {
+			return nil
+		}
+		return functionNormalizeSets(v.List())
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, ev := range v {
+			l[i] = functionNormalizeSets(ev)
+		}
+		return l
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, ev := range v {
+			m[k] = functionNormalizeSets(ev)
+		}
+		return m
+	}
+
+	return v
+}
@@ -6,6 +6,7 @@ package schema
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
 	"slices"
@@ -158,6 +159,10 @@ func (s *GRPCProviderServer) UpgradeResourceIdentity(ctx context.Context, req *t
 		return resp, nil
 	}
 
+	// The provider isn't required to migrate blocks which were replaced by
+	// single nested attributes
+	s.migrateSingleNestedAttributes(ctx, jsonMap, schemaBlock)
+
 	// The provider isn't required to clean out removed fields
 	s.removeAttributes(ctx, jsonMap, schemaBlock.ImpliedType())
 
@@ -197,10 +202,10 @@ func (s *GRPCProviderServer) GetMetadata(ctx context.Context, req *tfprotov5.Get
 
 	resp := &tfprotov5.GetMetadataResponse{
 		DataSources:        make([]tfprotov5.DataSourceMetadata, 0, len(s.provider.DataSourcesMap)),
-		EphemeralResources: make([]tfprotov5.EphemeralResourceMetadata, 0),
-		Functions:          make([]tfprotov5.FunctionMetadata, 0),
-		ListResources:      make([]tfprotov5.ListResourceMetadata, 0),
-		Actions:            make([]tfprotov5.ActionMetadata, 0),
+		EphemeralResources: make([]tfprotov5.EphemeralResourceMetadata, 0, len(s.provider.EphemeralResourcesMap)),
+		Functions:          make([]tfprotov5.FunctionMetadata, 0, len(s.provider.Functions)),
+		ListResources:      make([]tfprotov5.ListResourceMetadata, 0, len(s.provider.listResourceTypes())),
+		Actions:            make([]tfprotov5.ActionMetadata, 0, len(s.provider.ActionsMap)),
 		Resources:          make([]tfprotov5.ResourceMetadata, 0, len(s.provider.ResourcesMap)),
 		ServerCapabilities: s.serverCapabilities(),
 	}
@@ -217,6 +222,30 @@ func (s *GRPCProviderServer) GetMetadata(ctx context.Context, req *tfprotov5.Get
 		})
 	}
 
+	for typeName := range s.provider.EphemeralResourcesMap {
+		resp.EphemeralResources = append(resp.EphemeralResources, tfprotov5.EphemeralResourceMetadata{
+			TypeName: typeName,
+		})
+	}
+
+	for typeName := range s.provider.ActionsMap {
+		resp.Actions = append(resp.Actions, tfprotov5.ActionMetadata{
+			TypeName: typeName,
+		})
+	}
+
+	for _, typeName := range s.provider.listResourceTypes() {
+		resp.ListResources = append(resp.ListResources, tfprotov5.ListResourceMetadata{
+			TypeName: typeName,
+		})
+	}
+
+	for name := range s.provider.Functions {
+		resp.Functions = append(resp.Functions, tfprotov5.FunctionMetadata{
+			Name: name,
+		})
+	}
+
 	return resp, nil
 }
 
@@ -225,43 +254,167 @@ func (s *GRPCProviderServer) GetProviderSchema(ctx context.Context, req *tfproto
 
 	logging.HelperSchemaTrace(ctx, "Getting provider schema")
 
+	schemas := s.providerSchemas(ctx)
+
 	resp := &tfprotov5.GetProviderSchemaResponse{
-		DataSourceSchemas:        make(map[string]*tfprotov5.Schema, len(s.provider.DataSourcesMap)),
-		EphemeralResourceSchemas: make(map[string]*tfprotov5.Schema, 0),
-		Functions:                make(map[string]*tfprotov5.Function, 0),
-		ListResourceSchemas:      make(map[string]*tfprotov5.Schema, 0),
-		ActionSchemas:            make(map[string]*tfprotov5.ActionSchema, 0),
-		ResourceSchemas:          make(map[string]*tfprotov5.Schema, len(s.provider.ResourcesMap)),
+		DataSourceSchemas:        make(map[string]*tfprotov5.Schema, len(schemas.dataSources)),
+		EphemeralResourceSchemas: make(map[string]*tfprotov5.Schema, len(schemas.ephemeralResources)),
+		Functions:                make(map[string]*tfprotov5.Function, len(s.provider.Functions)),
+		ListResourceSchemas:      make(map[string]*tfprotov5.Schema, len(schemas.listResources)),
+		ActionSchemas:            make(map[string]*tfprotov5.ActionSchema, len(schemas.actions)),
+		ResourceSchemas:          make(map[string]*tfprotov5.Schema, len(schemas.resources)),
 		ServerCapabilities:       s.serverCapabilities(),
 	}
 
-	resp.Provider = &tfprotov5.Schema{
-		Block: convert.ConfigSchemaToProto(ctx, s.getProviderSchemaBlock()),
+	toProto := func(desc string, schema providerSchema) *tfprotov5.Schema {
+		// Protocol version 5 has no nested attributes, which would instead
+		// be sent as attributes of object types with different semantics.
+		if name := nestedAttributeName(schema.block); name != "" {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Unsupported Nested Attribute",
+				Detail: fmt.Sprintf("The %q attribute of the %s schema has a ConfigMode of SchemaConfigModeNestedAttr, ", name, desc) +
+					"which is only supported with protocol version 6. This is an issue with the provider and should be reported to the provider developers.",
+			})
+		}
+
+		return &tfprotov5.Schema{
+			Version: schema.version,
+			Block:   convert.ConfigSchemaToProto(ctx, schema.block),
+		}
+	}
+
+	resp.Provider = toProto("provider", schemas.provider)
+	resp.ProviderMeta = toProto("provider meta", schemas.providerMeta)
+
+	for typ, schema := range schemas.resources {
+		resp.ResourceSchemas[typ] = toProto(fmt.Sprintf("%q resource", typ), schema)
+	}
+
+	for typ, schema := range schemas.dataSources {
+		resp.DataSourceSchemas[typ] = toProto(fmt.Sprintf("%q data source", typ), schema)
+	}
+
+	for typ, schema := range schemas.ephemeralResources {
+		resp.EphemeralResourceSchemas[typ] = toProto(fmt.Sprintf("%q ephemeral resource", typ), schema)
+	}
+
+	for typ, schema := range schemas.actions {
+		resp.ActionSchemas[typ] = &tfprotov5.ActionSchema{
+			Schema: toProto(fmt.Sprintf("%q action", typ), schema),
+		}
+	}
+
+	for typ, schema := range schemas.listResources {
+		resp.ListResourceSchemas[typ] = toProto(fmt.Sprintf("%q list resource", typ), schema)
+	}
+
+	for name, f := range s.provider.Functions {
+		logging.HelperSchemaTrace(ctx, "Found function", map[string]interface{}{logging.KeyFunctionName: name})
+
+		function, err := f.ProtoFunction()
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf("getting function definition failed for function '%s': %w", name, err))
+			return resp, nil
+		}
+
+		resp.Functions[name] = function
+	}
+
+	return resp, nil
+}
+
+// nestedAttributeName returns the path of the first nested attribute within
+// the given block, or an empty string if it has none.
+func nestedAttributeName(b *configschema.Block) string {
+	if b == nil {
+		return ""
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(b.Attributes)) {
+		if b.Attributes[name].NestedType != nil {
+			return name
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(b.BlockTypes)) {
+		if nested := nestedAttributeName(&b.BlockTypes[name].Block); nested != "" {
+			return name + "." + nested
+		}
 	}
 
-	resp.ProviderMeta = &tfprotov5.Schema{
-		Block: convert.ConfigSchemaToProto(ctx, s.getProviderMetaSchemaBlock()),
+	return ""
+}
+
+// providerSchema is a schema of the provider, independent of the protocol
+// version it is sent with.
+type providerSchema struct {
+	version int64
+	block   *configschema.Block
+}
+
+// providerSchemas are the schemas of the provider, except functions, which
+// GetProviderSchema sends for each protocol version.
+type providerSchemas struct {
+	provider           providerSchema
+	providerMeta       providerSchema
+	resources          map[string]providerSchema
+	dataSources        map[string]providerSchema
+	ephemeralResources map[string]providerSchema
+	listResources      map[string]providerSchema
+	actions            map[string]providerSchema
+}
+
+// providerSchemas returns the schemas of the provider, to be converted to the
+// schemas of the protocol version of the GetProviderSchema RPC.
+func (s *GRPCProviderServer) providerSchemas(ctx context.Context) *providerSchemas {
+	schemas := &providerSchemas{
+		provider:           providerSchema{block: s.getProviderSchemaBlock()},
+		providerMeta:       providerSchema{block: s.getProviderMetaSchemaBlock()},
+		resources:          make(map[string]providerSchema, len(s.provider.ResourcesMap)),
+		dataSources:        make(map[string]providerSchema, len(s.provider.DataSourcesMap)),
+		ephemeralResources: make(map[string]providerSchema, len(s.provider.EphemeralResourcesMap)),
+		listResources:      make(map[string]providerSchema, len(s.provider.listResourceTypes())),
+		actions:            make(map[string]providerSchema, len(s.provider.ActionsMap)),
 	}
 
 	for typ, res := range s.provider.ResourcesMap {
 		logging.HelperSchemaTrace(ctx, "Found resource type", map[string]interface{}{logging.KeyResourceType: typ})
 
-		resp.ResourceSchemas[typ] = &tfprotov5.Schema{
-			Version: int64(res.SchemaVersion),
-			Block:   convert.ConfigSchemaToProto(ctx, res.CoreConfigSchema()),
+		schemas.resources[typ] = providerSchema{
+			version: int64(res.SchemaVersion),
+			block:   res.CoreConfigSchema(),
 		}
 	}
 
 	for typ, dat := range s.provider.DataSourcesMap {
 		logging.HelperSchemaTrace(ctx, "Found data source type", map[string]interface{}{logging.KeyDataSourceType: typ})
 
-		resp.DataSourceSchemas[typ] = &tfprotov5.Schema{
-			Version: int64(dat.SchemaVersion),
-			Block:   convert.ConfigSchemaToProto(ctx, dat.CoreConfigSchema()),
+		schemas.dataSources[typ] = providerSchema{
+			version: int64(dat.SchemaVersion),
+			block:   dat.CoreConfigSchema(),
 		}
 	}
 
-	return resp, nil
+	for typ, r := range s.provider.EphemeralResourcesMap {
+		logging.HelperSchemaTrace(ctx, "Found ephemeral resource type", map[string]interface{}{logging.KeyEphemeralResourceType: typ})
+
+		schemas.ephemeralResources[typ] = providerSchema{block: r.CoreConfigSchema()}
+	}
+
+	for typ, a := range s.provider.ActionsMap {
+		logging.HelperSchemaTrace(ctx, "Found action type", map[string]interface{}{logging.KeyActionType: typ})
+
+		schemas.actions[typ] = providerSchema{block: a.CoreConfigSchema()}
+	}
+
+	for _, typ := range s.provider.listResourceTypes() {
+		logging.HelperSchemaTrace(ctx, "Found list resource type", map[string]interface{}{logging.KeyResourceType: typ})
+
+		schemas.listResources[typ] = providerSchema{block: s.provider.ResourcesMap[typ].CoreListConfigSchema()}
+	}
+
+	return schemas
 }
 
 func (s *GRPCProviderServer) getProviderSchemaBlock() *configschema.Block {
@@ -377,7 +530,11 @@ func (s *GRPCProviderServer) PrepareProviderConfig(ctx context.Context, req *tfp
 		return resp, nil
 	}
 
-	config := terraform.NewResourceConfigShimmed(configVal, schemaBlock)
+	config, err := terraform.ShimResourceConfigFromValue(configVal, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
 
 	logging.HelperSchemaTrace(ctx, "Calling downstream")
 	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, s.provider.Validate(config))
@@ -432,7 +589,11 @@ func (s *GRPCProviderServer) ValidateResourceTypeConfig(ctx context.Context, req
 		}
 	}
 
-	config := terraform.NewResourceConfigShimmed(configVal, schemaBlock)
+	config, err := terraform.ShimResourceConfigFromValue(configVal, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
 
 	logging.HelperSchemaTrace(ctx, "Calling downstream")
 	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, s.provider.ValidateResource(req.TypeName, config))
@@ -459,7 +620,11 @@ func (s *GRPCProviderServer) ValidateDataSourceConfig(ctx context.Context, req *
 		return resp, nil
 	}
 
-	config := terraform.NewResourceConfigShimmed(configVal, schemaBlock)
+	config, err := terraform.ShimResourceConfigFromValue(configVal, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
 
 	logging.HelperSchemaTrace(ctx, "Calling downstream")
 	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, s.provider.ValidateDataSource(req.TypeName, config))
@@ -520,6 +685,10 @@ func (s *GRPCProviderServer) UpgradeResourceState(ctx context.Context, req *tfpr
 		return resp, nil
 	}
 
+	// The provider isn't required to migrate blocks which were replaced by
+	// single nested attributes
+	s.migrateSingleNestedAttributes(ctx, jsonMap, schemaBlock)
+
 	// The provider isn't required to clean out removed fields
 	s.removeAttributes(ctx, jsonMap, schemaBlock.ImpliedType())
 
@@ -569,7 +738,8 @@ func (s *GRPCProviderServer) upgradeFlatmapState(_ context.Context, version int,
 	// first determine if we need to call the legacy MigrateState func
 	requiresMigrate := version < res.SchemaVersion
 
-	schemaType := res.CoreConfigSchema().ImpliedType()
+	schemaBlock := res.CoreConfigSchema()
+	schemaType := schemaBlock.ImpliedType()
 
 	// if there are any StateUpgraders, then we need to only compare
 	// against the first version there
@@ -624,7 +794,18 @@ func (s *GRPCProviderServer) upgradeFlatmapState(_ context.Context, version int,
 	// now we know the state is up to the latest version that handled the
 	// flatmap format state. Now we can upgrade the format and continue from
 	// there.
-	newConfigVal, err := hcl2shim.HCL2ValueFromFlatmap(m, schemaType)
+	var newConfigVal cty.Value
+	var err error
+
+	// The current schema may contain attributes with a different flatmap
+	// representation, such as single nested attributes which were previously
+	// blocks, so it is used to decode the state when no upgrader type applies.
+	if schemaType.Equals(schemaBlock.ImpliedType()) {
+		newConfigVal, err = hcl2shim.HCL2ValueFromFlatmapBlock(m, schemaBlock)
+	} else {
+		newConfigVal, err = hcl2shim.HCL2ValueFromFlatmap(m, schemaType)
+	}
+
 	if err != nil {
 		return nil, 0, err
 	}
@@ -702,6 +883,82 @@ func (s *GRPCProviderServer) removeAttributes(ctx context.Context, v interface{}
 	}
 }
 
+// Replace the lists of zero or one objects which represent blocks in the JSON
+// state with null or the object respectively, wherever the schema now has a
+// single nested attribute, so that the json can be correctly decoded.
+func (s *GRPCProviderServer) migrateSingleNestedAttributes(ctx context.Context, v interface{}, schemaBlock *configschema.Block) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for name, attrS := range schemaBlock.Attributes {
+		if attrS.NestedType == nil {
+			continue
+		}
+
+		attrV, ok := m[name]
+		if !ok {
+			continue
+		}
+
+		nestedBlock := &configschema.Block{
+			Attributes: attrS.NestedType.Attributes,
+		}
+
+		if attrS.NestedType.Nesting != configschema.NestingSingle {
+			s.migrateNestedSingleNestedAttributes(ctx, attrV, attrS.NestedType.Nesting, nestedBlock)
+			continue
+		}
+
+		if l, ok := attrV.([]interface{}); ok {
+			switch len(l) {
+			case 0:
+				logging.HelperSchemaDebug(ctx, "migrating empty block to null single nested attribute", map[string]interface{}{"attribute": name})
+				attrV = nil
+			case 1:
+				logging.HelperSchemaDebug(ctx, "migrating block to single nested attribute", map[string]interface{}{"attribute": name})
+				attrV = l[0]
+			default:
+				// This will fail to decode further on, so there's no need to
+				// handle it here.
+				logging.HelperSchemaWarn(ctx, "unexpected number of blocks for single nested attribute in JSON state", map[string]interface{}{"attribute": name})
+			}
+
+			m[name] = attrV
+		}
+
+		s.migrateSingleNestedAttributes(ctx, attrV, nestedBlock)
+	}
+
+	for name, blockS := range schemaBlock.BlockTypes {
+		if blockV, ok := m[name]; ok {
+			s.migrateNestedSingleNestedAttributes(ctx, blockV, blockS.Nesting, &blockS.Block)
+		}
+	}
+}
+
+// migrateNestedSingleNestedAttributes calls migrateSingleNestedAttributes
+// for each object of the given nested block or nested attribute value.
+func (s *GRPCProviderServer) migrateNestedSingleNestedAttributes(ctx context.Context, v interface{}, nesting configschema.NestingMode, schemaBlock *configschema.Block) {
+	switch nesting {
+	case configschema.NestingSingle, configschema.NestingGroup:
+		s.migrateSingleNestedAttributes(ctx, v, schemaBlock)
+	case configschema.NestingList, configschema.NestingSet:
+		if l, ok := v.([]interface{}); ok {
+			for _, eV := range l {
+				s.migrateSingleNestedAttributes(ctx, eV, schemaBlock)
+			}
+		}
+	case configschema.NestingMap:
+		if m, ok := v.(map[string]interface{}); ok {
+			for _, eV := range m {
+				s.migrateSingleNestedAttributes(ctx, eV, schemaBlock)
+			}
+		}
+	}
+}
+
 func (s *GRPCProviderServer) StopProvider(ctx context.Context, _ *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
 	ctx = logging.InitContext(ctx)
 
@@ -740,7 +997,11 @@ func (s *GRPCProviderServer) ConfigureProvider(ctx context.Context, req *tfproto
 		return resp, nil
 	}
 
-	config := terraform.NewResourceConfigShimmed(configVal, schemaBlock)
+	config, err := terraform.ShimResourceConfigFromValue(configVal, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
 
 	// CtyValue is the raw protocol configuration data from newer APIs.
 	//
@@ -914,12 +1175,36 @@ func (s *GRPCProviderServer) ReadResource(ctx context.Context, req *tfprotov5.Re
 		instanceState.ProviderMeta = providerSchemaVal
 	}
 
-	newInstanceState, diags := res.RefreshWithoutUpgrade(ctx, instanceState, s.provider.Meta())
+	deferralAllowed := req.ClientCapabilities != nil && req.ClientCapabilities.DeferralAllowed
+
+	newInstanceState, deferred, diags := res.refreshWithoutUpgrade(ctx, instanceState, s.provider.Meta(), deferralAllowed)
 	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, diags)
 	if diags.HasError() {
 		return resp, nil
 	}
 
+	if deferred != nil {
+		if !deferralAllowed {
+			resp.Diagnostics = append(resp.Diagnostics, invalidDeferredResponseDiagnostic("Resource"))
+			return resp, nil
+		}
+
+		logging.HelperSchemaDebug(
+			ctx,
+			"Resource has deferred response configured, returning deferred response with current state.",
+			map[string]interface{}{
+				logging.KeyDeferredReason: deferred.Reason.String(),
+			},
+		)
+
+		resp.NewState = req.CurrentState
+		resp.NewIdentity = req.CurrentIdentity
+		resp.Deferred = &tfprotov5.Deferred{
+			Reason: tfprotov5.DeferredReason(deferred.Reason),
+		}
+		return resp, nil
+	}
+
 	if newInstanceState == nil || newInstanceState.ID == "" {
 		// The old provider API used an empty id to signal that the remote
 		// object appears to have been deleted, but our new protocol expects
@@ -937,7 +1222,7 @@ func (s *GRPCProviderServer) ReadResource(ctx context.Context, req *tfprotov5.Re
 	// helper/schema should always copy the ID over, but do it again just to be safe
 	newInstanceState.Attributes["id"] = newInstanceState.ID
 
-	newStateVal, err := hcl2shim.HCL2ValueFromFlatmap(newInstanceState.Attributes, schemaBlock.ImpliedType())
+	newStateVal, err := hcl2shim.HCL2ValueFromFlatmapBlock(newInstanceState.Attributes, schemaBlock)
 	if err != nil {
 		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
 		return resp, nil
@@ -1073,6 +1358,14 @@ func (s *GRPCProviderServer) PlanResourceChange(ctx context.Context, req *tfprot
 		return resp, nil
 	}
 
+	// Keep the prior state values of attributes and blocks whose proposed new
+	// values are semantically equal, so that they are planned without changes
+	proposedNewStateVal, _, semanticEqualsDiags := schemaMap(res.SchemaMap()).applySemanticEquals(ctx, priorStateVal, proposedNewStateVal, false)
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, semanticEqualsDiags)
+	if semanticEqualsDiags.HasError() {
+		return resp, nil
+	}
+
 	priorState, err := res.ShimInstanceStateFromValue(priorStateVal)
 	if err != nil {
 		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
@@ -1108,7 +1401,11 @@ func (s *GRPCProviderServer) PlanResourceChange(ctx context.Context, req *tfprot
 	}
 
 	// turn the proposed state into a legacy configuration
-	cfg := terraform.NewResourceConfigShimmed(proposedNewStateVal, schemaBlock)
+	cfg, err := terraform.ShimResourceConfigFromValue(proposedNewStateVal, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
 
 	var priorIdentityVal cty.Value
 	// add identity data to priorState
@@ -1132,12 +1429,24 @@ func (s *GRPCProviderServer) PlanResourceChange(ctx context.Context, req *tfprot
 		priorState.Identity = identityAttrs
 	}
 
-	diff, err := res.SimpleDiff(ctx, priorState, cfg, s.provider.Meta())
+	customizeDiff := &customizeDiffRun{
+		deferralAllowed: req.ClientCapabilities != nil && req.ClientCapabilities.DeferralAllowed,
+	}
+	diff, err := res.simpleDiff(ctx, priorState, cfg, s.provider.Meta(), customizeDiff)
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, customizeDiff.diagnostics)
+	if customizeDiff.diagnostics.HasError() {
+		return resp, nil
+	}
 	if err != nil {
 		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
 		return resp, nil
 	}
 
+	if customizeDiff.deferred != nil && !customizeDiff.deferralAllowed {
+		resp.Diagnostics = append(resp.Diagnostics, invalidDeferredResponseDiagnostic("Resource"))
+		return resp, nil
+	}
+
 	// if this is a new instance, we need to make sure ID is going to be computed
 	if create {
 		if diff == nil {
@@ -1163,6 +1472,12 @@ func (s *GRPCProviderServer) PlanResourceChange(ctx context.Context, req *tfprot
 		resp.PlannedState = req.PriorState
 		resp.PlannedPrivate = req.PriorPrivate
 		resp.PlannedIdentity = req.PriorIdentity
+
+		if customizeDiff.deferred != nil {
+			resp.Deferred = &tfprotov5.Deferred{
+				Reason: tfprotov5.DeferredReason(customizeDiff.deferred.Reason),
+			}
+		}
 		return resp, nil
 	}
 
@@ -1171,14 +1486,14 @@ func (s *GRPCProviderServer) PlanResourceChange(ctx context.Context, req *tfprot
 	}
 
 	// now we need to apply the diff to the prior state, so get the planned state
-	plannedAttrs, err := diff.Apply(priorState.Attributes, schemaBlock)
+	plannedAttrs, err := diff.Apply(priorState.Attributes, hcl2shim.LegacySchema(schemaBlock))
 
 	if err != nil {
 		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
 		return resp, nil
 	}
 
-	plannedStateVal, err := hcl2shim.HCL2ValueFromFlatmap(plannedAttrs, schemaBlock.ImpliedType())
+	plannedStateVal, err := hcl2shim.HCL2ValueFromFlatmapBlock(plannedAttrs, schemaBlock)
 	if err != nil {
 		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
 		return resp, nil
@@ -1219,6 +1534,19 @@ func (s *GRPCProviderServer) PlanResourceChange(ctx context.Context, req *tfprot
 		plannedStateVal = SetUnknowns(plannedStateVal, schemaBlock)
 	}
 
+	// Run the PlanModifiers of the attributes on the planned state
+	plannedStateVal, modifiersRequiresReplace, diags := schemaMap(res.SchemaMap()).runPlanModifiers(ctx, priorStateVal, configVal, proposedNewStateVal, plannedStateVal)
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, diags)
+	if diags.HasError() {
+		return resp, nil
+	}
+
+	plannedStateVal, err = schemaBlock.CoerceValue(plannedStateVal)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
 	// Set any write-only attribute values to null
 	plannedStateVal = setWriteOnlyNullValues(plannedStateVal, schemaBlock)
 
@@ -1276,6 +1604,8 @@ func (s *GRPCProviderServer) PlanResourceChange(ctx context.Context, req *tfprot
 				requiresNew = append(requiresNew, attr)
 			}
 		}
+	} else {
+		modifiersRequiresReplace = nil
 	}
 
 	// If anything requires a new resource already, or the "id" field indicates
@@ -1283,7 +1613,7 @@ func (s *GRPCProviderServer) PlanResourceChange(ctx context.Context, req *tfprot
 	// RequiresReplace so that core can tell if the instance is being replaced
 	// even if changes are being suppressed via "ignore_changes".
 	id := plannedStateVal.GetAttr("id")
-	if len(requiresNew) > 0 || id.IsNull() || !id.IsKnown() {
+	if len(requiresNew) > 0 || len(modifiersRequiresReplace) > 0 || id.IsNull() || !id.IsKnown() {
 		requiresNew = append(requiresNew, "id")
 	}
 
@@ -1293,6 +1623,13 @@ func (s *GRPCProviderServer) PlanResourceChange(ctx context.Context, req *tfprot
 		return resp, nil
 	}
 
+	// add the attributes whose PlanModifiers require instance replacement
+	for _, p := range modifiersRequiresReplace {
+		if !pathsContain(requiresReplace, p) {
+			requiresReplace = append(requiresReplace, p)
+		}
+	}
+
 	// convert these to the protocol structures
 	for _, p := range requiresReplace {
 		resp.RequiresReplace = append(resp.RequiresReplace, pathToAttributePath(p))
@@ -1311,6 +1648,19 @@ func (s *GRPCProviderServer) PlanResourceChange(ctx context.Context, req *tfprot
 		resp.Deferred = &tfprotov5.Deferred{
 			Reason: tfprotov5.DeferredReason(s.provider.providerDeferred.Reason),
 		}
+	} else if customizeDiff.deferred != nil {
+		// Resource deferred response is present, add the deferred response alongside the plan
+		logging.HelperSchemaDebug(
+			ctx,
+			"Resource has deferred response configured, returning deferred response with plan.",
+			map[string]interface{}{
+				logging.KeyDeferredReason: customizeDiff.deferred.Reason.String(),
+			},
+		)
+
+		resp.Deferred = &tfprotov5.Deferred{
+			Reason: tfprotov5.DeferredReason(customizeDiff.deferred.Reason),
+		}
 	}
 
 	if res.Identity != nil {
@@ -1526,7 +1876,7 @@ func (s *GRPCProviderServer) ApplyResourceChange(ctx context.Context, req *tfpro
 
 	// We keep the null val if we destroyed the resource, otherwise build the
 	// entire object, even if the new state was nil.
-	newStateVal, err = StateValueFromInstanceState(newInstanceState, schemaBlock.ImpliedType())
+	newStateVal, err = newInstanceState.AttrsAsObjectValueBlock(schemaBlock)
 	if err != nil {
 		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
 		return resp, nil
@@ -1691,12 +2041,53 @@ func (s *GRPCProviderServer) ImportResourceState(ctx context.Context, req *tfpro
 		identity = hcl2shim.FlatmapValueFromHCL2(identityVal)
 	}
 
-	newInstanceStates, err := s.provider.ImportStateWithIdentity(ctx, info, req.ID, identity)
+	deferralAllowed := req.ClientCapabilities != nil && req.ClientCapabilities.DeferralAllowed
+
+	newInstanceStates, deferred, err := s.provider.importStateWithIdentity(ctx, info, req.ID, identity, deferralAllowed)
 	if err != nil {
 		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
 		return resp, nil
 	}
 
+	if deferred != nil {
+		if !deferralAllowed {
+			resp.Diagnostics = append(resp.Diagnostics, invalidDeferredResponseDiagnostic("Resource"))
+			return resp, nil
+		}
+
+		logging.HelperSchemaDebug(
+			ctx,
+			"Resource has deferred response configured, returning deferred response.",
+			map[string]interface{}{
+				logging.KeyDeferredReason: deferred.Reason.String(),
+			},
+		)
+
+		// Send an unknown value for the imported object
+		schemaBlock := s.getResourceSchemaBlock(req.TypeName)
+		unknownVal := cty.UnknownVal(schemaBlock.ImpliedType())
+		unknownStateMp, err := msgpack.Marshal(unknownVal, schemaBlock.ImpliedType())
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+			return resp, nil
+		}
+
+		resp.ImportedResources = []*tfprotov5.ImportedResource{
+			{
+				TypeName: req.TypeName,
+				State: &tfprotov5.DynamicValue{
+					MsgPack: unknownStateMp,
+				},
+			},
+		}
+
+		resp.Deferred = &tfprotov5.Deferred{
+			Reason: tfprotov5.DeferredReason(deferred.Reason),
+		}
+
+		return resp, nil
+	}
+
 	for _, is := range newInstanceStates {
 		// copy the ID again just to be sure it wasn't missed
 		is.Attributes["id"] = is.ID
@@ -1707,7 +2098,7 @@ func (s *GRPCProviderServer) ImportResourceState(ctx context.Context, req *tfpro
 		}
 
 		schemaBlock := s.getResourceSchemaBlock(resourceType)
-		newStateVal, err := hcl2shim.HCL2ValueFromFlatmap(is.Attributes, schemaBlock.ImpliedType())
+		newStateVal, err := hcl2shim.HCL2ValueFromFlatmapBlock(is.Attributes, schemaBlock)
 		if err != nil {
 			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
 			return resp, nil
@@ -1964,11 +2355,9 @@ func (s *GRPCProviderServer) MoveResourceState(ctx context.Context, req *tfproto
 
 	ctx = logging.InitContext(ctx)
 
-	logging.HelperSchemaTrace(ctx, "Returning error for MoveResourceState")
-
 	resp := &tfprotov5.MoveResourceStateResponse{}
 
-	_, ok := s.provider.ResourcesMap[req.TargetTypeName]
+	res, ok := s.provider.ResourcesMap[req.TargetTypeName]
 
 	if !ok {
 		resp.Diagnostics = []*tfprotov5.Diagnostic{
@@ -1982,51 +2371,215 @@ func (s *GRPCProviderServer) MoveResourceState(ctx context.Context, req *tfproto
 		return resp, nil
 	}
 
-	resp.Diagnostics = []*tfprotov5.Diagnostic{
-		{
-			Severity: tfprotov5.DiagnosticSeverityError,
-			Summary:  "Move Resource State Not Supported",
-			Detail:   fmt.Sprintf("The %q resource type does not support moving resource state across resource types.", req.TargetTypeName),
-		},
-	}
+	var mover *StateMover
 
-	return resp, nil
-}
+	for i, m := range res.StateMovers {
+		if m.SourceTypeName != req.SourceTypeName || m.SourceSchemaVersion != req.SourceSchemaVersion {
+			continue
+		}
 
-func (s *GRPCProviderServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
-	ctx = logging.InitContext(ctx)
-	resp := &tfprotov5.ReadDataSourceResponse{}
+		if m.SourceProviderAddress != "" && m.SourceProviderAddress != req.SourceProviderAddress {
+			continue
+		}
 
-	schemaBlock := s.getDatasourceSchemaBlock(req.TypeName)
+		mover = &res.StateMovers[i]
+		break
+	}
 
-	if s.provider.providerDeferred != nil {
-		logging.HelperSchemaDebug(
-			ctx,
-			"Provider has deferred response configured, automatically returning deferred response.",
-			map[string]interface{}{
-				logging.KeyDeferredReason: s.provider.providerDeferred.Reason.String(),
-			},
-		)
+	if mover == nil {
+		logging.HelperSchemaTrace(ctx, "Returning error for MoveResourceState")
 
-		// Send an unknown value for the data source
-		unknownVal := cty.UnknownVal(schemaBlock.ImpliedType())
-		unknownStateMp, err := msgpack.Marshal(unknownVal, schemaBlock.ImpliedType())
-		if err != nil {
-			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
-			return resp, nil
-		}
+		detail := fmt.Sprintf("The %q resource type does not support moving resource state across resource types.", req.TargetTypeName)
 
-		resp.State = &tfprotov5.DynamicValue{
-			MsgPack: unknownStateMp,
+		if len(res.StateMovers) > 0 {
+			detail = fmt.Sprintf("The %q resource type does not support moving resource state from the %q resource type at schema version %d of provider %q.",
+				req.TargetTypeName, req.SourceTypeName, req.SourceSchemaVersion, req.SourceProviderAddress)
 		}
-		resp.Deferred = &tfprotov5.Deferred{
-			Reason: tfprotov5.DeferredReason(s.provider.providerDeferred.Reason),
+
+		resp.Diagnostics = []*tfprotov5.Diagnostic{
+			{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Move Resource State Not Supported",
+				Detail:   detail,
+			},
 		}
+
 		return resp, nil
 	}
 
-	configVal, err := msgpack.Unmarshal(req.Config.MsgPack, schemaBlock.ImpliedType())
-	if err != nil {
+	if req.SourceState == nil || len(req.SourceState.JSON) == 0 {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf("moving resource state requires the source state to be JSON encoded"))
+		return resp, nil
+	}
+
+	sourceState, err := s.unmarshalMoveRawState(req.SourceState.JSON, res)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf("decoding source state: %w", err))
+		return resp, nil
+	}
+
+	var sourceIdentity map[string]interface{}
+	if req.SourceIdentity != nil && len(req.SourceIdentity.JSON) > 0 {
+		sourceIdentity, err = s.unmarshalMoveRawState(req.SourceIdentity.JSON, res)
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf("decoding source identity: %w", err))
+			return resp, nil
+		}
+	}
+
+	moveReq := MoveStateRequest{
+		SourceProviderAddress: req.SourceProviderAddress,
+		SourceTypeName:        req.SourceTypeName,
+		SourceSchemaVersion:   req.SourceSchemaVersion,
+		SourceState:           sourceState,
+		SourceIdentity:        sourceIdentity,
+		Meta:                  s.provider.Meta(),
+		resource:              res,
+	}
+	moveResp := &MoveStateResponse{}
+
+	logging.HelperSchemaTrace(ctx, "Calling downstream")
+	mover.Move(ctx, moveReq, moveResp)
+	logging.HelperSchemaTrace(ctx, "Called downstream")
+
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, moveResp.Diagnostics)
+	if moveResp.Diagnostics.HasError() {
+		return resp, nil
+	}
+
+	var newInstanceState *terraform.InstanceState
+	if moveResp.ResourceData != nil {
+		newInstanceState = moveResp.ResourceData.State()
+	}
+
+	if newInstanceState == nil || newInstanceState.ID == "" {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf(
+			"Missing Resource State After Move: The Terraform provider unexpectedly returned no resource state after having no errors in the resource move. "+
+				"This is always a problem with the provider and should be reported to the provider developer",
+		))
+		return resp, nil
+	}
+
+	// helper/schema should always copy the ID over, but do it again just to be safe
+	newInstanceState.Attributes["id"] = newInstanceState.ID
+
+	schemaBlock := s.getResourceSchemaBlock(req.TargetTypeName)
+
+	newStateVal, err := hcl2shim.HCL2ValueFromFlatmapBlock(newInstanceState.Attributes, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	// Normalize the value and fill in any missing blocks.
+	newStateVal = objchange.NormalizeObjectFromLegacySDK(newStateVal, schemaBlock)
+	newStateVal = setWriteOnlyNullValues(newStateVal, schemaBlock)
+
+	newStateMP, err := msgpack.Marshal(newStateVal, schemaBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	resp.TargetState = &tfprotov5.DynamicValue{
+		MsgPack: newStateMP,
+	}
+
+	resp.TargetPrivate, err = json.Marshal(newInstanceState.Meta)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	// The target resource must have an identity if it declares one, even if
+	// the source resource had none.
+	if res.Identity != nil {
+		identityBlock, err := s.getResourceIdentitySchemaBlock(req.TargetTypeName)
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf("getting identity schema failed for resource '%s': %w", req.TargetTypeName, err))
+			return resp, nil
+		}
+
+		newIdentityVal, err := hcl2shim.HCL2ValueFromFlatmap(newInstanceState.Identity, identityBlock.ImpliedType())
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+			return resp, nil
+		}
+
+		if isCtyObjectNullOrEmpty(newIdentityVal) {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf(
+				"Missing Resource Identity After Move: The Terraform provider unexpectedly returned no resource identity after having no errors in the resource move. "+
+					"This is always a problem with the provider and should be reported to the provider developer",
+			))
+			return resp, nil
+		}
+
+		newIdentityMP, err := msgpack.Marshal(newIdentityVal, identityBlock.ImpliedType())
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+			return resp, nil
+		}
+
+		resp.TargetIdentity = &tfprotov5.ResourceIdentityData{
+			IdentityData: &tfprotov5.DynamicValue{
+				MsgPack: newIdentityMP,
+			},
+		}
+	}
+
+	return resp, nil
+}
+
+// unmarshalMoveRawState decodes the JSON source state or identity of a
+// MoveResourceState request, honoring the UseJSONNumber setting of the target
+// resource.
+func (s *GRPCProviderServer) unmarshalMoveRawState(b []byte, res *Resource) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+
+	var err error
+	if res.UseJSONNumber {
+		err = unmarshalJSON(b, &m)
+	} else {
+		err = json.Unmarshal(b, &m)
+	}
+
+	return m, err
+}
+
+func (s *GRPCProviderServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	ctx = logging.InitContext(ctx)
+	resp := &tfprotov5.ReadDataSourceResponse{}
+
+	schemaBlock := s.getDatasourceSchemaBlock(req.TypeName)
+
+	if s.provider.providerDeferred != nil {
+		logging.HelperSchemaDebug(
+			ctx,
+			"Provider has deferred response configured, automatically returning deferred response.",
+			map[string]interface{}{
+				logging.KeyDeferredReason: s.provider.providerDeferred.Reason.String(),
+			},
+		)
+
+		// Send an unknown value for the data source
+		unknownVal := cty.UnknownVal(schemaBlock.ImpliedType())
+		unknownStateMp, err := msgpack.Marshal(unknownVal, schemaBlock.ImpliedType())
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+			return resp, nil
+		}
+
+		resp.State = &tfprotov5.DynamicValue{
+			MsgPack: unknownStateMp,
+		}
+		resp.Deferred = &tfprotov5.Deferred{
+			Reason: tfprotov5.DeferredReason(s.provider.providerDeferred.Reason),
+		}
+		return resp, nil
+	}
+
+	configVal, err := msgpack.Unmarshal(req.Config.MsgPack, schemaBlock.ImpliedType())
+	if err != nil {
 		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
 		return resp, nil
 	}
@@ -2037,250 +2590,861 @@ func (s *GRPCProviderServer) ReadDataSource(ctx context.Context, req *tfprotov5.
 		return resp, nil
 	}
 
-	config := terraform.NewResourceConfigShimmed(configVal, schemaBlock)
+	config, err := terraform.ShimResourceConfigFromValue(configVal, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	// we need to still build the diff separately with the Read method to match
+	// the old behavior
+	res, ok := s.provider.DataSourcesMap[req.TypeName]
+	if !ok {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf("unknown data source: %s", req.TypeName))
+		return resp, nil
+	}
+	diff, err := res.Diff(ctx, nil, config, s.provider.Meta())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	// Not setting RawConfig here is okay, as ResourceData.GetRawConfig()
+	// will return a NullVal of the schema if there is no InstanceDiff.
+	if diff != nil {
+		diff.RawConfig = configVal
+	}
+
+	deferralAllowed := req.ClientCapabilities != nil && req.ClientCapabilities.DeferralAllowed
+
+	// now we can get the new complete data source
+	newInstanceState, deferred, diags := res.readDataApply(ctx, diff, s.provider.Meta(), deferralAllowed)
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, diags)
+	if diags.HasError() {
+		return resp, nil
+	}
+
+	if deferred != nil {
+		if !deferralAllowed {
+			resp.Diagnostics = append(resp.Diagnostics, invalidDeferredResponseDiagnostic("Data Source"))
+			return resp, nil
+		}
+
+		logging.HelperSchemaDebug(
+			ctx,
+			"Data source has deferred response configured, returning deferred response.",
+			map[string]interface{}{
+				logging.KeyDeferredReason: deferred.Reason.String(),
+			},
+		)
+
+		// Send an unknown value for the data source
+		unknownVal := cty.UnknownVal(schemaBlock.ImpliedType())
+		unknownStateMp, err := msgpack.Marshal(unknownVal, schemaBlock.ImpliedType())
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+			return resp, nil
+		}
+
+		resp.State = &tfprotov5.DynamicValue{
+			MsgPack: unknownStateMp,
+		}
+		resp.Deferred = &tfprotov5.Deferred{
+			Reason: tfprotov5.DeferredReason(deferred.Reason),
+		}
+		return resp, nil
+	}
+
+	newStateVal, err := newInstanceState.AttrsAsObjectValueBlock(schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	newStateVal = copyTimeoutValues(newStateVal, configVal)
+
+	newStateMP, err := msgpack.Marshal(newStateVal, schemaBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+	resp.State = &tfprotov5.DynamicValue{
+		MsgPack: newStateMP,
+	}
+	return resp, nil
+}
+
+func (s *GRPCProviderServer) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	ctx = logging.InitContext(ctx)
+	resp := &tfprotov5.CallFunctionResponse{}
+
+	f, ok := s.provider.Functions[req.Name]
+	if !ok {
+		resp.Error = &tfprotov5.FunctionError{
+			Text: fmt.Sprintf("Function Not Found: No function named %q was found in the provider.", req.Name),
+		}
+		return resp, nil
+	}
+
+	if len(req.Arguments) < len(f.Parameters) || (f.VariadicParameter == nil && len(req.Arguments) > len(f.Parameters)) {
+		resp.Error = &tfprotov5.FunctionError{
+			Text: fmt.Sprintf("Unexpected Function Arguments: The %q function expects %d arguments, got %d. "+
+				"This is always a bug in Terraform and should be reported to the Terraform developers.", req.Name, len(f.Parameters), len(req.Arguments)),
+		}
+		return resp, nil
+	}
+
+	args := make([]interface{}, 0, len(f.Parameters)+1)
+	var variadicArgs []interface{}
+
+	for i, arg := range req.Arguments {
+		param := f.VariadicParameter
+		if i < len(f.Parameters) {
+			param = f.Parameters[i]
+		}
+
+		paramSchema := param.schema()
+
+		argVal, err := msgpack.Unmarshal(arg.MsgPack, paramSchema.coreConfigSchemaType())
+		if err != nil {
+			resp.Error = functionArgumentError(i, fmt.Errorf("Invalid Argument: unable to decode argument %q: %w", param.Name, err))
+			return resp, nil
+		}
+
+		if argVal.IsNull() && !param.AllowNullValue {
+			resp.Error = functionArgumentError(i, fmt.Errorf("Invalid Argument: argument %q must not be null", param.Name))
+			return resp, nil
+		}
+
+		v, err := functionValueToGo(argVal, paramSchema)
+		if err != nil {
+			resp.Error = functionArgumentError(i, fmt.Errorf("Invalid Argument: argument %q: %w", param.Name, err))
+			return resp, nil
+		}
+
+		if i < len(f.Parameters) {
+			args = append(args, v)
+		} else {
+			variadicArgs = append(variadicArgs, v)
+		}
+	}
+
+	if f.VariadicParameter != nil {
+		if variadicArgs == nil {
+			variadicArgs = []interface{}{}
+		}
+		args = append(args, variadicArgs)
+	}
+
+	logging.HelperSchemaTrace(ctx, "Calling downstream")
+	result, err := f.Run(ctx, args)
+	logging.HelperSchemaTrace(ctx, "Called downstream")
+
+	if err != nil {
+		var argErr *FunctionArgumentError
+		if errors.As(err, &argErr) {
+			resp.Error = functionArgumentError(argErr.Index, err)
+			return resp, nil
+		}
+
+		resp.Error = &tfprotov5.FunctionError{
+			Text: err.Error(),
+		}
+		return resp, nil
+	}
+
+	returnType := f.Return.schema().coreConfigSchemaType()
+
+	resultVal, err := functionValueFromGo(result, returnType)
+	if err != nil {
+		resp.Error = &tfprotov5.FunctionError{
+			Text: fmt.Sprintf("Invalid Function Result: The %q function returned a value which could not be converted to its return type. "+
+				"This is always a bug in the provider and should be reported to the provider developers.\n\n"+
+				"Error: %s", req.Name, err),
+		}
+		return resp, nil
+	}
+
+	resultMP, err := msgpack.Marshal(resultVal, returnType)
+	if err != nil {
+		resp.Error = &tfprotov5.FunctionError{
+			Text: err.Error(),
+		}
+		return resp, nil
+	}
+
+	resp.Result = &tfprotov5.DynamicValue{
+		MsgPack: resultMP,
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServer) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	ctx = logging.InitContext(ctx)
+
+	logging.HelperSchemaTrace(ctx, "Getting provider functions")
+
+	resp := &tfprotov5.GetFunctionsResponse{
+		Functions: make(map[string]*tfprotov5.Function, len(s.provider.Functions)),
+	}
+
+	for name, f := range s.provider.Functions {
+		function, err := f.ProtoFunction()
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf("getting function definition failed for function '%s': %w", name, err))
+			return resp, nil
+		}
+
+		resp.Functions[name] = function
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServer) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	ctx = logging.InitContext(ctx)
+	resp := &tfprotov5.ValidateEphemeralResourceConfigResponse{}
+
+	r, ok := s.provider.EphemeralResourcesMap[req.TypeName]
+	if !ok {
+		resp.Diagnostics = unknownEphemeralResourceTypeDiags(req.TypeName)
+		return resp, nil
+	}
+
+	schemaBlock := r.CoreConfigSchema()
+
+	configVal, err := msgpack.Unmarshal(req.Config.MsgPack, schemaBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	// Ensure there are no nulls that will cause helper/schema to panic.
+	if err := validateConfigNulls(ctx, configVal, nil); err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	config, err := terraform.ShimResourceConfigFromValue(configVal, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	logging.HelperSchemaTrace(ctx, "Calling downstream")
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, r.Validate(config))
+	logging.HelperSchemaTrace(ctx, "Called downstream")
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServer) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	ctx = logging.InitContext(ctx)
+	resp := &tfprotov5.OpenEphemeralResourceResponse{}
+
+	r, ok := s.provider.EphemeralResourcesMap[req.TypeName]
+	if !ok {
+		resp.Diagnostics = unknownEphemeralResourceTypeDiags(req.TypeName)
+		return resp, nil
+	}
+
+	schemaBlock := r.CoreConfigSchema()
+
+	if s.provider.providerDeferred != nil {
+		logging.HelperSchemaDebug(
+			ctx,
+			"Provider has deferred response configured, automatically returning deferred response.",
+			map[string]interface{}{
+				logging.KeyDeferredReason: s.provider.providerDeferred.Reason.String(),
+			},
+		)
+
+		// Send an unknown value for the ephemeral resource
+		unknownVal := cty.UnknownVal(schemaBlock.ImpliedType())
+		unknownResultMP, err := msgpack.Marshal(unknownVal, schemaBlock.ImpliedType())
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+			return resp, nil
+		}
+
+		resp.Result = &tfprotov5.DynamicValue{
+			MsgPack: unknownResultMP,
+		}
+		resp.Deferred = &tfprotov5.Deferred{
+			Reason: tfprotov5.DeferredReason(s.provider.providerDeferred.Reason),
+		}
+		return resp, nil
+	}
+
+	configVal, err := msgpack.Unmarshal(req.Config.MsgPack, schemaBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	// Ensure there are no nulls that will cause helper/schema to panic.
+	if err := validateConfigNulls(ctx, configVal, nil); err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	deferralAllowed := req.ClientCapabilities != nil && req.ClientCapabilities.DeferralAllowed
+
+	logging.HelperSchemaTrace(ctx, "Calling downstream")
+	resultVal, openResp, err := r.open(ctx, configVal, deferralAllowed, s.provider.Meta())
+	logging.HelperSchemaTrace(ctx, "Called downstream")
+
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, openResp.Diagnostics)
+	if openResp.Diagnostics.HasError() {
+		return resp, nil
+	}
+
+	if openResp.Deferred != nil {
+		if !deferralAllowed {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Invalid Deferred Ephemeral Resource Response",
+				Detail: "Ephemeral resource configured a deferred response but the Terraform request " +
+					"did not indicate support for deferred actions. This is an issue with the provider and should be reported to the provider developers.",
+			})
+			return resp, nil
+		}
+
+		resultVal = cty.UnknownVal(schemaBlock.ImpliedType())
+		resp.Deferred = &tfprotov5.Deferred{
+			Reason: tfprotov5.DeferredReason(openResp.Deferred.Reason),
+		}
+	} else {
+		// Normalize the value and fill in any missing blocks.
+		resultVal = objchange.NormalizeObjectFromLegacySDK(resultVal, schemaBlock)
+	}
+
+	resultMP, err := msgpack.Marshal(resultVal, schemaBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	resp.Result = &tfprotov5.DynamicValue{
+		MsgPack: resultMP,
+	}
+
+	resp.Private, err = marshalEphemeralPrivate(openResp.Private)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	resp.RenewAt = openResp.RenewAt
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServer) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	ctx = logging.InitContext(ctx)
+	resp := &tfprotov5.RenewEphemeralResourceResponse{
+		Private: req.Private,
+	}
+
+	r, ok := s.provider.EphemeralResourcesMap[req.TypeName]
+	if !ok {
+		resp.Diagnostics = unknownEphemeralResourceTypeDiags(req.TypeName)
+		return resp, nil
+	}
+
+	if r.Renew == nil {
+		logging.HelperSchemaDebug(ctx, "Ephemeral resource does not implement Renew, skipping")
+		return resp, nil
+	}
+
+	private, err := unmarshalEphemeralPrivate(req.Private)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	renewReq := RenewEphemeralResourceRequest{
+		Private: private,
+		Meta:    s.provider.Meta(),
+	}
+	renewResp := &RenewEphemeralResourceResponse{}
+
+	logging.HelperSchemaTrace(ctx, "Calling downstream")
+	r.Renew(ctx, renewReq, renewResp)
+	logging.HelperSchemaTrace(ctx, "Called downstream")
+
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, renewResp.Diagnostics)
+	if renewResp.Diagnostics.HasError() {
+		return resp, nil
+	}
+
+	if renewResp.Private != nil {
+		resp.Private, err = marshalEphemeralPrivate(renewResp.Private)
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+			return resp, nil
+		}
+	}
+
+	resp.RenewAt = renewResp.RenewAt
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServer) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	ctx = logging.InitContext(ctx)
+	resp := &tfprotov5.CloseEphemeralResourceResponse{}
+
+	r, ok := s.provider.EphemeralResourcesMap[req.TypeName]
+	if !ok {
+		resp.Diagnostics = unknownEphemeralResourceTypeDiags(req.TypeName)
+		return resp, nil
+	}
+
+	if r.Close == nil {
+		logging.HelperSchemaDebug(ctx, "Ephemeral resource does not implement Close, skipping")
+		return resp, nil
+	}
+
+	private, err := unmarshalEphemeralPrivate(req.Private)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	closeReq := CloseEphemeralResourceRequest{
+		Private: private,
+		Meta:    s.provider.Meta(),
+	}
+	closeResp := &CloseEphemeralResourceResponse{}
+
+	logging.HelperSchemaTrace(ctx, "Calling downstream")
+	r.Close(ctx, closeReq, closeResp)
+	logging.HelperSchemaTrace(ctx, "Called downstream")
+
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, closeResp.Diagnostics)
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServer) ValidateListResourceConfig(ctx context.Context, req *tfprotov5.ValidateListResourceConfigRequest) (*tfprotov5.ValidateListResourceConfigResponse, error) {
+	ctx = logging.InitContext(ctx)
+	resp := &tfprotov5.ValidateListResourceConfigResponse{}
+
+	res, ok := s.provider.ResourcesMap[req.TypeName]
+	if !ok || res.List == nil {
+		resp.Diagnostics = unknownListResourceTypeDiags(req.TypeName)
+		return resp, nil
+	}
+
+	schemaBlock := res.CoreListConfigSchema()
+
+	configVal, err := msgpack.Unmarshal(req.Config.MsgPack, schemaBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	// Ensure there are no nulls that will cause helper/schema to panic.
+	if err := validateConfigNulls(ctx, configVal, nil); err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	config, err := terraform.ShimResourceConfigFromValue(configVal, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	logging.HelperSchemaTrace(ctx, "Calling downstream")
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, schemaMap(res.ListConfigSchema).Validate(config))
+	logging.HelperSchemaTrace(ctx, "Called downstream")
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServer) ListResource(ctx context.Context, req *tfprotov5.ListResourceRequest) (*tfprotov5.ListResourceServerStream, error) {
+	ctx = logging.InitContext(ctx)
+
+	res, ok := s.provider.ResourcesMap[req.TypeName]
+	if !ok || res.List == nil {
+		resp := &tfprotov5.ListResourceServerStream{
+			Results: slices.Values([]tfprotov5.ListResourceResult{
+				{
+					Diagnostics: unknownListResourceTypeDiags(req.TypeName),
+				},
+			}),
+		}
 
-	// we need to still build the diff separately with the Read method to match
-	// the old behavior
-	res, ok := s.provider.DataSourcesMap[req.TypeName]
-	if !ok {
-		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf("unknown data source: %s", req.TypeName))
 		return resp, nil
 	}
-	diff, err := res.Diff(ctx, nil, config, s.provider.Meta())
-	if err != nil {
-		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
-		return resp, nil
+
+	errorResult := func(err interface{}) *tfprotov5.ListResourceServerStream {
+		return &tfprotov5.ListResourceServerStream{
+			Results: slices.Values([]tfprotov5.ListResourceResult{
+				{
+					Diagnostics: convert.AppendProtoDiag(ctx, nil, err),
+				},
+			}),
+		}
 	}
 
-	// Not setting RawConfig here is okay, as ResourceData.GetRawConfig()
-	// will return a NullVal of the schema if there is no InstanceDiff.
-	if diff != nil {
-		diff.RawConfig = configVal
+	configVal, err := msgpack.Unmarshal(req.Config.MsgPack, res.CoreListConfigSchema().ImpliedType())
+	if err != nil {
+		return errorResult(err), nil
 	}
 
-	// now we can get the new complete data source
-	newInstanceState, diags := res.ReadDataApply(ctx, diff, s.provider.Meta())
-	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, diags)
-	if diags.HasError() {
-		return resp, nil
+	// Ensure there are no nulls that will cause helper/schema to panic.
+	if err := validateConfigNulls(ctx, configVal, nil); err != nil {
+		return errorResult(err), nil
 	}
 
-	newStateVal, err := StateValueFromInstanceState(newInstanceState, schemaBlock.ImpliedType())
+	config, err := schemaMap(res.ListConfigSchema).configData(ctx, configVal, s.provider.Meta())
 	if err != nil {
-		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
-		return resp, nil
+		return errorResult(err), nil
 	}
 
-	newStateVal = copyTimeoutValues(newStateVal, configVal)
+	schemaBlock := s.getResourceSchemaBlock(req.TypeName)
 
-	newStateMP, err := msgpack.Marshal(newStateVal, schemaBlock.ImpliedType())
+	identityBlock, err := s.getResourceIdentitySchemaBlock(req.TypeName)
 	if err != nil {
-		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
-		return resp, nil
+		return errorResult(fmt.Errorf("getting identity schema failed for resource '%s': %w", req.TypeName, err)), nil
 	}
-	resp.State = &tfprotov5.DynamicValue{
-		MsgPack: newStateMP,
+
+	listReq := ListResourceRequest{
+		Config:          config,
+		IncludeResource: req.IncludeResource,
+		Limit:           req.Limit,
+		Meta:            s.provider.Meta(),
+		resource:        res,
 	}
-	return resp, nil
-}
 
-func (s *GRPCProviderServer) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
-	ctx = logging.InitContext(ctx)
+	resp := &tfprotov5.ListResourceServerStream{
+		Results: func(yield func(tfprotov5.ListResourceResult) bool) {
+			var count int64
 
-	logging.HelperSchemaTrace(ctx, "Returning error for provider function call")
+			logging.HelperSchemaTrace(ctx, "Calling downstream")
+			for result := range res.List(ctx, listReq) {
+				protoResult := s.listResourceResult(ctx, result, schemaBlock, identityBlock, req.IncludeResource)
 
-	resp := &tfprotov5.CallFunctionResponse{
-		Error: &tfprotov5.FunctionError{
-			Text: fmt.Sprintf("Function Not Found: No function named %q was found in the provider.", req.Name),
+				if !yield(protoResult) {
+					break
+				}
+
+				// Results which only contain diagnostics do not count
+				// towards the limit.
+				if protoResult.Identity == nil {
+					continue
+				}
+
+				count++
+
+				if req.Limit > 0 && count >= req.Limit {
+					break
+				}
+			}
+			logging.HelperSchemaTrace(ctx, "Called downstream")
 		},
 	}
 
 	return resp, nil
 }
 
-func (s *GRPCProviderServer) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
-	ctx = logging.InitContext(ctx)
+// listResourceResult converts a single result of a Resource List function
+// into its protocol representation. The identity is always encoded with the
+// resource Identity schema, while the state is only encoded when Terraform
+// requested it.
+func (s *GRPCProviderServer) listResourceResult(ctx context.Context, result ListResourceResult, schemaBlock, identityBlock *configschema.Block, includeResource bool) tfprotov5.ListResourceResult {
+	resp := tfprotov5.ListResourceResult{
+		DisplayName: result.DisplayName,
+		Diagnostics: convert.AppendProtoDiag(ctx, nil, result.Diagnostics),
+	}
 
-	logging.HelperSchemaTrace(ctx, "Getting provider functions")
+	if result.Diagnostics.HasError() || result.ResourceData == nil {
+		return resp
+	}
 
-	resp := &tfprotov5.GetFunctionsResponse{
-		Functions: make(map[string]*tfprotov5.Function, 0),
+	d := result.ResourceData
+
+	if resp.DisplayName == "" {
+		resp.DisplayName = d.Id()
 	}
 
-	return resp, nil
-}
+	identity, err := d.identityState()
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp
+	}
 
-func (s *GRPCProviderServer) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
-	ctx = logging.InitContext(ctx)
+	identityVal, err := hcl2shim.HCL2ValueFromFlatmap(identity, identityBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp
+	}
 
-	logging.HelperSchemaTrace(ctx, "Returning error for ephemeral resource validate")
+	if isCtyObjectNullOrEmpty(identityVal) {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf(
+			"Missing Resource Identity After List: The Terraform provider unexpectedly returned a list result with no resource identity. "+
+				"This is always a problem with the provider and should be reported to the provider developer",
+		))
+		return resp
+	}
 
-	resp := &tfprotov5.ValidateEphemeralResourceConfigResponse{
-		Diagnostics: []*tfprotov5.Diagnostic{
-			{
-				Severity: tfprotov5.DiagnosticSeverityError,
-				Summary:  "Unknown Ephemeral Resource Type",
-				Detail:   fmt.Sprintf("The %q ephemeral resource type is not supported by this provider.", req.TypeName),
-			},
+	identityMP, err := msgpack.Marshal(identityVal, identityBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp
+	}
+
+	resp.Identity = &tfprotov5.ResourceIdentityData{
+		IdentityData: &tfprotov5.DynamicValue{
+			MsgPack: identityMP,
 		},
 	}
 
-	return resp, nil
-}
+	if !includeResource {
+		return resp
+	}
 
-func (s *GRPCProviderServer) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
-	ctx = logging.InitContext(ctx)
+	state := d.State()
+	if state == nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, fmt.Errorf(
+			"Missing Resource ID After List: The Terraform provider unexpectedly returned a list result with resource data but no ID. "+
+				"This is always a problem with the provider and should be reported to the provider developer",
+		))
+		return resp
+	}
 
-	logging.HelperSchemaTrace(ctx, "Returning error for ephemeral resource open")
+	// helper/schema should always copy the ID over, but do it again just to be safe
+	state.Attributes["id"] = state.ID
 
-	resp := &tfprotov5.OpenEphemeralResourceResponse{
-		Diagnostics: []*tfprotov5.Diagnostic{
-			{
-				Severity: tfprotov5.DiagnosticSeverityError,
-				Summary:  "Unknown Ephemeral Resource Type",
-				Detail:   fmt.Sprintf("The %q ephemeral resource type is not supported by this provider.", req.TypeName),
-			},
-		},
+	stateVal, err := hcl2shim.HCL2ValueFromFlatmapBlock(state.Attributes, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp
 	}
 
-	return resp, nil
+	// Normalize the value and fill in any missing blocks.
+	stateVal = objchange.NormalizeObjectFromLegacySDK(stateVal, schemaBlock)
+	stateVal = setWriteOnlyNullValues(stateVal, schemaBlock)
+
+	stateMP, err := msgpack.Marshal(stateVal, schemaBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp
+	}
+
+	resp.Resource = &tfprotov5.DynamicValue{
+		MsgPack: stateMP,
+	}
+
+	return resp
 }
 
-func (s *GRPCProviderServer) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+func (s *GRPCProviderServer) ValidateActionConfig(ctx context.Context, req *tfprotov5.ValidateActionConfigRequest) (*tfprotov5.ValidateActionConfigResponse, error) {
 	ctx = logging.InitContext(ctx)
+	resp := &tfprotov5.ValidateActionConfigResponse{}
 
-	logging.HelperSchemaTrace(ctx, "Returning error for ephemeral resource renew")
+	a, ok := s.provider.ActionsMap[req.ActionType]
+	if !ok {
+		resp.Diagnostics = unknownActionTypeDiags(req.ActionType)
+		return resp, nil
+	}
 
-	resp := &tfprotov5.RenewEphemeralResourceResponse{
-		Diagnostics: []*tfprotov5.Diagnostic{
-			{
-				Severity: tfprotov5.DiagnosticSeverityError,
-				Summary:  "Unknown Ephemeral Resource Type",
-				Detail:   fmt.Sprintf("The %q ephemeral resource type is not supported by this provider.", req.TypeName),
-			},
-		},
+	schemaBlock := a.CoreConfigSchema()
+
+	configVal, err := msgpack.Unmarshal(req.Config.MsgPack, schemaBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	// Ensure there are no nulls that will cause helper/schema to panic.
+	if err := validateConfigNulls(ctx, configVal, nil); err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	config, err := terraform.ShimResourceConfigFromValue(configVal, schemaBlock)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
 	}
 
+	logging.HelperSchemaTrace(ctx, "Calling downstream")
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, a.Validate(config))
+	logging.HelperSchemaTrace(ctx, "Called downstream")
+
 	return resp, nil
 }
 
-func (s *GRPCProviderServer) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+func (s *GRPCProviderServer) PlanAction(ctx context.Context, req *tfprotov5.PlanActionRequest) (*tfprotov5.PlanActionResponse, error) {
 	ctx = logging.InitContext(ctx)
+	resp := &tfprotov5.PlanActionResponse{}
 
-	logging.HelperSchemaTrace(ctx, "Returning error for ephemeral resource close")
+	a, ok := s.provider.ActionsMap[req.ActionType]
+	if !ok {
+		resp.Diagnostics = unknownActionTypeDiags(req.ActionType)
+		return resp, nil
+	}
 
-	resp := &tfprotov5.CloseEphemeralResourceResponse{
-		Diagnostics: []*tfprotov5.Diagnostic{
-			{
-				Severity: tfprotov5.DiagnosticSeverityError,
-				Summary:  "Unknown Ephemeral Resource Type",
-				Detail:   fmt.Sprintf("The %q ephemeral resource type is not supported by this provider.", req.TypeName),
+	if s.provider.providerDeferred != nil {
+		logging.HelperSchemaDebug(
+			ctx,
+			"Provider has deferred response configured, automatically returning deferred response.",
+			map[string]interface{}{
+				logging.KeyDeferredReason: s.provider.providerDeferred.Reason.String(),
 			},
-		},
+		)
+
+		resp.Deferred = &tfprotov5.Deferred{
+			Reason: tfprotov5.DeferredReason(s.provider.providerDeferred.Reason),
+		}
+		return resp, nil
 	}
 
-	return resp, nil
-}
+	if a.Plan == nil {
+		return resp, nil
+	}
 
-func (s *GRPCProviderServer) ValidateListResourceConfig(ctx context.Context, req *tfprotov5.ValidateListResourceConfigRequest) (*tfprotov5.ValidateListResourceConfigResponse, error) {
-	ctx = logging.InitContext(ctx)
+	schemaBlock := a.CoreConfigSchema()
 
-	logging.HelperSchemaTrace(ctx, "Returning error for list resource validate")
+	configVal, err := msgpack.Unmarshal(req.Config.MsgPack, schemaBlock.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
 
-	resp := &tfprotov5.ValidateListResourceConfigResponse{
-		Diagnostics: []*tfprotov5.Diagnostic{
-			{
-				Severity: tfprotov5.DiagnosticSeverityError,
-				Summary:  "Unknown List Resource Type",
-				Detail:   fmt.Sprintf("The %q list resource type is not supported by this provider.", req.TypeName),
-			},
-		},
+	// Ensure there are no nulls that will cause helper/schema to panic.
+	if err := validateConfigNulls(ctx, configVal, nil); err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
 	}
 
-	return resp, nil
-}
+	data, err := schemaMap(a.SchemaMap()).configData(ctx, configVal, s.provider.Meta())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, err)
+		return resp, nil
+	}
 
-func (s *GRPCProviderServer) ListResource(ctx context.Context, req *tfprotov5.ListResourceRequest) (*tfprotov5.ListResourceServerStream, error) {
-	ctx = logging.InitContext(ctx)
+	deferralAllowed := req.ClientCapabilities != nil && req.ClientCapabilities.DeferralAllowed
 
-	logging.HelperSchemaTrace(ctx, "Returning error for list resource list")
+	planReq := PlanActionRequest{
+		ResourceData:    data,
+		Meta:            s.provider.Meta(),
+		DeferralAllowed: deferralAllowed,
+	}
+	planResp := &PlanActionResponse{}
 
-	result := make([]tfprotov5.ListResourceResult, 0)
+	logging.HelperSchemaTrace(ctx, "Calling downstream")
+	a.Plan(ctx, planReq, planResp)
+	logging.HelperSchemaTrace(ctx, "Called downstream")
 
-	result = append(result, tfprotov5.ListResourceResult{
-		Diagnostics: []*tfprotov5.Diagnostic{
-			{
+	resp.Diagnostics = convert.AppendProtoDiag(ctx, resp.Diagnostics, planResp.Diagnostics)
+	if planResp.Diagnostics.HasError() {
+		return resp, nil
+	}
+
+	if planResp.Deferred != nil {
+		if !deferralAllowed {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 				Severity: tfprotov5.DiagnosticSeverityError,
-				Summary:  "Unknown List Resource Type",
-				Detail:   fmt.Sprintf("The %q list resource type is not supported by this provider.", req.TypeName),
-			},
-		},
-	})
+				Summary:  "Invalid Deferred Action Response",
+				Detail: "Action configured a deferred response but the Terraform request " +
+					"did not indicate support for deferred actions. This is an issue with the provider and should be reported to the provider developers.",
+			})
+			return resp, nil
+		}
 
-	resp := &tfprotov5.ListResourceServerStream{
-		Results: slices.Values(result),
+		resp.Deferred = &tfprotov5.Deferred{
+			Reason: tfprotov5.DeferredReason(planResp.Deferred.Reason),
+		}
 	}
 
 	return resp, nil
 }
 
-func (s *GRPCProviderServer) ValidateActionConfig(ctx context.Context, req *tfprotov5.ValidateActionConfigRequest) (*tfprotov5.ValidateActionConfigResponse, error) {
+func (s *GRPCProviderServer) InvokeAction(ctx context.Context, req *tfprotov5.InvokeActionRequest) (*tfprotov5.InvokeActionServerStream, error) {
 	ctx = logging.InitContext(ctx)
 
-	logging.HelperSchemaTrace(ctx, "Returning error for action type validate")
+	completed := func(diags []*tfprotov5.Diagnostic) *tfprotov5.InvokeActionServerStream {
+		return &tfprotov5.InvokeActionServerStream{
+			Events: slices.Values([]tfprotov5.InvokeActionEvent{
+				{
+					Type: tfprotov5.CompletedInvokeActionEventType{
+						Diagnostics: diags,
+					},
+				},
+			}),
+		}
+	}
 
-	resp := &tfprotov5.ValidateActionConfigResponse{
-		Diagnostics: []*tfprotov5.Diagnostic{
-			{
-				Severity: tfprotov5.DiagnosticSeverityError,
-				Summary:  "Unknown Action Type",
-				Detail:   fmt.Sprintf("The %q action type is not supported by this provider.", req.ActionType),
-			},
-		},
+	a, ok := s.provider.ActionsMap[req.ActionType]
+	if !ok {
+		return completed(unknownActionTypeDiags(req.ActionType)), nil
 	}
 
-	return resp, nil
-}
+	schemaBlock := a.CoreConfigSchema()
 
-func (s *GRPCProviderServer) PlanAction(ctx context.Context, req *tfprotov5.PlanActionRequest) (*tfprotov5.PlanActionResponse, error) {
-	ctx = logging.InitContext(ctx)
+	configVal, err := msgpack.Unmarshal(req.Config.MsgPack, schemaBlock.ImpliedType())
+	if err != nil {
+		return completed(convert.AppendProtoDiag(ctx, nil, err)), nil
+	}
 
-	logging.HelperSchemaTrace(ctx, "Returning error for action type plan")
+	// Ensure there are no nulls that will cause helper/schema to panic.
+	if err := validateConfigNulls(ctx, configVal, nil); err != nil {
+		return completed(convert.AppendProtoDiag(ctx, nil, err)), nil
+	}
 
-	resp := &tfprotov5.PlanActionResponse{
-		Diagnostics: []*tfprotov5.Diagnostic{
-			{
-				Severity: tfprotov5.DiagnosticSeverityError,
-				Summary:  "Unknown Action Type",
-				Detail:   fmt.Sprintf("The %q action type is not supported by this provider.", req.ActionType),
-			},
-		},
+	data, err := schemaMap(a.SchemaMap()).configData(ctx, configVal, s.provider.Meta())
+	if err != nil {
+		return completed(convert.AppendProtoDiag(ctx, nil, err)), nil
 	}
 
-	return resp, nil
-}
+	resp := &tfprotov5.InvokeActionServerStream{
+		Events: func(yield func(tfprotov5.InvokeActionEvent) bool) {
+			// Once Terraform stops receiving events, further progress
+			// messages from the action are dropped.
+			receiving := true
+
+			invokeReq := InvokeActionRequest{
+				ResourceData: data,
+				Meta:         s.provider.Meta(),
+				SendProgress: func(message string) {
+					if !receiving {
+						return
+					}
 
-func (s *GRPCProviderServer) InvokeAction(ctx context.Context, req *tfprotov5.InvokeActionRequest) (*tfprotov5.InvokeActionServerStream, error) {
-	ctx = logging.InitContext(ctx)
+					receiving = yield(tfprotov5.InvokeActionEvent{
+						Type: tfprotov5.ProgressInvokeActionEventType{
+							Message: message,
+						},
+					})
+				},
+			}
+			invokeResp := &InvokeActionResponse{}
 
-	logging.HelperSchemaTrace(ctx, "Returning error for action invoke")
+			logging.HelperSchemaTrace(ctx, "Calling downstream")
+			a.Invoke(ctx, invokeReq, invokeResp)
+			logging.HelperSchemaTrace(ctx, "Called downstream")
 
-	event := make([]tfprotov5.InvokeActionEvent, 0)
+			if !receiving {
+				return
+			}
 
-	event = append(event, tfprotov5.InvokeActionEvent{
-		Type: tfprotov5.CompletedInvokeActionEventType{
-			Diagnostics: []*tfprotov5.Diagnostic{
-				{
-					Severity: tfprotov5.DiagnosticSeverityError,
-					Summary:  "Unknown Action Type",
-					Detail:   fmt.Sprintf("The %q action type is not supported by this provider.", req.ActionType),
+			yield(tfprotov5.InvokeActionEvent{
+				Type: tfprotov5.CompletedInvokeActionEventType{
+					Diagnostics: convert.AppendProtoDiag(ctx, nil, invokeResp.Diagnostics),
 				},
-			},
+			})
 		},
-	})
-
-	resp := &tfprotov5.InvokeActionServerStream{
-		Events: slices.Values(event),
 	}
 
 	return resp, nil
@@ -2367,6 +3531,7 @@ func stripResourceModifiers(r *Resource) *Resource {
 	*newResource = *r
 
 	newResource.CustomizeDiff = nil
+	newResource.CustomizeDiffWithDiagnostics = nil
 	newResource.Schema = map[string]*Schema{}
 
 	for k, s := range r.SchemaMap() {
@@ -2622,6 +3787,18 @@ func validateConfigNulls(ctx context.Context, v cty.Value, path cty.Path) []*tfp
 	return diags
 }
 
+// invalidDeferredResponseDiagnostic returns the error diagnostic of a
+// resource or data source which set a deferred response, although the
+// Terraform request did not indicate support for deferred actions.
+func invalidDeferredResponseDiagnostic(kind string) *tfprotov5.Diagnostic {
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  fmt.Sprintf("Invalid Deferred %s Response", kind),
+		Detail: fmt.Sprintf("%s configured a deferred response but the Terraform request ", kind) +
+			"did not indicate support for deferred actions. This is an issue with the provider and should be reported to the provider developers.",
+	}
+}
+
 // Helper function that check a ConfigureProviderClientCapabilities struct to determine if a deferred response can be
 // returned to the Terraform client. If no ConfigureProviderClientCapabilities have been passed from the client, then false
 // is returned.
@@ -2652,6 +3829,53 @@ func (s *GRPCProviderServer) upgradeJSONIdentity(ctx context.Context, version in
 	return m, nil
 }
 
+// unknownActionTypeDiags returns the diagnostics for a request referencing
+// an action type the provider does not implement.
+func unknownActionTypeDiags(actionType string) []*tfprotov5.Diagnostic {
+	return []*tfprotov5.Diagnostic{
+		{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Unknown Action Type",
+			Detail:   fmt.Sprintf("The %q action type is not supported by this provider.", actionType),
+		},
+	}
+}
+
+// unknownListResourceTypeDiags returns the diagnostics for a request
+// referencing a list resource type the provider does not implement.
+func unknownListResourceTypeDiags(typeName string) []*tfprotov5.Diagnostic {
+	return []*tfprotov5.Diagnostic{
+		{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Unknown List Resource Type",
+			Detail:   fmt.Sprintf("The %q list resource type is not supported by this provider.", typeName),
+		},
+	}
+}
+
+// unknownEphemeralResourceTypeDiags returns the diagnostics for a request
+// referencing an ephemeral resource type the provider does not implement.
+func unknownEphemeralResourceTypeDiags(typeName string) []*tfprotov5.Diagnostic {
+	return []*tfprotov5.Diagnostic{
+		{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Unknown Ephemeral Resource Type",
+			Detail:   fmt.Sprintf("The %q ephemeral resource type is not supported by this provider.", typeName),
+		},
+	}
+}
+
+// functionArgumentError returns a function error associated with the argument
+// at the given index.
+func functionArgumentError(index int, err error) *tfprotov5.FunctionError {
+	argIndex := int64(index)
+
+	return &tfprotov5.FunctionError{
+		Text:             err.Error(),
+		FunctionArgument: &argIndex,
+	}
+}
+
 // isCtyObjectNullOrEmpty is a helper function that checks if a given cty object is null or if all it's immediate children are null (empty)
 func isCtyObjectNullOrEmpty(val cty.Value) bool {
 	if val.IsNull() {
@@ -7,7 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"math/big"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -3809,6 +3811,36 @@ func TestGRPCProviderServerGetMetadata(t *testing.T) {
 				},
 			},
 		},
+		"functions": {
+			Provider: &Provider{
+				Functions: map[string]*Function{
+					"test_function": {
+						Return: &FunctionReturn{
+							Type: TypeString,
+						},
+						Run: func(ctx context.Context, args []interface{}) (interface{}, error) {
+							return "", nil
+						},
+					},
+				},
+			},
+			Expected: &tfprotov5.GetMetadataResponse{
+				DataSources: []tfprotov5.DataSourceMetadata{},
+				Functions: []tfprotov5.FunctionMetadata{
+					{
+						Name: "test_function",
+					},
+				},
+				EphemeralResources: []tfprotov5.EphemeralResourceMetadata{},
+				ListResources:      []tfprotov5.ListResourceMetadata{},
+				Actions:            []tfprotov5.ActionMetadata{},
+				Resources:          []tfprotov5.ResourceMetadata{},
+				ServerCapabilities: &tfprotov5.ServerCapabilities{
+					GetProviderSchemaOptional: true,
+					GenerateResourceConfig:    true,
+				},
+			},
+		},
 		"resources": {
 			Provider: &Provider{
 				ResourcesMap: map[string]*Resource{
@@ -3830,46 +3862,1055 @@ func TestGRPCProviderServerGetMetadata(t *testing.T) {
 						TypeName: "test_resource2",
 					},
 				},
-				ServerCapabilities: &tfprotov5.ServerCapabilities{
-					GetProviderSchemaOptional: true,
-					GenerateResourceConfig:    true,
+				ServerCapabilities: &tfprotov5.ServerCapabilities{
+					GetProviderSchemaOptional: true,
+					GenerateResourceConfig:    true,
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := NewGRPCProviderServer(testCase.Provider)
+
+			testReq := &tfprotov5.GetMetadataRequest{}
+
+			resp, err := server.GetMetadata(context.Background(), testReq)
+
+			if err != nil {
+				t.Fatalf("unexpected gRPC error: %s", err)
+			}
+
+			// Prevent false positives with random map access in testing
+			sort.Slice(resp.DataSources, func(i int, j int) bool {
+				return resp.DataSources[i].TypeName < resp.DataSources[j].TypeName
+			})
+
+			sort.Slice(resp.Resources, func(i int, j int) bool {
+				return resp.Resources[i].TypeName < resp.Resources[j].TypeName
+			})
+
+			if diff := cmp.Diff(resp, testCase.Expected); diff != "" {
+				t.Errorf("unexpected response difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestGRPCProviderServerGetFunctions(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		Provider *Provider
+		Expected *tfprotov5.GetFunctionsResponse
+	}{
+		"no-functions": {
+			Provider: &Provider{},
+			Expected: &tfprotov5.GetFunctionsResponse{
+				Functions: map[string]*tfprotov5.Function{},
+			},
+		},
+		"functions": {
+			Provider: &Provider{
+				Functions: map[string]*Function{
+					"join": {
+						Summary:     "Join strings",
+						Description: "Joins strings with a separator.",
+						Parameters: []*FunctionParameter{
+							{
+								Name: "separator",
+								Type: TypeString,
+							},
+						},
+						VariadicParameter: &FunctionParameter{
+							Name:           "parts",
+							Type:           TypeList,
+							Elem:           &Schema{Type: TypeString},
+							AllowNullValue: true,
+						},
+						Return: &FunctionReturn{
+							Type: TypeString,
+						},
+						Run: func(ctx context.Context, args []interface{}) (interface{}, error) {
+							return "", nil
+						},
+					},
+				},
+			},
+			Expected: &tfprotov5.GetFunctionsResponse{
+				Functions: map[string]*tfprotov5.Function{
+					"join": {
+						Summary:         "Join strings",
+						Description:     "Joins strings with a separator.",
+						DescriptionKind: tfprotov5.StringKindPlain,
+						Parameters: []*tfprotov5.FunctionParameter{
+							{
+								Name:            "separator",
+								DescriptionKind: tfprotov5.StringKindPlain,
+								Type:            tftypes.String,
+							},
+						},
+						VariadicParameter: &tfprotov5.FunctionParameter{
+							Name:            "parts",
+							DescriptionKind: tfprotov5.StringKindPlain,
+							Type:            tftypes.List{ElementType: tftypes.String},
+							AllowNullValue:  true,
+						},
+						Return: &tfprotov5.FunctionReturn{
+							Type: tftypes.String,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := NewGRPCProviderServer(testCase.Provider)
+
+			resp, err := server.GetFunctions(context.Background(), &tfprotov5.GetFunctionsRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected gRPC error: %s", err)
+			}
+
+			if diff := cmp.Diff(resp, testCase.Expected); diff != "" {
+				t.Errorf("unexpected response difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestGRPCProviderServerCallFunction(t *testing.T) {
+	t.Parallel()
+
+	argIndex := func(i int64) *int64 { return &i }
+
+	mustMsgPack := func(val cty.Value) *tfprotov5.DynamicValue {
+		mp, err := msgpack.Marshal(val, val.Type())
+		if err != nil {
+			t.Fatalf("unable to marshal value: %s", err)
+		}
+		return &tfprotov5.DynamicValue{MsgPack: mp}
+	}
+
+	provider := &Provider{
+		Functions: map[string]*Function{
+			"repeat": {
+				Parameters: []*FunctionParameter{
+					{
+						Name: "input",
+						Type: TypeString,
+					},
+					{
+						Name: "count",
+						Type: TypeInt,
+					},
+				},
+				Return: &FunctionReturn{
+					Type: TypeList,
+					Elem: &Schema{Type: TypeString},
+				},
+				Run: func(ctx context.Context, args []interface{}) (interface{}, error) {
+					count := args[1].(int)
+					if count < 0 {
+						return nil, NewFunctionArgumentError(1, errors.New("count must not be negative"))
+					}
+
+					result := make([]string, count)
+					for i := range result {
+						result[i] = args[0].(string)
+					}
+					return result, nil
+				},
+			},
+			"sum": {
+				VariadicParameter: &FunctionParameter{
+					Name: "numbers",
+					Type: TypeFloat,
+				},
+				Return: &FunctionReturn{
+					Type: TypeFloat,
+				},
+				Run: func(ctx context.Context, args []interface{}) (interface{}, error) {
+					var sum float64
+					for _, n := range args[0].([]interface{}) {
+						sum += n.(float64)
+					}
+					return sum, nil
+				},
+			},
+			"unique": {
+				Parameters: []*FunctionParameter{
+					{
+						Name: "input",
+						Type: TypeSet,
+						Elem: &Schema{Type: TypeString},
+					},
+				},
+				Return: &FunctionReturn{
+					Type: TypeInt,
+				},
+				Run: func(ctx context.Context, args []interface{}) (interface{}, error) {
+					return args[0].(*Set).Len(), nil
+				},
+			},
+			"fail": {
+				Return: &FunctionReturn{
+					Type: TypeString,
+				},
+				Run: func(ctx context.Context, args []interface{}) (interface{}, error) {
+					return nil, errors.New("test error")
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		Request  *tfprotov5.CallFunctionRequest
+		Expected *tfprotov5.CallFunctionResponse
+	}{
+		"unknown-function": {
+			Request: &tfprotov5.CallFunctionRequest{
+				Name: "missing",
+			},
+			Expected: &tfprotov5.CallFunctionResponse{
+				Error: &tfprotov5.FunctionError{
+					Text: "Function Not Found: No function named \"missing\" was found in the provider.",
+				},
+			},
+		},
+		"result": {
+			Request: &tfprotov5.CallFunctionRequest{
+				Name: "repeat",
+				Arguments: []*tfprotov5.DynamicValue{
+					mustMsgPack(cty.StringVal("a")),
+					mustMsgPack(cty.NumberIntVal(2)),
+				},
+			},
+			Expected: &tfprotov5.CallFunctionResponse{
+				Result: mustMsgPack(cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("a")})),
+			},
+		},
+		"variadic": {
+			Request: &tfprotov5.CallFunctionRequest{
+				Name: "sum",
+				Arguments: []*tfprotov5.DynamicValue{
+					mustMsgPack(cty.NumberFloatVal(1.5)),
+					mustMsgPack(cty.NumberFloatVal(2)),
+				},
+			},
+			Expected: &tfprotov5.CallFunctionResponse{
+				Result: mustMsgPack(cty.NumberFloatVal(3.5)),
+			},
+		},
+		"variadic-empty": {
+			Request: &tfprotov5.CallFunctionRequest{
+				Name: "sum",
+			},
+			Expected: &tfprotov5.CallFunctionResponse{
+				Result: mustMsgPack(cty.NumberFloatVal(0)),
+			},
+		},
+		"set-argument": {
+			Request: &tfprotov5.CallFunctionRequest{
+				Name: "unique",
+				Arguments: []*tfprotov5.DynamicValue{
+					mustMsgPack(cty.SetVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")})),
+				},
+			},
+			Expected: &tfprotov5.CallFunctionResponse{
+				Result: mustMsgPack(cty.NumberIntVal(2)),
+			},
+		},
+		"null-argument": {
+			Request: &tfprotov5.CallFunctionRequest{
+				Name: "repeat",
+				Arguments: []*tfprotov5.DynamicValue{
+					mustMsgPack(cty.NullVal(cty.String)),
+					mustMsgPack(cty.NumberIntVal(2)),
+				},
+			},
+			Expected: &tfprotov5.CallFunctionResponse{
+				Error: &tfprotov5.FunctionError{
+					Text:             "Invalid Argument: argument \"input\" must not be null",
+					FunctionArgument: argIndex(0),
+				},
+			},
+		},
+		"invalid-int-argument": {
+			Request: &tfprotov5.CallFunctionRequest{
+				Name: "repeat",
+				Arguments: []*tfprotov5.DynamicValue{
+					mustMsgPack(cty.StringVal("a")),
+					mustMsgPack(cty.NumberFloatVal(1.5)),
+				},
+			},
+			Expected: &tfprotov5.CallFunctionResponse{
+				Error: &tfprotov5.FunctionError{
+					Text:             "Invalid Argument: argument \"count\": value must be a whole number, got 1.5",
+					FunctionArgument: argIndex(1),
+				},
+			},
+		},
+		"argument-error": {
+			Request: &tfprotov5.CallFunctionRequest{
+				Name: "repeat",
+				Arguments: []*tfprotov5.DynamicValue{
+					mustMsgPack(cty.StringVal("a")),
+					mustMsgPack(cty.NumberIntVal(-1)),
+				},
+			},
+			Expected: &tfprotov5.CallFunctionResponse{
+				Error: &tfprotov5.FunctionError{
+					Text:             "count must not be negative",
+					FunctionArgument: argIndex(1),
+				},
+			},
+		},
+		"error": {
+			Request: &tfprotov5.CallFunctionRequest{
+				Name: "fail",
+			},
+			Expected: &tfprotov5.CallFunctionResponse{
+				Error: &tfprotov5.FunctionError{
+					Text: "test error",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := NewGRPCProviderServer(provider)
+
+			resp, err := server.CallFunction(context.Background(), testCase.Request)
+
+			if err != nil {
+				t.Fatalf("unexpected gRPC error: %s", err)
+			}
+
+			if diff := cmp.Diff(resp, testCase.Expected); diff != "" {
+				t.Errorf("unexpected response difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestGRPCProviderServerEphemeralResource(t *testing.T) {
+	t.Parallel()
+
+	renewAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var closedLease string
+
+	server := NewGRPCProviderServer(&Provider{
+		EphemeralResourcesMap: map[string]*EphemeralResource{
+			"test_token": {
+				Schema: map[string]*Schema{
+					"name": {
+						Type:     TypeString,
+						Required: true,
+					},
+					"token": {
+						Type:      TypeString,
+						Computed:  true,
+						Sensitive: true,
+					},
+				},
+				Open: func(ctx context.Context, req OpenEphemeralResourceRequest, resp *OpenEphemeralResourceResponse) {
+					name := req.ResourceData.Get("name").(string)
+
+					if err := req.ResourceData.Set("token", "token-"+name); err != nil {
+						resp.Diagnostics = diag.FromErr(err)
+						return
+					}
+
+					resp.Private = map[string]interface{}{"lease": "lease-" + name}
+					resp.RenewAt = renewAt
+				},
+				Renew: func(ctx context.Context, req RenewEphemeralResourceRequest, resp *RenewEphemeralResourceResponse) {
+					resp.Private = map[string]interface{}{"lease": req.Private["lease"].(string) + "-renewed"}
+				},
+				Close: func(ctx context.Context, req CloseEphemeralResourceRequest, resp *CloseEphemeralResourceResponse) {
+					closedLease = req.Private["lease"].(string)
+				},
+			},
+		},
+	})
+
+	ty := cty.Object(map[string]cty.Type{
+		"name":  cty.String,
+		"token": cty.String,
+	})
+
+	configMP, err := msgpack.Marshal(cty.ObjectVal(map[string]cty.Value{
+		"name":  cty.StringVal("test"),
+		"token": cty.NullVal(cty.String),
+	}), ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validateResp, err := server.ValidateEphemeralResourceConfig(context.Background(), &tfprotov5.ValidateEphemeralResourceConfigRequest{
+		TypeName: "test_token",
+		Config:   &tfprotov5.DynamicValue{MsgPack: configMP},
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	if len(validateResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %#v", validateResp.Diagnostics)
+	}
+
+	openResp, err := server.OpenEphemeralResource(context.Background(), &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "test_token",
+		Config:   &tfprotov5.DynamicValue{MsgPack: configMP},
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	if len(openResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %#v", openResp.Diagnostics)
+	}
+
+	result, err := msgpack.Unmarshal(openResp.Result.MsgPack, ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedResult := cty.ObjectVal(map[string]cty.Value{
+		"name":  cty.StringVal("test"),
+		"token": cty.StringVal("token-test"),
+	})
+
+	if !result.RawEquals(expectedResult) {
+		t.Fatalf("expected result %#v, got %#v", expectedResult, result)
+	}
+
+	if !openResp.RenewAt.Equal(renewAt) {
+		t.Fatalf("expected RenewAt %s, got %s", renewAt, openResp.RenewAt)
+	}
+
+	renewResp, err := server.RenewEphemeralResource(context.Background(), &tfprotov5.RenewEphemeralResourceRequest{
+		TypeName: "test_token",
+		Private:  openResp.Private,
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	if len(renewResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %#v", renewResp.Diagnostics)
+	}
+
+	closeResp, err := server.CloseEphemeralResource(context.Background(), &tfprotov5.CloseEphemeralResourceRequest{
+		TypeName: "test_token",
+		Private:  renewResp.Private,
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	if len(closeResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %#v", closeResp.Diagnostics)
+	}
+
+	if closedLease != "lease-test-renewed" {
+		t.Fatalf("expected private data to round-trip to Close, got %q", closedLease)
+	}
+}
+
+func TestGRPCProviderServerOpenEphemeralResource_unknownType(t *testing.T) {
+	t.Parallel()
+
+	server := NewGRPCProviderServer(&Provider{})
+
+	resp, err := server.OpenEphemeralResource(context.Background(), &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "test_token",
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	expected := &tfprotov5.OpenEphemeralResourceResponse{
+		Diagnostics: []*tfprotov5.Diagnostic{
+			{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Unknown Ephemeral Resource Type",
+				Detail:   "The \"test_token\" ephemeral resource type is not supported by this provider.",
+			},
+		},
+	}
+
+	if diff := cmp.Diff(resp, expected); diff != "" {
+		t.Errorf("unexpected response difference: %s", diff)
+	}
+}
+
+func TestGRPCProviderServerListResource(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"one", "two", "three"}
+
+	server := NewGRPCProviderServer(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test_thing": {
+				Schema: map[string]*Schema{
+					"name": {
+						Type:     TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+				},
+				Identity: &ResourceIdentity{
+					SchemaFunc: func() map[string]*Schema {
+						return map[string]*Schema{
+							"name": {
+								Type:              TypeString,
+								RequiredForImport: true,
+							},
+						}
+					},
+				},
+				ListConfigSchema: map[string]*Schema{
+					"prefix": {
+						Type:     TypeString,
+						Optional: true,
+					},
+				},
+				List: func(ctx context.Context, req ListResourceRequest) iter.Seq[ListResourceResult] {
+					prefix := req.Config.Get("prefix").(string)
+
+					return func(yield func(ListResourceResult) bool) {
+						for _, name := range names {
+							d := req.NewResourceData()
+
+							identity, err := d.Identity()
+							if err != nil {
+								yield(ListResourceResult{Diagnostics: diag.FromErr(err)})
+								return
+							}
+
+							if err := identity.Set("name", prefix+name); err != nil {
+								yield(ListResourceResult{Diagnostics: diag.FromErr(err)})
+								return
+							}
+
+							if req.IncludeResource {
+								d.SetId(prefix + name)
+
+								if err := d.Set("name", prefix+name); err != nil {
+									yield(ListResourceResult{Diagnostics: diag.FromErr(err)})
+									return
+								}
+							}
+
+							if !yield(ListResourceResult{DisplayName: "Thing " + name, ResourceData: d}) {
+								return
+							}
+						}
+					}
+				},
+				CreateContext: func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+				ReadContext:   func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+				DeleteContext: func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+			},
+		},
+	})
+
+	configTy := cty.Object(map[string]cty.Type{
+		"prefix": cty.String,
+	})
+	identityTy := cty.Object(map[string]cty.Type{
+		"name": cty.String,
+	})
+	stateTy := cty.Object(map[string]cty.Type{
+		"id":   cty.String,
+		"name": cty.String,
+	})
+
+	configMP, err := msgpack.Marshal(cty.ObjectVal(map[string]cty.Value{
+		"prefix": cty.StringVal("test-"),
+	}), configTy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validateResp, err := server.ValidateListResourceConfig(context.Background(), &tfprotov5.ValidateListResourceConfigRequest{
+		TypeName: "test_thing",
+		Config:   &tfprotov5.DynamicValue{MsgPack: configMP},
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	if len(validateResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %#v", validateResp.Diagnostics)
+	}
+
+	testCases := map[string]struct {
+		IncludeResource bool
+		Limit           int64
+		ExpectedNames   []string
+	}{
+		"identity-only": {
+			ExpectedNames: []string{"one", "two", "three"},
+		},
+		"include-resource": {
+			IncludeResource: true,
+			ExpectedNames:   []string{"one", "two", "three"},
+		},
+		"limit": {
+			Limit:         2,
+			ExpectedNames: []string{"one", "two"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			stream, err := server.ListResource(context.Background(), &tfprotov5.ListResourceRequest{
+				TypeName:        "test_thing",
+				Config:          &tfprotov5.DynamicValue{MsgPack: configMP},
+				IncludeResource: testCase.IncludeResource,
+				Limit:           testCase.Limit,
+			})
+			if err != nil {
+				t.Fatalf("unexpected gRPC error: %s", err)
+			}
+
+			results := slices.Collect(stream.Results)
+
+			if len(results) != len(testCase.ExpectedNames) {
+				t.Fatalf("expected %d results, got %d", len(testCase.ExpectedNames), len(results))
+			}
+
+			for i, result := range results {
+				expectedName := testCase.ExpectedNames[i]
+
+				if len(result.Diagnostics) > 0 {
+					t.Fatalf("unexpected diagnostics: %#v", result.Diagnostics)
+				}
+
+				if result.DisplayName != "Thing "+expectedName {
+					t.Errorf("expected display name %q, got %q", "Thing "+expectedName, result.DisplayName)
+				}
+
+				identityVal, err := msgpack.Unmarshal(result.Identity.IdentityData.MsgPack, identityTy)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expectedIdentity := cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("test-" + expectedName),
+				})
+
+				if !identityVal.RawEquals(expectedIdentity) {
+					t.Errorf("expected identity %#v, got %#v", expectedIdentity, identityVal)
+				}
+
+				if !testCase.IncludeResource {
+					if result.Resource != nil {
+						t.Errorf("expected no resource, got %#v", result.Resource)
+					}
+					continue
+				}
+
+				stateVal, err := msgpack.Unmarshal(result.Resource.MsgPack, stateTy)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expectedState := cty.ObjectVal(map[string]cty.Value{
+					"id":   cty.StringVal("test-" + expectedName),
+					"name": cty.StringVal("test-" + expectedName),
+				})
+
+				if !stateVal.RawEquals(expectedState) {
+					t.Errorf("expected state %#v, got %#v", expectedState, stateVal)
+				}
+			}
+		})
+	}
+}
+
+func TestGRPCProviderServerListResource_nullConfigValue(t *testing.T) {
+	t.Parallel()
+
+	server := NewGRPCProviderServer(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test_thing": {
+				Schema: map[string]*Schema{
+					"name": {
+						Type:     TypeString,
+						Required: true,
+					},
+				},
+				ListConfigSchema: map[string]*Schema{
+					"names": {
+						Type:     TypeList,
+						Optional: true,
+						Elem:     &Schema{Type: TypeString},
+					},
+				},
+				List: func(ctx context.Context, req ListResourceRequest) iter.Seq[ListResourceResult] {
+					t.Error("unexpected List call")
+
+					return func(yield func(ListResourceResult) bool) {}
+				},
+			},
+		},
+	})
+
+	configTy := cty.Object(map[string]cty.Type{
+		"names": cty.List(cty.String),
+	})
+
+	configMP, err := msgpack.Marshal(cty.ObjectVal(map[string]cty.Value{
+		"names": cty.ListVal([]cty.Value{
+			cty.StringVal("one"),
+			cty.NullVal(cty.String),
+		}),
+	}), configTy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := server.ListResource(context.Background(), &tfprotov5.ListResourceRequest{
+		TypeName: "test_thing",
+		Config:   &tfprotov5.DynamicValue{MsgPack: configMP},
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	expected := []tfprotov5.ListResourceResult{
+		{
+			Diagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Null value found in list",
+					Detail:    "Null values are not allowed for this attribute value.",
+					Attribute: tftypes.NewAttributePath().WithAttributeName("names").WithElementKeyInt(1),
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(slices.Collect(stream.Results), expected); diff != "" {
+		t.Errorf("unexpected response difference: %s", diff)
+	}
+}
+
+func TestGRPCProviderServerListResource_unknownType(t *testing.T) {
+	t.Parallel()
+
+	server := NewGRPCProviderServer(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test_thing": {
+				Schema: map[string]*Schema{},
+			},
+		},
+	})
+
+	stream, err := server.ListResource(context.Background(), &tfprotov5.ListResourceRequest{
+		TypeName: "test_thing",
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	expected := []tfprotov5.ListResourceResult{
+		{
+			Diagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Unknown List Resource Type",
+					Detail:   "The \"test_thing\" list resource type is not supported by this provider.",
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(slices.Collect(stream.Results), expected); diff != "" {
+		t.Errorf("unexpected response difference: %s", diff)
+	}
+}
+
+func TestGRPCProviderServerAction(t *testing.T) {
+	t.Parallel()
+
+	server := NewGRPCProviderServer(&Provider{
+		ActionsMap: map[string]*Action{
+			"test_reboot": {
+				Schema: map[string]*Schema{
+					"instance_id": {
+						Type:     TypeString,
+						Required: true,
+					},
+				},
+				Plan: func(ctx context.Context, req PlanActionRequest, resp *PlanActionResponse) {
+					if req.ResourceData.Get("instance_id").(string) == "invalid" {
+						resp.Diagnostics = diag.Errorf("instance %q cannot be rebooted", "invalid")
+					}
+				},
+				Invoke: func(ctx context.Context, req InvokeActionRequest, resp *InvokeActionResponse) {
+					id := req.ResourceData.Get("instance_id").(string)
+
+					req.SendProgress("stopping " + id)
+					req.SendProgress("starting " + id)
+				},
+			},
+		},
+	})
+
+	ty := cty.Object(map[string]cty.Type{
+		"instance_id": cty.String,
+	})
+
+	configMP, err := msgpack.Marshal(cty.ObjectVal(map[string]cty.Value{
+		"instance_id": cty.StringVal("i-123"),
+	}), ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalidConfigMP, err := msgpack.Marshal(cty.ObjectVal(map[string]cty.Value{
+		"instance_id": cty.StringVal("invalid"),
+	}), ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validateResp, err := server.ValidateActionConfig(context.Background(), &tfprotov5.ValidateActionConfigRequest{
+		ActionType: "test_reboot",
+		Config:     &tfprotov5.DynamicValue{MsgPack: configMP},
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	if len(validateResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %#v", validateResp.Diagnostics)
+	}
+
+	planResp, err := server.PlanAction(context.Background(), &tfprotov5.PlanActionRequest{
+		ActionType: "test_reboot",
+		Config:     &tfprotov5.DynamicValue{MsgPack: invalidConfigMP},
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	expectedPlanResp := &tfprotov5.PlanActionResponse{
+		Diagnostics: []*tfprotov5.Diagnostic{
+			{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "instance \"invalid\" cannot be rebooted",
+			},
+		},
+	}
+
+	if diff := cmp.Diff(planResp, expectedPlanResp); diff != "" {
+		t.Errorf("unexpected plan response difference: %s", diff)
+	}
+
+	stream, err := server.InvokeAction(context.Background(), &tfprotov5.InvokeActionRequest{
+		ActionType: "test_reboot",
+		Config:     &tfprotov5.DynamicValue{MsgPack: configMP},
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	expectedEvents := []tfprotov5.InvokeActionEvent{
+		{
+			Type: tfprotov5.ProgressInvokeActionEventType{
+				Message: "stopping i-123",
+			},
+		},
+		{
+			Type: tfprotov5.ProgressInvokeActionEventType{
+				Message: "starting i-123",
+			},
+		},
+		{
+			Type: tfprotov5.CompletedInvokeActionEventType{},
+		},
+	}
+
+	if diff := cmp.Diff(slices.Collect(stream.Events), expectedEvents); diff != "" {
+		t.Errorf("unexpected events difference: %s", diff)
+	}
+}
+
+func TestGRPCProviderServerPlanAction_nullListElement(t *testing.T) {
+	t.Parallel()
+
+	server := NewGRPCProviderServer(&Provider{
+		ActionsMap: map[string]*Action{
+			"test_reboot": {
+				Schema: map[string]*Schema{
+					"instance_ids": {
+						Type:     TypeList,
+						Required: true,
+						Elem:     &Schema{Type: TypeString},
+					},
+				},
+				Plan: func(ctx context.Context, req PlanActionRequest, resp *PlanActionResponse) {
+					resp.Diagnostics = diag.Errorf("unexpected plan")
+				},
+			},
+		},
+	})
+
+	ty := cty.Object(map[string]cty.Type{
+		"instance_ids": cty.List(cty.String),
+	})
+
+	configMP, err := msgpack.Marshal(cty.ObjectVal(map[string]cty.Value{
+		"instance_ids": cty.ListVal([]cty.Value{
+			cty.StringVal("i-123"),
+			cty.NullVal(cty.String),
+		}),
+	}), ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	planResp, err := server.PlanAction(context.Background(), &tfprotov5.PlanActionRequest{
+		ActionType: "test_reboot",
+		Config:     &tfprotov5.DynamicValue{MsgPack: configMP},
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	expectedPlanResp := &tfprotov5.PlanActionResponse{
+		Diagnostics: []*tfprotov5.Diagnostic{
+			{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Null value found in list",
+				Detail:   "Null values are not allowed for this attribute value.",
+				Attribute: tftypes.NewAttributePath().
+					WithAttributeName("instance_ids").
+					WithElementKeyInt(1),
+			},
+		},
+	}
+
+	if diff := cmp.Diff(planResp, expectedPlanResp); diff != "" {
+		t.Errorf("unexpected plan response difference: %s", diff)
+	}
+}
+
+func TestGRPCProviderServerInvokeAction_unknownType(t *testing.T) {
+	t.Parallel()
+
+	server := NewGRPCProviderServer(&Provider{})
+
+	stream, err := server.InvokeAction(context.Background(), &tfprotov5.InvokeActionRequest{
+		ActionType: "test_reboot",
+	})
+	if err != nil {
+		t.Fatalf("unexpected gRPC error: %s", err)
+	}
+
+	expectedEvents := []tfprotov5.InvokeActionEvent{
+		{
+			Type: tfprotov5.CompletedInvokeActionEventType{
+				Diagnostics: []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Unknown Action Type",
+						Detail:   "The \"test_reboot\" action type is not supported by this provider.",
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(slices.Collect(stream.Events), expectedEvents); diff != "" {
+		t.Errorf("unexpected events difference: %s", diff)
+	}
+}
+
+func TestGRPCProviderServerMoveResourceState(t *testing.T) {
+	t.Parallel()
+
+	newStateMoverResource := func() *Resource {
+		return &Resource{
+			SchemaVersion: 1,
+			Schema: map[string]*Schema{
+				"name": {
+					Type:     TypeString,
+					Required: true,
+				},
+			},
+			Identity: &ResourceIdentity{
+				SchemaFunc: func() map[string]*Schema {
+					return map[string]*Schema{
+						"name": {
+							Type:              TypeString,
+							RequiredForImport: true,
+						},
+					}
 				},
 			},
-		},
-	}
-
-	for name, testCase := range testCases {
-		t.Run(name, func(t *testing.T) {
-			t.Parallel()
-
-			server := NewGRPCProviderServer(testCase.Provider)
+			StateMovers: []StateMover{
+				{
+					SourceProviderAddress: "registry.terraform.io/hashicorp/test",
+					SourceTypeName:        "test_resource_v1",
+					SourceSchemaVersion:   2,
+					Move: func(ctx context.Context, req MoveStateRequest, resp *MoveStateResponse) {
+						d := req.NewResourceData()
+						name := req.SourceState["old_name"].(string)
+
+						if req.SourceIdentity == nil {
+							// A ResourceData without the identity schema
+							d = (&Resource{SchemaVersion: 1, Schema: d.schema}).Data(nil)
+						}
 
-			testReq := &tfprotov5.GetMetadataRequest{}
+						d.SetId(req.SourceState["id"].(string))
 
-			resp, err := server.GetMetadata(context.Background(), testReq)
+						if err := d.Set("name", name); err != nil {
+							resp.Diagnostics = diag.FromErr(err)
+							return
+						}
 
-			if err != nil {
-				t.Fatalf("unexpected gRPC error: %s", err)
-			}
+						resp.ResourceData = d
 
-			// Prevent false positives with random map access in testing
-			sort.Slice(resp.DataSources, func(i int, j int) bool {
-				return resp.DataSources[i].TypeName < resp.DataSources[j].TypeName
-			})
+						if req.SourceIdentity == nil {
+							return
+						}
 
-			sort.Slice(resp.Resources, func(i int, j int) bool {
-				return resp.Resources[i].TypeName < resp.Resources[j].TypeName
-			})
+						identity, err := d.Identity()
+						if err != nil {
+							resp.Diagnostics = diag.FromErr(err)
+							return
+						}
 
-			if diff := cmp.Diff(resp, testCase.Expected); diff != "" {
-				t.Errorf("unexpected response difference: %s", diff)
-			}
-		})
+						if err := identity.Set("name", req.SourceIdentity["old_name"]); err != nil {
+							resp.Diagnostics = diag.FromErr(err)
+							return
+						}
+					},
+				},
+			},
+		}
 	}
-}
-
-func TestGRPCProviderServerMoveResourceState(t *testing.T) {
-	t.Parallel()
 
 	testCases := map[string]struct {
 		server   *GRPCProviderServer
@@ -3915,6 +4956,112 @@ func TestGRPCProviderServerMoveResourceState(t *testing.T) {
 				},
 			},
 		},
+		"request-SourceTypeName-no-match": {
+			server: NewGRPCProviderServer(&Provider{
+				ResourcesMap: map[string]*Resource{
+					"test_resource": newStateMoverResource(),
+				},
+			}),
+			request: &tfprotov5.MoveResourceStateRequest{
+				SourceProviderAddress: "registry.terraform.io/hashicorp/test",
+				SourceSchemaVersion:   2,
+				SourceTypeName:        "test_other",
+				TargetTypeName:        "test_resource",
+			},
+			expected: &tfprotov5.MoveResourceStateResponse{
+				Diagnostics: []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Move Resource State Not Supported",
+						Detail:   "The \"test_resource\" resource type does not support moving resource state from the \"test_other\" resource type at schema version 2 of provider \"registry.terraform.io/hashicorp/test\".",
+					},
+				},
+			},
+		},
+		"request-StateMover": {
+			server: NewGRPCProviderServer(&Provider{
+				ResourcesMap: map[string]*Resource{
+					"test_resource": newStateMoverResource(),
+				},
+			}),
+			request: &tfprotov5.MoveResourceStateRequest{
+				SourceProviderAddress: "registry.terraform.io/hashicorp/test",
+				SourceSchemaVersion:   2,
+				SourceState: &tfprotov5.RawState{
+					JSON: []byte(`{"id":"test-id","old_name":"test-name"}`),
+				},
+				SourceIdentity: &tfprotov5.RawState{
+					JSON: []byte(`{"old_name":"test-name"}`),
+				},
+				SourceTypeName: "test_resource_v1",
+				TargetTypeName: "test_resource",
+			},
+			expected: &tfprotov5.MoveResourceStateResponse{
+				TargetState: &tfprotov5.DynamicValue{
+					MsgPack: mustMsgpackMarshal(
+						cty.Object(map[string]cty.Type{
+							"id":   cty.String,
+							"name": cty.String,
+						}),
+						cty.ObjectVal(map[string]cty.Value{
+							"id":   cty.StringVal("test-id"),
+							"name": cty.StringVal("test-name"),
+						}),
+					),
+				},
+				TargetPrivate: []byte(`{"schema_version":"1"}`),
+				TargetIdentity: &tfprotov5.ResourceIdentityData{
+					IdentityData: &tfprotov5.DynamicValue{
+						MsgPack: mustMsgpackMarshal(
+							cty.Object(map[string]cty.Type{
+								"name": cty.String,
+							}),
+							cty.ObjectVal(map[string]cty.Value{
+								"name": cty.StringVal("test-name"),
+							}),
+						),
+					},
+				},
+			},
+		},
+		"request-StateMover-missing-identity": {
+			server: NewGRPCProviderServer(&Provider{
+				ResourcesMap: map[string]*Resource{
+					"test_resource": newStateMoverResource(),
+				},
+			}),
+			request: &tfprotov5.MoveResourceStateRequest{
+				SourceProviderAddress: "registry.terraform.io/hashicorp/test",
+				SourceSchemaVersion:   2,
+				SourceState: &tfprotov5.RawState{
+					JSON: []byte(`{"id":"test-id","old_name":"test-name"}`),
+				},
+				SourceTypeName: "test_resource_v1",
+				TargetTypeName: "test_resource",
+			},
+			expected: &tfprotov5.MoveResourceStateResponse{
+				TargetState: &tfprotov5.DynamicValue{
+					MsgPack: mustMsgpackMarshal(
+						cty.Object(map[string]cty.Type{
+							"id":   cty.String,
+							"name": cty.String,
+						}),
+						cty.ObjectVal(map[string]cty.Value{
+							"id":   cty.StringVal("test-id"),
+							"name": cty.StringVal("test-name"),
+						}),
+					),
+				},
+				TargetPrivate: []byte(`{"schema_version":"1"}`),
+				Diagnostics: []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary: "Missing Resource Identity After Move: The Terraform provider unexpectedly returned no resource identity after having no errors in the resource move. " +
+							"This is always a problem with the provider and should be reported to the provider developer",
+					},
+				},
+			},
+		},
 	}
 
 	for name, testCase := range testCases {
@@ -8268,26 +9415,307 @@ func TestPlanResourceChange_bigint(t *testing.T) {
 		},
 	}
 
-	resp, err := server.PlanResourceChange(context.Background(), testReq)
-	if err != nil {
-		t.Fatal(err)
-	}
+	resp, err := server.PlanResourceChange(context.Background(), testReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plannedStateVal, err := msgpack.Unmarshal(resp.PlannedState.MsgPack, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cmp.Equal(proposedVal, plannedStateVal, valueComparer) {
+		t.Fatal(cmp.Diff(proposedVal, plannedStateVal, valueComparer))
+	}
+
+	plannedStateFoo, acc := plannedStateVal.GetAttr("foo").AsBigFloat().Int64()
+	if acc != big.Exact {
+		t.Fatalf("Expected exact accuracy, got %s", acc)
+	}
+	if plannedStateFoo != 7227701560655103598 {
+		t.Fatalf("Expected %d, got %d, this represents a loss of precision in planning large numbers", 7227701560655103598, plannedStateFoo)
+	}
+}
+
+func TestPlanResourceChange_customizeDiffNested(t *testing.T) {
+	r := &Resource{
+		Schema: map[string]*Schema{
+			"network_interface": {
+				Type:     TypeList,
+				Optional: true,
+				Elem: &Resource{
+					Schema: map[string]*Schema{
+						"name": {
+							Type:     TypeString,
+							Optional: true,
+						},
+						"private_ip": {
+							Type:     TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"rule": {
+				Type:     TypeSet,
+				Optional: true,
+				Elem: &Resource{
+					Schema: map[string]*Schema{
+						"name": {
+							Type:     TypeString,
+							Optional: true,
+						},
+						"port": {
+							Type:     TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		CustomizeDiff: func(_ context.Context, d *ResourceDiff, _ interface{}) error {
+			if err := d.SetNewComputed("network_interface.0.private_ip"); err != nil {
+				return err
+			}
+			for _, k := range d.SetElementKeys("rule", func(v interface{}) bool {
+				return v.(map[string]interface{})["name"] == "web"
+			}) {
+				if err := d.SetNewComputed(k + ".port"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	server := NewGRPCProviderServer(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test": r,
+		},
+	})
+
+	schema := r.CoreConfigSchema()
+
+	priorVal := cty.ObjectVal(map[string]cty.Value{
+		"id": cty.StringVal("foo"),
+		"network_interface": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":       cty.StringVal("eth0"),
+				"private_ip": cty.StringVal("10.0.0.1"),
+			}),
+		}),
+		"rule": cty.SetVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"port": cty.NumberIntVal(80),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("db"),
+				"port": cty.NumberIntVal(5432),
+			}),
+		}),
+	})
+	priorState, err := msgpack.Marshal(priorVal, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := schema.CoerceValue(cty.ObjectVal(map[string]cty.Value{
+		"id": cty.NullVal(cty.String),
+		"network_interface": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":       cty.StringVal("eth0"),
+				"private_ip": cty.NullVal(cty.String),
+			}),
+		}),
+		"rule": cty.SetVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"port": cty.NullVal(cty.Number),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("db"),
+				"port": cty.NullVal(cty.Number),
+			}),
+		}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	configBytes, err := msgpack.Marshal(config, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testReq := &tfprotov5.PlanResourceChangeRequest{
+		TypeName: "test",
+		PriorState: &tfprotov5.DynamicValue{
+			MsgPack: priorState,
+		},
+		ProposedNewState: &tfprotov5.DynamicValue{
+			MsgPack: priorState,
+		},
+		Config: &tfprotov5.DynamicValue{
+			MsgPack: configBytes,
+		},
+	}
+
+	resp, err := server.PlanResourceChange(context.Background(), testReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	plannedStateVal, err := msgpack.Unmarshal(resp.PlannedState.MsgPack, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedVal := cty.ObjectVal(map[string]cty.Value{
+		"id": cty.StringVal("foo"),
+		"network_interface": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":       cty.StringVal("eth0"),
+				"private_ip": cty.UnknownVal(cty.String),
+			}),
+		}),
+		"rule": cty.SetVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"port": cty.UnknownVal(cty.Number),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("db"),
+				"port": cty.NumberIntVal(5432),
+			}),
+		}),
+	})
+
+	if !cmp.Equal(expectedVal, plannedStateVal, valueComparer) {
+		t.Fatal(cmp.Diff(expectedVal, plannedStateVal, valueComparer))
+	}
+
+	if len(resp.RequiresReplace) > 0 {
+		t.Fatalf("unexpected RequiresReplace: %v", resp.RequiresReplace)
+	}
+}
+
+func TestPlanResourceChange_customizeDiffWithDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		diags               diag.Diagnostics
+		expectedDiagnostics []*tfprotov5.Diagnostic
+		expectPlannedState  bool
+	}{
+		"warning": {
+			diags: diag.Diagnostics{
+				{
+					Severity:      diag.Warning,
+					Summary:       "Large size",
+					AttributePath: cty.GetAttrPath("size"),
+				},
+			},
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity:  tfprotov5.DiagnosticSeverityWarning,
+					Summary:   "Large size",
+					Attribute: tftypes.NewAttributePath().WithAttributeName("size"),
+				},
+			},
+			expectPlannedState: true,
+		},
+		"errors": {
+			diags: diag.Diagnostics{
+				{
+					Severity:      diag.Error,
+					Summary:       "Invalid size",
+					AttributePath: cty.GetAttrPath("size"),
+				},
+				{
+					Severity: diag.Error,
+					Summary:  "Invalid resource",
+				},
+			},
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Invalid size",
+					Attribute: tftypes.NewAttributePath().WithAttributeName("size"),
+				},
+				{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Invalid resource",
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
 
-	plannedStateVal, err := msgpack.Unmarshal(resp.PlannedState.MsgPack, schema.ImpliedType())
-	if err != nil {
-		t.Fatal(err)
-	}
+			r := &Resource{
+				Schema: map[string]*Schema{
+					"size": {
+						Type:     TypeInt,
+						Optional: true,
+					},
+				},
+				CustomizeDiffWithDiagnostics: func(_ context.Context, _ *ResourceDiff, _ interface{}) diag.Diagnostics {
+					return tc.diags
+				},
+			}
 
-	if !cmp.Equal(proposedVal, plannedStateVal, valueComparer) {
-		t.Fatal(cmp.Diff(proposedVal, plannedStateVal, valueComparer))
-	}
+			server := NewGRPCProviderServer(&Provider{
+				ResourcesMap: map[string]*Resource{
+					"test": r,
+				},
+			})
 
-	plannedStateFoo, acc := plannedStateVal.GetAttr("foo").AsBigFloat().Int64()
-	if acc != big.Exact {
-		t.Fatalf("Expected exact accuracy, got %s", acc)
-	}
-	if plannedStateFoo != 7227701560655103598 {
-		t.Fatalf("Expected %d, got %d, this represents a loss of precision in planning large numbers", 7227701560655103598, plannedStateFoo)
+			schema := r.CoreConfigSchema()
+
+			priorState, err := msgpack.Marshal(cty.NullVal(schema.ImpliedType()), schema.ImpliedType())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			configVal := cty.ObjectVal(map[string]cty.Value{
+				"id":   cty.NullVal(cty.String),
+				"size": cty.NumberIntVal(500),
+			})
+			config, err := msgpack.Marshal(configVal, schema.ImpliedType())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := server.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+				TypeName: "test",
+				PriorState: &tfprotov5.DynamicValue{
+					MsgPack: priorState,
+				},
+				ProposedNewState: &tfprotov5.DynamicValue{
+					MsgPack: config,
+				},
+				Config: &tfprotov5.DynamicValue{
+					MsgPack: config,
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expectedDiagnostics, resp.Diagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+
+			if tc.expectPlannedState != (resp.PlannedState != nil) {
+				t.Errorf("expected planned state: %t, got: %#v", tc.expectPlannedState, resp.PlannedState)
+			}
+		})
 	}
 }
 
@@ -9618,59 +11046,536 @@ New Identity: cty.ObjectVal(map[string]cty.Value{"identity":cty.StringVal("chang
 						),
 					},
 				},
-				Config: &tfprotov5.DynamicValue{
-					MsgPack: mustMsgpackMarshal(
-						cty.Object(map[string]cty.Type{
-							"id":   cty.String,
-							"test": cty.String,
-						}),
-						cty.ObjectVal(map[string]cty.Value{
-							"id":   cty.NullVal(cty.String),
-							"test": cty.StringVal("initial"),
-						}),
-					),
+				Config: &tfprotov5.DynamicValue{
+					MsgPack: mustMsgpackMarshal(
+						cty.Object(map[string]cty.Type{
+							"id":   cty.String,
+							"test": cty.String,
+						}),
+						cty.ObjectVal(map[string]cty.Value{
+							"id":   cty.NullVal(cty.String),
+							"test": cty.StringVal("initial"),
+						}),
+					),
+				},
+			},
+			expected: &tfprotov5.ApplyResourceChangeResponse{
+				NewState: &tfprotov5.DynamicValue{
+					MsgPack: mustMsgpackMarshal(
+						cty.Object(map[string]cty.Type{
+							"id":   cty.String,
+							"test": cty.String,
+						}),
+						cty.NullVal(cty.Object(map[string]cty.Type{
+							"id":   cty.String,
+							"test": cty.String,
+						})),
+					),
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := testCase.server.ApplyResourceChange(context.Background(), testCase.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(resp, testCase.expected, valueComparer); diff != "" {
+				ty := testCase.server.getResourceSchemaBlock("test").ImpliedType()
+
+				if resp != nil && resp.NewState != nil {
+					t.Logf("resp.NewState.MsgPack: %s", mustMsgpackUnmarshal(ty, resp.NewState.MsgPack))
+				}
+
+				if testCase.expected != nil && testCase.expected.NewState != nil {
+					t.Logf("expected: %s", mustMsgpackUnmarshal(ty, testCase.expected.NewState.MsgPack))
+				}
+
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestGRPCProviderServer_dynamicAttributePartlyUnknown(t *testing.T) {
+	t.Parallel()
+
+	res := &Resource{
+		Schema: map[string]*Schema{
+			"payload": {
+				Type:     TypeDynamic,
+				Required: true,
+			},
+		},
+		CreateContext: func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+		ReadContext:   func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+		DeleteContext: func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+	}
+
+	server := NewGRPCProviderServer(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test": res,
+		},
+	})
+
+	ty := res.CoreConfigSchema().ImpliedType()
+
+	payload := cty.ObjectVal(map[string]cty.Value{
+		"name":  cty.StringVal("example"),
+		"owner": cty.UnknownVal(cty.String),
+		"items": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.DynamicVal}),
+		"tags":  cty.MapVal(map[string]cty.Value{"a": cty.StringVal("b"), "c": cty.UnknownVal(cty.String)}),
+		"zones": cty.SetVal([]cty.Value{cty.StringVal("a"), cty.UnknownVal(cty.String)}),
+	})
+
+	configVal := cty.ObjectVal(map[string]cty.Value{
+		"id":      cty.NullVal(cty.String),
+		"payload": payload,
+	})
+
+	resp, err := server.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "test",
+		PriorState:       &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, cty.NullVal(ty))},
+		ProposedNewState: &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+		Config:           &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("unexpected plan diagnostics: %#v", resp.Diagnostics)
+	}
+
+	plannedVal := mustMsgpackUnmarshal(ty, resp.PlannedState.MsgPack)
+
+	// Sets with unknown elements cannot keep their known elements.
+	expected := cty.ObjectVal(map[string]cty.Value{
+		"name":  cty.StringVal("example"),
+		"owner": cty.UnknownVal(cty.String),
+		"items": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.DynamicVal}),
+		"tags":  cty.MapVal(map[string]cty.Value{"a": cty.StringVal("b"), "c": cty.UnknownVal(cty.String)}),
+		"zones": cty.UnknownVal(cty.Set(cty.String)),
+	})
+
+	if !plannedVal.GetAttr("payload").RawEquals(expected) {
+		t.Fatalf("expected planned payload %#v, got %#v", expected, plannedVal.GetAttr("payload"))
+	}
+}
+
+func TestGRPCProviderServer_dynamicAttribute(t *testing.T) {
+	t.Parallel()
+
+	res := &Resource{
+		Schema: map[string]*Schema{
+			"payload": {
+				Type:     TypeDynamic,
+				Required: true,
+			},
+			"payload_type": {
+				Type:     TypeString,
+				Computed: true,
+			},
+		},
+		CreateContext: func(_ context.Context, d *ResourceData, _ interface{}) diag.Diagnostics {
+			payload, err := d.GetDynamic("payload")
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			d.SetId("test")
+
+			if err := d.Set("payload_type", payload.Type().FriendlyName()); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return nil
+		},
+		ReadContext: func(_ context.Context, d *ResourceData, _ interface{}) diag.Diagnostics {
+			payload, err := d.GetDynamic("payload")
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			if err := d.Set("payload", payload); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return nil
+		},
+		UpdateContext: func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+		DeleteContext: func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+	}
+
+	server := NewGRPCProviderServer(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test": res,
+		},
+	})
+
+	ty := res.CoreConfigSchema().ImpliedType()
+
+	payload := cty.ObjectVal(map[string]cty.Value{
+		"name":  cty.StringVal("example"),
+		"items": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.StringVal("two")}),
+	})
+
+	configVal := cty.ObjectVal(map[string]cty.Value{
+		"id":           cty.NullVal(cty.String),
+		"payload":      payload,
+		"payload_type": cty.NullVal(cty.String),
+	})
+
+	planResp, err := server.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "test",
+		PriorState:       &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, cty.NullVal(ty))},
+		ProposedNewState: &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+		Config:           &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(planResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected plan diagnostics: %#v", planResp.Diagnostics)
+	}
+
+	plannedVal := mustMsgpackUnmarshal(ty, planResp.PlannedState.MsgPack)
+
+	if !plannedVal.GetAttr("payload").RawEquals(payload) {
+		t.Fatalf("expected planned payload %#v, got %#v", payload, plannedVal.GetAttr("payload"))
+	}
+
+	applyResp, err := server.ApplyResourceChange(context.Background(), &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:       "test",
+		PriorState:     &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, cty.NullVal(ty))},
+		PlannedState:   planResp.PlannedState,
+		PlannedPrivate: planResp.PlannedPrivate,
+		Config:         &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applyResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected apply diagnostics: %#v", applyResp.Diagnostics)
+	}
+
+	newStateVal := mustMsgpackUnmarshal(ty, applyResp.NewState.MsgPack)
+
+	expectedStateVal := cty.ObjectVal(map[string]cty.Value{
+		"id":           cty.StringVal("test"),
+		"payload":      payload,
+		"payload_type": cty.StringVal("object"),
+	})
+
+	if !newStateVal.RawEquals(expectedStateVal) {
+		t.Fatalf("expected new state %#v, got %#v", expectedStateVal, newStateVal)
+	}
+
+	readResp, err := server.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+		TypeName:     "test",
+		CurrentState: applyResp.NewState,
+		Private:      applyResp.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(readResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected read diagnostics: %#v", readResp.Diagnostics)
+	}
+
+	readStateVal := mustMsgpackUnmarshal(ty, readResp.NewState.MsgPack)
+
+	if !readStateVal.RawEquals(expectedStateVal) {
+		t.Fatalf("expected read state %#v, got %#v", expectedStateVal, readStateVal)
+	}
+
+	proposedVal := cty.ObjectVal(map[string]cty.Value{
+		"id":           cty.StringVal("test"),
+		"payload":      payload,
+		"payload_type": cty.StringVal("object"),
+	})
+
+	updatePlanResp, err := server.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "test",
+		PriorState:       readResp.NewState,
+		PriorPrivate:     readResp.Private,
+		ProposedNewState: &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, proposedVal)},
+		Config:           &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(updatePlanResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected plan diagnostics: %#v", updatePlanResp.Diagnostics)
+	}
+
+	updatePlannedVal := mustMsgpackUnmarshal(ty, updatePlanResp.PlannedState.MsgPack)
+
+	if !updatePlannedVal.RawEquals(expectedStateVal) {
+		t.Fatalf("expected no changes, got planned state %#v", updatePlannedVal)
+	}
+}
+
+func TestGRPCProviderServerGetProviderSchema_nestedAttribute(t *testing.T) {
+	t.Parallel()
+
+	server := NewGRPCProviderServer(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test_resource": {
+				Schema: map[string]*Schema{
+					"block": {
+						Type:     TypeList,
+						Optional: true,
+						Elem: &Resource{
+							Schema: map[string]*Schema{
+								"nested": {
+									Type:       TypeSet,
+									Optional:   true,
+									ConfigMode: SchemaConfigModeNestedAttr,
+									Elem: &Resource{
+										Schema: map[string]*Schema{
+											"name": {
+												Type:     TypeString,
+												Required: true,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []*tfprotov5.Diagnostic{
+		{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Unsupported Nested Attribute",
+			Detail: "The \"block.nested\" attribute of the \"test_resource\" resource schema has a ConfigMode of SchemaConfigModeNestedAttr, " +
+				"which is only supported with protocol version 6. This is an issue with the provider and should be reported to the provider developers.",
+		},
+	}
+
+	if diff := cmp.Diff(expected, resp.Diagnostics); diff != "" {
+		t.Errorf("unexpected difference: %s", diff)
+	}
+}
+
+func TestGRPCProviderServer_nestedAttributes(t *testing.T) {
+	t.Parallel()
+
+	res := &Resource{
+		Schema: map[string]*Schema{
+			"single": {
+				Type:       TypeList,
+				Optional:   true,
+				MaxItems:   1,
+				ConfigMode: SchemaConfigModeNestedAttr,
+				Elem: &Resource{
+					Schema: map[string]*Schema{
+						"name": {
+							Type:     TypeString,
+							Required: true,
+						},
+						"computed": {
+							Type:     TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"list": {
+				Type:       TypeList,
+				Optional:   true,
+				ConfigMode: SchemaConfigModeNestedAttr,
+				Elem: &Resource{
+					Schema: map[string]*Schema{
+						"name": {
+							Type:     TypeString,
+							Optional: true,
+						},
+					},
 				},
 			},
-			expected: &tfprotov5.ApplyResourceChangeResponse{
-				NewState: &tfprotov5.DynamicValue{
-					MsgPack: mustMsgpackMarshal(
-						cty.Object(map[string]cty.Type{
-							"id":   cty.String,
-							"test": cty.String,
-						}),
-						cty.NullVal(cty.Object(map[string]cty.Type{
-							"id":   cty.String,
-							"test": cty.String,
-						})),
-					),
+			"unset": {
+				Type:       TypeList,
+				Optional:   true,
+				MaxItems:   1,
+				ConfigMode: SchemaConfigModeNestedAttr,
+				Elem: &Resource{
+					Schema: map[string]*Schema{
+						"name": {
+							Type:     TypeString,
+							Optional: true,
+						},
+					},
 				},
 			},
 		},
+		CreateContext: func(_ context.Context, d *ResourceData, _ interface{}) diag.Diagnostics {
+			d.SetId("test")
+
+			if err := d.Set("single", []interface{}{
+				map[string]interface{}{
+					"name":     d.Get("single.0.name"),
+					"computed": "computed-" + d.Get("single.0.name").(string),
+				},
+			}); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return nil
+		},
+		ReadContext:   func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+		UpdateContext: func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+		DeleteContext: func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
 	}
 
-	for name, testCase := range testCases {
-		t.Run(name, func(t *testing.T) {
-			t.Parallel()
+	server := NewGRPCProviderServer(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test": res,
+		},
+	})
 
-			resp, err := testCase.server.ApplyResourceChange(context.Background(), testCase.req)
-			if err != nil {
-				t.Fatal(err)
-			}
+	ty := res.CoreConfigSchema().ImpliedType()
 
-			if diff := cmp.Diff(resp, testCase.expected, valueComparer); diff != "" {
-				ty := testCase.server.getResourceSchemaBlock("test").ImpliedType()
+	listType := cty.Object(map[string]cty.Type{
+		"name": cty.String,
+	})
 
-				if resp != nil && resp.NewState != nil {
-					t.Logf("resp.NewState.MsgPack: %s", mustMsgpackUnmarshal(ty, resp.NewState.MsgPack))
-				}
+	configVal := cty.ObjectVal(map[string]cty.Value{
+		"id": cty.NullVal(cty.String),
+		"single": cty.ObjectVal(map[string]cty.Value{
+			"name":     cty.StringVal("a"),
+			"computed": cty.NullVal(cty.String),
+		}),
+		"list": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("b"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.NullVal(cty.String),
+			}),
+		}),
+		"unset": cty.NullVal(listType),
+	})
 
-				if testCase.expected != nil && testCase.expected.NewState != nil {
-					t.Logf("expected: %s", mustMsgpackUnmarshal(ty, testCase.expected.NewState.MsgPack))
-				}
+	planResp, err := server.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "test",
+		PriorState:       &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, cty.NullVal(ty))},
+		ProposedNewState: &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+		Config:           &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
 
-				t.Error(diff)
-			}
-		})
+	if len(planResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected plan diagnostics: %#v", planResp.Diagnostics)
+	}
+
+	plannedVal := mustMsgpackUnmarshal(ty, planResp.PlannedState.MsgPack)
+
+	expectedPlannedVal := cty.ObjectVal(map[string]cty.Value{
+		"id": cty.UnknownVal(cty.String),
+		"single": cty.ObjectVal(map[string]cty.Value{
+			"name":     cty.StringVal("a"),
+			"computed": cty.UnknownVal(cty.String),
+		}),
+		"list":  configVal.GetAttr("list"),
+		"unset": cty.NullVal(listType),
+	})
+
+	if !plannedVal.RawEquals(expectedPlannedVal) {
+		t.Fatalf("expected planned state %#v, got %#v", expectedPlannedVal, plannedVal)
+	}
+
+	applyResp, err := server.ApplyResourceChange(context.Background(), &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:       "test",
+		PriorState:     &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, cty.NullVal(ty))},
+		PlannedState:   planResp.PlannedState,
+		PlannedPrivate: planResp.PlannedPrivate,
+		Config:         &tfprotov5.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applyResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected apply diagnostics: %#v", applyResp.Diagnostics)
+	}
+
+	newStateVal := mustMsgpackUnmarshal(ty, applyResp.NewState.MsgPack)
+
+	expectedStateVal := cty.ObjectVal(map[string]cty.Value{
+		"id": cty.StringVal("test"),
+		"single": cty.ObjectVal(map[string]cty.Value{
+			"name":     cty.StringVal("a"),
+			"computed": cty.StringVal("computed-a"),
+		}),
+		"list":  configVal.GetAttr("list"),
+		"unset": cty.NullVal(listType),
+	})
+
+	if !newStateVal.RawEquals(expectedStateVal) {
+		t.Fatalf("expected new state %#v, got %#v", expectedStateVal, newStateVal)
+	}
+
+	readResp, err := server.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+		TypeName:     "test",
+		CurrentState: applyResp.NewState,
+		Private:      applyResp.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(readResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected read diagnostics: %#v", readResp.Diagnostics)
+	}
+
+	readStateVal := mustMsgpackUnmarshal(ty, readResp.NewState.MsgPack)
+
+	if !readStateVal.RawEquals(expectedStateVal) {
+		t.Fatalf("expected read state %#v, got %#v", expectedStateVal, readStateVal)
+	}
+
+	// The prior state of a block which was changed to a single nested
+	// attribute is migrated by UpgradeResourceState.
+	upgradeResp, err := server.UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: "test",
+		RawState: &tfprotov5.RawState{
+			JSON: []byte(`{"id":"test","single":[{"name":"a","computed":"computed-a"}],"list":[{"name":"b"},{"name":null}],"unset":[]}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(upgradeResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected upgrade diagnostics: %#v", upgradeResp.Diagnostics)
+	}
+
+	upgradedStateVal := mustMsgpackUnmarshal(ty, upgradeResp.UpgradedState.MsgPack)
+
+	if !upgradedStateVal.RawEquals(expectedStateVal) {
+		t.Fatalf("expected upgraded state %#v, got %#v", expectedStateVal, upgradedStateVal)
 	}
 }
 
@@ -13291,3 +15196,275 @@ func mustMsgpackUnmarshal(ty cty.Type, b []byte) cty.Value {
 
 	return result
 }
+
+func TestGRPCProviderServerResourceDeferred(t *testing.T) {
+	t.Parallel()
+
+	newResource := func() *Resource {
+		return &Resource{
+			Schema: map[string]*Schema{
+				"foo": {
+					Type:     TypeString,
+					Optional: true,
+				},
+			},
+			CustomizeDiff: func(ctx context.Context, d *ResourceDiff, meta interface{}) error {
+				if d.Get("foo").(string) == "defer" {
+					d.Defer(DeferredReasonAbsentPrereq)
+				}
+				return nil
+			},
+			ReadContext: func(ctx context.Context, d *ResourceData, meta interface{}) diag.Diagnostics {
+				if d.Get("foo").(string) == "defer" {
+					d.Defer(DeferredReasonAbsentPrereq)
+				}
+				return nil
+			},
+			Importer: &ResourceImporter{
+				StateContext: func(ctx context.Context, d *ResourceData, meta interface{}) ([]*ResourceData, error) {
+					if d.Id() == "defer" {
+						d.Defer(DeferredReasonAbsentPrereq)
+					}
+					return []*ResourceData{d}, nil
+				},
+			},
+		}
+	}
+
+	newServer := func() *GRPCProviderServer {
+		return NewGRPCProviderServer(&Provider{
+			ResourcesMap: map[string]*Resource{
+				"test": newResource(),
+			},
+			DataSourcesMap: map[string]*Resource{
+				"test": newResource(),
+			},
+		})
+	}
+
+	ty := newResource().CoreConfigSchema().ImpliedType()
+
+	stateVal := cty.ObjectVal(map[string]cty.Value{
+		"id":  cty.StringVal("bar"),
+		"foo": cty.StringVal("defer"),
+	})
+	state := &tfprotov5.DynamicValue{
+		MsgPack: mustMsgpackMarshal(ty, stateVal),
+	}
+	config := &tfprotov5.DynamicValue{
+		MsgPack: mustMsgpackMarshal(ty, cty.ObjectVal(map[string]cty.Value{
+			"id":  cty.NullVal(cty.String),
+			"foo": cty.StringVal("defer"),
+		})),
+	}
+	unknownState := &tfprotov5.DynamicValue{
+		MsgPack: mustMsgpackMarshal(ty, cty.UnknownVal(ty)),
+	}
+
+	expectedDeferred := &tfprotov5.Deferred{
+		Reason: tfprotov5.DeferredReasonAbsentPrereq,
+	}
+
+	t.Run("PlanResourceChange", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := newServer().PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+			TypeName:         "test",
+			PriorState:       state,
+			ProposedNewState: state,
+			Config:           config,
+			ClientCapabilities: &tfprotov5.PlanResourceChangeClientCapabilities{
+				DeferralAllowed: true,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Diagnostics) > 0 {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		if diff := cmp.Diff(expectedDeferred, resp.Deferred); diff != "" {
+			t.Errorf("unexpected deferred difference: %s", diff)
+		}
+
+		plannedStateVal, err := msgpack.Unmarshal(resp.PlannedState.MsgPack, ty)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !cmp.Equal(stateVal, plannedStateVal, valueComparer) {
+			t.Error(cmp.Diff(stateVal, plannedStateVal, valueComparer))
+		}
+	})
+
+	t.Run("PlanResourceChange-deferral-not-allowed", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := newServer().PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+			TypeName:         "test",
+			PriorState:       state,
+			ProposedNewState: state,
+			Config:           config,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []*tfprotov5.Diagnostic{
+			invalidDeferredResponseDiagnostic("Resource"),
+		}
+
+		if diff := cmp.Diff(expected, resp.Diagnostics); diff != "" {
+			t.Errorf("unexpected diagnostics difference: %s", diff)
+		}
+
+		if resp.Deferred != nil {
+			t.Errorf("unexpected deferred response: %v", resp.Deferred)
+		}
+	})
+
+	t.Run("ReadResource", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := newServer().ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+			TypeName:     "test",
+			CurrentState: state,
+			ClientCapabilities: &tfprotov5.ReadResourceClientCapabilities{
+				DeferralAllowed: true,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Diagnostics) > 0 {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		if diff := cmp.Diff(expectedDeferred, resp.Deferred); diff != "" {
+			t.Errorf("unexpected deferred difference: %s", diff)
+		}
+
+		if diff := cmp.Diff(state, resp.NewState); diff != "" {
+			t.Errorf("unexpected new state difference: %s", diff)
+		}
+	})
+
+	t.Run("ReadResource-deferral-not-allowed", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := newServer().ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+			TypeName:     "test",
+			CurrentState: state,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []*tfprotov5.Diagnostic{
+			invalidDeferredResponseDiagnostic("Resource"),
+		}
+
+		if diff := cmp.Diff(expected, resp.Diagnostics); diff != "" {
+			t.Errorf("unexpected diagnostics difference: %s", diff)
+		}
+	})
+
+	t.Run("ReadDataSource", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := newServer().ReadDataSource(context.Background(), &tfprotov5.ReadDataSourceRequest{
+			TypeName: "test",
+			Config:   config,
+			ClientCapabilities: &tfprotov5.ReadDataSourceClientCapabilities{
+				DeferralAllowed: true,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Diagnostics) > 0 {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		if diff := cmp.Diff(expectedDeferred, resp.Deferred); diff != "" {
+			t.Errorf("unexpected deferred difference: %s", diff)
+		}
+
+		if diff := cmp.Diff(unknownState, resp.State); diff != "" {
+			t.Errorf("unexpected state difference: %s", diff)
+		}
+	})
+
+	t.Run("ReadDataSource-deferral-not-allowed", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := newServer().ReadDataSource(context.Background(), &tfprotov5.ReadDataSourceRequest{
+			TypeName: "test",
+			Config:   config,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []*tfprotov5.Diagnostic{
+			invalidDeferredResponseDiagnostic("Data Source"),
+		}
+
+		if diff := cmp.Diff(expected, resp.Diagnostics); diff != "" {
+			t.Errorf("unexpected diagnostics difference: %s", diff)
+		}
+	})
+
+	t.Run("ImportResourceState", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := newServer().ImportResourceState(context.Background(), &tfprotov5.ImportResourceStateRequest{
+			TypeName: "test",
+			ID:       "defer",
+			ClientCapabilities: &tfprotov5.ImportResourceStateClientCapabilities{
+				DeferralAllowed: true,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Diagnostics) > 0 {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		expected := &tfprotov5.ImportResourceStateResponse{
+			ImportedResources: []*tfprotov5.ImportedResource{
+				{
+					TypeName: "test",
+					State:    unknownState,
+				},
+			},
+			Deferred: expectedDeferred,
+		}
+
+		if diff := cmp.Diff(expected, resp); diff != "" {
+			t.Errorf("unexpected response difference: %s", diff)
+		}
+	})
+
+	t.Run("ImportResourceState-deferral-not-allowed", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := newServer().ImportResourceState(context.Background(), &tfprotov5.ImportResourceStateRequest{
+			TypeName: "test",
+			ID:       "defer",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []*tfprotov5.Diagnostic{
+			invalidDeferredResponseDiagnostic("Resource"),
+		}
+
+		if diff := cmp.Diff(expected, resp.Diagnostics); diff != "" {
+			t.Errorf("unexpected diagnostics difference: %s", diff)
+		}
+	})
+}
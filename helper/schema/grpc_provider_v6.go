@@ -0,0 +1,753 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugin/convert"
+)
+
+// Verify provider server interface implementation.
+var (
+	_ tfprotov6.ProviderServer                 = (*GRPCProviderServerV6)(nil)
+	_ tfprotov6.ProviderServerWithListResource = (*GRPCProviderServerV6)(nil)
+	_ tfprotov6.ProviderServerWithActions      = (*GRPCProviderServerV6)(nil)
+)
+
+// NewGRPCProviderServerV6 returns a server for the given provider which
+// implements version 6 of the plugin protocol, such as for muxing with
+// providers built on terraform-plugin-framework.
+//
+// Schemas are sent with protocol version 6 features, such as attributes with
+// a ConfigMode of SchemaConfigModeNestedAttr being sent as nested attributes.
+// All other RPCs are served by the implementation of the server returned by
+// NewGRPCProviderServer, converting their requests and responses between
+// the structurally identical types of the two protocol versions, so they
+// behave the same for both.
+func NewGRPCProviderServerV6(p *Provider) *GRPCProviderServerV6 {
+	return &GRPCProviderServerV6{
+		server: NewGRPCProviderServer(p),
+	}
+}
+
+// GRPCProviderServerV6 handles the server, or plugin side of the rpc
+// connection, for version 6 of the plugin protocol, by delegating to a
+// GRPCProviderServer.
+type GRPCProviderServerV6 struct {
+	server *GRPCProviderServer
+}
+
+// StopContext derives a new context from the passed in grpc context.
+// It creates a goroutine to wait for the server stop and propagates
+// cancellation to the derived grpc context.
+func (s *GRPCProviderServerV6) StopContext(ctx context.Context) context.Context {
+	return s.server.StopContext(ctx)
+}
+
+func (s *GRPCProviderServerV6) GetMetadata(ctx context.Context, req *tfprotov6.GetMetadataRequest) (*tfprotov6.GetMetadataResponse, error) {
+	v5Resp, err := s.server.GetMetadata(ctx, &tfprotov5.GetMetadataRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &tfprotov6.GetMetadataResponse{
+		ServerCapabilities: (*tfprotov6.ServerCapabilities)(v5Resp.ServerCapabilities),
+		Diagnostics:        v6Diagnostics(v5Resp.Diagnostics),
+	}
+
+	for _, m := range v5Resp.DataSources {
+		resp.DataSources = append(resp.DataSources, tfprotov6.DataSourceMetadata(m))
+	}
+
+	for _, m := range v5Resp.Functions {
+		resp.Functions = append(resp.Functions, tfprotov6.FunctionMetadata(m))
+	}
+
+	for _, m := range v5Resp.Resources {
+		resp.Resources = append(resp.Resources, tfprotov6.ResourceMetadata(m))
+	}
+
+	for _, m := range v5Resp.EphemeralResources {
+		resp.EphemeralResources = append(resp.EphemeralResources, tfprotov6.EphemeralResourceMetadata(m))
+	}
+
+	for _, m := range v5Resp.ListResources {
+		resp.ListResources = append(resp.ListResources, tfprotov6.ListResourceMetadata(m))
+	}
+
+	for _, m := range v5Resp.Actions {
+		resp.Actions = append(resp.Actions, tfprotov6.ActionMetadata(m))
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServerV6) GetProviderSchema(ctx context.Context, req *tfprotov6.GetProviderSchemaRequest) (*tfprotov6.GetProviderSchemaResponse, error) {
+	ctx = logging.InitContext(ctx)
+
+	logging.HelperSchemaTrace(ctx, "Getting provider schema")
+
+	p := s.server.provider
+	schemas := s.server.providerSchemas(ctx)
+
+	resp := &tfprotov6.GetProviderSchemaResponse{
+		DataSourceSchemas:        make(map[string]*tfprotov6.Schema, len(schemas.dataSources)),
+		EphemeralResourceSchemas: make(map[string]*tfprotov6.Schema, len(schemas.ephemeralResources)),
+		Functions:                make(map[string]*tfprotov6.Function, len(p.Functions)),
+		ListResourceSchemas:      make(map[string]*tfprotov6.Schema, len(schemas.listResources)),
+		ActionSchemas:            make(map[string]*tfprotov6.ActionSchema, len(schemas.actions)),
+		ResourceSchemas:          make(map[string]*tfprotov6.Schema, len(schemas.resources)),
+		ServerCapabilities:       (*tfprotov6.ServerCapabilities)(s.server.serverCapabilities()),
+	}
+
+	toProto := func(schema providerSchema) *tfprotov6.Schema {
+		return &tfprotov6.Schema{
+			Version: schema.version,
+			Block:   convert.ConfigSchemaToProtoV6(ctx, schema.block),
+		}
+	}
+
+	resp.Provider = toProto(schemas.provider)
+	resp.ProviderMeta = toProto(schemas.providerMeta)
+
+	for typ, schema := range schemas.resources {
+		resp.ResourceSchemas[typ] = toProto(schema)
+	}
+
+	for typ, schema := range schemas.dataSources {
+		resp.DataSourceSchemas[typ] = toProto(schema)
+	}
+
+	for typ, schema := range schemas.ephemeralResources {
+		resp.EphemeralResourceSchemas[typ] = toProto(schema)
+	}
+
+	for typ, schema := range schemas.actions {
+		resp.ActionSchemas[typ] = &tfprotov6.ActionSchema{
+			Schema: toProto(schema),
+		}
+	}
+
+	for typ, schema := range schemas.listResources {
+		resp.ListResourceSchemas[typ] = toProto(schema)
+	}
+
+	for name, f := range p.Functions {
+		logging.HelperSchemaTrace(ctx, "Found function", map[string]interface{}{logging.KeyFunctionName: name})
+
+		function, err := f.ProtoFunction()
+		if err != nil {
+			resp.Diagnostics = v6Diagnostics(convert.AppendProtoDiag(ctx, nil, fmt.Errorf("getting function definition failed for function '%s': %w", name, err)))
+			return resp, nil
+		}
+
+		resp.Functions[name] = v6Function(function)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServerV6) GetResourceIdentitySchemas(ctx context.Context, req *tfprotov6.GetResourceIdentitySchemasRequest) (*tfprotov6.GetResourceIdentitySchemasResponse, error) {
+	ctx = logging.InitContext(ctx)
+
+	logging.HelperSchemaTrace(ctx, "Getting resource identity schemas")
+
+	resp := &tfprotov6.GetResourceIdentitySchemasResponse{
+		IdentitySchemas: make(map[string]*tfprotov6.ResourceIdentitySchema),
+	}
+
+	for typ, res := range s.server.provider.ResourcesMap {
+		logging.HelperSchemaTrace(ctx, "Found resource identity type", map[string]interface{}{logging.KeyResourceType: typ})
+
+		if res.Identity != nil {
+			idschema, err := res.CoreIdentitySchema()
+
+			if err != nil {
+				resp.Diagnostics = v6Diagnostics(convert.AppendProtoDiag(ctx, nil, fmt.Errorf("getting identity schema failed for resource '%s': %w", typ, err)))
+				return resp, nil
+			}
+
+			resp.IdentitySchemas[typ] = &tfprotov6.ResourceIdentitySchema{
+				Version:            res.Identity.Version,
+				IdentityAttributes: convert.ConfigIdentitySchemaToProtoV6(ctx, idschema),
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServerV6) ValidateProviderConfig(ctx context.Context, req *tfprotov6.ValidateProviderConfigRequest) (*tfprotov6.ValidateProviderConfigResponse, error) {
+	v5Resp, err := s.server.PrepareProviderConfig(ctx, &tfprotov5.PrepareProviderConfigRequest{
+		Config: v5DynamicValue(req.Config),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ValidateProviderConfigResponse{
+		PreparedConfig: v6DynamicValue(v5Resp.PreparedConfig),
+		Diagnostics:    v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) ConfigureProvider(ctx context.Context, req *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	v5Resp, err := s.server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		TerraformVersion:   req.TerraformVersion,
+		Config:             v5DynamicValue(req.Config),
+		ClientCapabilities: (*tfprotov5.ConfigureProviderClientCapabilities)(req.ClientCapabilities),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ConfigureProviderResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) StopProvider(ctx context.Context, req *tfprotov6.StopProviderRequest) (*tfprotov6.StopProviderResponse, error) {
+	v5Resp, err := s.server.StopProvider(ctx, &tfprotov5.StopProviderRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.StopProviderResponse{
+		Error: v5Resp.Error,
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) ValidateResourceConfig(ctx context.Context, req *tfprotov6.ValidateResourceConfigRequest) (*tfprotov6.ValidateResourceConfigResponse, error) {
+	v5Resp, err := s.server.ValidateResourceTypeConfig(ctx, &tfprotov5.ValidateResourceTypeConfigRequest{
+		TypeName:           req.TypeName,
+		Config:             v5DynamicValue(req.Config),
+		ClientCapabilities: (*tfprotov5.ValidateResourceTypeConfigClientCapabilities)(req.ClientCapabilities),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ValidateResourceConfigResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) UpgradeResourceState(ctx context.Context, req *tfprotov6.UpgradeResourceStateRequest) (*tfprotov6.UpgradeResourceStateResponse, error) {
+	v5Resp, err := s.server.UpgradeResourceState(ctx, &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: req.TypeName,
+		Version:  req.Version,
+		RawState: (*tfprotov5.RawState)(req.RawState),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.UpgradeResourceStateResponse{
+		UpgradedState: v6DynamicValue(v5Resp.UpgradedState),
+		Diagnostics:   v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) UpgradeResourceIdentity(ctx context.Context, req *tfprotov6.UpgradeResourceIdentityRequest) (*tfprotov6.UpgradeResourceIdentityResponse, error) {
+	v5Resp, err := s.server.UpgradeResourceIdentity(ctx, &tfprotov5.UpgradeResourceIdentityRequest{
+		TypeName:    req.TypeName,
+		Version:     req.Version,
+		RawIdentity: (*tfprotov5.RawState)(req.RawIdentity),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.UpgradeResourceIdentityResponse{
+		UpgradedIdentity: v6ResourceIdentityData(v5Resp.UpgradedIdentity),
+		Diagnostics:      v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) ReadResource(ctx context.Context, req *tfprotov6.ReadResourceRequest) (*tfprotov6.ReadResourceResponse, error) {
+	v5Resp, err := s.server.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		TypeName:           req.TypeName,
+		CurrentState:       v5DynamicValue(req.CurrentState),
+		Private:            req.Private,
+		ProviderMeta:       v5DynamicValue(req.ProviderMeta),
+		ClientCapabilities: (*tfprotov5.ReadResourceClientCapabilities)(req.ClientCapabilities),
+		CurrentIdentity:    v5ResourceIdentityData(req.CurrentIdentity),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ReadResourceResponse{
+		NewState:    v6DynamicValue(v5Resp.NewState),
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+		Private:     v5Resp.Private,
+		Deferred:    v6Deferred(v5Resp.Deferred),
+		NewIdentity: v6ResourceIdentityData(v5Resp.NewIdentity),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) PlanResourceChange(ctx context.Context, req *tfprotov6.PlanResourceChangeRequest) (*tfprotov6.PlanResourceChangeResponse, error) {
+	v5Resp, err := s.server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:           req.TypeName,
+		PriorState:         v5DynamicValue(req.PriorState),
+		ProposedNewState:   v5DynamicValue(req.ProposedNewState),
+		Config:             v5DynamicValue(req.Config),
+		PriorPrivate:       req.PriorPrivate,
+		ProviderMeta:       v5DynamicValue(req.ProviderMeta),
+		ClientCapabilities: (*tfprotov5.PlanResourceChangeClientCapabilities)(req.ClientCapabilities),
+		PriorIdentity:      v5ResourceIdentityData(req.PriorIdentity),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.PlanResourceChangeResponse{
+		PlannedState:                v6DynamicValue(v5Resp.PlannedState),
+		RequiresReplace:             v5Resp.RequiresReplace,
+		PlannedPrivate:              v5Resp.PlannedPrivate,
+		Diagnostics:                 v6Diagnostics(v5Resp.Diagnostics),
+		UnsafeToUseLegacyTypeSystem: v5Resp.UnsafeToUseLegacyTypeSystem,
+		Deferred:                    v6Deferred(v5Resp.Deferred),
+		PlannedIdentity:             v6ResourceIdentityData(v5Resp.PlannedIdentity),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) ApplyResourceChange(ctx context.Context, req *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	v5Resp, err := s.server.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:        req.TypeName,
+		PriorState:      v5DynamicValue(req.PriorState),
+		PlannedState:    v5DynamicValue(req.PlannedState),
+		Config:          v5DynamicValue(req.Config),
+		PlannedPrivate:  req.PlannedPrivate,
+		ProviderMeta:    v5DynamicValue(req.ProviderMeta),
+		PlannedIdentity: v5ResourceIdentityData(req.PlannedIdentity),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ApplyResourceChangeResponse{
+		NewState:                    v6DynamicValue(v5Resp.NewState),
+		Private:                     v5Resp.Private,
+		Diagnostics:                 v6Diagnostics(v5Resp.Diagnostics),
+		UnsafeToUseLegacyTypeSystem: v5Resp.UnsafeToUseLegacyTypeSystem,
+		NewIdentity:                 v6ResourceIdentityData(v5Resp.NewIdentity),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) ImportResourceState(ctx context.Context, req *tfprotov6.ImportResourceStateRequest) (*tfprotov6.ImportResourceStateResponse, error) {
+	v5Resp, err := s.server.ImportResourceState(ctx, &tfprotov5.ImportResourceStateRequest{
+		TypeName:           req.TypeName,
+		ID:                 req.ID,
+		ClientCapabilities: (*tfprotov5.ImportResourceStateClientCapabilities)(req.ClientCapabilities),
+		Identity:           v5ResourceIdentityData(req.Identity),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &tfprotov6.ImportResourceStateResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+		Deferred:    v6Deferred(v5Resp.Deferred),
+	}
+
+	for _, r := range v5Resp.ImportedResources {
+		resp.ImportedResources = append(resp.ImportedResources, &tfprotov6.ImportedResource{
+			TypeName: r.TypeName,
+			State:    v6DynamicValue(r.State),
+			Private:  r.Private,
+			Identity: v6ResourceIdentityData(r.Identity),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServerV6) MoveResourceState(ctx context.Context, req *tfprotov6.MoveResourceStateRequest) (*tfprotov6.MoveResourceStateResponse, error) {
+	v5Resp, err := s.server.MoveResourceState(ctx, &tfprotov5.MoveResourceStateRequest{
+		SourcePrivate:               req.SourcePrivate,
+		SourceProviderAddress:       req.SourceProviderAddress,
+		SourceSchemaVersion:         req.SourceSchemaVersion,
+		SourceState:                 (*tfprotov5.RawState)(req.SourceState),
+		SourceTypeName:              req.SourceTypeName,
+		TargetTypeName:              req.TargetTypeName,
+		SourceIdentity:              (*tfprotov5.RawState)(req.SourceIdentity),
+		SourceIdentitySchemaVersion: req.SourceIdentitySchemaVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.MoveResourceStateResponse{
+		TargetPrivate:  v5Resp.TargetPrivate,
+		TargetState:    v6DynamicValue(v5Resp.TargetState),
+		Diagnostics:    v6Diagnostics(v5Resp.Diagnostics),
+		TargetIdentity: v6ResourceIdentityData(v5Resp.TargetIdentity),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) GenerateResourceConfig(ctx context.Context, req *tfprotov6.GenerateResourceConfigRequest) (*tfprotov6.GenerateResourceConfigResponse, error) {
+	v5Resp, err := s.server.GenerateResourceConfig(ctx, &tfprotov5.GenerateResourceConfigRequest{
+		TypeName: req.TypeName,
+		State:    v5DynamicValue(req.State),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.GenerateResourceConfigResponse{
+		Config:      v6DynamicValue(v5Resp.Config),
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) ValidateDataResourceConfig(ctx context.Context, req *tfprotov6.ValidateDataResourceConfigRequest) (*tfprotov6.ValidateDataResourceConfigResponse, error) {
+	v5Resp, err := s.server.ValidateDataSourceConfig(ctx, &tfprotov5.ValidateDataSourceConfigRequest{
+		TypeName: req.TypeName,
+		Config:   v5DynamicValue(req.Config),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ValidateDataResourceConfigResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) ReadDataSource(ctx context.Context, req *tfprotov6.ReadDataSourceRequest) (*tfprotov6.ReadDataSourceResponse, error) {
+	v5Resp, err := s.server.ReadDataSource(ctx, &tfprotov5.ReadDataSourceRequest{
+		TypeName:           req.TypeName,
+		Config:             v5DynamicValue(req.Config),
+		ProviderMeta:       v5DynamicValue(req.ProviderMeta),
+		ClientCapabilities: (*tfprotov5.ReadDataSourceClientCapabilities)(req.ClientCapabilities),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ReadDataSourceResponse{
+		State:       v6DynamicValue(v5Resp.State),
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+		Deferred:    v6Deferred(v5Resp.Deferred),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) CallFunction(ctx context.Context, req *tfprotov6.CallFunctionRequest) (*tfprotov6.CallFunctionResponse, error) {
+	v5Req := &tfprotov5.CallFunctionRequest{
+		Name: req.Name,
+	}
+
+	for _, arg := range req.Arguments {
+		v5Req.Arguments = append(v5Req.Arguments, v5DynamicValue(arg))
+	}
+
+	v5Resp, err := s.server.CallFunction(ctx, v5Req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.CallFunctionResponse{
+		Error:  (*tfprotov6.FunctionError)(v5Resp.Error),
+		Result: v6DynamicValue(v5Resp.Result),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) GetFunctions(ctx context.Context, req *tfprotov6.GetFunctionsRequest) (*tfprotov6.GetFunctionsResponse, error) {
+	v5Resp, err := s.server.GetFunctions(ctx, &tfprotov5.GetFunctionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &tfprotov6.GetFunctionsResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+		Functions:   make(map[string]*tfprotov6.Function, len(v5Resp.Functions)),
+	}
+
+	for name, f := range v5Resp.Functions {
+		resp.Functions[name] = v6Function(f)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCProviderServerV6) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov6.ValidateEphemeralResourceConfigRequest) (*tfprotov6.ValidateEphemeralResourceConfigResponse, error) {
+	v5Resp, err := s.server.ValidateEphemeralResourceConfig(ctx, &tfprotov5.ValidateEphemeralResourceConfigRequest{
+		TypeName: req.TypeName,
+		Config:   v5DynamicValue(req.Config),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ValidateEphemeralResourceConfigResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) OpenEphemeralResource(ctx context.Context, req *tfprotov6.OpenEphemeralResourceRequest) (*tfprotov6.OpenEphemeralResourceResponse, error) {
+	v5Resp, err := s.server.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName:           req.TypeName,
+		Config:             v5DynamicValue(req.Config),
+		ClientCapabilities: (*tfprotov5.OpenEphemeralResourceClientCapabilities)(req.ClientCapabilities),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.OpenEphemeralResourceResponse{
+		Result:      v6DynamicValue(v5Resp.Result),
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+		Private:     v5Resp.Private,
+		RenewAt:     v5Resp.RenewAt,
+		Deferred:    v6Deferred(v5Resp.Deferred),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) RenewEphemeralResource(ctx context.Context, req *tfprotov6.RenewEphemeralResourceRequest) (*tfprotov6.RenewEphemeralResourceResponse, error) {
+	v5Resp, err := s.server.RenewEphemeralResource(ctx, &tfprotov5.RenewEphemeralResourceRequest{
+		TypeName: req.TypeName,
+		Private:  req.Private,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.RenewEphemeralResourceResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+		Private:     v5Resp.Private,
+		RenewAt:     v5Resp.RenewAt,
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) CloseEphemeralResource(ctx context.Context, req *tfprotov6.CloseEphemeralResourceRequest) (*tfprotov6.CloseEphemeralResourceResponse, error) {
+	v5Resp, err := s.server.CloseEphemeralResource(ctx, &tfprotov5.CloseEphemeralResourceRequest{
+		TypeName: req.TypeName,
+		Private:  req.Private,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.CloseEphemeralResourceResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) ValidateListResourceConfig(ctx context.Context, req *tfprotov6.ValidateListResourceConfigRequest) (*tfprotov6.ValidateListResourceConfigResponse, error) {
+	v5Resp, err := s.server.ValidateListResourceConfig(ctx, &tfprotov5.ValidateListResourceConfigRequest{
+		TypeName:              req.TypeName,
+		Config:                v5DynamicValue(req.Config),
+		IncludeResourceObject: v5DynamicValue(req.IncludeResourceObject),
+		Limit:                 v5DynamicValue(req.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ValidateListResourceConfigResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) ListResource(ctx context.Context, req *tfprotov6.ListResourceRequest) (*tfprotov6.ListResourceServerStream, error) {
+	v5Stream, err := s.server.ListResource(ctx, &tfprotov5.ListResourceRequest{
+		TypeName:        req.TypeName,
+		Config:          v5DynamicValue(req.Config),
+		IncludeResource: req.IncludeResource,
+		Limit:           req.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ListResourceServerStream{
+		Results: func(yield func(tfprotov6.ListResourceResult) bool) {
+			for result := range v5Stream.Results {
+				v6Result := tfprotov6.ListResourceResult{
+					DisplayName: result.DisplayName,
+					Resource:    v6DynamicValue(result.Resource),
+					Identity:    v6ResourceIdentityData(result.Identity),
+					Diagnostics: v6Diagnostics(result.Diagnostics),
+				}
+
+				if !yield(v6Result) {
+					return
+				}
+			}
+		},
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) ValidateActionConfig(ctx context.Context, req *tfprotov6.ValidateActionConfigRequest) (*tfprotov6.ValidateActionConfigResponse, error) {
+	v5Resp, err := s.server.ValidateActionConfig(ctx, &tfprotov5.ValidateActionConfigRequest{
+		ActionType: req.ActionType,
+		Config:     v5DynamicValue(req.Config),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.ValidateActionConfigResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) PlanAction(ctx context.Context, req *tfprotov6.PlanActionRequest) (*tfprotov6.PlanActionResponse, error) {
+	v5Resp, err := s.server.PlanAction(ctx, &tfprotov5.PlanActionRequest{
+		ActionType:         req.ActionType,
+		Config:             v5DynamicValue(req.Config),
+		ClientCapabilities: (*tfprotov5.PlanActionClientCapabilities)(req.ClientCapabilities),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.PlanActionResponse{
+		Diagnostics: v6Diagnostics(v5Resp.Diagnostics),
+		Deferred:    v6Deferred(v5Resp.Deferred),
+	}, nil
+}
+
+func (s *GRPCProviderServerV6) InvokeAction(ctx context.Context, req *tfprotov6.InvokeActionRequest) (*tfprotov6.InvokeActionServerStream, error) {
+	v5Stream, err := s.server.InvokeAction(ctx, &tfprotov5.InvokeActionRequest{
+		ActionType:         req.ActionType,
+		Config:             v5DynamicValue(req.Config),
+		ClientCapabilities: (*tfprotov5.InvokeActionClientCapabilities)(req.ClientCapabilities),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.InvokeActionServerStream{
+		Events: func(yield func(tfprotov6.InvokeActionEvent) bool) {
+			for event := range v5Stream.Events {
+				var v6Event tfprotov6.InvokeActionEvent
+
+				switch e := event.Type.(type) {
+				case tfprotov5.ProgressInvokeActionEventType:
+					v6Event.Type = tfprotov6.ProgressInvokeActionEventType{
+						Message: e.Message,
+					}
+				case tfprotov5.CompletedInvokeActionEventType:
+					v6Event.Type = tfprotov6.CompletedInvokeActionEventType{
+						Diagnostics: v6Diagnostics(e.Diagnostics),
+					}
+				}
+
+				if !yield(v6Event) {
+					return
+				}
+			}
+		},
+	}, nil
+}
+
+// The helpers below convert the protocol version 5 types used by
+// GRPCProviderServer to and from their protocol version 6 equivalents. Types
+// with identical definitions in both versions are converted directly.
+
+func v5DynamicValue(in *tfprotov6.DynamicValue) *tfprotov5.DynamicValue {
+	return (*tfprotov5.DynamicValue)(in)
+}
+
+func v6DynamicValue(in *tfprotov5.DynamicValue) *tfprotov6.DynamicValue {
+	return (*tfprotov6.DynamicValue)(in)
+}
+
+func v5ResourceIdentityData(in *tfprotov6.ResourceIdentityData) *tfprotov5.ResourceIdentityData {
+	if in == nil {
+		return nil
+	}
+
+	return &tfprotov5.ResourceIdentityData{
+		IdentityData: v5DynamicValue(in.IdentityData),
+	}
+}
+
+func v6ResourceIdentityData(in *tfprotov5.ResourceIdentityData) *tfprotov6.ResourceIdentityData {
+	if in == nil {
+		return nil
+	}
+
+	return &tfprotov6.ResourceIdentityData{
+		IdentityData: v6DynamicValue(in.IdentityData),
+	}
+}
+
+func v6Deferred(in *tfprotov5.Deferred) *tfprotov6.Deferred {
+	if in == nil {
+		return nil
+	}
+
+	return &tfprotov6.Deferred{
+		Reason: tfprotov6.DeferredReason(in.Reason),
+	}
+}
+
+func v6Diagnostics(in []*tfprotov5.Diagnostic) []*tfprotov6.Diagnostic {
+	if in == nil {
+		return nil
+	}
+
+	diags := make([]*tfprotov6.Diagnostic, 0, len(in))
+
+	for _, d := range in {
+		diags = append(diags, &tfprotov6.Diagnostic{
+			Severity:  tfprotov6.DiagnosticSeverity(d.Severity),
+			Summary:   d.Summary,
+			Detail:    d.Detail,
+			Attribute: d.Attribute,
+		})
+	}
+
+	return diags
+}
+
+func v6Function(in *tfprotov5.Function) *tfprotov6.Function {
+	if in == nil {
+		return nil
+	}
+
+	f := &tfprotov6.Function{
+		VariadicParameter:  v6FunctionParameter(in.VariadicParameter),
+		Summary:            in.Summary,
+		Description:        in.Description,
+		DescriptionKind:    tfprotov6.StringKind(in.DescriptionKind),
+		DeprecationMessage: in.DeprecationMessage,
+	}
+
+	for _, p := range in.Parameters {
+		f.Parameters = append(f.Parameters, v6FunctionParameter(p))
+	}
+
+	if in.Return != nil {
+		f.Return = &tfprotov6.FunctionReturn{
+			Type: in.Return.Type,
+		}
+	}
+
+	return f
+}
+
+func v6FunctionParameter(in *tfprotov5.FunctionParameter) *tfprotov6.FunctionParameter {
+	if in == nil {
+		return nil
+	}
+
+	return &tfprotov6.FunctionParameter{
+		AllowNullValue:     in.AllowNullValue,
+		AllowUnknownValues: in.AllowUnknownValues,
+		Description:        in.Description,
+		DescriptionKind:    tfprotov6.StringKind(in.DescriptionKind),
+		Name:               in.Name,
+		Type:               in.Type,
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestGRPCProviderServerV6GetProviderSchema(t *testing.T) {
+	t.Parallel()
+
+	server := NewGRPCProviderServerV6(&Provider{
+		Schema: map[string]*Schema{
+			"region": {
+				Type:     TypeString,
+				Optional: true,
+			},
+		},
+		ResourcesMap: map[string]*Resource{
+			"test_resource": {
+				Schema: map[string]*Schema{
+					"nested": {
+						Type:       TypeList,
+						Optional:   true,
+						MaxItems:   1,
+						ConfigMode: SchemaConfigModeNestedAttr,
+						Elem: &Resource{
+							Schema: map[string]*Schema{
+								"name": {
+									Type:     TypeString,
+									Required: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := &tfprotov6.GetProviderSchemaResponse{
+		Provider: &tfprotov6.Schema{
+			Block: &tfprotov6.SchemaBlock{
+				Attributes: []*tfprotov6.SchemaAttribute{
+					{
+						Name:            "region",
+						Type:            tftypes.String,
+						Optional:        true,
+						DescriptionKind: tfprotov6.StringKindPlain,
+					},
+				},
+			},
+		},
+		ProviderMeta: &tfprotov6.Schema{
+			Block: &tfprotov6.SchemaBlock{},
+		},
+		ResourceSchemas: map[string]*tfprotov6.Schema{
+			"test_resource": {
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: []*tfprotov6.SchemaAttribute{
+						{
+							Name:            "id",
+							Type:            tftypes.String,
+							Optional:        true,
+							Computed:        true,
+							DescriptionKind: tfprotov6.StringKindPlain,
+						},
+						{
+							Name: "nested",
+							NestedType: &tfprotov6.SchemaObject{
+								Attributes: []*tfprotov6.SchemaAttribute{
+									{
+										Name:            "name",
+										Type:            tftypes.String,
+										Required:        true,
+										DescriptionKind: tfprotov6.StringKindPlain,
+									},
+								},
+								Nesting: tfprotov6.SchemaObjectNestingModeSingle,
+							},
+							Optional:        true,
+							DescriptionKind: tfprotov6.StringKindPlain,
+						},
+					},
+				},
+			},
+		},
+		DataSourceSchemas:        map[string]*tfprotov6.Schema{},
+		EphemeralResourceSchemas: map[string]*tfprotov6.Schema{},
+		ListResourceSchemas:      map[string]*tfprotov6.Schema{},
+		ActionSchemas:            map[string]*tfprotov6.ActionSchema{},
+		Functions:                map[string]*tfprotov6.Function{},
+		ServerCapabilities: &tfprotov6.ServerCapabilities{
+			GetProviderSchemaOptional: true,
+			GenerateResourceConfig:    true,
+		},
+	}
+
+	if diff := cmp.Diff(expected, resp); diff != "" {
+		t.Fatalf("unexpected difference: %s", diff)
+	}
+}
+
+func TestGRPCProviderServerV6_resourceChange(t *testing.T) {
+	t.Parallel()
+
+	res := &Resource{
+		Schema: map[string]*Schema{
+			"name": {
+				Type:     TypeString,
+				Required: true,
+			},
+			"computed": {
+				Type:     TypeString,
+				Computed: true,
+			},
+		},
+		CreateContext: func(_ context.Context, d *ResourceData, _ interface{}) diag.Diagnostics {
+			d.SetId("test")
+
+			if err := d.Set("computed", "computed-"+d.Get("name").(string)); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return diag.Diagnostics{
+				{
+					Severity: diag.Warning,
+					Summary:  "created",
+				},
+			}
+		},
+		ReadContext:   func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+		DeleteContext: func(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics { return nil },
+	}
+
+	server := NewGRPCProviderServerV6(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test": res,
+		},
+	})
+
+	ty := res.CoreConfigSchema().ImpliedType()
+
+	configVal := cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.NullVal(cty.String),
+		"name":     cty.StringVal("a"),
+		"computed": cty.NullVal(cty.String),
+	})
+
+	planResp, err := server.PlanResourceChange(context.Background(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "test",
+		PriorState:       &tfprotov6.DynamicValue{MsgPack: mustMsgpackMarshal(ty, cty.NullVal(ty))},
+		ProposedNewState: &tfprotov6.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+		Config:           &tfprotov6.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(planResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected plan diagnostics: %#v", planResp.Diagnostics)
+	}
+
+	plannedVal := mustMsgpackUnmarshal(ty, planResp.PlannedState.MsgPack)
+
+	expectedPlannedVal := cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.UnknownVal(cty.String),
+		"name":     cty.StringVal("a"),
+		"computed": cty.UnknownVal(cty.String),
+	})
+
+	if !plannedVal.RawEquals(expectedPlannedVal) {
+		t.Fatalf("expected planned state %#v, got %#v", expectedPlannedVal, plannedVal)
+	}
+
+	applyResp, err := server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       "test",
+		PriorState:     &tfprotov6.DynamicValue{MsgPack: mustMsgpackMarshal(ty, cty.NullVal(ty))},
+		PlannedState:   planResp.PlannedState,
+		PlannedPrivate: planResp.PlannedPrivate,
+		Config:         &tfprotov6.DynamicValue{MsgPack: mustMsgpackMarshal(ty, configVal)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDiags := []*tfprotov6.Diagnostic{
+		{
+			Severity: tfprotov6.DiagnosticSeverityWarning,
+			Summary:  "created",
+		},
+	}
+
+	if diff := cmp.Diff(expectedDiags, applyResp.Diagnostics); diff != "" {
+		t.Fatalf("unexpected apply diagnostics difference: %s", diff)
+	}
+
+	newStateVal := mustMsgpackUnmarshal(ty, applyResp.NewState.MsgPack)
+
+	expectedStateVal := cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.StringVal("test"),
+		"name":     cty.StringVal("a"),
+		"computed": cty.StringVal("computed-a"),
+	})
+
+	if !newStateVal.RawEquals(expectedStateVal) {
+		t.Fatalf("expected new state %#v, got %#v", expectedStateVal, newStateVal)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"iter"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+)
+
+// ListResourceFunc is the function used to list the remote objects of a
+// managed resource type.
+type ListResourceFunc func(context.Context, ListResourceRequest) iter.Seq[ListResourceResult]
+
+type ListResourceRequest struct {
+	// Config is used to query the configuration of the list block, as
+	// defined by the ListConfigSchema of the resource.
+	Config *ResourceData
+
+	// IncludeResource indicates whether Terraform requested the full state
+	// of each remote object. When false, only the identity and display name
+	// of each result are sent to Terraform, so implementations can skip
+	// additional API calls needed to populate the full state.
+	IncludeResource bool
+
+	// Limit is the maximum number of results Terraform expects. Results
+	// beyond the limit are discarded, but implementations should stop
+	// producing results once the limit is reached to avoid unnecessary API
+	// calls. Zero means no limit.
+	Limit int64
+
+	// Meta is the value returned by the provider configuration function,
+	// conventionally used to store API clients.
+	Meta interface{}
+
+	resource *Resource
+}
+
+// NewResourceData returns an empty ResourceData for the resource being
+// listed, to be populated with the identity and, if IncludeResource is
+// true, the state of a single result.
+func (r ListResourceRequest) NewResourceData() *ResourceData {
+	return r.resource.Data(nil)
+}
+
+type ListResourceResult struct {
+	// DisplayName is a human-readable name for the remote object, shown by
+	// Terraform when presenting the results. If empty, the ResourceData ID
+	// is used.
+	DisplayName string
+
+	// ResourceData contains the identity of the remote object and, if the
+	// request IncludeResource field is true, its state. The ID must be set
+	// when the state is included.
+	ResourceData *ResourceData
+
+	// Diagnostics report errors or warnings related to listing the remote
+	// object. A result may contain only diagnostics, for example when the
+	// remote API returned an error.
+	Diagnostics diag.Diagnostics
+}
+
+// CoreListConfigSchema lowers the ListConfigSchema of the resource to the
+// schema model expected by Terraform core. Unlike the resource schema, no
+// implicit "id" attribute is added.
+func (r *Resource) CoreListConfigSchema() *configschema.Block {
+	block := schemaMap(r.ListConfigSchema).CoreConfigSchema()
+
+	desc := r.Description
+	descKind := configschema.StringKind(DescriptionKind)
+	if desc == "" {
+		// fallback to plain text if empty
+		descKind = configschema.StringPlain
+	}
+
+	block.Description = desc
+	block.DescriptionKind = descKind
+	block.Deprecated = r.DeprecationMessage != ""
+	block.DeprecationMessage = r.DeprecationMessage
+
+	return block
+}
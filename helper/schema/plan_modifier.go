@@ -0,0 +1,257 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
+)
+
+// PlanModifierFunc is a function used to modify the planned value of an
+// attribute, after the SDK planned the resource with its Default,
+// DiffSuppressFunc, ForceNew and CustomizeDiff logic.
+//
+// The function can set the PlanValue of the response to change the planned
+// value, and RequiresReplace to require the replacement of the resource.
+// The planned value of an attribute which is not Computed must equal its
+// configuration value.
+type PlanModifierFunc func(ctx context.Context, req PlanModifierRequest, resp *PlanModifierResponse)
+
+// PlanModifierRequest is the request of a PlanModifierFunc. Attribute values
+// are of the type of the attribute in the Terraform type system, so that a
+// TypeString attribute is a cty.String value.
+type PlanModifierRequest struct {
+	// Path is the path of the attribute.
+	Path cty.Path
+
+	// PriorState is the prior state of the resource, which is null when the
+	// resource is created.
+	PriorState cty.Value
+
+	// Config is the configuration of the resource.
+	Config cty.Value
+
+	// ProposedNewState is the new state of the resource proposed by
+	// Terraform, which merges the configuration into the prior state.
+	ProposedNewState cty.Value
+
+	// PriorValue is the prior state value of the attribute.
+	PriorValue cty.Value
+
+	// ConfigValue is the configuration value of the attribute.
+	ConfigValue cty.Value
+
+	// ProposedValue is the proposed new state value of the attribute.
+	ProposedValue cty.Value
+
+	// PlanValue is the planned value of the attribute, including changes of
+	// earlier PlanModifiers of the attribute.
+	PlanValue cty.Value
+}
+
+// PlanModifierResponse is the response of a PlanModifierFunc.
+type PlanModifierResponse struct {
+	// PlanValue is the planned value of the attribute, which defaults to the
+	// PlanValue of the request.
+	PlanValue cty.Value
+
+	// RequiresReplace requires the replacement of the resource when the
+	// planned value differs from the prior state value.
+	RequiresReplace bool
+
+	// Diagnostics are returned to Terraform. Diagnostics without an
+	// AttributePath are returned with the path of the attribute.
+	Diagnostics diag.Diagnostics
+}
+
+// RequiresReplaceIfFunc is a function called by RequiresReplaceIf to decide
+// whether a change of the attribute requires the replacement of the resource.
+type RequiresReplaceIfFunc func(ctx context.Context, req PlanModifierRequest) (bool, diag.Diagnostics)
+
+// UseStateForUnknown returns a PlanModifierFunc which plans the prior state
+// value of a Computed attribute instead of an unknown value, for attributes
+// which do not change after the resource is created, such as an identifier
+// assigned by the remote API. The value is still unknown when the resource
+// is created or replaced, or when its configuration value is unknown.
+//
+// The resource must not change the value of the attribute when it is
+// updated, as Terraform requires the applied value to match the plan.
+func UseStateForUnknown() PlanModifierFunc {
+	return func(ctx context.Context, req PlanModifierRequest, resp *PlanModifierResponse) {
+		if req.PriorValue.IsNull() || req.PlanValue.IsKnown() || !req.ConfigValue.IsKnown() {
+			return
+		}
+
+		resp.PlanValue = req.PriorValue
+	}
+}
+
+// RequiresReplaceIf returns a PlanModifierFunc which requires the replacement
+// of an existing resource when the planned value of the attribute differs
+// from its prior state value and the given function returns true, such as
+// when a size decreases.
+func RequiresReplaceIf(f RequiresReplaceIfFunc) PlanModifierFunc {
+	return func(ctx context.Context, req PlanModifierRequest, resp *PlanModifierResponse) {
+		if req.PriorState.IsNull() || req.PlanValue.RawEquals(req.PriorValue) {
+			return
+		}
+
+		requiresReplace, diags := f(ctx, req)
+		resp.Diagnostics = append(resp.Diagnostics, diags...)
+		resp.RequiresReplace = requiresReplace
+	}
+}
+
+// RequiresReplaceIfConfigured returns a PlanModifierFunc which requires the
+// replacement of an existing resource when the attribute is configured and
+// its planned value differs from its prior state value. Unlike ForceNew, a
+// Computed attribute whose configuration is removed keeps its value without
+// replacing the resource.
+func RequiresReplaceIfConfigured() PlanModifierFunc {
+	return RequiresReplaceIf(func(ctx context.Context, req PlanModifierRequest) (bool, diag.Diagnostics) {
+		return !req.ConfigValue.IsNull(), nil
+	})
+}
+
+// runPlanModifiers runs the PlanModifiers of the attributes of a resource on
+// its planned state. It returns the modified planned state and the paths of
+// the attributes whose PlanModifiers require the replacement of the resource.
+func (m schemaMap) runPlanModifiers(ctx context.Context, prior, config, proposed, planned cty.Value) (cty.Value, []cty.Path, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var requiresReplace []cty.Path
+
+	result, err := cty.Transform(planned, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		schema := m.valuePathSchema(path)
+		if schema == nil || len(schema.PlanModifiers) == 0 {
+			return v, nil
+		}
+
+		req := PlanModifierRequest{
+			Path:             path.Copy(),
+			PriorState:       prior,
+			Config:           config,
+			ProposedNewState: proposed,
+			PriorValue:       valueAtPath(prior, path, v.Type()),
+			ConfigValue:      valueAtPath(config, path, v.Type()),
+			ProposedValue:    valueAtPath(proposed, path, v.Type()),
+			PlanValue:        v,
+		}
+
+		logField := map[string]interface{}{logging.KeyAttributePath: ctyPathToFlatmapPath(path)}
+
+		logging.HelperSchemaTrace(ctx, "Calling downstream", logField)
+
+		for _, f := range schema.PlanModifiers {
+			resp := &PlanModifierResponse{
+				PlanValue: req.PlanValue,
+			}
+
+			f(ctx, req, resp)
+
+			for i := range resp.Diagnostics {
+				if resp.Diagnostics[i].AttributePath == nil {
+					resp.Diagnostics[i].AttributePath = req.Path
+				}
+			}
+
+			diags = append(diags, resp.Diagnostics...)
+
+			if resp.Diagnostics.HasError() {
+				break
+			}
+
+			if resp.RequiresReplace && !resp.PlanValue.RawEquals(req.PriorValue) {
+				requiresReplace = append(requiresReplace, req.Path)
+			}
+
+			req.PlanValue = resp.PlanValue
+		}
+
+		logging.HelperSchemaTrace(ctx, "Called downstream", logField)
+
+		return req.PlanValue, nil
+	})
+	if err != nil {
+		return planned, nil, append(diags, diag.FromErr(err)...)
+	}
+
+	return result, requiresReplace, diags
+}
+
+// valuePathSchema returns the schema of the attribute or block with the
+// given path of a resource value, or nil if the path is not an attribute or
+// block, such as a collection element, or is nested within a set.
+func (m schemaMap) valuePathSchema(path cty.Path) *Schema {
+	current := m
+
+	for i := 0; i < len(path); i++ {
+		step, ok := path[i].(cty.GetAttrStep)
+		if !ok {
+			return nil
+		}
+
+		s, ok := current[step.Name]
+		if !ok {
+			return nil
+		}
+
+		if i == len(path)-1 {
+			return s
+		}
+
+		r, ok := s.Elem.(*Resource)
+		if !ok || s.Type == TypeSet {
+			return nil
+		}
+
+		// Skip the index of the list element, which is absent for nested
+		// attributes of a single object
+		if _, ok := path[i+1].(cty.IndexStep); ok {
+			i++
+		}
+
+		current = r.SchemaMap()
+	}
+
+	return nil
+}
+
+// valueAtPath returns the value with the given path within a resource
+// value, which is null if it does not exist, or unknown if an enclosing value
+// is unknown.
+func valueAtPath(val cty.Value, path cty.Path, ty cty.Type) cty.Value {
+	for _, step := range path {
+		if !val.IsKnown() {
+			return cty.UnknownVal(ty)
+		}
+
+		if val.IsNull() {
+			return cty.NullVal(ty)
+		}
+
+		next, err := step.Apply(val)
+		if err != nil {
+			return cty.NullVal(ty)
+		}
+
+		val = next
+	}
+
+	return val
+}
+
+// pathsContain returns true if the given paths contain the given path.
+func pathsContain(paths []cty.Path, path cty.Path) bool {
+	for _, p := range paths {
+		if p.Equals(path) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,296 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestSchemaMapRunPlanModifiers(t *testing.T) {
+	t.Parallel()
+
+	m := schemaMap{
+		"name": {
+			Type:          TypeString,
+			Optional:      true,
+			Computed:      true,
+			PlanModifiers: []PlanModifierFunc{RequiresReplaceIfConfigured()},
+		},
+		"arn": {
+			Type:          TypeString,
+			Computed:      true,
+			PlanModifiers: []PlanModifierFunc{UseStateForUnknown()},
+		},
+		"size": {
+			Type:     TypeInt,
+			Optional: true,
+			PlanModifiers: []PlanModifierFunc{
+				RequiresReplaceIf(func(_ context.Context, req PlanModifierRequest) (bool, diag.Diagnostics) {
+					return req.PlanValue.LessThan(req.PriorValue).True(), nil
+				}),
+			},
+		},
+		"disk": {
+			Type:     TypeList,
+			Optional: true,
+			Elem: &Resource{
+				Schema: map[string]*Schema{
+					"id": {
+						Type:          TypeString,
+						Computed:      true,
+						PlanModifiers: []PlanModifierFunc{UseStateForUnknown()},
+					},
+				},
+			},
+		},
+	}
+
+	ty := cty.Object(map[string]cty.Type{
+		"name": cty.String,
+		"arn":  cty.String,
+		"size": cty.Number,
+		"disk": cty.List(cty.Object(map[string]cty.Type{
+			"id": cty.String,
+		})),
+	})
+
+	obj := func(name, arn cty.Value, size int64, diskID cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"name": name,
+			"arn":  arn,
+			"size": cty.NumberIntVal(size),
+			"disk": cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"id": diskID,
+				}),
+			}),
+		})
+	}
+
+	prior := obj(cty.StringVal("a"), cty.StringVal("arn:a"), 10, cty.StringVal("disk-1"))
+
+	testCases := map[string]struct {
+		prior                   cty.Value
+		config                  cty.Value
+		planned                 cty.Value
+		expected                cty.Value
+		expectedRequiresReplace []cty.Path
+	}{
+		"use state for unknown": {
+			prior:    prior,
+			config:   obj(cty.StringVal("a"), cty.NullVal(cty.String), 10, cty.NullVal(cty.String)),
+			planned:  obj(cty.StringVal("a"), cty.UnknownVal(cty.String), 10, cty.UnknownVal(cty.String)),
+			expected: prior,
+		},
+		"use state for unknown on create": {
+			prior:    cty.NullVal(ty),
+			config:   obj(cty.StringVal("a"), cty.NullVal(cty.String), 10, cty.NullVal(cty.String)),
+			planned:  obj(cty.StringVal("a"), cty.UnknownVal(cty.String), 10, cty.UnknownVal(cty.String)),
+			expected: obj(cty.StringVal("a"), cty.UnknownVal(cty.String), 10, cty.UnknownVal(cty.String)),
+		},
+		"requires replace if": {
+			prior:    prior,
+			config:   obj(cty.StringVal("a"), cty.NullVal(cty.String), 5, cty.NullVal(cty.String)),
+			planned:  obj(cty.StringVal("a"), cty.StringVal("arn:a"), 5, cty.StringVal("disk-1")),
+			expected: obj(cty.StringVal("a"), cty.StringVal("arn:a"), 5, cty.StringVal("disk-1")),
+			expectedRequiresReplace: []cty.Path{
+				cty.GetAttrPath("size"),
+			},
+		},
+		"requires replace if false": {
+			prior:    prior,
+			config:   obj(cty.StringVal("a"), cty.NullVal(cty.String), 20, cty.NullVal(cty.String)),
+			planned:  obj(cty.StringVal("a"), cty.StringVal("arn:a"), 20, cty.StringVal("disk-1")),
+			expected: obj(cty.StringVal("a"), cty.StringVal("arn:a"), 20, cty.StringVal("disk-1")),
+		},
+		"requires replace if configured": {
+			prior:    prior,
+			config:   obj(cty.StringVal("b"), cty.NullVal(cty.String), 10, cty.NullVal(cty.String)),
+			planned:  obj(cty.StringVal("b"), cty.StringVal("arn:a"), 10, cty.StringVal("disk-1")),
+			expected: obj(cty.StringVal("b"), cty.StringVal("arn:a"), 10, cty.StringVal("disk-1")),
+			expectedRequiresReplace: []cty.Path{
+				cty.GetAttrPath("name"),
+			},
+		},
+		"requires replace if configured without config": {
+			prior:    prior,
+			config:   obj(cty.NullVal(cty.String), cty.NullVal(cty.String), 10, cty.NullVal(cty.String)),
+			planned:  obj(cty.UnknownVal(cty.String), cty.StringVal("arn:a"), 10, cty.StringVal("disk-1")),
+			expected: obj(cty.UnknownVal(cty.String), cty.StringVal("arn:a"), 10, cty.StringVal("disk-1")),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, requiresReplace, diags := m.runPlanModifiers(context.Background(), tc.prior, tc.config, tc.planned, tc.planned)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if !got.RawEquals(tc.expected) {
+				t.Errorf("expected %#v, got %#v", tc.expected, got)
+			}
+
+			if diff := cmp.Diff(tc.expectedRequiresReplace, requiresReplace, cmp.Comparer(func(a, b cty.Path) bool { return a.Equals(b) })); diff != "" {
+				t.Errorf("unexpected RequiresReplace difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaMapRunPlanModifiers_diagnostics(t *testing.T) {
+	t.Parallel()
+
+	var called []string
+
+	m := schemaMap{
+		"foo": {
+			Type:     TypeString,
+			Optional: true,
+			PlanModifiers: []PlanModifierFunc{
+				func(_ context.Context, _ PlanModifierRequest, resp *PlanModifierResponse) {
+					called = append(called, "first")
+					resp.Diagnostics = diag.Errorf("invalid foo")
+				},
+				func(_ context.Context, _ PlanModifierRequest, _ *PlanModifierResponse) {
+					called = append(called, "second")
+				},
+			},
+		},
+	}
+
+	val := cty.ObjectVal(map[string]cty.Value{
+		"foo": cty.StringVal("bar"),
+	})
+
+	_, _, diags := m.runPlanModifiers(context.Background(), val, val, val, val)
+
+	expected := diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       "invalid foo",
+			AttributePath: cty.GetAttrPath("foo"),
+		},
+	}
+
+	if diff := cmp.Diff(expected, diags, cmp.Comparer(func(a, b cty.Path) bool { return a.Equals(b) })); diff != "" {
+		t.Errorf("unexpected diagnostics difference: %s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"first"}, called); diff != "" {
+		t.Errorf("unexpected calls difference: %s", diff)
+	}
+}
+
+func TestPlanResourceChange_planModifiers(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{
+		Schema: map[string]*Schema{
+			"arn": {
+				Type:          TypeString,
+				Computed:      true,
+				PlanModifiers: []PlanModifierFunc{UseStateForUnknown()},
+			},
+			"size": {
+				Type:     TypeInt,
+				Optional: true,
+				PlanModifiers: []PlanModifierFunc{
+					RequiresReplaceIf(func(_ context.Context, req PlanModifierRequest) (bool, diag.Diagnostics) {
+						return req.PlanValue.LessThan(req.PriorValue).True(), nil
+					}),
+				},
+			},
+		},
+		CustomizeDiff: func(_ context.Context, d *ResourceDiff, _ interface{}) error {
+			return d.SetNewComputed("arn")
+		},
+	}
+
+	server := NewGRPCProviderServer(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test": r,
+		},
+	})
+
+	schema := r.CoreConfigSchema()
+
+	priorVal := cty.ObjectVal(map[string]cty.Value{
+		"id":   cty.StringVal("foo"),
+		"arn":  cty.StringVal("arn:foo"),
+		"size": cty.NumberIntVal(10),
+	})
+	priorState, err := msgpack.Marshal(priorVal, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proposedVal := cty.ObjectVal(map[string]cty.Value{
+		"id":   cty.StringVal("foo"),
+		"arn":  cty.StringVal("arn:foo"),
+		"size": cty.NumberIntVal(5),
+	})
+	proposedState, err := msgpack.Marshal(proposedVal, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configVal := cty.ObjectVal(map[string]cty.Value{
+		"id":   cty.NullVal(cty.String),
+		"arn":  cty.NullVal(cty.String),
+		"size": cty.NumberIntVal(5),
+	})
+	config, err := msgpack.Marshal(configVal, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName: "test",
+		PriorState: &tfprotov5.DynamicValue{
+			MsgPack: priorState,
+		},
+		ProposedNewState: &tfprotov5.DynamicValue{
+			MsgPack: proposedState,
+		},
+		Config: &tfprotov5.DynamicValue{
+			MsgPack: config,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	plannedStateVal, err := msgpack.Unmarshal(resp.PlannedState.MsgPack, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cmp.Equal(proposedVal, plannedStateVal, valueComparer) {
+		t.Fatal(cmp.Diff(proposedVal, plannedStateVal, valueComparer))
+	}
+
+	expectedRequiresReplace := []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("id"),
+		tftypes.NewAttributePath().WithAttributeName("size"),
+	}
+
+	if diff := cmp.Diff(expectedRequiresReplace, resp.RequiresReplace); diff != "" {
+		t.Fatalf("unexpected RequiresReplace difference: %s", diff)
+	}
+}
@@ -73,6 +73,22 @@ type Provider struct {
 	// and must *not* implement Create, Update or Delete.
 	DataSourcesMap map[string]*Resource
 
+	// EphemeralResourcesMap is the collection of available ephemeral
+	// resources that this provider implements, with an EphemeralResource
+	// instance defining the schema and Open, Renew and Close operations of
+	// each.
+	//
+	// Ephemeral resource data is never persisted in plan or state.
+	EphemeralResourcesMap map[string]*EphemeralResource
+
+	// ActionsMap is the collection of available actions that this provider
+	// implements, with an Action instance defining the schema and Invoke
+	// operation of each.
+	//
+	// Actions are day-2 operations, such as rotating a key or rebooting an
+	// instance, which practitioners trigger from configuration.
+	ActionsMap map[string]*Action
+
 	// ProviderMetaSchema is the schema for the configuration of the meta
 	// information for this provider. If this provider has no meta info,
 	// this can be omitted. This functionality is currently experimental
@@ -81,6 +97,14 @@ type Provider struct {
 	// Terraform team.
 	ProviderMetaSchema map[string]*Schema
 
+	// Functions is the collection of provider-defined functions that this
+	// provider implements, keyed by function name. Functions must not be nil,
+	// which InternalValidate verifies.
+	//
+	// Functions may be called before the provider is configured, so they
+	// do not have access to the provider meta value.
+	Functions map[string]*Function
+
 	// ConfigureFunc is a function for configuring the provider. If the
 	// provider doesn't need to be configured, this can be omitted.
 	//
@@ -243,6 +267,31 @@ func (p *Provider) InternalValidate() error {
 		}
 	}
 
+	for k, r := range p.EphemeralResourcesMap {
+		if err := r.InternalValidate(); err != nil {
+			validationErrors = append(validationErrors, fmt.Errorf("ephemeral resource %s: %s", k, err))
+		}
+	}
+
+	for k, a := range p.ActionsMap {
+		if err := a.InternalValidate(); err != nil {
+			validationErrors = append(validationErrors, fmt.Errorf("action %s: %s", k, err))
+			continue
+		}
+
+		for _, typeName := range a.LinkedResources {
+			if _, ok := p.ResourcesMap[typeName]; !ok {
+				validationErrors = append(validationErrors, fmt.Errorf("action %s: linked resource %s is not a managed resource of this provider", k, typeName))
+			}
+		}
+	}
+
+	for k, f := range p.Functions {
+		if err := f.InternalValidate(); err != nil {
+			validationErrors = append(validationErrors, fmt.Errorf("function %s: %s", k, err))
+		}
+	}
+
 	return errors.Join(validationErrors...)
 }
 
@@ -484,30 +533,44 @@ func (p *Provider) ImportStateWithIdentity(
 	info *terraform.InstanceInfo,
 	id string,
 	identity map[string]string) ([]*terraform.InstanceState, error) {
+	states, _, err := p.importStateWithIdentity(ctx, info, id, identity, false)
+	return states, err
+}
+
+// importStateWithIdentity functions like ImportStateWithIdentity, but also
+// returns the deferred response set by ResourceData.Defer, in which case no
+// states are returned.
+func (p *Provider) importStateWithIdentity(
+	ctx context.Context,
+	info *terraform.InstanceInfo,
+	id string,
+	identity map[string]string,
+	deferralAllowed bool) ([]*terraform.InstanceState, *Deferred, error) {
 	// Find the resource
 	r, ok := p.ResourcesMap[info.Type]
 	if !ok {
-		return nil, fmt.Errorf("unknown resource type: %s", info.Type)
+		return nil, nil, fmt.Errorf("unknown resource type: %s", info.Type)
 	}
 
 	// If it doesn't support import, error
 	if r.Importer == nil {
-		return nil, fmt.Errorf("resource %s doesn't support import", info.Type)
+		return nil, nil, fmt.Errorf("resource %s doesn't support import", info.Type)
 	}
 
 	// Create the data
 	data := r.Data(nil)
 	data.SetId(id)
 	data.SetType(info.Type)
+	data.deferralAllowed = deferralAllowed
 
 	if data.identitySchema != nil {
 		identityData, err := data.Identity()
 		if err != nil {
-			return nil, err // this should not happen, as we checked above
+			return nil, nil, err // this should not happen, as we checked above
 		}
 		identityData.raw = identity
 	} else if identity != nil {
-		return nil, fmt.Errorf("resource %s doesn't support identity import", info.Type)
+		return nil, nil, fmt.Errorf("resource %s doesn't support identity import", info.Type)
 	}
 
 	// Call the import function
@@ -524,7 +587,17 @@ func (p *Provider) ImportStateWithIdentity(
 		logging.HelperSchemaTrace(ctx, "Called downstream")
 
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+	}
+
+	if data.deferred != nil {
+		return nil, data.deferred, nil
+	}
+
+	for _, r := range results {
+		if r != nil && r.deferred != nil {
+			return nil, r.deferred, nil
 		}
 	}
 
@@ -532,14 +605,14 @@ func (p *Provider) ImportStateWithIdentity(
 	states := make([]*terraform.InstanceState, len(results))
 	for i, r := range results {
 		if r == nil {
-			return nil, fmt.Errorf("The provider returned a missing resource during ImportResourceState. " +
+			return nil, nil, fmt.Errorf("The provider returned a missing resource during ImportResourceState. " +
 				"This is generally a bug in the resource implementation for import. " +
 				"Resource import code should return an error for missing resources and skip returning a missing or empty ResourceData. " +
 				"Please report this to the provider developers.")
 		}
 
 		if r.Id() == "" {
-			return nil, fmt.Errorf("The provider returned a resource missing an identifier during ImportResourceState. " +
+			return nil, nil, fmt.Errorf("The provider returned a resource missing an identifier during ImportResourceState. " +
 				"This is generally a bug in the resource implementation for import. " +
 				"Resource import code should not call d.SetId(\"\") or create an empty ResourceData. " +
 				"If the resource is missing, instead return an error. " +
@@ -553,14 +626,14 @@ func (p *Provider) ImportStateWithIdentity(
 	// isn't obvious so we circumvent that with a friendlier error.
 	for _, s := range states {
 		if s == nil {
-			return nil, fmt.Errorf("The provider returned a missing resource during ImportResourceState. " +
+			return nil, nil, fmt.Errorf("The provider returned a missing resource during ImportResourceState. " +
 				"This is generally a bug in the resource implementation for import. " +
 				"Resource import code should return an error for missing resources. " +
 				"Please report this to the provider developers.")
 		}
 	}
 
-	return states, nil
+	return states, nil, nil
 }
 
 // ValidateDataSource is called once at the beginning with the raw
@@ -610,6 +683,20 @@ func (p *Provider) DataSources() []terraform.DataSource {
 	return result
 }
 
+// listResourceTypes returns the sorted names of the resource types which
+// implement List.
+func (p *Provider) listResourceTypes() []string {
+	keys := make([]string, 0)
+	for k, r := range p.ResourcesMap {
+		if r != nil && r.List != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
 // UserAgent returns a string suitable for use in the User-Agent header of
 // requests generated by the provider. The generated string contains the
 // version of Terraform, the Plugin SDK, and the provider used to generate the
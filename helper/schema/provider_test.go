@@ -2634,6 +2634,131 @@ func TestProvider_InternalValidate(t *testing.T) {
 			},
 			ExpectedErr: nil,
 		},
+		"Action with unknown linked resource returns an error": {
+			P: &Provider{
+				ActionsMap: map[string]*Action{
+					"action-foo": {
+						Schema: map[string]*Schema{
+							"foo": {
+								Type:     TypeString,
+								Optional: true,
+							},
+						},
+						LinkedResources: []string{"resource-foo"},
+						Invoke:          func(ctx context.Context, req InvokeActionRequest, resp *InvokeActionResponse) {},
+					},
+				},
+			},
+			ExpectedErr: fmt.Errorf("action action-foo: linked resource resource-foo is not a managed resource of this provider"),
+		},
+		"Action missing Invoke returns an error": {
+			P: &Provider{
+				ActionsMap: map[string]*Action{
+					"action-foo": {
+						Schema: map[string]*Schema{},
+					},
+				},
+			},
+			ExpectedErr: fmt.Errorf("action action-foo: Invoke must be implemented"),
+		},
+		"Ephemeral resource missing Open returns an error": {
+			P: &Provider{
+				EphemeralResourcesMap: map[string]*EphemeralResource{
+					"ephemeral-foo": {
+						Schema: map[string]*Schema{
+							"foo": {
+								Type:     TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+			ExpectedErr: fmt.Errorf("ephemeral resource ephemeral-foo: Open must be implemented"),
+		},
+		"Ephemeral resource with write-only attribute returns an error": {
+			P: &Provider{
+				EphemeralResourcesMap: map[string]*EphemeralResource{
+					"ephemeral-foo": {
+						Schema: map[string]*Schema{
+							"foo": {
+								Type:      TypeString,
+								Optional:  true,
+								WriteOnly: true,
+							},
+						},
+						Open: func(ctx context.Context, req OpenEphemeralResourceRequest, resp *OpenEphemeralResourceResponse) {},
+					},
+				},
+			},
+			ExpectedErr: fmt.Errorf("ephemeral resource ephemeral-foo: cannot contain write-only attributes"),
+		},
+		"Function returns no errors": {
+			P: &Provider{
+				Functions: map[string]*Function{
+					"echo": {
+						Parameters: []*FunctionParameter{
+							{
+								Name: "input",
+								Type: TypeString,
+							},
+						},
+						Return: &FunctionReturn{
+							Type: TypeString,
+						},
+						Run: func(ctx context.Context, args []interface{}) (interface{}, error) {
+							return args[0], nil
+						},
+					},
+				},
+			},
+			ExpectedErr: nil,
+		},
+		"Function nil returns an error": {
+			P: &Provider{
+				Functions: map[string]*Function{
+					"echo": nil,
+				},
+			},
+			ExpectedErr: fmt.Errorf("function echo: function is nil"),
+		},
+		"Function missing Run returns an error": {
+			P: &Provider{
+				Functions: map[string]*Function{
+					"echo": {
+						Return: &FunctionReturn{
+							Type: TypeString,
+						},
+					},
+				},
+			},
+			ExpectedErr: fmt.Errorf("function echo: Run must be set"),
+		},
+		"Function with duplicate parameter names returns an error": {
+			P: &Provider{
+				Functions: map[string]*Function{
+					"echo": {
+						Parameters: []*FunctionParameter{
+							{
+								Name: "input",
+								Type: TypeString,
+							},
+						},
+						VariadicParameter: &FunctionParameter{
+							Name: "input",
+							Type: TypeString,
+						},
+						Return: &FunctionReturn{
+							Type: TypeString,
+						},
+						Run: func(ctx context.Context, args []interface{}) (interface{}, error) {
+							return args[0], nil
+						},
+					},
+				},
+			},
+			ExpectedErr: fmt.Errorf("function echo: parameter 1: duplicate name \"input\""),
+		},
 	}
 
 	for name, tc := range cases {
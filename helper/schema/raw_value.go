@@ -0,0 +1,134 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/hcl2shim"
+)
+
+// The functions in this file implement the null-aware getters of ResourceData
+// and ResourceDiff, such as GetStringPtr, which read values from the raw
+// values Terraform sent the SDK rather than from the legacy field readers, so
+// null values can be told apart from zero values.
+
+// rawValueForPhase returns the first of the given raw values which is not
+// null. The plan is only sent while planning and applying a create or update,
+// the state is also sent while reading and deleting, and the configuration is
+// the only value sent while reading data sources.
+func rawValueForPhase(plan, state, config cty.Value) cty.Value {
+	for _, val := range []cty.Value{plan, state, config} {
+		if !val.IsNull() {
+			return val
+		}
+	}
+
+	return cty.NilVal
+}
+
+// rawValueAtKey returns the value at the given key, as used with Get, within
+// the given raw value. cty.NilVal is returned if there is no value at the key,
+// such as when it is within a set, whose elements cannot be addressed.
+func rawValueAtKey(val cty.Value, key string) cty.Value {
+	for _, part := range strings.Split(key, ".") {
+		if val.IsNull() || !val.IsKnown() {
+			return cty.NilVal
+		}
+
+		ty := val.Type()
+
+		switch {
+		case ty.IsObjectType():
+			if ty.HasAttribute(part) {
+				val = val.GetAttr(part)
+				continue
+			}
+
+			// Single nested attributes are objects in raw values, but lists
+			// of one object when using Get.
+			if part != "0" {
+				return cty.NilVal
+			}
+		case ty.IsListType() || ty.IsTupleType():
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= val.LengthInt() {
+				return cty.NilVal
+			}
+
+			val = val.Index(cty.NumberIntVal(int64(i)))
+		case ty.IsMapType():
+			k := cty.StringVal(part)
+			if !val.HasIndex(k).True() {
+				return cty.NilVal
+			}
+
+			val = val.Index(k)
+		default:
+			return cty.NilVal
+		}
+	}
+
+	return val
+}
+
+func rawStringPtr(val cty.Value) *string {
+	if val.IsNull() || !val.IsKnown() || !val.Type().Equals(cty.String) {
+		return nil
+	}
+
+	s := val.AsString()
+
+	return &s
+}
+
+func rawInt64Ptr(val cty.Value) *int64 {
+	if val.IsNull() || !val.IsKnown() || !val.Type().Equals(cty.Number) {
+		return nil
+	}
+
+	i, _ := val.AsBigFloat().Int64()
+
+	return &i
+}
+
+func rawBoolPtr(val cty.Value) *bool {
+	if val.IsNull() || !val.IsKnown() || !val.Type().Equals(cty.Bool) {
+		return nil
+	}
+
+	b := val.True()
+
+	return &b
+}
+
+func rawListPtr(val cty.Value) *[]interface{} {
+	if val.IsNull() || !val.IsWhollyKnown() {
+		return nil
+	}
+
+	ty := val.Type()
+
+	var l []interface{}
+
+	switch {
+	case ty.IsObjectType():
+		// Single nested attributes are lists of one object when using Get.
+		l = []interface{}{hcl2shim.ConfigValueFromHCL2(val)}
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		l = make([]interface{}, 0, val.LengthInt())
+
+		for it := val.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			l = append(l, hcl2shim.ConfigValueFromHCL2(ev))
+		}
+	default:
+		return nil
+	}
+
+	return &l
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/hashicorp/go-cty/cty"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/hcl2shim"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/diagutils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -127,6 +129,18 @@ type Resource struct {
 	// MigrateState.
 	StateUpgraders []StateUpgrader
 
+	// StateMovers contains the functions responsible for moving the state of
+	// a resource of another type, potentially from another provider, into
+	// this resource type. It is called by Terraform when a practitioner
+	// configures a moved block whose source is a different resource type,
+	// such as when a resource type is renamed. This field is only valid when
+	// the Resource is a managed resource.
+	//
+	// Each StateMover declares the source provider address, resource type
+	// and schema version it can handle. Terraform requests are matched
+	// against each StateMover in order and the first match is used.
+	StateMovers []StateMover
+
 	// Create is called when the provider must create a new instance of a
 	// managed resource. This field is only valid when the Resource is a
 	// managed resource. Only one of Create, CreateContext, or
@@ -552,6 +566,16 @@ type Resource struct {
 	// diagnostic when passed back to Terraform.
 	CustomizeDiff CustomizeDiffFunc
 
+	// CustomizeDiffWithDiagnostics functions like CustomizeDiff, except that
+	// it returns diagnostics instead of an error, so that it can return
+	// warnings, multiple errors and errors for specific attributes with an
+	// AttributePath. The plan is aborted if the diagnostics contain an error.
+	// CustomizeDiffWithDiagnostics cannot be set with CustomizeDiff.
+	//
+	// The diag.Diagnostics return parameter, if not nil, can contain any
+	// combination and multiple of warning and/or error diagnostics.
+	CustomizeDiffWithDiagnostics CustomizeDiffWithDiagnosticsFunc
+
 	// Importer is called when the provider must import an instance of a
 	// managed resource. This field is only valid when the Resource is a
 	// managed resource.
@@ -663,6 +687,25 @@ type Resource struct {
 	// Developers should prefer other validation methods first as this validation function
 	// deals with raw cty values.
 	ValidateRawResourceConfigFuncs []ValidateRawResourceConfigFunc
+
+	// ListConfigSchema is the structure and type information for the
+	// configuration of list blocks of this resource type, such as filters
+	// used to narrow the results. This field is optional and is only valid
+	// when List is set.
+	ListConfigSchema map[string]*Schema
+
+	// List is called when Terraform evaluates a list block of this resource
+	// type, typically in response to a `terraform query` command, to
+	// enumerate existing remote objects for bulk import.
+	//
+	// The function returns an iterator of results, each containing a
+	// ResourceData with the identity and, if requested, the full state of a
+	// remote object. Use the NewResourceData method of the request to
+	// create the ResourceData for each result.
+	//
+	// This field is optional and is only valid when the Resource is a
+	// managed resource with an Identity.
+	List ListResourceFunc
 }
 
 // ResourceBehavior controls SDK-specific logic when interacting
@@ -729,6 +772,11 @@ func (r *Resource) SchemaMap() map[string]*Schema {
 func (r *Resource) ShimInstanceStateFromValue(state cty.Value) (*terraform.InstanceState, error) {
 	// Get the raw shimmed value. While this is correct, the set hashes don't
 	// match those from the Schema.
+	state, err := hcl2shim.LegacyValueFromHCL2Block(state, r.CoreConfigSchema())
+	if err != nil {
+		return nil, err
+	}
+
 	s := terraform.NewInstanceStateShimmedFromValue(state, r.SchemaVersion)
 
 	// We now rebuild the state through the ResourceData, so that the set indexes
@@ -829,9 +877,86 @@ type StateUpgrader struct {
 // align to the typing mentioned above.
 type StateUpgradeFunc func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error)
 
+// StateMover moves the state of a single source resource type and schema
+// version into the Resource which declares it.
+type StateMover struct {
+	// SourceProviderAddress is the fully qualified address of the provider
+	// of the source resource, such as
+	// "registry.terraform.io/hashicorp/example". If empty, the source
+	// resource may belong to any provider.
+	SourceProviderAddress string
+
+	// SourceTypeName is the resource type of the source resource, such as
+	// "example_instance_v1". This field is required.
+	SourceTypeName string
+
+	// SourceSchemaVersion is the schema version of the source resource state
+	// that this StateMover handles.
+	SourceSchemaVersion int64
+
+	// Move converts the source resource state and identity into the state of
+	// the target resource. This field is required.
+	Move StateMoveFunc
+}
+
+// StateMoveFunc is the function used to move the state of a source resource
+// into the target resource.
+type StateMoveFunc func(context.Context, MoveStateRequest, *MoveStateResponse)
+
+type MoveStateRequest struct {
+	// SourceProviderAddress is the fully qualified address of the provider
+	// of the source resource.
+	SourceProviderAddress string
+
+	// SourceTypeName is the resource type of the source resource.
+	SourceTypeName string
+
+	// SourceSchemaVersion is the schema version of the source resource
+	// state.
+	SourceSchemaVersion int64
+
+	// SourceState is the raw state of the source resource, decoded into the
+	// default JSON types using a map[string]interface{}. As the source
+	// resource may belong to another provider, no schema information is
+	// available to convert the values into the types returned by the
+	// ResourceData Get* methods.
+	SourceState map[string]interface{}
+
+	// SourceIdentity is the raw identity of the source resource, decoded
+	// into the default JSON types. It is nil if the source resource has no
+	// identity.
+	SourceIdentity map[string]interface{}
+
+	// Meta is the value returned by the provider configuration function,
+	// conventionally used to store API clients.
+	Meta interface{}
+
+	resource *Resource
+}
+
+// NewResourceData returns an empty ResourceData for the target resource, to
+// be populated from the source state and returned in the MoveStateResponse.
+func (r MoveStateRequest) NewResourceData() *ResourceData {
+	return r.resource.Data(nil)
+}
+
+type MoveStateResponse struct {
+	// ResourceData is the state of the target resource, typically created
+	// with the NewResourceData method of the request. The ID must be set.
+	// If the target resource has an Identity, it must be set as well.
+	ResourceData *ResourceData
+
+	// Diagnostics report errors or warnings related to moving the resource
+	// state.
+	Diagnostics diag.Diagnostics
+}
+
 // See Resource documentation.
 type CustomizeDiffFunc func(context.Context, *ResourceDiff, interface{}) error
 
+// See Resource documentation.
+type CustomizeDiffWithDiagnosticsFunc func(context.Context, *ResourceDiff, interface{}) diag.Diagnostics
+
 func (r *Resource) create(ctx context.Context, d *ResourceData, meta interface{}) diag.Diagnostics {
 	if r.Create != nil {
 		if err := r.Create(d, meta); err != nil {
@@ -1008,7 +1133,7 @@ func (r *Resource) Diff(
 		return nil, fmt.Errorf("[ERR] Error decoding timeout: %s", err)
 	}
 
-	instanceDiff, err := schemaMap(r.SchemaMap()).Diff(ctx, s, c, r.CustomizeDiff, meta, true)
+	instanceDiff, err := schemaMap(r.SchemaMap()).Diff(ctx, s, c, r.customizeDiffFunc(nil), meta, true)
 	if err != nil {
 		return instanceDiff, err
 	}
@@ -1029,9 +1154,20 @@ func (r *Resource) SimpleDiff(
 	s *terraform.InstanceState,
 	c *terraform.ResourceConfig,
 	meta interface{}) (*terraform.InstanceDiff, error) {
+	return r.simpleDiff(ctx, s, c, meta, nil)
+}
+
+// simpleDiff functions like SimpleDiff, but also records the diagnostics and
+// deferred response of the CustomizeDiff call in run, if not nil.
+func (r *Resource) simpleDiff(
+	ctx context.Context,
+	s *terraform.InstanceState,
+	c *terraform.ResourceConfig,
+	meta interface{},
+	run *customizeDiffRun) (*terraform.InstanceDiff, error) {
 
 	// TODO: figure out if it makes sense to be able to set identity in CustomizeDiff at all
-	instanceDiff, err := schemaMapWithIdentity{r.SchemaMap(), r.Identity.SchemaMap()}.Diff(ctx, s, c, r.CustomizeDiff, meta, false)
+	instanceDiff, err := schemaMapWithIdentity{r.SchemaMap(), r.Identity.SchemaMap()}.Diff(ctx, s, c, r.customizeDiffFunc(run), meta, false)
 	if err != nil {
 		return instanceDiff, err
 	}
@@ -1055,6 +1191,60 @@ func (r *Resource) SimpleDiff(
 	return instanceDiff, nil
 }
 
+// customizeDiffRun holds the inputs and results of the CustomizeDiff or
+// CustomizeDiffWithDiagnostics call of a diff, which InstanceDiff cannot
+// hold.
+type customizeDiffRun struct {
+	// deferralAllowed is returned by ResourceDiff.DeferralAllowed.
+	deferralAllowed bool
+
+	// diagnostics are the diagnostics returned by
+	// CustomizeDiffWithDiagnostics.
+	diagnostics diag.Diagnostics
+
+	// deferred is the deferred response set by ResourceDiff.Defer.
+	deferred *Deferred
+}
+
+// customizeDiffFunc returns the CustomizeDiff of the resource or, if
+// CustomizeDiffWithDiagnostics is set instead, a CustomizeDiffFunc which calls
+// it and returns its error diagnostics as an error, which aborts the diff.
+// If run is not nil, the function also records the diagnostics and deferred
+// response in it.
+func (r *Resource) customizeDiffFunc(run *customizeDiffRun) CustomizeDiffFunc {
+	if r.CustomizeDiffWithDiagnostics == nil && (r.CustomizeDiff == nil || run == nil) {
+		return r.CustomizeDiff
+	}
+
+	return func(ctx context.Context, d *ResourceDiff, meta interface{}) error {
+		if run != nil {
+			d.deferralAllowed = run.deferralAllowed
+		}
+
+		var err error
+
+		if r.CustomizeDiffWithDiagnostics != nil {
+			diags := r.CustomizeDiffWithDiagnostics(ctx, d, meta)
+
+			if run != nil {
+				run.diagnostics = append(run.diagnostics, diags...)
+			}
+
+			if diags.HasError() {
+				err = diagutils.ErrorDiags(diags)
+			}
+		} else {
+			err = r.CustomizeDiff(ctx, d, meta)
+		}
+
+		if run != nil && d.deferred != nil {
+			run.deferred = d.deferred
+		}
+
+		return err
+	}
+}
+
 // Validate validates the resource configuration against the schema.
 func (r *Resource) Validate(c *terraform.ResourceConfig) diag.Diagnostics {
 	diags := schemaMap(r.SchemaMap()).Validate(c)
@@ -1077,12 +1267,25 @@ func (r *Resource) ReadDataApply(
 	d *terraform.InstanceDiff,
 	meta interface{},
 ) (*terraform.InstanceState, diag.Diagnostics) {
+	state, _, diags := r.readDataApply(ctx, d, meta, false)
+	return state, diags
+}
+
+// readDataApply functions like ReadDataApply, but also returns the deferred
+// response set by ResourceData.Defer.
+func (r *Resource) readDataApply(
+	ctx context.Context,
+	d *terraform.InstanceDiff,
+	meta interface{},
+	deferralAllowed bool,
+) (*terraform.InstanceState, *Deferred, diag.Diagnostics) {
 	// Data sources are always built completely from scratch
 	// on each read, so the source state is always nil.
 	data, err := schemaMap(r.SchemaMap()).Data(nil, d)
 	if err != nil {
-		return nil, diag.FromErr(err)
+		return nil, nil, diag.FromErr(err)
 	}
+	data.deferralAllowed = deferralAllowed
 
 	logging.HelperSchemaTrace(ctx, "Calling downstream")
 	diags := r.read(ctx, data, meta)
@@ -1097,7 +1300,7 @@ func (r *Resource) ReadDataApply(
 		state.ID = "-"
 	}
 
-	return r.recordCurrentSchemaVersion(state), diags
+	return r.recordCurrentSchemaVersion(state), data.deferred, diags
 }
 
 // RefreshWithoutUpgrade reads the instance state, but does not call
@@ -1108,9 +1311,20 @@ func (r *Resource) RefreshWithoutUpgrade(
 	ctx context.Context,
 	s *terraform.InstanceState,
 	meta interface{}) (*terraform.InstanceState, diag.Diagnostics) {
+	state, _, diags := r.refreshWithoutUpgrade(ctx, s, meta, false)
+	return state, diags
+}
+
+// refreshWithoutUpgrade functions like RefreshWithoutUpgrade, but also
+// returns the deferred response set by ResourceData.Defer.
+func (r *Resource) refreshWithoutUpgrade(
+	ctx context.Context,
+	s *terraform.InstanceState,
+	meta interface{},
+	deferralAllowed bool) (*terraform.InstanceState, *Deferred, diag.Diagnostics) {
 	// If the ID is already somehow blank, it doesn't exist
 	if s.ID == "" {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	rt := ResourceTimeout{}
@@ -1127,7 +1341,7 @@ func (r *Resource) RefreshWithoutUpgrade(
 		// affect our Read later.
 		data, err := schema.Data(s, nil)
 		if err != nil {
-			return s, diag.FromErr(err)
+			return s, nil, diag.FromErr(err)
 		}
 		data.timeouts = &rt
 
@@ -1140,19 +1354,20 @@ func (r *Resource) RefreshWithoutUpgrade(
 		logging.HelperSchemaTrace(ctx, "Called downstream")
 
 		if err != nil {
-			return s, diag.FromErr(err)
+			return s, nil, diag.FromErr(err)
 		}
 
 		if !exists {
-			return nil, nil
+			return nil, nil, nil
 		}
 	}
 
 	data, err := schema.Data(s, nil)
 	if err != nil {
-		return s, diag.FromErr(err)
+		return s, nil, diag.FromErr(err)
 	}
 	data.timeouts = &rt
+	data.deferralAllowed = deferralAllowed
 
 	if s != nil {
 		data.providerMeta = s.ProviderMeta
@@ -1168,7 +1383,8 @@ func (r *Resource) RefreshWithoutUpgrade(
 	}
 
 	schema.handleDiffSuppressOnRefresh(ctx, s, state)
-	return r.recordCurrentSchemaVersion(state), diags
+	diags = append(diags, schema.handleSemanticEqualsOnRefresh(ctx, s, state)...)
+	return r.recordCurrentSchemaVersion(state), data.deferred, diags
 }
 
 func (r *Resource) createFuncSet() bool {
@@ -1208,7 +1424,7 @@ func (r *Resource) InternalValidate(topSchemaMap schemaMap, writable bool) error
 		}
 
 		// CustomizeDiff cannot be defined for read-only resources
-		if r.CustomizeDiff != nil {
+		if r.CustomizeDiff != nil || r.CustomizeDiffWithDiagnostics != nil {
 			return fmt.Errorf("cannot implement CustomizeDiff")
 		}
 	}
@@ -1300,6 +1516,16 @@ func (r *Resource) InternalValidate(topSchemaMap schemaMap, writable bool) error
 		return fmt.Errorf("missing StateUpgrader between %d and %d", lastVersion, r.SchemaVersion)
 	}
 
+	for i, m := range r.StateMovers {
+		if m.SourceTypeName == "" {
+			return fmt.Errorf("StateMover %d missing SourceTypeName", i)
+		}
+
+		if m.Move == nil {
+			return fmt.Errorf("StateMover %d missing StateMoveFunc", i)
+		}
+	}
+
 	// Data source
 	if r.isTopLevel() && !writable {
 		tsm = schema
@@ -1310,10 +1536,31 @@ func (r *Resource) InternalValidate(topSchemaMap schemaMap, writable bool) error
 		}
 	}
 
+	if r.List != nil {
+		if !writable {
+			return fmt.Errorf("List is only valid for managed resources")
+		}
+
+		if r.Identity == nil {
+			return fmt.Errorf("List requires Identity to be set")
+		}
+
+		listConfigSchema := schemaMap(r.ListConfigSchema)
+		if err := listConfigSchema.InternalValidate(listConfigSchema); err != nil {
+			return fmt.Errorf("ListConfigSchema: %w", err)
+		}
+	} else if r.ListConfigSchema != nil {
+		return fmt.Errorf("ListConfigSchema requires List to be set")
+	}
+
 	if r.SchemaFunc != nil && r.Schema != nil {
 		return fmt.Errorf("SchemaFunc and Schema should not both be set")
 	}
 
+	if r.CustomizeDiff != nil && r.CustomizeDiffWithDiagnostics != nil {
+		return fmt.Errorf("CustomizeDiff and CustomizeDiffWithDiagnostics should not both be set")
+	}
+
 	// check context funcs are not set alongside their nonctx counterparts
 	if r.CreateContext != nil && r.Create != nil {
 		return fmt.Errorf("CreateContext and Create should not both be set")
@@ -1571,6 +1818,11 @@ func (r *ResourceIdentity) InternalIdentityValidate() error {
 				" between config and state representation. "+
 				"There is no config for resource identity, nothing to compare.", k)
 		}
+		if v.SemanticEquals != nil {
+			return fmt.Errorf("%s: SemanticEquals is for comparing configuration"+
+				" and state values. There is no config for resource identity,"+
+				" nothing to compare.", k)
+		}
 		if len(v.ExactlyOneOf) > 0 {
 			return fmt.Errorf("%s: ExactlyOneOf is for configurable attributes,"+
 				"there's nothing to configure for resource identity", k)
@@ -41,6 +41,8 @@ type ResourceData struct {
 	timeouts       *ResourceTimeout
 	providerMeta   cty.Value
 
+	deferralAllowed bool
+
 	// Don't set
 	multiReader *MultiLevelFieldReader
 	setWriter   *MapFieldWriter
@@ -49,6 +51,7 @@ type ResourceData struct {
 	partial     bool
 	once        sync.Once
 	isNew       bool
+	deferred    *Deferred
 
 	panicOnError bool
 }
@@ -154,7 +157,7 @@ func (d *ResourceData) TfTypeResourceState() (*tftypes.Value, error) {
 	// Although we handle adding/omitting timeouts to the schema depending on how it's been defined on the resource
 	// we don't process or convert the timeout values since they reside in Meta and aren't needed for the purposes
 	// of this function and in the context of a List.
-	stateVal, err := hcl2shim.HCL2ValueFromFlatmap(state.Attributes, s.ImpliedType())
+	stateVal, err := hcl2shim.HCL2ValueFromFlatmapBlock(state.Attributes, s)
 	if err != nil {
 		return nil, fmt.Errorf("converting resource state flatmap to cty value: %+v", err)
 	}
@@ -221,6 +224,20 @@ func (d *ResourceData) GetOkExists(key string) (interface{}, bool) {
 	return r.Value, exists
 }
 
+// GetDynamic returns the value of the TypeDynamic attribute with the given
+// key, decoded into a cty.Value with its original type. A null value is
+// returned if the attribute is not set.
+func (d *ResourceData) GetDynamic(key string) (cty.Value, error) {
+	s, ok := d.schema[key]
+	if !ok || s.Type != TypeDynamic {
+		return cty.NilVal, fmt.Errorf("%s: attribute is not TypeDynamic", key)
+	}
+
+	raw, _ := d.Get(key).(string)
+
+	return hcl2shim.DecodeDynamicValue(raw)
+}
+
 func (d *ResourceData) getRaw(key string, level getSource) getResult {
 	var parts []string
 	if key != "" {
@@ -516,37 +533,52 @@ func (d *ResourceData) State() *terraform.InstanceState {
 
 	// If the ResourceData has an identitySchema:
 	// copy over identity data (by getting it so we also include changes)
-	// In order to build the final state attributes, we read the full
-	// attribute set as a map[string]interface{}, write it to a MapFieldWriter,
-	// and then use that map.
 	if d.identitySchema != nil {
-		rawMapIdentity := make(map[string]interface{})
-		identityData, err := d.Identity()
-		// This error shouldn't happen, as we check for the identity schema first
-		if err == nil {
-			for k := range d.identitySchema {
-				raw := identityData.get([]string{k})
-				if raw.Exists {
-					rawMapIdentity[k] = raw.Value
-					if raw.ValueProcessed != nil {
-						rawMapIdentity[k] = raw.ValueProcessed
-					}
-				}
-			}
-
-			mapWIdentity := &MapFieldWriter{Schema: d.identitySchema}
-			if err := mapWIdentity.WriteField(nil, rawMapIdentity); err != nil {
-				log.Printf("[ERR] Error writing identity fields: %s", err)
-				return nil
-			}
+		identity, err := d.identityState()
+		if err != nil {
+			log.Printf("[ERR] Error writing identity fields: %s", err)
+			return nil
+		}
 
-			result.Identity = mapWIdentity.Map()
+		if identity != nil {
+			result.Identity = identity
 		}
 	}
 
 	return &result
 }
 
+// identityState returns the flatmap representation of the identity data,
+// including any changes. In order to build the flatmap, we read the full
+// identity as a map[string]interface{}, write it to a MapFieldWriter, and
+// then use that map.
+func (d *ResourceData) identityState() (map[string]string, error) {
+	identityData, err := d.Identity()
+	// This error shouldn't happen, as callers check for the identity schema
+	// first
+	if err != nil {
+		return nil, nil
+	}
+
+	rawMapIdentity := make(map[string]interface{})
+	for k := range d.identitySchema {
+		raw := identityData.get([]string{k})
+		if raw.Exists {
+			rawMapIdentity[k] = raw.Value
+			if raw.ValueProcessed != nil {
+				rawMapIdentity[k] = raw.ValueProcessed
+			}
+		}
+	}
+
+	mapWIdentity := &MapFieldWriter{Schema: d.identitySchema}
+	if err := mapWIdentity.WriteField(nil, rawMapIdentity); err != nil {
+		return nil, err
+	}
+
+	return mapWIdentity.Map(), nil
+}
+
 // Timeout returns the data for the given timeout key
 // Returns a duration of 20 minutes for any key not found, or not found and no default.
 func (d *ResourceData) Timeout(key string) time.Duration {
@@ -837,6 +869,43 @@ func (d *ResourceData) GetRawPlan() cty.Value {
 	return cty.NullVal(schemaMap(d.schema).CoreConfigSchema().ImpliedType())
 }
 
+// GetStringPtr returns the value of the TypeString attribute with the given
+// key, or nil if the value is null or not yet known. Unlike GetOk, an empty
+// string is returned if it is the value of the attribute.
+//
+// The value is read from the raw plan while creating or updating a resource,
+// from the raw state while reading or deleting a resource, and otherwise from
+// the raw configuration, so values written with Set are not returned. Keys
+// within sets are not supported.
+func (d *ResourceData) GetStringPtr(key string) *string {
+	return rawStringPtr(d.getRawValueAtKey(key))
+}
+
+// GetInt64Ptr returns the value of the TypeInt attribute with the given key,
+// or nil if the value is null or not yet known. Values are read in the same
+// way as GetStringPtr.
+func (d *ResourceData) GetInt64Ptr(key string) *int64 {
+	return rawInt64Ptr(d.getRawValueAtKey(key))
+}
+
+// GetBoolPtr returns the value of the TypeBool attribute with the given key,
+// or nil if the value is null or not yet known. Values are read in the same
+// way as GetStringPtr.
+func (d *ResourceData) GetBoolPtr(key string) *bool {
+	return rawBoolPtr(d.getRawValueAtKey(key))
+}
+
+// GetListPtr returns the elements of the TypeList or TypeSet attribute with
+// the given key, in the same form as Get, or nil if the value is null or not
+// wholly known. Values are read in the same way as GetStringPtr.
+func (d *ResourceData) GetListPtr(key string) *[]interface{} {
+	return rawListPtr(d.getRawValueAtKey(key))
+}
+
+func (d *ResourceData) getRawValueAtKey(key string) cty.Value {
+	return rawValueAtKey(rawValueForPhase(d.GetRawPlan(), d.GetRawState(), d.GetRawConfig()), key)
+}
+
 // IdentityData is only available for managed resources, data sources
 // will return an error. // TODO: return error in case of data sources
 func (d *ResourceData) Identity() (*IdentityData, error) {
@@ -865,3 +934,27 @@ func (d *ResourceData) Identity() (*IdentityData, error) {
 
 	return d.newIdentity, nil
 }
+
+// Defer indicates to Terraform that reading or importing the resource or data
+// source cannot be completed yet and should be deferred, with the given
+// reason, such as DeferredReasonAbsentPrereq when a resource it depends on
+// does not exist yet. Defer is only honored in Read and Importer functions,
+// and is only allowed when DeferralAllowed returns true, otherwise the
+// operation fails with an error.
+//
+// NOTE: This functionality is related to deferred action support, which is currently experimental and is subject
+// to change or break without warning. It is not protected by version compatibility guarantees.
+func (d *ResourceData) Defer(reason DeferredReason) {
+	d.deferred = &Deferred{
+		Reason: reason,
+	}
+}
+
+// DeferralAllowed returns true if the Terraform request indicated support for
+// deferred actions, so that Defer can be called.
+//
+// NOTE: This functionality is related to deferred action support, which is currently experimental and is subject
+// to change or break without warning. It is not protected by version compatibility guarantees.
+func (d *ResourceData) DeferralAllowed() bool {
+	return d.deferralAllowed
+}
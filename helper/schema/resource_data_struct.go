@@ -0,0 +1,522 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/hcl2shim"
+)
+
+// structTagKey is the struct field tag containing the name of the schema
+// attribute which the field is decoded from and encoded to by GetInto and
+// SetFrom.
+const structTagKey = "tfsdk"
+
+var ctyValueType = reflect.TypeOf(cty.Value{})
+
+// GetInto decodes the values of all attributes of the schema into the struct
+// pointed to by dst, as an alternative to calling Get for each attribute and
+// asserting the type of its value.
+//
+// Each exported field of the struct must have a tfsdk tag containing the name
+// of an attribute, such as `tfsdk:"name"`, and each attribute of the schema
+// must have a field. Fields tagged with `tfsdk:"-"` are ignored. Field types
+// must match attribute types:
+//
+//   - TypeBool: bool
+//   - TypeInt: any integer type, such as int or int64
+//   - TypeFloat: float32 or float64
+//   - TypeString: string
+//   - TypeDynamic: cty.Value
+//   - TypeList and TypeSet: a slice of the Elem type, or of structs following
+//     the same rules if Elem is *Resource
+//   - TypeMap: a map with string keys and values of the Elem type, which
+//     defaults to string
+//
+// Any field may be a pointer to these types, in which case it is set to nil
+// if the attribute is not set or its value is not yet known, rather than to
+// the zero value. As with GetOkExists, whether nested attributes are set
+// cannot always be determined.
+//
+// Diagnostics are returned if the struct does not match the schema, with
+// the path of the mismatched attribute.
+func (d *ResourceData) GetInto(dst interface{}) diag.Diagnostics {
+	v := reflect.ValueOf(dst)
+
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return diag.Errorf("GetInto requires a non-nil pointer to a struct, got %T", dst)
+	}
+
+	fields, diags := structFields(v.Elem().Type(), d.schema, nil)
+	if diags.HasError() {
+		return diags
+	}
+
+	return d.decodeObject(nil, d.schema, fields, v.Elem(), nil)
+}
+
+// SetFrom sets the values of all attributes of the schema from the fields of
+// the given struct, or pointer to a struct, as an alternative to calling Set
+// for each attribute. The struct must follow the same rules as for GetInto.
+// Nil pointers and collections are passed to Set as nil.
+//
+// Diagnostics are returned if the struct does not match the schema, or a
+// value cannot be set, with the path of the attribute.
+func (d *ResourceData) SetFrom(src interface{}) diag.Diagnostics {
+	v := reflect.ValueOf(src)
+
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return diag.Errorf("SetFrom requires a struct or non-nil pointer to a struct, got %T", src)
+	}
+
+	fields, diags := structFields(v.Type(), d.schema, nil)
+	if diags.HasError() {
+		return diags
+	}
+
+	for _, k := range sortedMapKeys(fields) {
+		path := cty.GetAttrPath(k)
+
+		raw, valDiags := encodeStructValue(v.Field(fields[k]), d.schema[k], path)
+		diags = append(diags, valDiags...)
+
+		if valDiags.HasError() {
+			continue
+		}
+
+		if err := d.Set(k, raw); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Unable to set attribute",
+				Detail:        err.Error(),
+				AttributePath: path,
+			})
+		}
+	}
+
+	return diags
+}
+
+// structFields returns the indexes of the fields of the given struct type by
+// the names in their tfsdk tags, verifying that the fields match the given
+// schema.
+func structFields(typ reflect.Type, schema map[string]*Schema, path cty.Path) (map[string]int, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	fields := make(map[string]int, len(schema))
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := field.Tag.Lookup(structTagKey)
+
+		switch {
+		case name == "-":
+			continue
+		case !ok || name == "":
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid struct field",
+				Detail:        fmt.Sprintf("Field %s of %s must have a %s tag with the name of an attribute.", field.Name, typ, structTagKey),
+				AttributePath: path,
+			})
+			continue
+		}
+
+		fieldPath := append(path.Copy(), cty.GetAttrStep{Name: name})
+
+		s, ok := schema[name]
+		if !ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid struct field",
+				Detail:        fmt.Sprintf("Field %s of %s does not match an attribute of the schema.", field.Name, typ),
+				AttributePath: fieldPath,
+			})
+			continue
+		}
+
+		if _, ok := fields[name]; ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid struct field",
+				Detail:        fmt.Sprintf("Field %s of %s has the same %s tag as another field.", field.Name, typ, structTagKey),
+				AttributePath: fieldPath,
+			})
+			continue
+		}
+
+		fields[name] = i
+		diags = append(diags, structFieldTypeDiags(field.Type, s, fieldPath)...)
+	}
+
+	for _, name := range sortedMapKeys(schema) {
+		if _, ok := fields[name]; !ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Missing struct field",
+				Detail:        fmt.Sprintf("%s has no field with a %s tag for the attribute.", typ, structTagKey),
+				AttributePath: append(path.Copy(), cty.GetAttrStep{Name: name}),
+			})
+		}
+	}
+
+	return fields, diags
+}
+
+// structFieldTypeDiags verifies that a field of the given type can hold
+// values of the given schema.
+func structFieldTypeDiags(typ reflect.Type, s *Schema, path cty.Path) diag.Diagnostics {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	var ok bool
+
+	switch s.Type {
+	case TypeBool:
+		ok = typ.Kind() == reflect.Bool
+	case TypeInt:
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			ok = true
+		}
+	case TypeFloat:
+		ok = typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64
+	case TypeString:
+		ok = typ.Kind() == reflect.String
+	case TypeDynamic:
+		ok = typ == ctyValueType
+	case TypeList, TypeSet:
+		if typ.Kind() != reflect.Slice {
+			break
+		}
+
+		if r, isResource := s.Elem.(*Resource); isResource {
+			elemType := typ.Elem()
+			if elemType.Kind() == reflect.Ptr {
+				elemType = elemType.Elem()
+			}
+
+			if elemType.Kind() != reflect.Struct {
+				break
+			}
+
+			// Elements of sets have no path.
+			elemPath := path
+			if s.Type == TypeList {
+				elemPath = append(path.Copy(), cty.IndexStep{Key: cty.NumberIntVal(0)})
+			}
+
+			_, diags := structFields(elemType, r.SchemaMap(), elemPath)
+			return diags
+		}
+
+		return structFieldTypeDiags(typ.Elem(), elemSchema(s), path)
+	case TypeMap:
+		if typ.Kind() != reflect.Map || typ.Key().Kind() != reflect.String {
+			break
+		}
+
+		return structFieldTypeDiags(typ.Elem(), elemSchema(s), path)
+	}
+
+	if ok {
+		return nil
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       "Invalid struct field type",
+			Detail:        fmt.Sprintf("A field of type %s cannot hold values of an attribute of type %s.", typ, s.Type),
+			AttributePath: path,
+		},
+	}
+}
+
+// elemSchema returns the schema of the elements of a list, set or map of
+// primitive values, which are strings by default.
+func elemSchema(s *Schema) *Schema {
+	switch elem := s.Elem.(type) {
+	case *Schema:
+		return elem
+	case ValueType:
+		return &Schema{Type: elem}
+	default:
+		return &Schema{Type: TypeString}
+	}
+}
+
+func (d *ResourceData) decodeObject(addr []string, schema map[string]*Schema, fields map[string]int, v reflect.Value, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, k := range sortedMapKeys(fields) {
+		fieldAddr := append(addr[:len(addr):len(addr)], k)
+		fieldPath := append(path.Copy(), cty.GetAttrStep{Name: k})
+
+		diags = append(diags, d.decodeField(fieldAddr, schema[k], v.Field(fields[k]), fieldPath)...)
+	}
+
+	return diags
+}
+
+func (d *ResourceData) decodeField(addr []string, s *Schema, v reflect.Value, path cty.Path) diag.Diagnostics {
+	r := d.get(addr, getSourceSet)
+
+	if r.Computed || !r.Exists {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	return d.decodeValue(addr, r.Value, s, indirect(v), path)
+}
+
+func (d *ResourceData) decodeValue(addr []string, raw interface{}, s *Schema, v reflect.Value, path cty.Path) diag.Diagnostics {
+	if raw == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch s.Type {
+	case TypeDynamic:
+		val, err := hcl2shim.DecodeDynamicValue(raw.(string))
+		if err != nil {
+			return diag.Diagnostics{
+				{
+					Severity:      diag.Error,
+					Summary:       "Unable to decode dynamic value",
+					Detail:        err.Error(),
+					AttributePath: path,
+				},
+			}
+		}
+
+		v.Set(reflect.ValueOf(val))
+	case TypeList, TypeSet:
+		var elemAddrs []string
+		var elems []interface{}
+
+		switch raw := raw.(type) {
+		case *Set:
+			for _, code := range raw.listCode() {
+				elemAddrs = append(elemAddrs, code)
+				elems = append(elems, raw.m[code])
+			}
+		case []interface{}:
+			for i, elem := range raw {
+				elemAddrs = append(elemAddrs, strconv.Itoa(i))
+				elems = append(elems, elem)
+			}
+		}
+
+		v.Set(reflect.MakeSlice(v.Type(), len(elems), len(elems)))
+
+		var diags diag.Diagnostics
+
+		for i, elem := range elems {
+			// Elements of sets have no path.
+			elemPath := path
+			if s.Type == TypeList {
+				elemPath = append(path.Copy(), cty.IndexStep{Key: cty.NumberIntVal(int64(i))})
+			}
+
+			ev := indirect(v.Index(i))
+
+			if r, ok := s.Elem.(*Resource); ok {
+				elemAddr := append(addr[:len(addr):len(addr)], elemAddrs[i])
+				fields, _ := structFields(ev.Type(), r.SchemaMap(), elemPath)
+				diags = append(diags, d.decodeObject(elemAddr, r.SchemaMap(), fields, ev, elemPath)...)
+				continue
+			}
+
+			diags = append(diags, d.decodeValue(nil, elem, elemSchema(s), ev, elemPath)...)
+		}
+
+		return diags
+	case TypeMap:
+		m, _ := raw.(map[string]interface{})
+
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(m)))
+
+		var diags diag.Diagnostics
+
+		for k, elem := range m {
+			ev := reflect.New(v.Type().Elem()).Elem()
+
+			diags = append(diags, d.decodeValue(nil, elem, elemSchema(s), indirect(ev), append(path.Copy(), cty.IndexStep{Key: cty.StringVal(k)}))...)
+
+			v.SetMapIndex(reflect.ValueOf(k), ev)
+		}
+
+		return diags
+	default:
+		rv := reflect.ValueOf(raw)
+
+		if !rv.Type().ConvertibleTo(v.Type()) {
+			return diag.Diagnostics{
+				{
+					Severity:      diag.Error,
+					Summary:       "Invalid struct field type",
+					Detail:        fmt.Sprintf("A value of type %T cannot be stored in a field of type %s.", raw, v.Type()),
+					AttributePath: path,
+				},
+			}
+		}
+
+		v.Set(rv.Convert(v.Type()))
+	}
+
+	return nil
+}
+
+// encodeStructValue returns the value of the given struct field in the form
+// accepted by ResourceData.Set.
+func encodeStructValue(v reflect.Value, s *Schema, path cty.Path) (interface{}, diag.Diagnostics) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		v = v.Elem()
+	}
+
+	switch s.Type {
+	case TypeBool:
+		return v.Bool(), nil
+	case TypeInt:
+		return int(v.Int()), nil
+	case TypeFloat:
+		return v.Float(), nil
+	case TypeString:
+		return v.String(), nil
+	case TypeDynamic:
+		return v.Interface(), nil
+	case TypeList, TypeSet:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		var diags diag.Diagnostics
+
+		elems := make([]interface{}, 0, v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			// Elements of sets have no path.
+			elemPath := path
+			if s.Type == TypeList {
+				elemPath = append(path.Copy(), cty.IndexStep{Key: cty.NumberIntVal(int64(i))})
+			}
+
+			ev := v.Index(i)
+
+			r, ok := s.Elem.(*Resource)
+			if !ok {
+				elem, elemDiags := encodeStructValue(ev, elemSchema(s), elemPath)
+				diags = append(diags, elemDiags...)
+				elems = append(elems, elem)
+				continue
+			}
+
+			if ev.Kind() == reflect.Ptr {
+				if ev.IsNil() {
+					continue
+				}
+
+				ev = ev.Elem()
+			}
+
+			elem, elemDiags := encodeStructObject(ev, r.SchemaMap(), elemPath)
+			diags = append(diags, elemDiags...)
+			elems = append(elems, elem)
+		}
+
+		return elems, diags
+	case TypeMap:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		var diags diag.Diagnostics
+
+		m := make(map[string]interface{}, v.Len())
+
+		for it := v.MapRange(); it.Next(); {
+			k := it.Key().String()
+
+			elem, elemDiags := encodeStructValue(it.Value(), elemSchema(s), append(path.Copy(), cty.IndexStep{Key: cty.StringVal(k)}))
+			diags = append(diags, elemDiags...)
+			m[k] = elem
+		}
+
+		return m, diags
+	}
+
+	return nil, diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       "Invalid struct field type",
+			Detail:        fmt.Sprintf("Values of attributes of type %s cannot be set from a struct.", s.Type),
+			AttributePath: path,
+		},
+	}
+}
+
+func encodeStructObject(v reflect.Value, schema map[string]*Schema, path cty.Path) (map[string]interface{}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	fields, _ := structFields(v.Type(), schema, path)
+	m := make(map[string]interface{}, len(fields))
+
+	for _, k := range sortedMapKeys(fields) {
+		raw, valDiags := encodeStructValue(v.Field(fields[k]), schema[k], append(path.Copy(), cty.GetAttrStep{Name: k}))
+		diags = append(diags, valDiags...)
+
+		if raw != nil {
+			m[k] = raw
+		}
+	}
+
+	return m, diags
+}
+
+// indirect returns the value a pointer points to, allocating it if
+// necessary, or the given value if it is not a pointer.
+func indirect(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Ptr {
+		return v
+	}
+
+	if v.IsNil() {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+
+	return v.Elem()
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
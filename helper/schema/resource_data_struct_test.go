@@ -0,0 +1,302 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+type testStructRule struct {
+	Port    int64   `tfsdk:"port"`
+	Comment *string `tfsdk:"comment"`
+}
+
+type testStructModel struct {
+	Name     string            `tfsdk:"name"`
+	Count    *int              `tfsdk:"count"`
+	Enabled  *bool             `tfsdk:"enabled"`
+	Ratio    float64           `tfsdk:"ratio"`
+	Tags     map[string]string `tfsdk:"tags"`
+	Aliases  []string          `tfsdk:"aliases"`
+	Rules    []testStructRule  `tfsdk:"rule"`
+	Internal string            `tfsdk:"-"`
+
+	unexported string
+}
+
+func testStructSchema() map[string]*Schema {
+	return map[string]*Schema{
+		"name": {
+			Type:     TypeString,
+			Required: true,
+		},
+		"count": {
+			Type:     TypeInt,
+			Optional: true,
+		},
+		"enabled": {
+			Type:     TypeBool,
+			Optional: true,
+		},
+		"ratio": {
+			Type:     TypeFloat,
+			Optional: true,
+		},
+		"tags": {
+			Type:     TypeMap,
+			Optional: true,
+			Elem:     &Schema{Type: TypeString},
+		},
+		"aliases": {
+			Type:     TypeSet,
+			Optional: true,
+			Elem:     &Schema{Type: TypeString},
+		},
+		"rule": {
+			Type:     TypeList,
+			Optional: true,
+			Elem: &Resource{
+				Schema: map[string]*Schema{
+					"port": {
+						Type:     TypeInt,
+						Required: true,
+					},
+					"comment": {
+						Type:     TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestResourceDataGetInto(t *testing.T) {
+	t.Parallel()
+
+	comment := "ssh"
+	count := 0
+
+	cases := map[string]struct {
+		State *terraform.InstanceState
+		Want  testStructModel
+	}{
+		"empty": {
+			State: &terraform.InstanceState{
+				ID: "test",
+				Attributes: map[string]string{
+					"id":   "test",
+					"name": "empty",
+				},
+			},
+			Want: testStructModel{
+				Name: "empty",
+			},
+		},
+		"full": {
+			State: &terraform.InstanceState{
+				ID: "test",
+				Attributes: map[string]string{
+					"id":                "test",
+					"name":              "full",
+					"count":             "0",
+					"enabled":           "false",
+					"ratio":             "0.5",
+					"tags.%":            "1",
+					"tags.env":          "prod",
+					"aliases.#":         "1",
+					"aliases.123":       "a",
+					"rule.#":            "2",
+					"rule.0.port":       "22",
+					"rule.0.comment":    "ssh",
+					"rule.1.port":       "443",
+					"rule.1.comment":    "",
+					"rule.1.irrelevant": "",
+				},
+			},
+			Want: testStructModel{
+				Name:    "full",
+				Count:   &count,
+				Enabled: new(bool),
+				Ratio:   0.5,
+				Tags: map[string]string{
+					"env": "prod",
+				},
+				Aliases: []string{"a"},
+				Rules: []testStructRule{
+					{
+						Port:    22,
+						Comment: &comment,
+					},
+					{
+						Port:    443,
+						Comment: new(string),
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := schemaMap(testStructSchema()).Data(tc.State, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := testStructModel{
+				Internal: "kept",
+			}
+
+			if diags := d.GetInto(&got); diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			tc.Want.Internal = "kept"
+
+			if diff := cmp.Diff(tc.Want, got, cmp.AllowUnexported(testStructModel{})); diff != "" {
+				t.Fatalf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestResourceDataGetInto_mismatch(t *testing.T) {
+	t.Parallel()
+
+	type nestedModel struct {
+		Port string `tfsdk:"port"`
+	}
+
+	type model struct {
+		Name    int           `tfsdk:"name"`
+		Count   *int          `tfsdk:"count"`
+		Enabled bool          `tfsdk:"enabled"`
+		Ratio   float64       `tfsdk:"ratio"`
+		Tags    []string      `tfsdk:"tags"`
+		Aliases []string      `tfsdk:"aliases"`
+		Rules   []nestedModel `tfsdk:"rule"`
+		Extra   string        `tfsdk:"extra"`
+		Untaged string
+	}
+
+	d, err := schemaMap(testStructSchema()).Data(nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	diags := d.GetInto(&model{})
+
+	var paths []cty.Path
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			t.Fatalf("unexpected diagnostic: %#v", d)
+		}
+
+		paths = append(paths, d.AttributePath)
+	}
+
+	expected := []cty.Path{
+		cty.GetAttrPath("name"),
+		cty.GetAttrPath("tags"),
+		cty.GetAttrPath("rule").IndexInt(0).GetAttr("port"),
+		cty.GetAttrPath("rule").IndexInt(0).GetAttr("comment"),
+		cty.GetAttrPath("extra"),
+		nil,
+	}
+
+	if diff := cmp.Diff(expected, paths, cmp.Comparer(cty.Path.Equals)); diff != "" {
+		t.Fatalf("unexpected difference: %s", diff)
+	}
+
+	if diags := d.GetInto(model{}); !diags.HasError() {
+		t.Fatal("expected error for non-pointer destination")
+	}
+}
+
+func TestResourceDataSetFrom(t *testing.T) {
+	t.Parallel()
+
+	d, err := schemaMap(testStructSchema()).Data(&terraform.InstanceState{
+		ID: "test",
+		Attributes: map[string]string{
+			"id":      "test",
+			"name":    "old",
+			"count":   "3",
+			"enabled": "true",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	comment := "https"
+
+	src := testStructModel{
+		Name:  "new",
+		Ratio: 1.5,
+		Tags: map[string]string{
+			"env": "dev",
+		},
+		Aliases: []string{"b", "a"},
+		Rules: []testStructRule{
+			{
+				Port:    443,
+				Comment: &comment,
+			},
+			{
+				Port: 80,
+			},
+		},
+	}
+
+	if diags := d.SetFrom(&src); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	expected := map[string]string{
+		"id":        "test",
+		"name":      "new",
+		"count":     "0",
+		"enabled":   "false",
+		"ratio":     "1.5",
+		"tags.%":    "1",
+		"tags.env":  "dev",
+		"aliases.#": "2",
+		fmt.Sprintf("aliases.%d", HashSchema(&Schema{Type: TypeString})("a")): "a",
+		fmt.Sprintf("aliases.%d", HashSchema(&Schema{Type: TypeString})("b")): "b",
+		"rule.#":         "2",
+		"rule.0.port":    "443",
+		"rule.0.comment": "https",
+		"rule.1.port":    "80",
+		"rule.1.comment": "",
+	}
+
+	if diff := cmp.Diff(expected, d.State().Attributes); diff != "" {
+		t.Fatalf("unexpected difference: %s", diff)
+	}
+
+	got := testStructModel{}
+
+	if diags := d.GetInto(&got); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	src.Count = new(int)
+	src.Enabled = new(bool)
+	src.Aliases = []string{"a", "b"}
+
+	if diff := cmp.Diff(src, got, cmp.AllowUnexported(testStructModel{})); diff != "" {
+		t.Fatalf("unexpected round trip difference: %s", diff)
+	}
+}
@@ -4495,3 +4495,127 @@ func TestResourceData_TfTypeResourceState(t *testing.T) {
 func testPtrTo(raw interface{}) interface{} {
 	return &raw
 }
+
+func TestResourceDataGetPtr(t *testing.T) {
+	t.Parallel()
+
+	objType := cty.Object(map[string]cty.Type{
+		"name":    cty.String,
+		"enabled": cty.Bool,
+	})
+
+	rawVal := cty.ObjectVal(map[string]cty.Value{
+		"name":    cty.StringVal(""),
+		"unset":   cty.NullVal(cty.String),
+		"count":   cty.NumberIntVal(0),
+		"enabled": cty.False,
+		"unknown": cty.UnknownVal(cty.Bool),
+		"list": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":    cty.StringVal("a"),
+				"enabled": cty.NullVal(cty.Bool),
+			}),
+		}),
+		"set":    cty.SetVal([]cty.Value{cty.StringVal("b")}),
+		"single": cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("c"), "enabled": cty.True}),
+		"empty":  cty.ListValEmpty(objType),
+	})
+
+	cases := map[string]struct {
+		Diff  *terraform.InstanceDiff
+		State *terraform.InstanceState
+	}{
+		"plan": {
+			Diff: &terraform.InstanceDiff{
+				RawConfig: cty.NullVal(rawVal.Type()),
+				RawPlan:   rawVal,
+				RawState:  cty.NullVal(rawVal.Type()),
+			},
+		},
+		"state": {
+			State: &terraform.InstanceState{
+				RawConfig: cty.NullVal(rawVal.Type()),
+				RawPlan:   cty.NullVal(rawVal.Type()),
+				RawState:  rawVal,
+			},
+		},
+		"config": {
+			Diff: &terraform.InstanceDiff{
+				RawConfig: rawVal,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &ResourceData{
+				diff:  tc.Diff,
+				state: tc.State,
+			}
+
+			if got := d.GetStringPtr("name"); got == nil || *got != "" {
+				t.Errorf("expected empty string for name, got %v", got)
+			}
+
+			if got := d.GetStringPtr("unset"); got != nil {
+				t.Errorf("expected nil for unset, got %q", *got)
+			}
+
+			if got := d.GetInt64Ptr("count"); got == nil || *got != 0 {
+				t.Errorf("expected 0 for count, got %v", got)
+			}
+
+			if got := d.GetBoolPtr("enabled"); got == nil || *got {
+				t.Errorf("expected false for enabled, got %v", got)
+			}
+
+			if got := d.GetBoolPtr("unknown"); got != nil {
+				t.Errorf("expected nil for unknown, got %t", *got)
+			}
+
+			if got := d.GetStringPtr("list.0.name"); got == nil || *got != "a" {
+				t.Errorf("expected a for list.0.name, got %v", got)
+			}
+
+			if got := d.GetBoolPtr("list.0.enabled"); got != nil {
+				t.Errorf("expected nil for list.0.enabled, got %t", *got)
+			}
+
+			if got := d.GetStringPtr("list.1.name"); got != nil {
+				t.Errorf("expected nil for list.1.name, got %q", *got)
+			}
+
+			if got := d.GetBoolPtr("single.0.enabled"); got == nil || !*got {
+				t.Errorf("expected true for single.0.enabled, got %v", got)
+			}
+
+			if got := d.GetStringPtr("count"); got != nil {
+				t.Errorf("expected nil for count as string, got %q", *got)
+			}
+
+			expectedList := []interface{}{
+				map[string]interface{}{
+					"name": "a",
+				},
+			}
+
+			if got := d.GetListPtr("list"); got == nil || !reflect.DeepEqual(*got, expectedList) {
+				t.Errorf("expected %#v for list, got %#v", expectedList, got)
+			}
+
+			if got := d.GetListPtr("set"); got == nil || !reflect.DeepEqual(*got, []interface{}{"b"}) {
+				t.Errorf("expected [b] for set, got %#v", got)
+			}
+
+			if got := d.GetListPtr("empty"); got == nil || len(*got) != 0 {
+				t.Errorf("expected empty list, got %#v", got)
+			}
+
+			if got := d.GetListPtr("unset"); got != nil {
+				t.Errorf("expected nil for unset, got %#v", *got)
+			}
+		})
+	}
+}
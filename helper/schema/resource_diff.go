@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -49,7 +50,7 @@ func (w *newValueWriter) WriteField(address []string, value interface{}, compute
 		return errors.New("Non-nil value with computed set")
 	}
 
-	if err := w.MapFieldWriter.WriteField(address, value); err != nil {
+	if err := w.MapFieldWriter.writeNestedField(address, value); err != nil {
 		return err
 	}
 
@@ -114,6 +115,11 @@ func (r *newValueReader) ReadField(address []string) (FieldReadResult, error) {
 //
 // All functions in ResourceDiff, save for ForceNew, can only be used on
 // computed fields.
+//
+// Keys may address fields nested within list and set elements, such as
+// network_interface.0.private_ip for a list element, or rule.1234.port for
+// the set element with the hash code 1234. SetElementKeys returns the keys
+// of set elements matching a predicate.
 type ResourceDiff struct {
 	// The schema for the resource being worked on.
 	schema map[string]*Schema
@@ -150,6 +156,11 @@ type ResourceDiff struct {
 	forcedNewKeys map[string]bool
 
 	newIdentity *IdentityData
+
+	// Whether the Terraform request allows deferred actions, and the deferred
+	// response set by Defer.
+	deferralAllowed bool
+	deferred        *Deferred
 }
 
 // newResourceDiff creates a new ResourceDiff instance.
@@ -297,9 +308,16 @@ func (d *ResourceDiff) diffChange(key string) (interface{}, interface{}, bool, b
 // be correct for the attribute's schema (mostly relevant for maps, lists, and
 // sets). The original value from the state is used as the old value.
 //
+// The key may address a field nested within existing list or set elements.
+// Nested values are only read back by Get and GetChange with their own key,
+// not as part of the value of the enclosing list or set.
+//
 // This function is only allowed on computed attributes.
 func (d *ResourceDiff) SetNew(key string, value interface{}) error {
-	if err := d.checkKey(key, "SetNew", false); err != nil {
+	if err := d.checkKey(key, "SetNew", true); err != nil {
+		return err
+	}
+	if err := d.checkElements(key, "SetNew"); err != nil {
 		return err
 	}
 
@@ -309,15 +327,65 @@ func (d *ResourceDiff) SetNew(key string, value interface{}) error {
 // SetNewComputed functions like SetNew, except that it blanks out a new value
 // and marks it as computed.
 //
+// The key may address a field nested within existing list or set elements,
+// in the same way as SetNew.
+//
 // This function is only allowed on computed attributes.
 func (d *ResourceDiff) SetNewComputed(key string) error {
-	if err := d.checkKey(key, "SetNewComputed", false); err != nil {
+	if err := d.checkKey(key, "SetNewComputed", true); err != nil {
+		return err
+	}
+	if err := d.checkElements(key, "SetNewComputed"); err != nil {
 		return err
 	}
 
 	return d.setDiff(key, nil, true)
 }
 
+// SetElementKeys returns the keys of the elements of the TypeSet attribute
+// with the given key for which the predicate returns true, such as
+// rule.1234 for the element with the hash code 1234. The predicate is called
+// with each element of the new value of the set, in the same form as the
+// elements returned by Get.
+//
+// The returned keys can be used to address fields nested within the elements
+// in SetNew, SetNewComputed and ForceNew:
+//
+//	for _, k := range d.SetElementKeys("rule", func(v interface{}) bool {
+//		return v.(map[string]interface{})["name"] == "web"
+//	}) {
+//		if err := d.SetNewComputed(k + ".port"); err != nil {
+//			return err
+//		}
+//	}
+func (d *ResourceDiff) SetElementKeys(key string, predicate func(interface{}) bool) []string {
+	var keys []string
+
+	set, ok := d.get(strings.Split(key, "."), "newDiff").Value.(*Set)
+	if !ok {
+		return keys
+	}
+
+	for _, code := range set.listCode() {
+		if predicate(set.m[code]) {
+			keys = append(keys, key+"."+code)
+		}
+	}
+
+	return keys
+}
+
+// updatedKeySchema returns the schema of a key returned by UpdatedKeys, which
+// may be nested, or nil if the key is not valid.
+func (d *ResourceDiff) updatedKeySchema(key string) *Schema {
+	schemaL := addrToSchema(strings.Split(key, "."), d.schema)
+	if len(schemaL) == 0 {
+		return nil
+	}
+
+	return schemaL[len(schemaL)-1]
+}
+
 // setDiff performs common diff setting behaviour.
 func (d *ResourceDiff) setDiff(key string, newValue interface{}, computed bool) error {
 	if err := d.clear(key); err != nil {
@@ -344,6 +412,10 @@ func (d *ResourceDiff) setDiff(key string, newValue interface{}, computed bool)
 // ensure that the diff looks like the diff for a new resource as much as
 // possible. CustomizeDiff should expect such a scenario and act correctly.
 //
+// The key may address a field nested within list or set elements, in the same
+// way as SetNew. The schema of the field is shared by all elements, so
+// changes of the field in other elements also force a new resource.
+//
 // This function is a no-op/error if there is no diff.
 //
 // Note that the change to schema is permanent for the lifecycle of this
@@ -581,6 +653,42 @@ func (d *ResourceDiff) GetRawPlan() cty.Value {
 	return cty.NullVal(schemaMap(d.schema).CoreConfigSchema().ImpliedType())
 }
 
+// GetStringPtr returns the value of the TypeString attribute with the given
+// key, or nil if the value is null or not yet known. Unlike GetOk, an empty
+// string is returned if it is the value of the attribute.
+//
+// The value is read from the raw plan, or from the raw state when destroying
+// the resource, so values written with SetNew are not returned. Keys within
+// sets are not supported.
+func (d *ResourceDiff) GetStringPtr(key string) *string {
+	return rawStringPtr(d.getRawValueAtKey(key))
+}
+
+// GetInt64Ptr returns the value of the TypeInt attribute with the given key,
+// or nil if the value is null or not yet known. Values are read in the same
+// way as GetStringPtr.
+func (d *ResourceDiff) GetInt64Ptr(key string) *int64 {
+	return rawInt64Ptr(d.getRawValueAtKey(key))
+}
+
+// GetBoolPtr returns the value of the TypeBool attribute with the given key,
+// or nil if the value is null or not yet known. Values are read in the same
+// way as GetStringPtr.
+func (d *ResourceDiff) GetBoolPtr(key string) *bool {
+	return rawBoolPtr(d.getRawValueAtKey(key))
+}
+
+// GetListPtr returns the elements of the TypeList or TypeSet attribute with
+// the given key, in the same form as Get, or nil if the value is null or not
+// wholly known. Values are read in the same way as GetStringPtr.
+func (d *ResourceDiff) GetListPtr(key string) *[]interface{} {
+	return rawListPtr(d.getRawValueAtKey(key))
+}
+
+func (d *ResourceDiff) getRawValueAtKey(key string) cty.Value {
+	return rawValueAtKey(rawValueForPhase(d.GetRawPlan(), d.GetRawState(), d.GetRawConfig()), key)
+}
+
 // getChange gets values from two different levels, designed for use in
 // diffChange, HasChange, and GetChange.
 //
@@ -689,6 +797,51 @@ func (d *ResourceDiff) checkKey(key, caller string, nested bool) error {
 	return nil
 }
 
+// enclosingSetKey returns the key of the outermost set containing the element
+// addressed by a nested key, or an empty string if the key is not nested
+// within a set element.
+func (d *ResourceDiff) enclosingSetKey(key string) string {
+	keyParts := strings.Split(key, ".")
+	for i := 1; i < len(keyParts); i++ {
+		schemaL := addrToSchema(keyParts[:i], d.schema)
+		if len(schemaL) > 0 && schemaL[len(schemaL)-1].Type == TypeSet {
+			return strings.Join(keyParts[:i], ".")
+		}
+	}
+	return ""
+}
+
+// checkElements checks that the list and set elements addressed by a nested
+// key exist in the new value, so that no diff is written for elements that
+// will not exist.
+func (d *ResourceDiff) checkElements(key, caller string) error {
+	keyParts := strings.Split(key, ".")
+	for i := 1; i < len(keyParts); i++ {
+		schemaL := addrToSchema(keyParts[:i], d.schema)
+		if len(schemaL) == 0 {
+			return fmt.Errorf("%s: invalid key: %s", caller, key)
+		}
+
+		switch schemaL[len(schemaL)-1].Type {
+		case TypeList:
+			list, _ := d.get(keyParts[:i], "newDiff").Value.([]interface{})
+			idx, err := strconv.Atoi(keyParts[i])
+			if err != nil || idx < 0 || idx >= len(list) {
+				return fmt.Errorf("%s: invalid key: %s: list %s has no element %s", caller, key, strings.Join(keyParts[:i], "."), keyParts[i])
+			}
+		case TypeSet:
+			set, ok := d.get(keyParts[:i], "newDiff").Value.(*Set)
+			if ok {
+				_, ok = set.m[keyParts[i]]
+			}
+			if !ok {
+				return fmt.Errorf("%s: invalid key: %s: set %s has no element %s", caller, key, strings.Join(keyParts[:i], "."), keyParts[i])
+			}
+		}
+	}
+	return nil
+}
+
 func (d *ResourceDiff) Identity() (*IdentityData, error) {
 	// return memoized value if available
 	if d.newIdentity != nil {
@@ -707,3 +860,27 @@ func (d *ResourceDiff) Identity() (*IdentityData, error) {
 
 	return d.newIdentity, nil
 }
+
+// Defer indicates to Terraform that the change of the resource cannot be
+// planned yet and should be deferred, with the given reason, such as
+// DeferredReasonResourceConfigUnknown when a configuration value required to
+// plan the change is unknown. The plan is still returned to Terraform. Defer
+// is only allowed when DeferralAllowed returns true, otherwise the plan fails
+// with an error.
+//
+// NOTE: This functionality is related to deferred action support, which is currently experimental and is subject
+// to change or break without warning. It is not protected by version compatibility guarantees.
+func (d *ResourceDiff) Defer(reason DeferredReason) {
+	d.deferred = &Deferred{
+		Reason: reason,
+	}
+}
+
+// DeferralAllowed returns true if the Terraform request indicated support for
+// deferred actions, so that Defer can be called.
+//
+// NOTE: This functionality is related to deferred action support, which is currently experimental and is subject
+// to change or break without warning. It is not protected by version compatibility guarantees.
+func (d *ResourceDiff) DeferralAllowed() bool {
+	return d.deferralAllowed
+}
@@ -938,6 +938,74 @@ func TestForceNew(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "set element by hash",
+			Schema: map[string]*Schema{
+				"rule": testNestedDiffSchema()["rule"],
+			},
+			State: &terraform.InstanceState{
+				Attributes: map[string]string{
+					"rule.#":      "1",
+					"rule.1.name": "web",
+					"rule.1.port": "80",
+				},
+			},
+			Config: testConfig(t, map[string]interface{}{
+				"rule": []interface{}{
+					map[string]interface{}{
+						"name": "db",
+					},
+				},
+			}),
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"rule.1.name": {
+						Old:        "web",
+						New:        "",
+						NewRemoved: true,
+					},
+					"rule.1.port": {
+						Old:        "80",
+						New:        "0",
+						NewRemoved: true,
+					},
+					"rule.2.name": {
+						Old: "",
+						New: "db",
+					},
+					"rule.2.port": {
+						Old:         "",
+						NewComputed: true,
+					},
+				},
+			},
+			Key: "rule.2.name",
+			Expected: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"rule.1.name": {
+						Old:         "web",
+						New:         "",
+						NewRemoved:  true,
+						RequiresNew: true,
+					},
+					"rule.1.port": {
+						Old:        "80",
+						New:        "0",
+						NewRemoved: true,
+					},
+					"rule.2.name": {
+						Old:         "",
+						New:         "db",
+						RequiresNew: true,
+					},
+					"rule.2.port": {
+						Old:         "",
+						New:         "0",
+						NewComputed: true,
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -964,6 +1032,217 @@ func TestForceNew(t *testing.T) {
 	}
 }
 
+// testNestedDiffSchema returns a schema with computed fields nested within
+// list and set elements, for use with the nested key tests.
+func testNestedDiffSchema() map[string]*Schema {
+	return map[string]*Schema{
+		"network_interface": {
+			Type:     TypeList,
+			Optional: true,
+			Elem: &Resource{
+				Schema: map[string]*Schema{
+					"name": {
+						Type:     TypeString,
+						Optional: true,
+					},
+					"private_ip": {
+						Type:     TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
+		"rule": {
+			Type:     TypeSet,
+			Optional: true,
+			Elem: &Resource{
+				Schema: map[string]*Schema{
+					"name": {
+						Type:     TypeString,
+						Optional: true,
+					},
+					"port": {
+						Type:     TypeInt,
+						Computed: true,
+					},
+				},
+			},
+			Set: func(v interface{}) int {
+				if v.(map[string]interface{})["name"] == "web" {
+					return 1
+				}
+				return 2
+			},
+		},
+	}
+}
+
+// testNestedDiffState returns the state for testNestedDiffSchema.
+func testNestedDiffState() *terraform.InstanceState {
+	return &terraform.InstanceState{
+		ID: "foo",
+		Attributes: map[string]string{
+			"id":                             "foo",
+			"network_interface.#":            "1",
+			"network_interface.0.name":       "eth0",
+			"network_interface.0.private_ip": "10.0.0.1",
+			"rule.#":                         "2",
+			"rule.1.name":                    "web",
+			"rule.1.port":                    "80",
+			"rule.2.name":                    "db",
+			"rule.2.port":                    "5432",
+		},
+	}
+}
+
+// testNestedDiffConfig returns the config for testNestedDiffSchema, which
+// matches testNestedDiffState.
+func testNestedDiffConfig(t *testing.T) *terraform.ResourceConfig {
+	return testConfig(t, map[string]interface{}{
+		"network_interface": []interface{}{
+			map[string]interface{}{
+				"name": "eth0",
+			},
+		},
+		"rule": []interface{}{
+			map[string]interface{}{
+				"name": "web",
+			},
+			map[string]interface{}{
+				"name": "db",
+			},
+		},
+	})
+}
+
+func TestSetNewNested(t *testing.T) {
+	cases := []struct {
+		Name          string
+		Key           string
+		NewValue      interface{}
+		Computed      bool
+		Expected      map[string]*terraform.ResourceAttrDiff
+		ExpectedError bool
+	}{
+		{
+			Name:     "list element",
+			Key:      "network_interface.0.private_ip",
+			NewValue: "10.0.0.2",
+			Expected: map[string]*terraform.ResourceAttrDiff{
+				"network_interface.0.private_ip": {
+					Old: "10.0.0.1",
+					New: "10.0.0.2",
+				},
+			},
+		},
+		{
+			Name:     "list element, computed",
+			Key:      "network_interface.0.private_ip",
+			Computed: true,
+			Expected: map[string]*terraform.ResourceAttrDiff{
+				"network_interface.0.private_ip": {
+					Old:         "10.0.0.1",
+					NewComputed: true,
+				},
+			},
+		},
+		{
+			Name:     "set element by hash",
+			Key:      "rule.2.port",
+			NewValue: 5433,
+			Expected: map[string]*terraform.ResourceAttrDiff{
+				"rule.#":      {Old: "2", New: "2"},
+				"rule.1.name": {Old: "web", New: "web"},
+				"rule.1.port": {Old: "80", New: "80"},
+				"rule.2.name": {Old: "db", New: "db"},
+				"rule.2.port": {Old: "5432", New: "5433"},
+			},
+		},
+		{
+			Name:     "set element by hash, computed",
+			Key:      "rule.1.port",
+			Computed: true,
+			Expected: map[string]*terraform.ResourceAttrDiff{
+				"rule.#":      {Old: "2", New: "2"},
+				"rule.1.name": {Old: "web", New: "web"},
+				"rule.1.port": {Old: "80", New: "0", NewComputed: true},
+				"rule.2.name": {Old: "db", New: "db"},
+				"rule.2.port": {Old: "5432", New: "5432"},
+			},
+		},
+		{
+			Name:          "missing list element",
+			Key:           "network_interface.1.private_ip",
+			NewValue:      "10.0.0.2",
+			ExpectedError: true,
+		},
+		{
+			Name:          "missing set element",
+			Key:           "rule.3.port",
+			Computed:      true,
+			ExpectedError: true,
+		},
+		{
+			Name:          "non-computed nested key",
+			Key:           "network_interface.0.name",
+			NewValue:      "eth1",
+			ExpectedError: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			m := schemaMap(testNestedDiffSchema())
+			diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{}}
+			d := newResourceDiff(schemaMapWithIdentity{m, nil}, testNestedDiffConfig(t), testNestedDiffState(), diff)
+
+			var err error
+			if tc.Computed {
+				err = d.SetNewComputed(tc.Key)
+			} else {
+				err = d.SetNew(tc.Key, tc.NewValue)
+			}
+			switch {
+			case err != nil && !tc.ExpectedError:
+				t.Fatalf("bad: %s", err)
+			case err == nil && tc.ExpectedError:
+				t.Fatalf("Expected error, got none")
+			case err != nil && tc.ExpectedError:
+				return
+			}
+			if err := m.diffUpdatedKeys(context.Background(), diff, d); err != nil {
+				t.Fatalf("bad: %s", err)
+			}
+			if diff := cmp.Diff(tc.Expected, diff.Attributes); diff != "" {
+				t.Fatalf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSetElementKeys(t *testing.T) {
+	m := schemaMap(testNestedDiffSchema())
+	diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{}}
+	d := newResourceDiff(schemaMapWithIdentity{m, nil}, testNestedDiffConfig(t), testNestedDiffState(), diff)
+
+	keys := d.SetElementKeys("rule", func(v interface{}) bool {
+		return v.(map[string]interface{})["name"] == "db"
+	})
+	if diff := cmp.Diff([]string{"rule.2"}, keys); diff != "" {
+		t.Fatalf("unexpected difference: %s", diff)
+	}
+
+	keys = d.SetElementKeys("rule", func(v interface{}) bool {
+		return true
+	})
+	if diff := cmp.Diff([]string{"rule.1", "rule.2"}, keys); diff != "" {
+		t.Fatalf("unexpected difference: %s", diff)
+	}
+
+	if keys := d.SetElementKeys("network_interface", func(v interface{}) bool { return true }); len(keys) != 0 {
+		t.Fatalf("expected no keys for a list, got %v", keys)
+	}
+}
+
 func TestClear(t *testing.T) {
 	cases := []resourceDiffTestCase{
 		{
@@ -2417,3 +2696,74 @@ func TestResourceDiffGetRawConfigAt(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceDiffGetPtr(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]*Schema{
+		"name": {
+			Type:     TypeString,
+			Optional: true,
+		},
+		"count": {
+			Type:     TypeInt,
+			Optional: true,
+		},
+		"enabled": {
+			Type:     TypeBool,
+			Optional: true,
+		},
+		"tags": {
+			Type:     TypeList,
+			Optional: true,
+			Elem:     &Schema{Type: TypeString},
+		},
+	}
+
+	ty := schemaMap(schema).CoreConfigSchema().ImpliedType()
+
+	plan := cty.ObjectVal(map[string]cty.Value{
+		"id":      cty.UnknownVal(cty.String),
+		"name":    cty.NullVal(cty.String),
+		"count":   cty.NumberIntVal(0),
+		"enabled": cty.False,
+		"tags":    cty.ListVal([]cty.Value{cty.StringVal("a")}),
+	})
+
+	d := newResourceDiff(schemaMapWithIdentity{schema, nil}, nil, nil, &terraform.InstanceDiff{
+		RawConfig: plan,
+		RawPlan:   plan,
+		RawState:  cty.NullVal(ty),
+	})
+
+	if got := d.GetStringPtr("name"); got != nil {
+		t.Errorf("expected nil for name, got %q", *got)
+	}
+
+	if got := d.GetStringPtr("id"); got != nil {
+		t.Errorf("expected nil for unknown id, got %q", *got)
+	}
+
+	if got := d.GetInt64Ptr("count"); got == nil || *got != 0 {
+		t.Errorf("expected 0 for count, got %v", got)
+	}
+
+	if got := d.GetBoolPtr("enabled"); got == nil || *got {
+		t.Errorf("expected false for enabled, got %v", got)
+	}
+
+	if got := d.GetListPtr("tags"); got == nil || !reflect.DeepEqual(*got, []interface{}{"a"}) {
+		t.Errorf("expected [a] for tags, got %#v", got)
+	}
+
+	// Destroy plans have a null plan, so the state is read.
+	d = newResourceDiff(schemaMapWithIdentity{schema, nil}, nil, nil, &terraform.InstanceDiff{
+		RawConfig: cty.NullVal(ty),
+		RawPlan:   cty.NullVal(ty),
+		RawState:  plan,
+	})
+
+	if got := d.GetBoolPtr("enabled"); got == nil || *got {
+		t.Errorf("expected false for enabled in state, got %v", got)
+	}
+}
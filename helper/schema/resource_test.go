@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"reflect"
 	"testing"
 	"time"
@@ -869,6 +870,44 @@ func TestResourceInternalValidate(t *testing.T) {
 			true,
 			true,
 		},
+		"CustomizeDiff and CustomizeDiffWithDiagnostics should not both be set": {
+			&Resource{
+				Create: Noop,
+				Read:   Noop,
+				Update: Noop,
+				Delete: Noop,
+				Schema: map[string]*Schema{
+					"foo": {
+						Type:     TypeString,
+						Required: true,
+					},
+				},
+				CustomizeDiff: func(_ context.Context, _ *ResourceDiff, _ interface{}) error {
+					return nil
+				},
+				CustomizeDiffWithDiagnostics: func(_ context.Context, _ *ResourceDiff, _ interface{}) diag.Diagnostics {
+					return nil
+				},
+			},
+			true,
+			true,
+		},
+		"CustomizeDiffWithDiagnostics for read-only resource": {
+			&Resource{
+				Read: Noop,
+				Schema: map[string]*Schema{
+					"foo": {
+						Type:     TypeString,
+						Computed: true,
+					},
+				},
+				CustomizeDiffWithDiagnostics: func(_ context.Context, _ *ResourceDiff, _ interface{}) diag.Diagnostics {
+					return nil
+				},
+			},
+			false,
+			true,
+		},
 		"Create and CreateContext should not both be set": {
 			&Resource{
 				Create:        Noop,
@@ -1094,6 +1133,82 @@ func TestResourceInternalValidate(t *testing.T) {
 			Writable: false,
 			Err:      true,
 		},
+		"List without Identity": {
+			In: &Resource{
+				Schema: map[string]*Schema{
+					"test": {
+						Type:     TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+				},
+				Create: Noop,
+				Read:   Noop,
+				Delete: Noop,
+				List: func(context.Context, ListResourceRequest) iter.Seq[ListResourceResult] {
+					return nil
+				},
+			},
+			Writable: true,
+			Err:      true,
+		},
+		"List with Identity": {
+			In: &Resource{
+				Schema: map[string]*Schema{
+					"test": {
+						Type:     TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+				},
+				Identity: &ResourceIdentity{
+					SchemaFunc: func() map[string]*Schema {
+						return map[string]*Schema{
+							"test": {
+								Type:              TypeString,
+								RequiredForImport: true,
+							},
+						}
+					},
+				},
+				ListConfigSchema: map[string]*Schema{
+					"filter": {
+						Type:     TypeString,
+						Optional: true,
+					},
+				},
+				Create: Noop,
+				Read:   Noop,
+				Delete: Noop,
+				List: func(context.Context, ListResourceRequest) iter.Seq[ListResourceResult] {
+					return nil
+				},
+			},
+			Writable: true,
+			Err:      false,
+		},
+		"ListConfigSchema without List": {
+			In: &Resource{
+				Schema: map[string]*Schema{
+					"test": {
+						Type:     TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+				},
+				ListConfigSchema: map[string]*Schema{
+					"filter": {
+						Type:     TypeString,
+						Optional: true,
+					},
+				},
+				Create: Noop,
+				Read:   Noop,
+				Delete: Noop,
+			},
+			Writable: true,
+			Err:      true,
+		},
 		"Writable SchemaFunc and Schema should not both be set": {
 			In: &Resource{
 				Schema: map[string]*Schema{
@@ -1231,6 +1346,76 @@ func TestResourceRefresh_DiffSuppressOnRefresh(t *testing.T) {
 	}
 }
 
+func TestResourceRefresh_SemanticEqualsOnRefresh(t *testing.T) {
+	r := &Resource{
+		SchemaVersion: 2,
+		Schema: map[string]*Schema{
+			"tags": {
+				Type:     TypeList,
+				Optional: true,
+				Elem:     &Schema{Type: TypeString},
+				SemanticEquals: func(_ context.Context, prior, proposed cty.Value) (bool, diag.Diagnostics) {
+					return prior.LengthInt() == proposed.LengthInt(), nil
+				},
+				DiffSuppressOnRefresh: true,
+			},
+			"names": {
+				Type:     TypeList,
+				Optional: true,
+				Elem:     &Schema{Type: TypeString},
+				SemanticEquals: func(_ context.Context, prior, proposed cty.Value) (bool, diag.Diagnostics) {
+					return true, nil
+				},
+			},
+		},
+	}
+
+	r.Read = func(d *ResourceData, m interface{}) error {
+		if err := d.Set("tags", []interface{}{"B", "A"}); err != nil {
+			return err
+		}
+
+		return d.Set("names", []interface{}{"B", "A"})
+	}
+
+	s := &terraform.InstanceState{
+		ID: "bar",
+		Attributes: map[string]string{
+			"tags.#":  "2",
+			"tags.0":  "a",
+			"tags.1":  "b",
+			"names.#": "2",
+			"names.0": "a",
+			"names.1": "b",
+		},
+	}
+
+	expected := &terraform.InstanceState{
+		ID: "bar",
+		Attributes: map[string]string{
+			"id":      "bar",
+			"tags.#":  "2",
+			"tags.0":  "a", // new value was semantically equal
+			"tags.1":  "b",
+			"names.#": "2",
+			"names.0": "B", // DiffSuppressOnRefresh is not set
+			"names.1": "A",
+		},
+		Meta: map[string]interface{}{
+			"schema_version": "2",
+		},
+	}
+
+	actual, diags := r.RefreshWithoutUpgrade(context.Background(), s, 42)
+	if diags.HasError() {
+		t.Fatalf("err: %s", diagutils.ErrorDiags(diags))
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
 func TestResourceRefresh_blankId(t *testing.T) {
 	r := &Resource{
 		Schema: map[string]*Schema{
@@ -58,6 +58,14 @@ type Schema struct {
 	//   TypeList - []interface{}
 	//   TypeMap - map[string]interface{}
 	//   TypeSet - *schema.Set
+	//   TypeDynamic - string
+	//
+	// TypeDynamic accepts a value of any type, such as an object or a list of
+	// mixed element types. As the legacy state cannot represent arbitrary
+	// values, Get returns the value as a JSON string which includes its type.
+	// Use ResourceData.GetDynamic to decode it into a cty.Value, and
+	// ResourceData.Set with a cty.Value to set it. TypeDynamic is only
+	// supported for top-level attributes.
 	//
 	Type ValueType
 
@@ -79,6 +87,26 @@ type Schema struct {
 	// When Computed is set without Optional, the attribute is not settable
 	// in configuration at all and so SchemaConfigModeAttr is the automatic
 	// behavior, and SchemaConfigModeBlock is not permitted.
+	//
+	// If Elem is *schema.Resource then setting ConfigMode to
+	// SchemaConfigModeNestedAttr will represent it in configuration as a
+	// nested attribute, which is assigned with an expression such as
+	// attr = [{ ... }] while each attribute of the nested Resource keeps its
+	// own Required, Optional, Computed and Sensitive flags. TypeList and
+	// TypeSet are nested attributes of lists and sets of objects, except that
+	// TypeList with MaxItems of 1 is a nested attribute of a single object.
+	// Nested attributes of maps of objects are not supported, as TypeMap
+	// does not support an Elem of *schema.Resource.
+	// ResourceData represents nested attributes in the same way as blocks, so
+	// a block can be changed to a nested attribute without changing how it
+	// is read and written. Prior state of a block which was changed to a
+	// single nested attribute is migrated automatically.
+	//
+	// Nested attributes are only supported when serving protocol version 6,
+	// such as with NewGRPCProviderServerV6, as earlier versions of the
+	// protocol have no nested attributes. The server returned by
+	// NewGRPCProviderServer returns an error diagnostic for the provider
+	// schema when any schema has a nested attribute.
 	ConfigMode SchemaConfigMode
 
 	// Required indicates whether the practitioner must enter a value in the
@@ -122,7 +150,8 @@ type Schema struct {
 	// encapsulating Resource is a managed resource.
 	//
 	// If conditional replacement logic is needed, use the Resource type
-	// CustomizeDiff field to call the ResourceDiff type ForceNew method.
+	// CustomizeDiff field to call the ResourceDiff type ForceNew method, or
+	// the RequiresReplaceIf PlanModifiers.
 	ForceNew bool
 
 	// If this is non-nil, the provided function will be used during diff
@@ -170,8 +199,45 @@ type Schema struct {
 	// This is an opt-in because it was a later addition to the DiffSuppressFunc
 	// functionality which would cause some significant changes in behavior
 	// for existing providers if activated everywhere all at once.
+	//
+	// DiffSuppressOnRefresh also enables using SemanticEquals during the
+	// refresh step, in which case DiffSuppressFunc is not required.
 	DiffSuppressOnRefresh bool
 
+	// SemanticEquals is a function called to determine whether the proposed
+	// new value of this attribute or block is semantically equal to its prior
+	// state value, such as JSON documents which only differ in whitespace or
+	// lists of CIDR blocks in different notations. If so, the prior state
+	// value is kept in the plan, and during the refresh step when
+	// DiffSuppressOnRefresh is set.
+	//
+	// Unlike DiffSuppressFunc, it is called once with the whole value of the
+	// attribute or block, which is of its type in the Terraform type system,
+	// so that it can compare collections and nested blocks as a whole. It is
+	// only called when both values are known and not null, and not when the
+	// resource is created.
+	//
+	// Within TypeSet blocks, each element of the proposed value is replaced
+	// with an element of the prior value which it is equal to once the
+	// SemanticEquals of its attributes and blocks are applied. During the
+	// refresh step, the set is only kept if all of its elements match.
+	//
+	// SemanticEquals is not supported on the Elem of a collection attribute,
+	// where it can be set on the collection attribute itself instead.
+	SemanticEquals SchemaSemanticEqualsFunc
+
+	// PlanModifiers are functions called in order to modify the planned
+	// value of this attribute or block, after the SDK planned the resource
+	// with its Default, DiffSuppressFunc, ForceNew and CustomizeDiff logic.
+	// They are given the prior state, configuration, proposed new state and
+	// planned values of the attribute, and can change the planned value or
+	// require the replacement of the resource, such as with the built-in
+	// UseStateForUnknown, RequiresReplaceIf and RequiresReplaceIfConfigured.
+	//
+	// PlanModifiers are only called for managed resources when the plan has
+	// changes, and are not supported within TypeSet elements.
+	PlanModifiers []PlanModifierFunc
+
 	// Default indicates a value to set if this attribute is not set in the
 	// configuration. Default cannot be used with DefaultFunc or Required.
 	// Default is only supported if the Type is TypeBool, TypeFloat, TypeInt,
@@ -434,6 +500,7 @@ const (
 	SchemaConfigModeAuto SchemaConfigMode = iota
 	SchemaConfigModeAttr
 	SchemaConfigModeBlock
+	SchemaConfigModeNestedAttr
 )
 
 // SchemaDiffSuppressFunc is a function which can be used to determine
@@ -443,6 +510,13 @@ const (
 // Return true if the diff should be suppressed, false to retain it.
 type SchemaDiffSuppressFunc func(k, oldValue, newValue string, d *ResourceData) bool
 
+// SchemaSemanticEqualsFunc is a function which can be used to determine
+// whether a new value of an attribute or block is semantically equal to its
+// prior value, so that the prior value is kept.
+//
+// Return true if the values are semantically equal, false otherwise.
+type SchemaSemanticEqualsFunc func(ctx context.Context, prior, proposed cty.Value) (bool, diag.Diagnostics)
+
 // SchemaDefaultFunc is a function called to return a default value for
 // a field.
 type SchemaDefaultFunc func() (interface{}, error)
@@ -682,6 +756,33 @@ func (m schemaMap) Data(
 	return schemaMapWithIdentity{m, nil}.Data(s, d)
 }
 
+// configData returns a ResourceData for the given configuration value, for
+// concepts such as ephemeral resources and list resources which only read
+// configuration and have no prior state.
+func (m schemaMap) configData(ctx context.Context, configVal cty.Value, meta interface{}) (*ResourceData, error) {
+	config, err := terraform.ShimResourceConfigFromValue(configVal, m.CoreConfigSchema())
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := m.Diff(ctx, nil, config, nil, meta, false)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := m.Data(nil, diff)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure GetRawConfig() returns the configuration even when the diff is
+	// empty.
+	data.config = config
+	data.config.CtyValue = configVal
+
+	return data, nil
+}
+
 // DeepCopy returns a copy of this schemaMap. The copy can be safely modified
 // without affecting the original.
 func (m *schemaMap) DeepCopy() schemaMap {
@@ -759,11 +860,8 @@ func (m schemaMapWithIdentity) Diff(
 		if err != nil {
 			return nil, err
 		}
-		for _, k := range rd.UpdatedKeys() {
-			err := m.diff(ctx, k, mc.schemaMap[k], result, rd, false)
-			if err != nil {
-				return nil, err
-			}
+		if err := m.diffUpdatedKeys(ctx, result, rd); err != nil {
+			return nil, err
 		}
 		// copy over identity data (by getting it so we also include changes)
 		// In order to build the final identity attributes, we read the full
@@ -828,11 +926,8 @@ func (m schemaMapWithIdentity) Diff(
 				if err := customizeDiff(ctx, rd, meta); err != nil {
 					return nil, err
 				}
-				for _, k := range rd.UpdatedKeys() {
-					err := m.diff(ctx, k, mc.schemaMap[k], result2, rd, false)
-					if err != nil {
-						return nil, err
-					}
+				if err := m.diffUpdatedKeys(ctx, result2, rd); err != nil {
+					return nil, err
 				}
 				// copy over identity data (by getting it so we also include changes)
 				// In order to build the final identity attributes, we read the full
@@ -914,6 +1009,33 @@ func (m schemaMapWithIdentity) Diff(
 	return result, nil
 }
 
+// diffUpdatedKeys re-calculates the diff of the keys updated by a
+// CustomizeDiff function.
+func (m schemaMap) diffUpdatedKeys(ctx context.Context, diff *terraform.InstanceDiff, rd *ResourceDiff) error {
+	for _, k := range rd.UpdatedKeys() {
+		all := false
+
+		// The shims only apply the elements of a set which are in the diff,
+		// so all elements of a set are diffed when a field nested within one
+		// of its elements was updated.
+		if setKey := rd.enclosingSetKey(k); setKey != "" {
+			k = setKey
+			all = true
+		}
+
+		schema := rd.updatedKeySchema(k)
+		if schema == nil {
+			return fmt.Errorf("%s is not a valid key", k)
+		}
+
+		if err := m.diff(ctx, k, schema, diff, rd, all); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Diff returns the diff for a resource given the schema map,
 // state, and configuration.
 func (m schemaMap) Diff(
@@ -994,6 +1116,25 @@ func (m schemaMap) internalValidate(topSchemaMap schemaMap, attrsOnly bool) erro
 			}
 		case SchemaConfigModeAttr:
 			// anything goes
+		case SchemaConfigModeNestedAttr:
+			r, ok := v.Elem.(*Resource)
+			if !ok {
+				return fmt.Errorf("%s: ConfigMode of nested attribute is allowed only when Elem is *schema.Resource", k)
+			}
+			if v.Type != TypeList && v.Type != TypeSet {
+				return fmt.Errorf("%s: ConfigMode of nested attribute is allowed only for TypeList and TypeSet", k)
+			}
+			if attrsOnly {
+				return fmt.Errorf("%s: ConfigMode of nested attribute cannot be used in child of schema with ConfigMode of attribute", k)
+			}
+			for nk, nv := range r.SchemaMap() {
+				if _, ok := nv.Elem.(*Resource); ok && nv.ConfigMode != SchemaConfigModeAttr && nv.ConfigMode != SchemaConfigModeNestedAttr {
+					return fmt.Errorf("%s.%s: in *schema.Resource with ConfigMode of nested attribute, so must have ConfigMode of attribute or nested attribute", k, nk)
+				}
+			}
+			if schemaMap(r.SchemaMap()).hasWriteOnly() {
+				return fmt.Errorf("%s: ConfigMode of nested attribute cannot contain WriteOnly attributes", k)
+			}
 		case SchemaConfigModeAuto:
 			// Since "Auto" for Elem: *Resource would create a nested block,
 			// and that's impossible inside an attribute, we require it to be
@@ -1071,8 +1212,26 @@ func (m schemaMap) internalValidate(topSchemaMap schemaMap, attrsOnly bool) erro
 			}
 		}
 
-		if v.DiffSuppressOnRefresh && v.DiffSuppressFunc == nil {
-			return fmt.Errorf("%s: cannot set DiffSuppressOnRefresh without DiffSuppressFunc", k)
+		if v.DiffSuppressOnRefresh && v.DiffSuppressFunc == nil && v.SemanticEquals == nil {
+			return fmt.Errorf("%s: cannot set DiffSuppressOnRefresh without DiffSuppressFunc or SemanticEquals", k)
+		}
+
+		if v.Type == TypeDynamic {
+			if v.Elem != nil {
+				return fmt.Errorf("%s: Elem is not valid for TypeDynamic", k)
+			}
+
+			if v.Default != nil || v.DefaultFunc != nil {
+				return fmt.Errorf("%s: Default is not valid for TypeDynamic", k)
+			}
+		}
+
+		if v.Elem == TypeDynamic {
+			return fmt.Errorf("%s: TypeDynamic is not valid as Elem", k)
+		}
+
+		if es, ok := v.Elem.(*Schema); ok && es.Type == TypeDynamic {
+			return fmt.Errorf("%s: TypeDynamic is not valid as Elem", k)
 		}
 
 		if v.Type == TypeList || v.Type == TypeSet {
@@ -1096,12 +1255,22 @@ func (m schemaMap) internalValidate(topSchemaMap schemaMap, attrsOnly bool) erro
 			case *Resource:
 				attrsOnly := attrsOnly || v.ConfigMode == SchemaConfigModeAttr
 
+				for nk, nv := range t.SchemaMap() {
+					if nv.Type == TypeDynamic {
+						return fmt.Errorf("%s.%s: TypeDynamic is only supported for top-level attributes", k, nk)
+					}
+				}
+
 				blockHasWriteOnly := schemaMap(t.SchemaMap()).hasWriteOnly()
 
 				if v.Type == TypeSet && blockHasWriteOnly {
 					return fmt.Errorf("%s: Set Block type cannot contain WriteOnly attributes", k)
 				}
 
+				if v.Type == TypeSet && schemaMap(t.SchemaMap()).hasPlanModifiers() {
+					return fmt.Errorf("%s: Set Block type cannot contain attributes with PlanModifiers", k)
+				}
+
 				if v.Computed && blockHasWriteOnly {
 					return fmt.Errorf("%s: Block types with Computed set to true cannot contain WriteOnly attributes", k)
 				}
@@ -1110,7 +1279,7 @@ func (m schemaMap) internalValidate(topSchemaMap schemaMap, attrsOnly bool) erro
 					return err
 				}
 			case *Schema:
-				bad := t.Computed || t.Optional || t.Required
+				bad := t.Computed || t.Optional || t.Required || len(t.PlanModifiers) > 0 || t.SemanticEquals != nil
 				if bad {
 					return fmt.Errorf(
 						"%s: Elem must have only Type set", k)
@@ -1295,7 +1464,7 @@ func (m schemaMap) diff(
 
 	var err error
 	switch schema.Type {
-	case TypeBool, TypeInt, TypeFloat, TypeString:
+	case TypeBool, TypeInt, TypeFloat, TypeString, TypeDynamic:
 		err = m.diffString(k, schema, unsuppressedDiff, d, all)
 	case TypeList:
 		err = m.diffList(ctx, k, schema, unsuppressedDiff, d, all)
@@ -2478,6 +2647,10 @@ func (m schemaMap) validatePrimitive(
 			})
 		}
 		decoded = n
+	case TypeDynamic:
+		// The value was encoded as a JSON string by the config shims, and
+		// any type is valid.
+		decoded = raw
 	default:
 		panic(fmt.Sprintf("Unknown validation type: %#v", schema.Type))
 	}
@@ -2524,6 +2697,38 @@ func (m schemaMap) validateType(
 	return diags
 }
 
+// hasPlanModifiers returns true if any attribute or block, including nested
+// ones, has PlanModifiers.
+func (m schemaMap) hasPlanModifiers() bool {
+	for _, v := range m {
+		if len(v.PlanModifiers) > 0 {
+			return true
+		}
+
+		if r, ok := v.Elem.(*Resource); ok && schemaMap(r.SchemaMap()).hasPlanModifiers() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasSemanticEquals returns true if any attribute or block, including nested
+// ones, has SemanticEquals.
+func (m schemaMap) hasSemanticEquals() bool {
+	for _, v := range m {
+		if v.SemanticEquals != nil {
+			return true
+		}
+
+		if r, ok := v.Elem.(*Resource); ok && schemaMap(r.SchemaMap()).hasSemanticEquals() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // hasWriteOnly returns true if the schemaMap contains any WriteOnly attributes.
 func (m schemaMap) hasWriteOnly() bool {
 	for _, v := range m {
@@ -2565,7 +2770,7 @@ func (t ValueType) Zero() interface{} {
 		return 0
 	case TypeFloat:
 		return 0.0
-	case TypeString:
+	case TypeString, TypeDynamic:
 		return ""
 	case TypeList:
 		return []interface{}{}
@@ -3151,6 +3151,280 @@ func TestSchemaMap_InternalValidate(t *testing.T) {
 			true,
 		},
 
+		"ConfigMode of nested attribute with Elem *Schema": {
+			map[string]*Schema{
+				"foo": {
+					Type:       TypeList,
+					Optional:   true,
+					ConfigMode: SchemaConfigModeNestedAttr,
+					Elem:       &Schema{Type: TypeString},
+				},
+			},
+			true,
+		},
+
+		"ConfigMode of nested attribute with TypeMap": {
+			map[string]*Schema{
+				"foo": {
+					Type:       TypeMap,
+					Optional:   true,
+					ConfigMode: SchemaConfigModeNestedAttr,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"bar": {
+								Type:     TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+			true,
+		},
+
+		"ConfigMode of nested attribute containing block": {
+			map[string]*Schema{
+				"foo": {
+					Type:       TypeList,
+					Optional:   true,
+					ConfigMode: SchemaConfigModeNestedAttr,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"bar": {
+								Type:     TypeList,
+								Optional: true,
+								Elem: &Resource{
+									Schema: map[string]*Schema{
+										"baz": {
+											Type:     TypeString,
+											Optional: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			true,
+		},
+
+		"ConfigMode of nested attribute containing WriteOnly": {
+			map[string]*Schema{
+				"foo": {
+					Type:       TypeList,
+					Optional:   true,
+					ConfigMode: SchemaConfigModeNestedAttr,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"bar": {
+								Type:      TypeString,
+								Optional:  true,
+								WriteOnly: true,
+							},
+						},
+					},
+				},
+			},
+			true,
+		},
+
+		"PlanModifiers within list block": {
+			map[string]*Schema{
+				"foo": {
+					Type:     TypeList,
+					Optional: true,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"bar": {
+								Type:          TypeString,
+								Computed:      true,
+								PlanModifiers: []PlanModifierFunc{UseStateForUnknown()},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+
+		"PlanModifiers within set block": {
+			map[string]*Schema{
+				"foo": {
+					Type:     TypeSet,
+					Optional: true,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"bar": {
+								Type:          TypeString,
+								Computed:      true,
+								PlanModifiers: []PlanModifierFunc{UseStateForUnknown()},
+							},
+						},
+					},
+				},
+			},
+			true,
+		},
+
+		"PlanModifiers on collection element": {
+			map[string]*Schema{
+				"foo": {
+					Type:     TypeList,
+					Optional: true,
+					Elem: &Schema{
+						Type:          TypeString,
+						PlanModifiers: []PlanModifierFunc{UseStateForUnknown()},
+					},
+				},
+			},
+			true,
+		},
+
+		"SemanticEquals within set block": {
+			map[string]*Schema{
+				"foo": {
+					Type:     TypeSet,
+					Optional: true,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"bar": {
+								Type:     TypeString,
+								Optional: true,
+								SemanticEquals: func(_ context.Context, _, _ cty.Value) (bool, diag.Diagnostics) {
+									return true, nil
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+
+		"SemanticEquals on collection element": {
+			map[string]*Schema{
+				"foo": {
+					Type:     TypeList,
+					Optional: true,
+					Elem: &Schema{
+						Type: TypeString,
+						SemanticEquals: func(_ context.Context, _, _ cty.Value) (bool, diag.Diagnostics) {
+							return true, nil
+						},
+					},
+				},
+			},
+			true,
+		},
+
+		"ConfigMode of nested attribute in child of attribute": {
+			map[string]*Schema{
+				"foo": {
+					Type:       TypeList,
+					Optional:   true,
+					ConfigMode: SchemaConfigModeAttr,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"bar": {
+								Type:       TypeList,
+								Optional:   true,
+								ConfigMode: SchemaConfigModeNestedAttr,
+								Elem: &Resource{
+									Schema: map[string]*Schema{
+										"baz": {
+											Type:     TypeString,
+											Optional: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			true,
+		},
+
+		"ConfigMode of nested attribute": {
+			map[string]*Schema{
+				"foo": {
+					Type:       TypeSet,
+					Optional:   true,
+					ConfigMode: SchemaConfigModeNestedAttr,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"bar": {
+								Type:       TypeList,
+								Optional:   true,
+								MaxItems:   1,
+								ConfigMode: SchemaConfigModeNestedAttr,
+								Elem: &Resource{
+									Schema: map[string]*Schema{
+										"baz": {
+											Type:     TypeString,
+											Computed: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+
+		"TypeDynamic with Elem": {
+			map[string]*Schema{
+				"foo": {
+					Type:     TypeDynamic,
+					Optional: true,
+					Elem:     &Schema{Type: TypeString},
+				},
+			},
+			true,
+		},
+
+		"TypeDynamic as Elem": {
+			map[string]*Schema{
+				"foo": {
+					Type:     TypeList,
+					Optional: true,
+					Elem:     &Schema{Type: TypeDynamic},
+				},
+			},
+			true,
+		},
+
+		"TypeDynamic in nested block": {
+			map[string]*Schema{
+				"foo": {
+					Type:     TypeList,
+					Optional: true,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"bar": {
+								Type:     TypeDynamic,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+			true,
+		},
+
+		"TypeDynamic top-level attribute": {
+			map[string]*Schema{
+				"foo": {
+					Type:     TypeDynamic,
+					Optional: true,
+				},
+			},
+			false,
+		},
+
 		"Missing Type": {
 			map[string]*Schema{
 				"foo": {
@@ -4809,6 +5083,20 @@ func TestSchemaMap_InternalValidate(t *testing.T) {
 			true,
 		},
 
+		"DiffSuppressOnRefresh with SemanticEquals": {
+			map[string]*Schema{
+				"string": {
+					Type:     TypeString,
+					Optional: true,
+					SemanticEquals: func(_ context.Context, _, _ cty.Value) (bool, diag.Diagnostics) {
+						return true, nil
+					},
+					DiffSuppressOnRefresh: true,
+				},
+			},
+			false,
+		},
+
 		"DiffSuppressOnRefresh with DiffSuppressFunc": {
 			map[string]*Schema{
 				"string": {
@@ -0,0 +1,254 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklog"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// applySemanticEquals replaces the values of the attributes and blocks of
+// proposed with their prior value, where their SemanticEquals function
+// reports them as semantically equal. When refresh is true, only attributes
+// and blocks with DiffSuppressOnRefresh are compared. It returns the resulting
+// value and the paths of the replaced values.
+func (m schemaMap) applySemanticEquals(ctx context.Context, prior, proposed cty.Value, refresh bool) (cty.Value, []cty.Path, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var equal []cty.Path
+
+	if prior.IsNull() || !prior.IsKnown() || proposed.IsNull() || !proposed.IsKnown() || !m.hasSemanticEquals() {
+		return proposed, nil, nil
+	}
+
+	result, err := cty.Transform(proposed, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		schema := m.valuePathSchema(path)
+		if schema == nil || diags.HasError() || v.IsNull() || !v.IsWhollyKnown() {
+			return v, nil
+		}
+
+		priorV := valueAtPath(prior, path, v.Type())
+		if priorV.IsNull() || !priorV.IsWhollyKnown() || priorV.RawEquals(v) {
+			return v, nil
+		}
+
+		// The elements of set blocks have no path of their own to compare
+		// them with, so they are matched with the prior elements instead.
+		if r, ok := schema.Elem.(*Resource); ok && schema.Type == TypeSet && schemaMap(r.SchemaMap()).hasSemanticEquals() {
+			var setDiags diag.Diagnostics
+
+			v, setDiags = schemaMap(r.SchemaMap()).matchSetElements(ctx, path, priorV, v, refresh)
+			diags = append(diags, setDiags...)
+
+			if v.RawEquals(priorV) {
+				equal = append(equal, path.Copy())
+
+				return v, nil
+			}
+		}
+
+		if schema.SemanticEquals == nil || (refresh && !schema.DiffSuppressOnRefresh) {
+			return v, nil
+		}
+
+		logField := map[string]interface{}{logging.KeyAttributePath: ctyPathToFlatmapPath(path)}
+
+		logging.HelperSchemaTrace(ctx, "Calling downstream", logField)
+		isEqual, eqDiags := schema.SemanticEquals(ctx, priorV, v)
+		logging.HelperSchemaTrace(ctx, "Called downstream", logField)
+
+		for i := range eqDiags {
+			if eqDiags[i].AttributePath == nil {
+				eqDiags[i].AttributePath = path.Copy()
+			}
+		}
+
+		diags = append(diags, eqDiags...)
+
+		if eqDiags.HasError() || !isEqual {
+			return v, nil
+		}
+
+		logging.HelperSchemaDebug(ctx, "Ignoring change due to SemanticEquals", logField)
+
+		equal = append(equal, path.Copy())
+
+		return priorV, nil
+	})
+	if err != nil {
+		return proposed, nil, append(diags, diag.FromErr(err)...)
+	}
+
+	return result, equal, diags
+}
+
+// matchSetElements replaces each element of the proposed value of the set
+// block with the given path with an element of its prior value, if they are
+// equal once the SemanticEquals of the attributes and blocks of the element
+// are applied. Elements which are equal to a prior element are kept as is.
+func (m schemaMap) matchSetElements(ctx context.Context, path cty.Path, prior, proposed cty.Value, refresh bool) (cty.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	priorElems := prior.AsValueSlice()
+	matched := make([]bool, len(priorElems))
+
+	var elems, unmatched []cty.Value
+
+	for _, elem := range proposed.AsValueSlice() {
+		i := matchingElement(priorElems, matched, elem)
+		if i < 0 {
+			unmatched = append(unmatched, elem)
+			continue
+		}
+
+		matched[i] = true
+		elems = append(elems, elem)
+	}
+
+	for _, elem := range unmatched {
+		match := elem
+
+		for i, priorElem := range priorElems {
+			if matched[i] {
+				continue
+			}
+
+			result, _, eqDiags := m.applySemanticEquals(ctx, priorElem, elem, refresh)
+
+			for j := range eqDiags {
+				elemPath := path.Copy().Index(elem)
+				eqDiags[j].AttributePath = append(elemPath, eqDiags[j].AttributePath...)
+			}
+
+			diags = append(diags, eqDiags...)
+
+			if diags.HasError() {
+				return proposed, diags
+			}
+
+			if result.RawEquals(priorElem) {
+				matched[i] = true
+				match = priorElem
+
+				break
+			}
+		}
+
+		elems = append(elems, match)
+	}
+
+	if len(elems) == 0 {
+		return proposed, diags
+	}
+
+	return cty.SetVal(elems), diags
+}
+
+// matchingElement returns the index of the first element of elems which is
+// not yet matched and equal to elem, or -1 if there is none.
+func matchingElement(elems []cty.Value, matched []bool, elem cty.Value) int {
+	for i := range elems {
+		if !matched[i] && elems[i].RawEquals(elem) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// handleSemanticEqualsOnRefresh visits each of the attributes and blocks
+// whose schema sets both SemanticEquals and DiffSuppressOnRefresh and, if
+// the new value is semantically equal to the old one, overwrites the new
+// value with the old one, in-place.
+func (m schemaMapWithIdentity) handleSemanticEqualsOnRefresh(ctx context.Context, oldState, newState *terraform.InstanceState) diag.Diagnostics {
+	if newState == nil || oldState == nil || !m.schemaMap.hasSemanticEquals() {
+		return nil // nothing to do, then
+	}
+
+	block := m.schemaMap.CoreConfigSchema()
+
+	oldVal, err := oldState.AttrsAsObjectValueBlock(block)
+	if err != nil {
+		tfsdklog.Warn(ctx, fmt.Sprintf("schemaMap.handleSemanticEqualsOnRefresh failed to convert old state: %s", err))
+		return nil
+	}
+
+	newVal, err := newState.AttrsAsObjectValueBlock(block)
+	if err != nil {
+		tfsdklog.Warn(ctx, fmt.Sprintf("schemaMap.handleSemanticEqualsOnRefresh failed to convert new state: %s", err))
+		return nil
+	}
+
+	_, equal, diags := m.schemaMap.applySemanticEquals(ctx, oldVal, newVal, true)
+
+	for _, path := range equal {
+		key := m.schemaMap.flatmapKey(path)
+
+		for k := range newState.Attributes {
+			if k == key || strings.HasPrefix(k, key+".") {
+				delete(newState.Attributes, k)
+			}
+		}
+
+		for k, v := range oldState.Attributes {
+			if k == key || strings.HasPrefix(k, key+".") {
+				newState.Attributes[k] = v // keep the old value, then
+			}
+		}
+	}
+
+	return diags
+}
+
+// flatmapKey returns the flatmap key of the attribute or block with the given
+// path of a resource value, such as disk.0.size, including the index of
+// nested attributes of a single object, which are lists of one element in the
+// flatmap representation.
+func (m schemaMap) flatmapKey(path cty.Path) string {
+	var parts []string
+
+	current := m
+
+	for i, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			parts = append(parts, step.Name)
+
+			s, ok := current[step.Name]
+			if !ok {
+				continue
+			}
+
+			r, ok := s.Elem.(*Resource)
+			if !ok {
+				continue
+			}
+
+			current = r.SchemaMap()
+
+			if i < len(path)-1 {
+				if _, ok := path[i+1].(cty.IndexStep); !ok {
+					parts = append(parts, "0")
+				}
+			}
+		case cty.IndexStep:
+			if step.Key.Type() == cty.Number {
+				idx, _ := step.Key.AsBigFloat().Int64()
+				parts = append(parts, strconv.FormatInt(idx, 10))
+			} else {
+				parts = append(parts, step.Key.AsString())
+			}
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,459 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestSchemaMapApplySemanticEquals(t *testing.T) {
+	t.Parallel()
+
+	jsonEquals := func(_ context.Context, prior, proposed cty.Value) (bool, diag.Diagnostics) {
+		var priorJSON, proposedJSON interface{}
+
+		if err := json.Unmarshal([]byte(prior.AsString()), &priorJSON); err != nil {
+			return false, diag.FromErr(err)
+		}
+
+		if err := json.Unmarshal([]byte(proposed.AsString()), &proposedJSON); err != nil {
+			return false, diag.FromErr(err)
+		}
+
+		return cmp.Equal(priorJSON, proposedJSON), nil
+	}
+
+	caseInsensitiveEquals := func(_ context.Context, prior, proposed cty.Value) (bool, diag.Diagnostics) {
+		if prior.LengthInt() != proposed.LengthInt() {
+			return false, nil
+		}
+
+		for i, v := range prior.AsValueSlice() {
+			if !strings.EqualFold(v.AsString(), proposed.Index(cty.NumberIntVal(int64(i))).AsString()) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+
+	m := schemaMap{
+		"policy": {
+			Type:           TypeString,
+			Optional:       true,
+			SemanticEquals: jsonEquals,
+		},
+		"names": {
+			Type:           TypeList,
+			Optional:       true,
+			Elem:           &Schema{Type: TypeString},
+			SemanticEquals: caseInsensitiveEquals,
+		},
+		"rule": {
+			Type:     TypeList,
+			Optional: true,
+			Elem: &Resource{
+				Schema: map[string]*Schema{
+					"document": {
+						Type:                  TypeString,
+						Optional:              true,
+						SemanticEquals:        jsonEquals,
+						DiffSuppressOnRefresh: true,
+					},
+				},
+			},
+		},
+	}
+
+	ty := cty.Object(map[string]cty.Type{
+		"policy": cty.String,
+		"names":  cty.List(cty.String),
+		"rule": cty.List(cty.Object(map[string]cty.Type{
+			"document": cty.String,
+		})),
+	})
+
+	obj := func(policy string, names []string, document cty.Value) cty.Value {
+		var nameVals []cty.Value
+		for _, n := range names {
+			nameVals = append(nameVals, cty.StringVal(n))
+		}
+
+		return cty.ObjectVal(map[string]cty.Value{
+			"policy": cty.StringVal(policy),
+			"names":  cty.ListVal(nameVals),
+			"rule": cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"document": document,
+				}),
+			}),
+		})
+	}
+
+	prior := obj(`{"a":1}`, []string{"a", "b"}, cty.StringVal(`{"b":2}`))
+
+	testCases := map[string]struct {
+		prior         cty.Value
+		proposed      cty.Value
+		refresh       bool
+		expected      cty.Value
+		expectedEqual []cty.Path
+	}{
+		"equal": {
+			prior:    prior,
+			proposed: obj(`{ "a": 1 }`, []string{"A", "B"}, cty.StringVal(`{ "b": 2 }`)),
+			expected: prior,
+			expectedEqual: []cty.Path{
+				cty.GetAttrPath("names"),
+				cty.GetAttrPath("policy"),
+				cty.GetAttrPath("rule").IndexInt(0).GetAttr("document"),
+			},
+		},
+		"not equal": {
+			prior:    prior,
+			proposed: obj(`{"a":2}`, []string{"A", "C"}, cty.StringVal(`{"b":3}`)),
+			expected: obj(`{"a":2}`, []string{"A", "C"}, cty.StringVal(`{"b":3}`)),
+		},
+		"unknown": {
+			prior:    prior,
+			proposed: obj(`{ "a": 1 }`, []string{"a", "b"}, cty.UnknownVal(cty.String)),
+			expected: obj(`{"a":1}`, []string{"a", "b"}, cty.UnknownVal(cty.String)),
+			expectedEqual: []cty.Path{
+				cty.GetAttrPath("policy"),
+			},
+		},
+		"create": {
+			prior:    cty.NullVal(ty),
+			proposed: obj(`{ "a": 1 }`, []string{"A", "B"}, cty.StringVal(`{ "b": 2 }`)),
+			expected: obj(`{ "a": 1 }`, []string{"A", "B"}, cty.StringVal(`{ "b": 2 }`)),
+		},
+		"refresh": {
+			prior:    prior,
+			proposed: obj(`{ "a": 1 }`, []string{"A", "B"}, cty.StringVal(`{ "b": 2 }`)),
+			refresh:  true,
+			expected: obj(`{ "a": 1 }`, []string{"A", "B"}, cty.StringVal(`{"b":2}`)),
+			expectedEqual: []cty.Path{
+				cty.GetAttrPath("rule").IndexInt(0).GetAttr("document"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, equal, diags := m.applySemanticEquals(context.Background(), tc.prior, tc.proposed, tc.refresh)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if !got.RawEquals(tc.expected) {
+				t.Errorf("expected %#v, got %#v", tc.expected, got)
+			}
+
+			sortPaths := cmpopts.SortSlices(func(a, b cty.Path) bool { return ctyPathToFlatmapPath(a) < ctyPathToFlatmapPath(b) })
+
+			if diff := cmp.Diff(tc.expectedEqual, equal, sortPaths, cmp.Comparer(func(a, b cty.Path) bool { return a.Equals(b) })); diff != "" {
+				t.Errorf("unexpected paths difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaMapApplySemanticEquals_diagnostics(t *testing.T) {
+	t.Parallel()
+
+	m := schemaMap{
+		"foo": {
+			Type:     TypeString,
+			Optional: true,
+			SemanticEquals: func(_ context.Context, _, _ cty.Value) (bool, diag.Diagnostics) {
+				return true, diag.Errorf("invalid foo")
+			},
+		},
+	}
+
+	prior := cty.ObjectVal(map[string]cty.Value{
+		"foo": cty.StringVal("bar"),
+	})
+	proposed := cty.ObjectVal(map[string]cty.Value{
+		"foo": cty.StringVal("baz"),
+	})
+
+	got, equal, diags := m.applySemanticEquals(context.Background(), prior, proposed, false)
+
+	expected := diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       "invalid foo",
+			AttributePath: cty.GetAttrPath("foo"),
+		},
+	}
+
+	if diff := cmp.Diff(expected, diags, cmp.Comparer(func(a, b cty.Path) bool { return a.Equals(b) })); diff != "" {
+		t.Errorf("unexpected diagnostics difference: %s", diff)
+	}
+
+	if !got.RawEquals(proposed) {
+		t.Errorf("expected %#v, got %#v", proposed, got)
+	}
+
+	if len(equal) > 0 {
+		t.Errorf("unexpected paths: %#v", equal)
+	}
+}
+
+func TestSchemaMapApplySemanticEquals_setBlock(t *testing.T) {
+	t.Parallel()
+
+	m := schemaMap{
+		"statement": {
+			Type:     TypeSet,
+			Optional: true,
+			Elem: &Resource{
+				Schema: map[string]*Schema{
+					"sid": {
+						Type:     TypeString,
+						Optional: true,
+					},
+					"document": {
+						Type:     TypeString,
+						Optional: true,
+						SemanticEquals: func(_ context.Context, prior, proposed cty.Value) (bool, diag.Diagnostics) {
+							return strings.EqualFold(prior.AsString(), proposed.AsString()), nil
+						},
+					},
+				},
+			},
+		},
+	}
+
+	obj := func(statements ...[2]string) cty.Value {
+		var elems []cty.Value
+		for _, s := range statements {
+			elems = append(elems, cty.ObjectVal(map[string]cty.Value{
+				"sid":      cty.StringVal(s[0]),
+				"document": cty.StringVal(s[1]),
+			}))
+		}
+
+		return cty.ObjectVal(map[string]cty.Value{
+			"statement": cty.SetVal(elems),
+		})
+	}
+
+	prior := obj([2]string{"one", "allow"}, [2]string{"two", "deny"})
+
+	testCases := map[string]struct {
+		proposed      cty.Value
+		refresh       bool
+		expected      cty.Value
+		expectedEqual []cty.Path
+	}{
+		"equal": {
+			proposed: obj([2]string{"one", "allow"}, [2]string{"two", "DENY"}),
+			expected: prior,
+			expectedEqual: []cty.Path{
+				cty.GetAttrPath("statement"),
+			},
+		},
+		"some equal": {
+			proposed: obj([2]string{"one", "ALLOW"}, [2]string{"three", "DENY"}),
+			expected: obj([2]string{"one", "allow"}, [2]string{"three", "DENY"}),
+		},
+		"not equal": {
+			proposed: obj([2]string{"one", "deny"}, [2]string{"two", "allow"}),
+			expected: obj([2]string{"one", "deny"}, [2]string{"two", "allow"}),
+		},
+		"refresh": {
+			proposed: obj([2]string{"one", "allow"}, [2]string{"two", "DENY"}),
+			refresh:  true,
+			expected: obj([2]string{"one", "allow"}, [2]string{"two", "DENY"}),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, equal, diags := m.applySemanticEquals(context.Background(), prior, tc.proposed, tc.refresh)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if !got.RawEquals(tc.expected) {
+				t.Errorf("expected %#v, got %#v", tc.expected, got)
+			}
+
+			if diff := cmp.Diff(tc.expectedEqual, equal, cmp.Comparer(func(a, b cty.Path) bool { return a.Equals(b) })); diff != "" {
+				t.Errorf("unexpected paths difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaMapFlatmapKey(t *testing.T) {
+	t.Parallel()
+
+	m := schemaMap{
+		"disk": {
+			Type:     TypeList,
+			Optional: true,
+			Elem: &Resource{
+				Schema: map[string]*Schema{
+					"size": {
+						Type:     TypeInt,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"network": {
+			Type:       TypeList,
+			Optional:   true,
+			MaxItems:   1,
+			ConfigMode: SchemaConfigModeNestedAttr,
+			Elem: &Resource{
+				Schema: map[string]*Schema{
+					"cidr": {
+						Type:     TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		path     cty.Path
+		expected string
+	}{
+		"attribute": {
+			path:     cty.GetAttrPath("disk"),
+			expected: "disk",
+		},
+		"list element attribute": {
+			path:     cty.GetAttrPath("disk").IndexInt(1).GetAttr("size"),
+			expected: "disk.1.size",
+		},
+		"single nested attribute": {
+			path:     cty.GetAttrPath("network").GetAttr("cidr"),
+			expected: "network.0.cidr",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := m.flatmapKey(tc.path); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPlanResourceChange_semanticEquals(t *testing.T) {
+	t.Parallel()
+
+	r := &Resource{
+		Schema: map[string]*Schema{
+			"names": {
+				Type:     TypeList,
+				Optional: true,
+				Elem:     &Schema{Type: TypeString},
+				SemanticEquals: func(_ context.Context, prior, proposed cty.Value) (bool, diag.Diagnostics) {
+					return prior.LengthInt() == proposed.LengthInt(), nil
+				},
+			},
+			"size": {
+				Type:     TypeInt,
+				Optional: true,
+			},
+		},
+	}
+
+	server := NewGRPCProviderServer(&Provider{
+		ResourcesMap: map[string]*Resource{
+			"test": r,
+		},
+	})
+
+	schema := r.CoreConfigSchema()
+
+	priorVal := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.StringVal("foo"),
+		"names": cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+		"size":  cty.NumberIntVal(10),
+	})
+	priorState, err := msgpack.Marshal(priorVal, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proposedVal := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.StringVal("foo"),
+		"names": cty.ListVal([]cty.Value{cty.StringVal("A"), cty.StringVal("B")}),
+		"size":  cty.NumberIntVal(5),
+	})
+	proposedState, err := msgpack.Marshal(proposedVal, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configVal := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.NullVal(cty.String),
+		"names": cty.ListVal([]cty.Value{cty.StringVal("A"), cty.StringVal("B")}),
+		"size":  cty.NumberIntVal(5),
+	})
+	config, err := msgpack.Marshal(configVal, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName: "test",
+		PriorState: &tfprotov5.DynamicValue{
+			MsgPack: priorState,
+		},
+		ProposedNewState: &tfprotov5.DynamicValue{
+			MsgPack: proposedState,
+		},
+		Config: &tfprotov5.DynamicValue{
+			MsgPack: config,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	plannedStateVal, err := msgpack.Unmarshal(resp.PlannedState.MsgPack, schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.StringVal("foo"),
+		"names": cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+		"size":  cty.NumberIntVal(5),
+	})
+
+	if !cmp.Equal(expected, plannedStateVal, valueComparer) {
+		t.Fatal(cmp.Diff(expected, plannedStateVal, valueComparer))
+	}
+}
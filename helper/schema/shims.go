@@ -39,7 +39,10 @@ func diffFromValues(ctx context.Context, prior, planned, config cty.Value, res *
 
 	configSchema := res.CoreConfigSchema()
 
-	cfg := terraform.NewResourceConfigShimmed(planned, configSchema)
+	cfg, err := terraform.ShimResourceConfigFromValue(planned, configSchema)
+	if err != nil {
+		return nil, err
+	}
 	removeConfigUnknowns(cfg.Config)
 	removeConfigUnknowns(cfg.Raw)
 
@@ -20,6 +20,7 @@ const (
 	TypeList
 	TypeMap
 	TypeSet
+	TypeDynamic
 	typeObject
 )
 
@@ -16,12 +16,13 @@ func _() {
 	_ = x[TypeList-5]
 	_ = x[TypeMap-6]
 	_ = x[TypeSet-7]
-	_ = x[typeObject-8]
+	_ = x[TypeDynamic-8]
+	_ = x[typeObject-9]
 }
 
-const _ValueType_name = "TypeInvalidTypeBoolTypeIntTypeFloatTypeStringTypeListTypeMapTypeSettypeObject"
+const _ValueType_name = "TypeInvalidTypeBoolTypeIntTypeFloatTypeStringTypeListTypeMapTypeSetTypeDynamictypeObject"
 
-var _ValueType_index = [...]uint8{0, 11, 19, 26, 35, 45, 53, 60, 67, 77}
+var _ValueType_index = [...]uint8{0, 11, 19, 26, 35, 45, 53, 60, 67, 78, 88}
 
 func (i ValueType) String() string {
 	if i < 0 || i >= ValueType(len(_ValueType_index)-1) {
@@ -0,0 +1,34 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package statecheck contains the StateCheck interface for checking the
+// Terraform state after a test step is applied, and the built-in checks
+// which implement it.
+//
+// Unlike the TestCheckFunc functions of the resource package, which compare
+// the flatmap strings of the SDK state, state checks compare the typed
+// values of the terraform show -json state format with the checks of the
+// knownvalue package. Values are addressed with cty paths, for example:
+//
+//	resource.TestStep{
+//		Config: `...`,
+//		ConfigStateChecks: []statecheck.StateCheck{
+//			statecheck.ExpectKnownValue(
+//				"examplecloud_thing.test",
+//				cty.GetAttrPath("rule").IndexInt(0).GetAttr("port"),
+//				knownvalue.Int64Between(1, 1024),
+//			),
+//			statecheck.ExpectKnownValue(
+//				"examplecloud_thing.test",
+//				cty.GetAttrPath("tags"),
+//				knownvalue.MapKeysExact("env", "name"),
+//			),
+//			statecheck.ExpectIdentityValue(
+//				"examplecloud_thing.test",
+//				cty.GetAttrPath("id"),
+//				knownvalue.NotNull(),
+//			),
+//			statecheck.ExpectKnownOutputValue("id", knownvalue.NotNull()),
+//		},
+//	}
+package statecheck
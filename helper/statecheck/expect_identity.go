@@ -0,0 +1,99 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package statecheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/knownvalue"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/tfjsonpath"
+)
+
+var _ StateCheck = expectIdentity{}
+
+type expectIdentity struct {
+	addr     string
+	identity map[string]knownvalue.Check
+}
+
+// CheckState implements the StateCheck interface.
+func (e expectIdentity) CheckState(_ context.Context, state *tfjson.State) error {
+	identity, err := stateResourceIdentity(state, e.addr)
+	if err != nil {
+		return err
+	}
+
+	if err := knownvalue.ObjectExact(e.identity).CheckValue(identity); err != nil {
+		return fmt.Errorf("%s - error checking identity: %w", e.addr, err)
+	}
+
+	return nil
+}
+
+// ExpectIdentity returns a state check that asserts that the identity of
+// the resource instance with the given address has exactly the attributes of
+// the given checks, with values matched by the checks.
+func ExpectIdentity(addr string, identity map[string]knownvalue.Check) StateCheck {
+	return expectIdentity{
+		addr:     addr,
+		identity: identity,
+	}
+}
+
+var _ StateCheck = expectIdentityValue{}
+
+type expectIdentityValue struct {
+	addr  string
+	path  cty.Path
+	check knownvalue.Check
+}
+
+// CheckState implements the StateCheck interface.
+func (e expectIdentityValue) CheckState(_ context.Context, state *tfjson.State) error {
+	identity, err := stateResourceIdentity(state, e.addr)
+	if err != nil {
+		return err
+	}
+
+	value, err := tfjsonpath.Traverse(identity, e.path)
+	if err != nil {
+		return fmt.Errorf("%s - identity %w", e.addr, err)
+	}
+
+	if err := e.check.CheckValue(value); err != nil {
+		return fmt.Errorf("%s - error checking value for identity attribute at path %s: %w", e.addr, tfjsonpath.String(e.path), err)
+	}
+
+	return nil
+}
+
+// ExpectIdentityValue returns a state check that asserts that the value at
+// the given path of the identity of the resource instance with the given
+// address passes the given known value check.
+func ExpectIdentityValue(addr string, path cty.Path, check knownvalue.Check) StateCheck {
+	return expectIdentityValue{
+		addr:  addr,
+		path:  path,
+		check: check,
+	}
+}
+
+// stateResourceIdentity returns the identity of the resource instance with
+// the given address.
+func stateResourceIdentity(state *tfjson.State, addr string) (map[string]interface{}, error) {
+	rs, err := stateResource(state, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if rs.IdentityValues == nil {
+		return nil, fmt.Errorf("%s - Identity not found in state", addr)
+	}
+
+	return rs.IdentityValues, nil
+}
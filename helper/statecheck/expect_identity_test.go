@@ -0,0 +1,141 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package statecheck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/knownvalue"
+)
+
+func TestExpectIdentity(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Addr          string
+		Identity      map[string]knownvalue.Check
+		ExpectedError string
+	}{
+		"exact": {
+			Addr: "examplecloud_thing.test",
+			Identity: map[string]knownvalue.Check{
+				"id":     knownvalue.StringExact("thing-1"),
+				"region": knownvalue.StringExact("us-east-1"),
+				"zones":  knownvalue.ListSizeExact(2),
+			},
+		},
+		"missing-attribute": {
+			Addr: "examplecloud_thing.test",
+			Identity: map[string]knownvalue.Check{
+				"id":    knownvalue.StringExact("thing-1"),
+				"zones": knownvalue.ListSizeExact(2),
+			},
+			ExpectedError: `examplecloud_thing.test - error checking identity: unexpected keys ["region"] for ObjectExact check`,
+		},
+		"check-error": {
+			Addr: "examplecloud_thing.test",
+			Identity: map[string]knownvalue.Check{
+				"id":     knownvalue.StringExact("thing-2"),
+				"region": knownvalue.StringExact("us-east-1"),
+				"zones":  knownvalue.ListSizeExact(2),
+			},
+			ExpectedError: `examplecloud_thing.test - error checking identity: object key "id": expected value "thing-2" for StringExact check, got: "thing-1"`,
+		},
+		"identity-not-found": {
+			Addr: "module.child.examplecloud_thing.test[0]",
+			Identity: map[string]knownvalue.Check{
+				"id": knownvalue.StringExact("thing-2"),
+			},
+			ExpectedError: "module.child.examplecloud_thing.test[0] - Identity not found in state",
+		},
+		"resource-not-found": {
+			Addr:          "examplecloud_thing.missing",
+			Identity:      map[string]knownvalue.Check{},
+			ExpectedError: "examplecloud_thing.missing - Resource not found in state",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ExpectIdentity(tc.Addr, tc.Identity).CheckState(context.Background(), testState())
+
+			if tc.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tc.ExpectedError {
+				t.Fatalf("expected error %q, got: %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestExpectIdentityValue(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Addr          string
+		Path          cty.Path
+		Check         knownvalue.Check
+		ExpectedError string
+	}{
+		"string": {
+			Addr:  "examplecloud_thing.test",
+			Path:  cty.GetAttrPath("region"),
+			Check: knownvalue.StringExact("us-east-1"),
+		},
+		"list-element": {
+			Addr:  "examplecloud_thing.test",
+			Path:  cty.GetAttrPath("zones").IndexInt(1),
+			Check: knownvalue.StringExact("b"),
+		},
+		"check-error": {
+			Addr:          "examplecloud_thing.test",
+			Path:          cty.GetAttrPath("id"),
+			Check:         knownvalue.StringExact("thing-2"),
+			ExpectedError: `examplecloud_thing.test - error checking value for identity attribute at path id: expected value "thing-2" for StringExact check, got: "thing-1"`,
+		},
+		"path-not-found": {
+			Addr:          "examplecloud_thing.test",
+			Path:          cty.GetAttrPath("missing"),
+			Check:         knownvalue.Null(),
+			ExpectedError: `examplecloud_thing.test - identity path missing: "missing" not found`,
+		},
+		"identity-not-found": {
+			Addr:          "module.child.examplecloud_thing.test[0]",
+			Path:          cty.GetAttrPath("id"),
+			Check:         knownvalue.NotNull(),
+			ExpectedError: "module.child.examplecloud_thing.test[0] - Identity not found in state",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ExpectIdentityValue(tc.Addr, tc.Path, tc.Check).CheckState(context.Background(), testState())
+
+			if tc.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tc.ExpectedError {
+				t.Fatalf("expected error %q, got: %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
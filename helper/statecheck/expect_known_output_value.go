@@ -0,0 +1,68 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package statecheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/knownvalue"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/tfjsonpath"
+)
+
+var _ StateCheck = expectKnownOutputValue{}
+
+type expectKnownOutputValue struct {
+	name  string
+	path  cty.Path
+	check knownvalue.Check
+}
+
+// CheckState implements the StateCheck interface.
+func (e expectKnownOutputValue) CheckState(_ context.Context, state *tfjson.State) error {
+	output, err := stateOutput(state, e.name)
+	if err != nil {
+		return err
+	}
+
+	value, err := tfjsonpath.Traverse(output.Value, e.path)
+	if err != nil {
+		return fmt.Errorf("%s - %w", e.name, err)
+	}
+
+	if err := e.check.CheckValue(value); err != nil {
+		if len(e.path) == 0 {
+			return fmt.Errorf("%s - error checking value for output: %w", e.name, err)
+		}
+
+		return fmt.Errorf("%s - error checking value for output at path %s: %w", e.name, tfjsonpath.String(e.path), err)
+	}
+
+	return nil
+}
+
+// ExpectKnownOutputValue returns a state check that asserts that the value of
+// the root module output with the given name passes the given known value
+// check.
+func ExpectKnownOutputValue(name string, check knownvalue.Check) StateCheck {
+	return expectKnownOutputValue{
+		name:  name,
+		check: check,
+	}
+}
+
+// ExpectKnownOutputValueAtPath returns a state check that asserts that the
+// value at the given path of the root module output with the given name
+// passes the given known value check. It is useful for outputs of object or
+// collection values.
+func ExpectKnownOutputValueAtPath(name string, path cty.Path, check knownvalue.Check) StateCheck {
+	return expectKnownOutputValue{
+		name:  name,
+		path:  path,
+		check: check,
+	}
+}
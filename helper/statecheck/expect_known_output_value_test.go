@@ -0,0 +1,71 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package statecheck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/knownvalue"
+)
+
+func TestExpectKnownOutputValue(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		StateCheck    StateCheck
+		ExpectedError string
+	}{
+		"value": {
+			StateCheck: ExpectKnownOutputValue("id", knownvalue.StringExact("thing-1")),
+		},
+		"object": {
+			StateCheck: ExpectKnownOutputValue("thing", knownvalue.ObjectExact(map[string]knownvalue.Check{
+				"name": knownvalue.StringExact("test"),
+				"tags": knownvalue.MapSizeExact(1),
+			})),
+		},
+		"path": {
+			StateCheck: ExpectKnownOutputValueAtPath("thing", cty.GetAttrPath("tags").IndexString("env"), knownvalue.StringExact("prod")),
+		},
+		"check-error": {
+			StateCheck:    ExpectKnownOutputValue("id", knownvalue.Null()),
+			ExpectedError: `id - error checking value for output: expected value null for Null check, got: "thing-1"`,
+		},
+		"path-check-error": {
+			StateCheck:    ExpectKnownOutputValueAtPath("thing", cty.GetAttrPath("name"), knownvalue.StringExact("other")),
+			ExpectedError: `thing - error checking value for output at path name: expected value "other" for StringExact check, got: "test"`,
+		},
+		"path-not-found": {
+			StateCheck:    ExpectKnownOutputValueAtPath("thing", cty.GetAttrPath("missing"), knownvalue.Null()),
+			ExpectedError: `thing - path missing: "missing" not found`,
+		},
+		"output-not-found": {
+			StateCheck:    ExpectKnownOutputValue("missing", knownvalue.NotNull()),
+			ExpectedError: "missing - Output not found in state",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.StateCheck.CheckState(context.Background(), testState())
+
+			if tc.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tc.ExpectedError {
+				t.Fatalf("expected error %q, got: %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
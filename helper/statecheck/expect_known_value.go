@@ -0,0 +1,53 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package statecheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/knownvalue"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/tfjsonpath"
+)
+
+var _ StateCheck = expectKnownValue{}
+
+type expectKnownValue struct {
+	addr  string
+	path  cty.Path
+	check knownvalue.Check
+}
+
+// CheckState implements the StateCheck interface.
+func (e expectKnownValue) CheckState(_ context.Context, state *tfjson.State) error {
+	rs, err := stateResource(state, e.addr)
+	if err != nil {
+		return err
+	}
+
+	value, err := tfjsonpath.Traverse(rs.AttributeValues, e.path)
+	if err != nil {
+		return fmt.Errorf("%s - %w", e.addr, err)
+	}
+
+	if err := e.check.CheckValue(value); err != nil {
+		return fmt.Errorf("%s - error checking value for attribute at path %s: %w", e.addr, tfjsonpath.String(e.path), err)
+	}
+
+	return nil
+}
+
+// ExpectKnownValue returns a state check that asserts that the value at the
+// given path of the resource instance with the given address passes the
+// given known value check.
+func ExpectKnownValue(addr string, path cty.Path, check knownvalue.Check) StateCheck {
+	return expectKnownValue{
+		addr:  addr,
+		path:  path,
+		check: check,
+	}
+}
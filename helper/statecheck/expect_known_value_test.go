@@ -0,0 +1,97 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package statecheck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/knownvalue"
+)
+
+func TestExpectKnownValue(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Addr          string
+		Path          cty.Path
+		Check         knownvalue.Check
+		ExpectedError string
+	}{
+		"string": {
+			Addr:  "examplecloud_thing.test",
+			Path:  cty.GetAttrPath("name"),
+			Check: knownvalue.StringExact("test"),
+		},
+		"null": {
+			Addr:  "examplecloud_thing.test",
+			Path:  cty.GetAttrPath("description"),
+			Check: knownvalue.Null(),
+		},
+		"list-element-attribute": {
+			Addr:  "examplecloud_thing.test",
+			Path:  cty.GetAttrPath("rule").IndexInt(1).GetAttr("port"),
+			Check: knownvalue.Int64Between(1, 1024),
+		},
+		"set": {
+			Addr: "examplecloud_thing.test",
+			Path: cty.GetAttrPath("rule"),
+			Check: knownvalue.SetPartial([]knownvalue.Check{
+				knownvalue.ObjectPartial(map[string]knownvalue.Check{
+					"port": knownvalue.Int64Exact(80),
+				}),
+			}),
+		},
+		"map-keys": {
+			Addr:  "examplecloud_thing.test",
+			Path:  cty.GetAttrPath("tags"),
+			Check: knownvalue.MapKeysExact("env", "name"),
+		},
+		"child-module": {
+			Addr:  "module.child.examplecloud_thing.test[0]",
+			Path:  cty.GetAttrPath("id"),
+			Check: knownvalue.StringExact("thing-2"),
+		},
+		"check-error": {
+			Addr:          "examplecloud_thing.test",
+			Path:          cty.GetAttrPath("tags").IndexString("env"),
+			Check:         knownvalue.StringExact("dev"),
+			ExpectedError: `examplecloud_thing.test - error checking value for attribute at path tags["env"]: expected value "dev" for StringExact check, got: "prod"`,
+		},
+		"path-not-found": {
+			Addr:          "examplecloud_thing.test",
+			Path:          cty.GetAttrPath("missing"),
+			Check:         knownvalue.Null(),
+			ExpectedError: `examplecloud_thing.test - path missing: "missing" not found`,
+		},
+		"resource-not-found": {
+			Addr:          "examplecloud_thing.missing",
+			Path:          cty.GetAttrPath("id"),
+			Check:         knownvalue.NotNull(),
+			ExpectedError: "examplecloud_thing.missing - Resource not found in state",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ExpectKnownValue(tc.Addr, tc.Path, tc.Check).CheckState(context.Background(), testState())
+
+			if tc.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tc.ExpectedError {
+				t.Fatalf("expected error %q, got: %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
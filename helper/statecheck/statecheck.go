@@ -0,0 +1,61 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package statecheck
+
+import (
+	"context"
+	"fmt"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// StateCheck defines a check of a Terraform state, in the format of the
+// terraform show -json command.
+type StateCheck interface {
+	// CheckState returns an error if the state does not pass the check.
+	CheckState(ctx context.Context, state *tfjson.State) error
+}
+
+// stateResource returns the resource instance with the given address, such
+// as examplecloud_thing.test or module.child.examplecloud_thing.test[0].
+func stateResource(state *tfjson.State, addr string) (*tfjson.StateResource, error) {
+	if state != nil && state.Values != nil {
+		if rs := moduleResource(state.Values.RootModule, addr); rs != nil {
+			return rs, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s - Resource not found in state", addr)
+}
+
+func moduleResource(module *tfjson.StateModule, addr string) *tfjson.StateResource {
+	if module == nil {
+		return nil
+	}
+
+	for _, rs := range module.Resources {
+		if rs.Address == addr {
+			return rs
+		}
+	}
+
+	for _, child := range module.ChildModules {
+		if rs := moduleResource(child, addr); rs != nil {
+			return rs
+		}
+	}
+
+	return nil
+}
+
+// stateOutput returns the root module output with the given name.
+func stateOutput(state *tfjson.State, name string) (*tfjson.StateOutput, error) {
+	if state != nil && state.Values != nil {
+		if output, ok := state.Values.Outputs[name]; ok && output != nil {
+			return output, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s - Output not found in state", name)
+}
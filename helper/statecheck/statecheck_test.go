@@ -0,0 +1,78 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package statecheck
+
+import (
+	"encoding/json"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// testState returns a state with an examplecloud_thing.test resource in the
+// root module, a module.child.examplecloud_thing.test[0] resource in a child
+// module and outputs, with numbers decoded as json.Number as in states read
+// by the terraform-exec library.
+func testState() *tfjson.State {
+	return &tfjson.State{
+		Values: &tfjson.StateValues{
+			Outputs: map[string]*tfjson.StateOutput{
+				"id": {
+					Value: "thing-1",
+				},
+				"thing": {
+					Value: map[string]interface{}{
+						"name": "test",
+						"tags": map[string]interface{}{
+							"env": "prod",
+						},
+					},
+				},
+			},
+			RootModule: &tfjson.StateModule{
+				Resources: []*tfjson.StateResource{
+					{
+						Address: "examplecloud_thing.test",
+						AttributeValues: map[string]interface{}{
+							"id":          "thing-1",
+							"name":        "test",
+							"description": nil,
+							"rule": []interface{}{
+								map[string]interface{}{
+									"port":     json.Number("443"),
+									"protocol": "tcp",
+								},
+								map[string]interface{}{
+									"port":     json.Number("80"),
+									"protocol": "tcp",
+								},
+							},
+							"tags": map[string]interface{}{
+								"env":  "prod",
+								"name": "test",
+							},
+						},
+						IdentityValues: map[string]interface{}{
+							"id":     "thing-1",
+							"region": "us-east-1",
+							"zones":  []interface{}{"a", "b"},
+						},
+					},
+				},
+				ChildModules: []*tfjson.StateModule{
+					{
+						Address: "module.child",
+						Resources: []*tfjson.StateResource{
+							{
+								Address: "module.child.examplecloud_thing.test[0]",
+								AttributeValues: map[string]interface{}{
+									"id": "thing-2",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
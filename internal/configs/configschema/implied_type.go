@@ -69,3 +69,32 @@ func (b *Block) ImpliedType() cty.Type {
 
 	return cty.Object(atys)
 }
+
+// ImpliedType returns the cty.Type that would result from decoding a nested
+// attribute value using the receiving object schema.
+func (o *Object) ImpliedType() cty.Type {
+	if o == nil {
+		return cty.EmptyObject
+	}
+
+	atys := make(map[string]cty.Type, len(o.Attributes))
+
+	for name, attrS := range o.Attributes {
+		atys[name] = attrS.Type
+	}
+
+	childType := cty.Object(atys)
+
+	switch o.Nesting {
+	case NestingSingle, NestingGroup:
+		return childType
+	case NestingList:
+		return cty.List(childType)
+	case NestingSet:
+		return cty.Set(childType)
+	case NestingMap:
+		return cty.Map(childType)
+	default:
+		panic("invalid nesting type")
+	}
+}
@@ -125,3 +125,68 @@ func TestBlockImpliedType(t *testing.T) {
 		})
 	}
 }
+
+func TestObjectImpliedType(t *testing.T) {
+	attrs := map[string]*Attribute{
+		"foo": {
+			Type:     cty.String,
+			Optional: true,
+		},
+		"bar": {
+			Type:     cty.Number,
+			Computed: true,
+		},
+	}
+
+	objectType := cty.Object(map[string]cty.Type{
+		"foo": cty.String,
+		"bar": cty.Number,
+	})
+
+	tests := map[string]struct {
+		Schema *Object
+		Want   cty.Type
+	}{
+		"nil": {
+			nil,
+			cty.EmptyObject,
+		},
+		"single": {
+			&Object{
+				Attributes: attrs,
+				Nesting:    NestingSingle,
+			},
+			objectType,
+		},
+		"list": {
+			&Object{
+				Attributes: attrs,
+				Nesting:    NestingList,
+			},
+			cty.List(objectType),
+		},
+		"set": {
+			&Object{
+				Attributes: attrs,
+				Nesting:    NestingSet,
+			},
+			cty.Set(objectType),
+		},
+		"map": {
+			&Object{
+				Attributes: attrs,
+				Nesting:    NestingMap,
+			},
+			cty.Map(objectType),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := test.Schema.ImpliedType()
+			if !got.Equals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
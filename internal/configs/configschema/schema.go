@@ -53,8 +53,17 @@ type Block struct {
 // Attribute represents a configuration attribute, within a block.
 type Attribute struct {
 	// Type is a type specification that the attribute's value must conform to.
+	//
+	// When NestedType is set, Type must be the type implied by NestedType, so
+	// that code which is unaware of nested attributes can treat the attribute
+	// as an attribute of an object or collection of objects type.
 	Type cty.Type
 
+	// NestedType indicates that the attribute is a nested attribute, whose
+	// value is an object or collection of objects with attributes of their
+	// own, each with their own Required, Optional, Computed and other flags.
+	NestedType *Object
+
 	// Description is an English-language description of the purpose and
 	// usage of the attribute. A description should be concise and use only
 	// one or two sentences, leaving full definition to longer-form
@@ -115,6 +124,18 @@ type Attribute struct {
 	OptionalForImport bool
 }
 
+// Object represents the embedding of an object within an attribute.
+type Object struct {
+	// Attributes describes the nested attributes which may appear inside
+	// the object.
+	Attributes map[string]*Attribute
+
+	// Nesting provides the nesting mode for the object, which determines
+	// how the resulting data will be converted into a data structure. Only
+	// NestingSingle, NestingList, NestingSet and NestingMap are valid.
+	Nesting NestingMode
+}
+
 // NestedBlock represents the embedding of one block within another.
 type NestedBlock struct {
 	// Block is the description of the block that's nested.
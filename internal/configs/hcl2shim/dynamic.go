@@ -0,0 +1,219 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package hcl2shim
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+)
+
+// EncodeDynamicValue encodes a value of a dynamically-typed attribute as a
+// JSON string which includes the type of the value, so it can be stored in
+// the legacy flatmap and config representations without losing type
+// information.
+//
+// The given value must be known, but may contain unknown values, such as an
+// object with an unknown attribute. The unknown values are encoded as nulls
+// and their paths are listed in an additional "unknown" property, so that the
+// known parts of the value are kept. Sets containing unknown values are
+// encoded as wholly unknown sets.
+func EncodeDynamicValue(v cty.Value) (string, error) {
+	if !v.IsKnown() {
+		return "", fmt.Errorf("value must be known")
+	}
+
+	var unknown [][]interface{}
+
+	if !v.IsWhollyKnown() {
+		v = nullUnknownValues(v, nil, &unknown)
+	}
+
+	b, err := ctyjson.Marshal(v, cty.DynamicPseudoType)
+	if err != nil {
+		return "", err
+	}
+
+	if len(unknown) == 0 {
+		return string(b), nil
+	}
+
+	var encoded map[string]interface{}
+
+	if err := json.Unmarshal(b, &encoded); err != nil {
+		return "", err
+	}
+
+	encoded["unknown"] = unknown
+
+	b, err = json.Marshal(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// DecodeDynamicValue is the opposite of EncodeDynamicValue. An empty string
+// decodes to a null value.
+func DecodeDynamicValue(s string) (cty.Value, error) {
+	if s == "" {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
+
+	var encoded struct {
+		Value   json.RawMessage `json:"value"`
+		Type    json.RawMessage `json:"type"`
+		Unknown [][]interface{} `json:"unknown"`
+	}
+
+	if err := json.Unmarshal([]byte(s), &encoded); err != nil || encoded.Unknown == nil {
+		return ctyjson.Unmarshal([]byte(s), cty.DynamicPseudoType)
+	}
+
+	b, err := json.Marshal(map[string]json.RawMessage{
+		"value": encoded.Value,
+		"type":  encoded.Type,
+	})
+	if err != nil {
+		return cty.DynamicVal, err
+	}
+
+	v, err := ctyjson.Unmarshal(b, cty.DynamicPseudoType)
+	if err != nil {
+		return v, err
+	}
+
+	for _, path := range encoded.Unknown {
+		v, err = unknownValueAtPath(v, path)
+		if err != nil {
+			return cty.DynamicVal, err
+		}
+	}
+
+	return v, nil
+}
+
+// nullUnknownValues replaces the unknown values within the given value with
+// nulls of the same type, and appends their paths to unknown. Path steps
+// are attribute names and map keys as strings, and list and tuple indexes as
+// numbers.
+func nullUnknownValues(v cty.Value, path []interface{}, unknown *[][]interface{}) cty.Value {
+	if v.IsNull() || v.IsWhollyKnown() {
+		return v
+	}
+
+	ty := v.Type()
+
+	switch {
+	case !v.IsKnown():
+	case ty.IsObjectType() || ty.IsMapType():
+		vals := v.AsValueMap()
+
+		for k, ev := range vals {
+			vals[k] = nullUnknownValues(ev, appendStep(path, k), unknown)
+		}
+
+		if ty.IsMapType() {
+			return cty.MapVal(vals)
+		}
+
+		return cty.ObjectVal(vals)
+	case ty.IsListType() || ty.IsTupleType():
+		vals := v.AsValueSlice()
+
+		for i, ev := range vals {
+			vals[i] = nullUnknownValues(ev, appendStep(path, i), unknown)
+		}
+
+		if ty.IsListType() {
+			return cty.ListVal(vals)
+		}
+
+		return cty.TupleVal(vals)
+	}
+
+	// The value is unknown, or a set whose elements cannot be addressed by
+	// a path.
+	*unknown = append(*unknown, append([]interface{}{}, path...))
+
+	return cty.NullVal(ty)
+}
+
+// unknownValueAtPath returns the given value with the value at the given
+// path, as encoded by nullUnknownValues, replaced by an unknown value.
+func unknownValueAtPath(v cty.Value, path []interface{}) (cty.Value, error) {
+	if len(path) == 0 {
+		return cty.UnknownVal(v.Type()), nil
+	}
+
+	if v.IsNull() || !v.IsKnown() {
+		return v, fmt.Errorf("invalid unknown value path %v", path)
+	}
+
+	ty := v.Type()
+
+	switch step := path[0].(type) {
+	case string:
+		if !ty.IsObjectType() && !ty.IsMapType() {
+			return v, fmt.Errorf("invalid unknown value path %v", path)
+		}
+
+		vals := v.AsValueMap()
+
+		ev, ok := vals[step]
+		if !ok {
+			return v, fmt.Errorf("invalid unknown value path %v", path)
+		}
+
+		ev, err := unknownValueAtPath(ev, path[1:])
+		if err != nil {
+			return v, err
+		}
+
+		vals[step] = ev
+
+		if ty.IsMapType() {
+			return cty.MapVal(vals), nil
+		}
+
+		return cty.ObjectVal(vals), nil
+	case float64:
+		if !ty.IsListType() && !ty.IsTupleType() {
+			return v, fmt.Errorf("invalid unknown value path %v", path)
+		}
+
+		vals := v.AsValueSlice()
+
+		i := int(step)
+		if i < 0 || i >= len(vals) {
+			return v, fmt.Errorf("invalid unknown value path %v", path)
+		}
+
+		ev, err := unknownValueAtPath(vals[i], path[1:])
+		if err != nil {
+			return v, err
+		}
+
+		vals[i] = ev
+
+		if ty.IsListType() {
+			return cty.ListVal(vals), nil
+		}
+
+		return cty.TupleVal(vals), nil
+	default:
+		return v, fmt.Errorf("invalid unknown value path %v", path)
+	}
+}
+
+// appendStep returns a copy of the given path with the given step appended.
+func appendStep(path []interface{}, step interface{}) []interface{} {
+	ret := make([]interface{}, len(path), len(path)+1)
+	copy(ret, path)
+
+	return append(ret, step)
+}
@@ -0,0 +1,80 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package hcl2shim
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestEncodeDynamicValue(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input cty.Value
+		want  cty.Value
+	}{
+		"known": {
+			input: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("example"),
+			}),
+			want: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("example"),
+			}),
+		},
+		"partly unknown": {
+			input: cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.StringVal("example"),
+				"owner": cty.UnknownVal(cty.String),
+				"items": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.DynamicVal}),
+				"list":  cty.ListVal([]cty.Value{cty.UnknownVal(cty.Bool), cty.True}),
+				"tags":  cty.MapVal(map[string]cty.Value{"a": cty.StringVal("b"), "c": cty.UnknownVal(cty.String)}),
+			}),
+			want: cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.StringVal("example"),
+				"owner": cty.UnknownVal(cty.String),
+				"items": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.DynamicVal}),
+				"list":  cty.ListVal([]cty.Value{cty.UnknownVal(cty.Bool), cty.True}),
+				"tags":  cty.MapVal(map[string]cty.Value{"a": cty.StringVal("b"), "c": cty.UnknownVal(cty.String)}),
+			}),
+		},
+		"set with unknown element": {
+			input: cty.TupleVal([]cty.Value{
+				cty.SetVal([]cty.Value{cty.StringVal("a"), cty.UnknownVal(cty.String)}),
+			}),
+			want: cty.TupleVal([]cty.Value{
+				cty.UnknownVal(cty.Set(cty.String)),
+			}),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := EncodeDynamicValue(test.input)
+			if err != nil {
+				t.Fatalf("unexpected error encoding: %s", err)
+			}
+
+			got, err := DecodeDynamicValue(encoded)
+			if err != nil {
+				t.Fatalf("unexpected error decoding: %s", err)
+			}
+
+			if !got.RawEquals(test.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEncodeDynamicValue_unknown(t *testing.T) {
+	t.Parallel()
+
+	if _, err := EncodeDynamicValue(cty.DynamicVal); err == nil {
+		t.Fatal("expected error, got none")
+	}
+}
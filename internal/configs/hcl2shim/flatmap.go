@@ -159,6 +159,8 @@ func hcl2ValueFromFlatmapValue(m map[string]string, key string, ty cty.Type) (ct
 	var val cty.Value
 	var err error
 	switch {
+	case ty.Equals(cty.DynamicPseudoType):
+		val, err = hcl2ValueFromFlatmapDynamic(m, key)
 	case ty.IsPrimitiveType():
 		val, err = hcl2ValueFromFlatmapPrimitive(m, key, ty)
 	case ty.IsObjectType():
@@ -202,6 +204,26 @@ func hcl2ValueFromFlatmapPrimitive(m map[string]string, key string, ty cty.Type)
 	return val, nil
 }
 
+// hcl2ValueFromFlatmapDynamic decodes a dynamically-typed value, which is
+// stored in flatmap as a single JSON string as produced by
+// EncodeDynamicValue.
+func hcl2ValueFromFlatmapDynamic(m map[string]string, key string) (cty.Value, error) {
+	rawVal, exists := m[key]
+	if !exists {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
+	if rawVal == UnknownVariableValue {
+		return cty.DynamicVal, nil
+	}
+
+	val, err := DecodeDynamicValue(rawVal)
+	if err != nil {
+		return cty.DynamicVal, fmt.Errorf("invalid value for %q in state: %s", key, err)
+	}
+
+	return val, nil
+}
+
 func hcl2ValueFromFlatmapObject(m map[string]string, prefix string, atys map[string]cty.Type) (cty.Value, error) {
 	vals := make(map[string]cty.Value)
 	for name, aty := range atys {
@@ -345,6 +345,25 @@ func TestHCL2ValueFromFlatmap(t *testing.T) {
 			Type: cty.EmptyObject,
 			Want: cty.EmptyObjectVal,
 		},
+		{
+			Flatmap: map[string]string{
+				"dyn":  `{"value":{"a":["b"]},"type":["object",{"a":["tuple",["string"]]}]}`,
+				"null": "",
+				"unk":  UnknownVariableValue,
+			},
+			Type: cty.Object(map[string]cty.Type{
+				"dyn":  cty.DynamicPseudoType,
+				"null": cty.DynamicPseudoType,
+				"unk":  cty.DynamicPseudoType,
+			}),
+			Want: cty.ObjectVal(map[string]cty.Value{
+				"dyn": cty.ObjectVal(map[string]cty.Value{
+					"a": cty.TupleVal([]cty.Value{cty.StringVal("b")}),
+				}),
+				"null": cty.NullVal(cty.DynamicPseudoType),
+				"unk":  cty.DynamicVal,
+			}),
+		},
 		{
 			Flatmap: map[string]string{
 				"foo": "blah",
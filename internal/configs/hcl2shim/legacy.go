@@ -0,0 +1,381 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package hcl2shim
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+)
+
+// The legacy flatmap and config representations used by helper/schema cannot
+// represent every value allowed by a schema. The functions in this file
+// convert between values conforming to a schema and values conforming to the
+// legacy version of that schema, as returned by LegacySchema, where:
+//
+//   - Dynamically-typed attributes contain strings, as returned by
+//     EncodeDynamicValue, rather than values of arbitrary types.
+//   - Nested attributes with NestingSingle contain lists of zero or one
+//     objects, rather than objects, which is how helper/schema represents
+//     them.
+
+// LegacySchema returns the legacy version of the given schema, which is the
+// schema the legacy flatmap and config representations of values conforming
+// to the given schema are decoded with.
+//
+// The given schema is returned unchanged if no conversion is required.
+func LegacySchema(schema *configschema.Block) *configschema.Block {
+	if !requiresLegacyShim(schema) {
+		return schema
+	}
+
+	ret := *schema // shallow copy
+
+	if schema.Attributes != nil {
+		ret.Attributes = legacyAttributes(schema.Attributes)
+	}
+
+	if schema.BlockTypes != nil {
+		ret.BlockTypes = make(map[string]*configschema.NestedBlock, len(schema.BlockTypes))
+
+		for name, blockS := range schema.BlockTypes {
+			nested := *blockS // shallow copy
+			nested.Block = *LegacySchema(&blockS.Block)
+			ret.BlockTypes[name] = &nested
+		}
+	}
+
+	return &ret
+}
+
+// LegacyValueFromHCL2Block converts the given object value, which must conform
+// to the given schema, into a value conforming to the legacy version of the
+// schema. This must be called before lowering a value into the legacy
+// flatmap or config representations.
+func LegacyValueFromHCL2Block(v cty.Value, schema *configschema.Block) (cty.Value, error) {
+	if !requiresLegacyShim(schema) {
+		return v, nil
+	}
+
+	return legacyValueFromHCL2Block(v, schema, nil)
+}
+
+// HCL2ValueFromFlatmapBlock is like HCL2ValueFromFlatmap, but decodes the
+// given flatmap using the legacy version of the given schema and converts the
+// result back into a value conforming to the schema itself.
+func HCL2ValueFromFlatmapBlock(m map[string]string, schema *configschema.Block) (cty.Value, error) {
+	if !requiresLegacyShim(schema) {
+		return HCL2ValueFromFlatmap(m, schema.ImpliedType())
+	}
+
+	val, err := HCL2ValueFromFlatmap(m, LegacySchema(schema).ImpliedType())
+	if err != nil {
+		return val, err
+	}
+
+	return hcl2ValueFromLegacyBlock(val, schema), nil
+}
+
+// requiresLegacyShim returns true if the given schema contains any
+// dynamically-typed attributes or nested attributes with NestingSingle.
+func requiresLegacyShim(schema *configschema.Block) bool {
+	if schema == nil {
+		return false
+	}
+
+	if attributesRequireLegacyShim(schema.Attributes) {
+		return true
+	}
+
+	for _, blockS := range schema.BlockTypes {
+		if requiresLegacyShim(&blockS.Block) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func attributesRequireLegacyShim(attrs map[string]*configschema.Attribute) bool {
+	for _, attrS := range attrs {
+		if attrS.Type.Equals(cty.DynamicPseudoType) {
+			return true
+		}
+
+		if attrS.NestedType == nil {
+			continue
+		}
+
+		if attrS.NestedType.Nesting == configschema.NestingSingle || attributesRequireLegacyShim(attrS.NestedType.Attributes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func legacyAttributes(attrs map[string]*configschema.Attribute) map[string]*configschema.Attribute {
+	ret := make(map[string]*configschema.Attribute, len(attrs))
+
+	for name, attrS := range attrs {
+		if attrS.NestedType == nil {
+			ret[name] = attrS
+			continue
+		}
+
+		nestedType := *attrS.NestedType // shallow copy
+		nestedType.Attributes = legacyAttributes(attrS.NestedType.Attributes)
+
+		if nestedType.Nesting == configschema.NestingSingle {
+			nestedType.Nesting = configschema.NestingList
+		}
+
+		attr := *attrS // shallow copy
+		attr.NestedType = &nestedType
+		attr.Type = nestedType.ImpliedType()
+		ret[name] = &attr
+	}
+
+	return ret
+}
+
+// nestedObjectBlock returns a block schema containing the attributes of the
+// given nested attribute, so the objects of the nested attribute can be
+// handled like blocks.
+func nestedObjectBlock(attrS *configschema.Attribute) *configschema.Block {
+	return &configschema.Block{
+		Attributes: attrS.NestedType.Attributes,
+	}
+}
+
+func legacyValueFromHCL2Block(v cty.Value, schema *configschema.Block, path cty.Path) (cty.Value, error) {
+	if v.IsNull() {
+		return cty.NullVal(LegacySchema(schema).ImpliedType()), nil
+	}
+
+	if !v.IsKnown() {
+		return cty.UnknownVal(LegacySchema(schema).ImpliedType()), nil
+	}
+
+	vals := v.AsValueMap()
+
+	if len(vals) == 0 {
+		return v, nil
+	}
+
+	for name, attrS := range schema.Attributes {
+		av, ok := vals[name]
+		if !ok {
+			continue
+		}
+
+		lv, err := legacyValueFromHCL2Attribute(av, attrS, path.GetAttr(name))
+		if err != nil {
+			return v, err
+		}
+
+		vals[name] = lv
+	}
+
+	for name, blockS := range schema.BlockTypes {
+		bv, ok := vals[name]
+		if !ok {
+			continue
+		}
+
+		lv, err := legacyValueFromHCL2Nested(bv, blockS.Nesting, &blockS.Block, path.GetAttr(name))
+		if err != nil {
+			return v, err
+		}
+
+		vals[name] = lv
+	}
+
+	return cty.ObjectVal(vals), nil
+}
+
+func legacyValueFromHCL2Attribute(v cty.Value, attrS *configschema.Attribute, path cty.Path) (cty.Value, error) {
+	switch {
+	case attrS.Type.Equals(cty.DynamicPseudoType):
+		switch {
+		case v.IsNull():
+			return cty.NullVal(cty.String), nil
+		case !v.IsKnown():
+			return cty.UnknownVal(cty.String), nil
+		}
+
+		// Partly unknown values are encoded with their known parts.
+		s, err := EncodeDynamicValue(v)
+		if err != nil {
+			return v, path.NewError(fmt.Errorf("encoding dynamic value: %w", err))
+		}
+
+		return cty.StringVal(s), nil
+	case attrS.NestedType == nil:
+		return v, nil
+	case attrS.NestedType.Nesting == configschema.NestingSingle:
+		legacyType := cty.List(LegacySchema(nestedObjectBlock(attrS)).ImpliedType())
+
+		if v.IsNull() {
+			return cty.NullVal(legacyType), nil
+		}
+
+		if !v.IsKnown() {
+			return cty.UnknownVal(legacyType), nil
+		}
+
+		ev, err := legacyValueFromHCL2Block(v, nestedObjectBlock(attrS), path)
+		if err != nil {
+			return v, err
+		}
+
+		return cty.ListVal([]cty.Value{ev}), nil
+	default:
+		return legacyValueFromHCL2Nested(v, attrS.NestedType.Nesting, nestedObjectBlock(attrS), path)
+	}
+}
+
+func legacyValueFromHCL2Nested(v cty.Value, nesting configschema.NestingMode, schema *configschema.Block, path cty.Path) (cty.Value, error) {
+	if nesting == configschema.NestingSingle || nesting == configschema.NestingGroup {
+		return legacyValueFromHCL2Block(v, schema, path)
+	}
+
+	ety := LegacySchema(schema).ImpliedType()
+
+	return convertNestedCollection(v, nesting, ety, func(ev cty.Value, step cty.PathStep) (cty.Value, error) {
+		return legacyValueFromHCL2Block(ev, schema, append(path.Copy(), step))
+	})
+}
+
+func hcl2ValueFromLegacyBlock(v cty.Value, schema *configschema.Block) cty.Value {
+	if v.IsNull() {
+		return cty.NullVal(schema.ImpliedType())
+	}
+
+	if !v.IsKnown() {
+		return cty.UnknownVal(schema.ImpliedType())
+	}
+
+	vals := v.AsValueMap()
+
+	if len(vals) == 0 {
+		return v
+	}
+
+	for name, attrS := range schema.Attributes {
+		av, ok := vals[name]
+		if !ok || attrS.NestedType == nil {
+			continue
+		}
+
+		vals[name] = hcl2ValueFromLegacyNestedAttribute(av, attrS)
+	}
+
+	for name, blockS := range schema.BlockTypes {
+		bv, ok := vals[name]
+		if !ok {
+			continue
+		}
+
+		vals[name] = hcl2ValueFromLegacyNested(bv, blockS.Nesting, &blockS.Block)
+	}
+
+	return cty.ObjectVal(vals)
+}
+
+func hcl2ValueFromLegacyNestedAttribute(v cty.Value, attrS *configschema.Attribute) cty.Value {
+	if attrS.NestedType.Nesting != configschema.NestingSingle {
+		return hcl2ValueFromLegacyNested(v, attrS.NestedType.Nesting, nestedObjectBlock(attrS))
+	}
+
+	if !v.IsKnown() {
+		return cty.UnknownVal(attrS.Type)
+	}
+
+	if v.IsNull() || v.LengthInt() == 0 {
+		return cty.NullVal(attrS.Type)
+	}
+
+	return hcl2ValueFromLegacyBlock(v.Index(cty.NumberIntVal(0)), nestedObjectBlock(attrS))
+}
+
+func hcl2ValueFromLegacyNested(v cty.Value, nesting configschema.NestingMode, schema *configschema.Block) cty.Value {
+	if nesting == configschema.NestingSingle || nesting == configschema.NestingGroup {
+		return hcl2ValueFromLegacyBlock(v, schema)
+	}
+
+	// The conversion function never returns an error.
+	val, _ := convertNestedCollection(v, nesting, schema.ImpliedType(), func(ev cty.Value, _ cty.PathStep) (cty.Value, error) {
+		return hcl2ValueFromLegacyBlock(ev, schema), nil
+	})
+
+	return val
+}
+
+// convertNestedCollection returns a copy of the given list, set or map of
+// objects, with each element converted by the given function into an object
+// of the given element type.
+func convertNestedCollection(v cty.Value, nesting configschema.NestingMode, ety cty.Type, convertElem func(cty.Value, cty.PathStep) (cty.Value, error)) (cty.Value, error) {
+	var ty cty.Type
+
+	switch nesting {
+	case configschema.NestingList:
+		ty = cty.List(ety)
+	case configschema.NestingSet:
+		ty = cty.Set(ety)
+	case configschema.NestingMap:
+		ty = cty.Map(ety)
+	default:
+		return v, fmt.Errorf("invalid nesting mode %s", nesting)
+	}
+
+	if v.IsNull() {
+		return cty.NullVal(ty), nil
+	}
+
+	if !v.IsKnown() {
+		return cty.UnknownVal(ty), nil
+	}
+
+	if v.LengthInt() == 0 {
+		switch nesting {
+		case configschema.NestingList:
+			return cty.ListValEmpty(ety), nil
+		case configschema.NestingSet:
+			return cty.SetValEmpty(ety), nil
+		default:
+			return cty.MapValEmpty(ety), nil
+		}
+	}
+
+	var elems []cty.Value
+	mapElems := make(map[string]cty.Value)
+
+	for it := v.ElementIterator(); it.Next(); {
+		k, ev := it.Element()
+
+		cv, err := convertElem(ev, cty.IndexStep{Key: k})
+		if err != nil {
+			return v, err
+		}
+
+		if nesting == configschema.NestingMap {
+			mapElems[k.AsString()] = cv
+			continue
+		}
+
+		elems = append(elems, cv)
+	}
+
+	switch nesting {
+	case configschema.NestingList:
+		return cty.ListVal(elems), nil
+	case configschema.NestingSet:
+		return cty.SetVal(elems), nil
+	default:
+		return cty.MapVal(mapElems), nil
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package hcl2shim
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+)
+
+func TestLegacyValueFromHCL2Block(t *testing.T) {
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"dynamic": {
+				Type:     cty.DynamicPseudoType,
+				Optional: true,
+			},
+			"single": {
+				Type: cty.Object(map[string]cty.Type{
+					"name": cty.String,
+				}),
+				NestedType: &configschema.Object{
+					Attributes: map[string]*configschema.Attribute{
+						"name": {
+							Type:     cty.String,
+							Optional: true,
+						},
+					},
+					Nesting: configschema.NestingSingle,
+				},
+				Optional: true,
+			},
+		},
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"block": {
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"single": {
+							Type: cty.Object(map[string]cty.Type{
+								"name": cty.String,
+							}),
+							NestedType: &configschema.Object{
+								Attributes: map[string]*configschema.Attribute{
+									"name": {
+										Type:     cty.String,
+										Optional: true,
+									},
+								},
+								Nesting: configschema.NestingSingle,
+							},
+							Optional: true,
+						},
+					},
+				},
+				Nesting: configschema.NestingList,
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		Value    cty.Value
+		Want     cty.Value
+		Flatmap  map[string]string
+		WantBack cty.Value
+	}{
+		"null": {
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"dynamic": cty.NullVal(cty.DynamicPseudoType),
+				"single": cty.NullVal(cty.Object(map[string]cty.Type{
+					"name": cty.String,
+				})),
+				"block": cty.ListValEmpty(cty.Object(map[string]cty.Type{
+					"single": cty.Object(map[string]cty.Type{
+						"name": cty.String,
+					}),
+				})),
+			}),
+			Want: cty.ObjectVal(map[string]cty.Value{
+				"dynamic": cty.NullVal(cty.String),
+				"single": cty.NullVal(cty.List(cty.Object(map[string]cty.Type{
+					"name": cty.String,
+				}))),
+				"block": cty.ListValEmpty(cty.Object(map[string]cty.Type{
+					"single": cty.List(cty.Object(map[string]cty.Type{
+						"name": cty.String,
+					})),
+				})),
+			}),
+			Flatmap: map[string]string{
+				"block.#": "0",
+			},
+		},
+		"known": {
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"dynamic": cty.ListVal([]cty.Value{cty.True}),
+				"single": cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("a"),
+				}),
+				"block": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"single": cty.ObjectVal(map[string]cty.Value{
+							"name": cty.StringVal("b"),
+						}),
+					}),
+					cty.ObjectVal(map[string]cty.Value{
+						"single": cty.NullVal(cty.Object(map[string]cty.Type{
+							"name": cty.String,
+						})),
+					}),
+				}),
+			}),
+			Want: cty.ObjectVal(map[string]cty.Value{
+				"dynamic": cty.StringVal(`{"value":[true],"type":["list","bool"]}`),
+				"single": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"name": cty.StringVal("a"),
+					}),
+				}),
+				"block": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"single": cty.ListVal([]cty.Value{
+							cty.ObjectVal(map[string]cty.Value{
+								"name": cty.StringVal("b"),
+							}),
+						}),
+					}),
+					cty.ObjectVal(map[string]cty.Value{
+						"single": cty.NullVal(cty.List(cty.Object(map[string]cty.Type{
+							"name": cty.String,
+						}))),
+					}),
+				}),
+			}),
+			Flatmap: map[string]string{
+				"dynamic":               `{"value":[true],"type":["list","bool"]}`,
+				"single.#":              "1",
+				"single.0.name":         "a",
+				"block.#":               "2",
+				"block.0.single.#":      "1",
+				"block.0.single.0.name": "b",
+			},
+		},
+		"unknown": {
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"dynamic": cty.DynamicVal,
+				"single": cty.UnknownVal(cty.Object(map[string]cty.Type{
+					"name": cty.String,
+				})),
+				"block": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"single": cty.ObjectVal(map[string]cty.Value{
+							"name": cty.UnknownVal(cty.String),
+						}),
+					}),
+				}),
+			}),
+			Want: cty.ObjectVal(map[string]cty.Value{
+				"dynamic": cty.UnknownVal(cty.String),
+				"single": cty.UnknownVal(cty.List(cty.Object(map[string]cty.Type{
+					"name": cty.String,
+				}))),
+				"block": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"single": cty.ListVal([]cty.Value{
+							cty.ObjectVal(map[string]cty.Value{
+								"name": cty.UnknownVal(cty.String),
+							}),
+						}),
+					}),
+				}),
+			}),
+			Flatmap: map[string]string{
+				"dynamic":               UnknownVariableValue,
+				"single.#":              UnknownVariableValue,
+				"block.#":               "1",
+				"block.0.single.#":      "1",
+				"block.0.single.0.name": UnknownVariableValue,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := LegacyValueFromHCL2Block(test.Value, schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Fatalf("wrong legacy value\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+
+			flatmap := FlatmapValueFromHCL2(got)
+
+			if diff := cmp.Diff(test.Flatmap, flatmap); diff != "" {
+				t.Fatalf("wrong flatmap: %s", diff)
+			}
+
+			back, err := HCL2ValueFromFlatmapBlock(flatmap, schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !back.RawEquals(test.Value) {
+				t.Fatalf("wrong round-tripped value\ngot:  %#v\nwant: %#v", back, test.Value)
+			}
+		})
+	}
+}
+
+func TestLegacySchema(t *testing.T) {
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"single": {
+				Type: cty.Object(map[string]cty.Type{
+					"name": cty.String,
+				}),
+				NestedType: &configschema.Object{
+					Attributes: map[string]*configschema.Attribute{
+						"name": {
+							Type:     cty.String,
+							Optional: true,
+						},
+					},
+					Nesting: configschema.NestingSingle,
+				},
+				Optional: true,
+			},
+		},
+	}
+
+	want := cty.Object(map[string]cty.Type{
+		"single": cty.List(cty.Object(map[string]cty.Type{
+			"name": cty.String,
+		})),
+	})
+
+	got := LegacySchema(schema).ImpliedType()
+
+	if !got.Equals(want) {
+		t.Fatalf("wrong legacy type\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	if !schema.ImpliedType().Equals(cty.Object(map[string]cty.Type{
+		"single": cty.Object(map[string]cty.Type{
+			"name": cty.String,
+		}),
+	})) {
+		t.Fatal("original schema was modified")
+	}
+}
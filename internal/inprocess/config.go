@@ -0,0 +1,424 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package inprocess
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	tfjson "github.com/hashicorp/terraform-json"
+	zcty "github.com/zclconf/go-cty/cty"
+)
+
+// config is the decoded root module of a configuration. The bodies of
+// provider, resource and data blocks are decoded later using the schemas of
+// the provider servers.
+type config struct {
+	providers map[string]*providerConfig
+	variables map[string]*variableConfig
+	locals    map[string]hcl.Expression
+	resources map[string]*resourceConfig
+	outputs   map[string]*outputConfig
+}
+
+type providerConfig struct {
+	name string
+	body hcl.Body
+}
+
+type variableConfig struct {
+	name      string
+	typ       zcty.Type
+	required  bool
+	def       zcty.Value
+	sensitive bool
+}
+
+type resourceConfig struct {
+	mode      tfjson.ResourceMode
+	typeName  string
+	name      string
+	provider  string
+	count     hcl.Expression
+	dependsOn []hcl.Traversal
+	body      hcl.Body
+}
+
+// addr returns the address of the resource, such as examplecloud_thing.foo
+// or data.examplecloud_thing.foo.
+func (r *resourceConfig) addr() string {
+	return resourceAddr(r.mode, r.typeName, r.name)
+}
+
+type outputConfig struct {
+	name      string
+	expr      hcl.Expression
+	sensitive bool
+	dependsOn []hcl.Traversal
+}
+
+var configFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "terraform"},
+		{Type: "provider", LabelNames: []string{"name"}},
+		{Type: "variable", LabelNames: []string{"name"}},
+		{Type: "locals"},
+		{Type: "output", LabelNames: []string{"name"}},
+		{Type: "resource", LabelNames: []string{"type", "name"}},
+		{Type: "data", LabelNames: []string{"type", "name"}},
+	},
+}
+
+var providerBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "alias"},
+		{Name: "version"},
+	},
+}
+
+var variableBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "default"},
+		{Name: "description"},
+		{Name: "nullable"},
+		{Name: "sensitive"},
+		{Name: "type"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "validation"},
+	},
+}
+
+var outputBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "value", Required: true},
+		{Name: "depends_on"},
+		{Name: "description"},
+		{Name: "sensitive"},
+	},
+}
+
+var resourceBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "count"},
+		{Name: "depends_on"},
+		{Name: "for_each"},
+		{Name: "provider"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "connection"},
+		{Type: "lifecycle"},
+		{Type: "provisioner", LabelNames: []string{"type"}},
+	},
+}
+
+// parseConfig parses the given configuration files of a root module, keyed
+// by file name. Files with the .tf extension must be written in the native
+// HCL syntax and files with the .tf.json extension in the JSON syntax, while
+// any other files are ignored, as they are by Terraform.
+func parseConfig(files map[string][]byte) (*config, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	names := make([]string, 0, len(files))
+
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	parser := hclparse.NewParser()
+	bodies := make([]hcl.Body, 0, len(names))
+
+	for _, name := range names {
+		var file *hcl.File
+		var moreDiags hcl.Diagnostics
+
+		switch {
+		case strings.HasSuffix(name, ".tf.json"):
+			file, moreDiags = parser.ParseJSON(files[name], name)
+		case strings.HasSuffix(name, ".tf"):
+			file, moreDiags = parser.ParseHCL(files[name], name)
+		default:
+			continue
+		}
+
+		diags = append(diags, moreDiags...)
+
+		if file != nil {
+			bodies = append(bodies, file.Body)
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	cfg := &config{
+		providers: make(map[string]*providerConfig),
+		variables: make(map[string]*variableConfig),
+		locals:    make(map[string]hcl.Expression),
+		resources: make(map[string]*resourceConfig),
+		outputs:   make(map[string]*outputConfig),
+	}
+
+	for _, body := range bodies {
+		diags = append(diags, cfg.decodeBody(body)...)
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return cfg, diags
+}
+
+// decodeBody decodes the blocks of a configuration file into the
+// configuration.
+func (c *config) decodeBody(body hcl.Body) hcl.Diagnostics {
+	content, diags := body.Content(configFileSchema)
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "provider":
+			diags = append(diags, c.decodeProviderBlock(block)...)
+		case "variable":
+			diags = append(diags, c.decodeVariableBlock(block)...)
+		case "locals":
+			attrs, moreDiags := block.Body.JustAttributes()
+			diags = append(diags, moreDiags...)
+
+			for name, attr := range attrs {
+				if _, ok := c.locals[name]; ok {
+					diags = append(diags, duplicateDiag(attr.NameRange, "local value", name))
+
+					continue
+				}
+
+				c.locals[name] = attr.Expr
+			}
+		case "output":
+			diags = append(diags, c.decodeOutputBlock(block)...)
+		case "resource":
+			diags = append(diags, c.decodeResourceBlock(block, tfjson.ManagedResourceMode)...)
+		case "data":
+			diags = append(diags, c.decodeResourceBlock(block, tfjson.DataResourceMode)...)
+		}
+	}
+
+	return diags
+}
+
+func (c *config) decodeProviderBlock(block *hcl.Block) hcl.Diagnostics {
+	content, remain, diags := block.Body.PartialContent(providerBlockSchema)
+
+	if attr, ok := content.Attributes["alias"]; ok {
+		return append(diags, unsupportedDiag(attr.Range, "Provider aliases"))
+	}
+
+	name := block.Labels[0]
+
+	if _, ok := c.providers[name]; ok {
+		return append(diags, duplicateDiag(block.DefRange, "provider", name))
+	}
+
+	c.providers[name] = &providerConfig{
+		name: name,
+		body: remain,
+	}
+
+	return diags
+}
+
+func (c *config) decodeVariableBlock(block *hcl.Block) hcl.Diagnostics {
+	content, diags := block.Body.Content(variableBlockSchema)
+
+	v := &variableConfig{
+		name:     block.Labels[0],
+		typ:      zcty.DynamicPseudoType,
+		required: true,
+	}
+
+	if _, ok := c.variables[v.name]; ok {
+		return append(diags, duplicateDiag(block.DefRange, "variable", v.name))
+	}
+
+	if attr, ok := content.Attributes["type"]; ok {
+		ty, moreDiags := typeexpr.TypeConstraint(attr.Expr)
+		diags = append(diags, moreDiags...)
+		v.typ = ty
+	}
+
+	if attr, ok := content.Attributes["default"]; ok {
+		val, moreDiags := attr.Expr.Value(nil)
+		diags = append(diags, moreDiags...)
+		v.required = false
+		v.def = val
+	}
+
+	if attr, ok := content.Attributes["sensitive"]; ok {
+		diags = append(diags, decodeBool(attr, &v.sensitive)...)
+	}
+
+	c.variables[v.name] = v
+
+	return diags
+}
+
+func (c *config) decodeOutputBlock(block *hcl.Block) hcl.Diagnostics {
+	content, diags := block.Body.Content(outputBlockSchema)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	o := &outputConfig{
+		name: block.Labels[0],
+		expr: content.Attributes["value"].Expr,
+	}
+
+	if _, ok := c.outputs[o.name]; ok {
+		return append(diags, duplicateDiag(block.DefRange, "output", o.name))
+	}
+
+	if attr, ok := content.Attributes["sensitive"]; ok {
+		diags = append(diags, decodeBool(attr, &o.sensitive)...)
+	}
+
+	if attr, ok := content.Attributes["depends_on"]; ok {
+		traversals, moreDiags := decodeDependsOn(attr)
+		diags = append(diags, moreDiags...)
+		o.dependsOn = traversals
+	}
+
+	c.outputs[o.name] = o
+
+	return diags
+}
+
+func (c *config) decodeResourceBlock(block *hcl.Block, mode tfjson.ResourceMode) hcl.Diagnostics {
+	content, remain, diags := block.Body.PartialContent(resourceBlockSchema)
+
+	r := &resourceConfig{
+		mode:     mode,
+		typeName: block.Labels[0],
+		name:     block.Labels[1],
+		provider: defaultProviderName(block.Labels[0]),
+		body:     remain,
+	}
+
+	if _, ok := c.resources[r.addr()]; ok {
+		return append(diags, duplicateDiag(block.DefRange, string(mode)+" resource", r.addr()))
+	}
+
+	if attr, ok := content.Attributes["for_each"]; ok {
+		diags = append(diags, unsupportedDiag(attr.Range, "The for_each meta-arguments"))
+	}
+
+	for _, b := range content.Blocks {
+		diags = append(diags, unsupportedDiag(b.DefRange, fmt.Sprintf("Nested %q blocks", b.Type)))
+	}
+
+	if attr, ok := content.Attributes["count"]; ok {
+		r.count = attr.Expr
+	}
+
+	if attr, ok := content.Attributes["provider"]; ok {
+		traversal, moreDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		diags = append(diags, moreDiags...)
+
+		switch {
+		case moreDiags.HasErrors():
+		case len(traversal) > 1:
+			diags = append(diags, unsupportedDiag(attr.Range, "Provider aliases"))
+		default:
+			r.provider = traversal.RootName()
+		}
+	}
+
+	if attr, ok := content.Attributes["depends_on"]; ok {
+		traversals, moreDiags := decodeDependsOn(attr)
+		diags = append(diags, moreDiags...)
+		r.dependsOn = traversals
+	}
+
+	c.resources[r.addr()] = r
+
+	return diags
+}
+
+func decodeBool(attr *hcl.Attribute, dst *bool) hcl.Diagnostics {
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if val.IsNull() || !val.Type().Equals(zcty.Bool) {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid argument value",
+			Detail:   fmt.Sprintf("The %q argument must be either true or false.", attr.Name),
+			Subject:  attr.Range.Ptr(),
+		})
+	}
+
+	*dst = val.True()
+
+	return diags
+}
+
+func decodeDependsOn(attr *hcl.Attribute) ([]hcl.Traversal, hcl.Diagnostics) {
+	exprs, diags := hcl.ExprList(attr.Expr)
+
+	traversals := make([]hcl.Traversal, 0, len(exprs))
+
+	for _, expr := range exprs {
+		traversal, moreDiags := hcl.AbsTraversalForExpr(expr)
+		diags = append(diags, moreDiags...)
+
+		if !moreDiags.HasErrors() {
+			traversals = append(traversals, traversal)
+		}
+	}
+
+	return traversals, diags
+}
+
+// defaultProviderName returns the local name of the provider of the given
+// resource type when the resource has no provider meta-argument, which is the
+// prefix of the type up to the first underscore.
+func defaultProviderName(typeName string) string {
+	name, _, _ := strings.Cut(typeName, "_")
+
+	return name
+}
+
+func resourceAddr(mode tfjson.ResourceMode, typeName, name string) string {
+	if mode == tfjson.DataResourceMode {
+		return "data." + typeName + "." + name
+	}
+
+	return typeName + "." + name
+}
+
+func unsupportedDiag(rng hcl.Range, what string) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unsupported configuration",
+		Detail:   what + " are not supported by the in-process Terraform core.",
+		Subject:  rng.Ptr(),
+	}
+}
+
+func duplicateDiag(rng hcl.Range, kind, name string) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Duplicate " + kind + " configuration",
+		Detail:   fmt.Sprintf("A %s named %q was already declared.", kind, name),
+		Subject:  rng.Ptr(),
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package inprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	zcty "github.com/zclconf/go-cty/cty"
+)
+
+// TerraformVersion is the Terraform version reported to providers and in the
+// state and plans of a Core.
+const TerraformVersion = "1.13.0"
+
+// Core plans and applies a configuration using provider servers running in
+// the same process, keeping the resulting state in memory.
+type Core struct {
+	providers map[string]*provider
+	config    *config
+	state     *state
+	variables map[string]zcty.Value
+}
+
+// NewCore returns a Core with an empty configuration and state, which uses
+// the given provider servers, keyed by the local name of the provider.
+func NewCore(ctx context.Context, servers map[string]tfprotov5.ProviderServer) (*Core, error) {
+	c := &Core{
+		config: &config{},
+		state:  newState(),
+	}
+
+	if err := c.SetProviders(ctx, servers); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// SetProviders replaces the provider servers of the core, keyed by the local
+// name of the provider.
+func (c *Core) SetProviders(ctx context.Context, servers map[string]tfprotov5.ProviderServer) error {
+	providers := make(map[string]*provider, len(servers))
+
+	for name, server := range servers {
+		p, err := newProvider(ctx, name, server)
+		if err != nil {
+			return err
+		}
+
+		providers[name] = p
+	}
+
+	c.providers = providers
+
+	return nil
+}
+
+// SetConfig replaces the configuration of the core with the given
+// configuration, which must be written in the native HCL syntax.
+func (c *Core) SetConfig(src string) error {
+	return c.SetConfigFiles(map[string][]byte{"main.tf": []byte(src)})
+}
+
+// SetConfigFiles replaces the configuration of the core with the given files
+// of a root module, keyed by file name. Files with the .tf extension must be
+// written in the native HCL syntax and files with the .tf.json extension in
+// the JSON syntax, while any other files are ignored.
+func (c *Core) SetConfigFiles(files map[string][]byte) error {
+	cfg, diags := parseConfig(files)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	c.config = cfg
+
+	return nil
+}
+
+// SetVariables replaces the values of the root module input variables with
+// those of the given variable definitions file, which must be written in the
+// JSON syntax. Values are converted to the types of their variable blocks
+// when the configuration is walked, and values of undeclared variables are
+// ignored. A nil file removes every value.
+func (c *Core) SetVariables(src []byte) error {
+	if src == nil {
+		c.variables = nil
+
+		return nil
+	}
+
+	file, diags := hclparse.NewParser().ParseJSON(src, "terraform.tfvars.json")
+	if diags.HasErrors() {
+		return diags
+	}
+
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return diags
+	}
+
+	variables := make(map[string]zcty.Value, len(attrs))
+
+	for name, attr := range attrs {
+		val, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return diags
+		}
+
+		variables[name] = val
+	}
+
+	c.variables = variables
+
+	return nil
+}
+
+// Copy returns a Core using the same providers and configuration as the
+// receiver, with a copy of its state, so that operations on the copy do not
+// change the state of the receiver.
+func (c *Core) Copy() *Core {
+	return &Core{
+		providers: c.providers,
+		config:    c.config,
+		state:     c.state.deepCopy(),
+		variables: c.variables,
+	}
+}
+
+// State returns the current state, in the format of the terraform show -json
+// command.
+func (c *Core) State() (*tfjson.State, error) {
+	return c.state.json(c.providers)
+}
+
+// Refresh updates the state with the remote objects of the managed resources
+// and reads the data resources of the configuration.
+func (c *Core) Refresh(ctx context.Context) error {
+	w := c.newWalker(walkRefresh)
+
+	if err := w.walk(ctx); err != nil {
+		return err
+	}
+
+	c.state = w.state
+
+	return nil
+}
+
+// Plan refreshes a copy of the state and returns the changes required to make
+// the remote objects match the configuration, without applying them.
+func (c *Core) Plan(ctx context.Context) (*tfjson.Plan, error) {
+	w := c.newWalker(walkPlan)
+
+	if err := w.walk(ctx); err != nil {
+		return nil, err
+	}
+
+	return w.plan()
+}
+
+// Apply refreshes the state, plans the changes required to make the remote
+// objects match the configuration and applies them. The state is updated
+// with the changes applied before any error.
+func (c *Core) Apply(ctx context.Context) error {
+	w := c.newWalker(walkApply)
+
+	err := w.walk(ctx)
+
+	c.state = w.state
+
+	return err
+}
+
+// DestroyPlan refreshes a copy of the state and returns the changes required
+// to destroy every managed resource, without applying them.
+func (c *Core) DestroyPlan(ctx context.Context) (*tfjson.Plan, error) {
+	w := c.newWalker(walkRefresh)
+
+	if err := w.walk(ctx); err != nil {
+		return nil, err
+	}
+
+	w.changes = nil
+	w.outputChanges = nil
+
+	for _, ri := range destroyOrder(w.state.resources) {
+		w.changes = append(w.changes, &resourceChange{
+			instance: ri,
+			actions:  tfjson.Actions{tfjson.ActionDelete},
+			prior:    ri.value,
+			planned:  nullValue(ri.value),
+		})
+	}
+
+	for name, o := range w.state.outputs {
+		w.outputChanges = append(w.outputChanges, &outputChange{
+			name:    name,
+			actions: tfjson.Actions{tfjson.ActionDelete},
+			prior:   o,
+		})
+	}
+
+	return w.plan()
+}
+
+// Destroy refreshes the state and destroys every managed resource in it. The
+// state is updated with the resources destroyed before any error.
+func (c *Core) Destroy(ctx context.Context) error {
+	w := c.newWalker(walkRefresh)
+
+	if err := w.walk(ctx); err != nil {
+		return err
+	}
+
+	c.state = w.state
+
+	for _, ri := range destroyOrder(c.state.resources) {
+		if err := w.destroyInstance(ctx, ri); err != nil {
+			return err
+		}
+	}
+
+	for addr, ri := range c.state.resources {
+		if ri.mode == tfjson.DataResourceMode {
+			delete(c.state.resources, addr)
+		}
+	}
+
+	c.state.outputs = make(map[string]*outputValue)
+
+	return nil
+}
+
+// Import imports the remote object with the given import identifier into the
+// state as the given resource instance address, which must be declared in the
+// configuration, and reads it.
+func (c *Core) Import(ctx context.Context, addr, id string) error {
+	return c.importResource(ctx, addr, id, nil)
+}
+
+// ImportIdentity imports the remote object with the given resource identity,
+// in the format of the identity of a resource in the terraform show -json
+// command, into the state as the given resource instance address, and reads
+// it.
+func (c *Core) ImportIdentity(ctx context.Context, addr string, identity map[string]interface{}) error {
+	if identity == nil {
+		return fmt.Errorf("%s: import identity must not be nil", addr)
+	}
+
+	return c.importResource(ctx, addr, "", identity)
+}
+
+// importResource imports a remote object by either import identifier or
+// resource identity.
+func (c *Core) importResource(ctx context.Context, addr, id string, identity map[string]interface{}) error {
+	resAddr, index, err := parseInstanceAddr(addr)
+	if err != nil {
+		return err
+	}
+
+	addr = instanceAddr(resAddr, index)
+
+	rc, ok := c.config.resources[resAddr]
+	if !ok || rc.mode != tfjson.ManagedResourceMode {
+		return fmt.Errorf("Error: Configuration for import target does not exist\n\nThe configuration for the given import %s does not exist. All target instances must have an associated configuration to be imported.", addr)
+	}
+
+	if _, ok := c.state.resources[addr]; ok {
+		return fmt.Errorf("Error: Resource already managed by Terraform\n\nTerraform is already managing a remote object for %s. To import to this address you must first remove the existing object from the state.", addr)
+	}
+
+	// Walk the configuration first to configure the providers.
+	w := c.newWalker(walkRefresh)
+
+	if err := w.walk(ctx); err != nil {
+		return err
+	}
+
+	p := c.providers[rc.provider]
+
+	req := &tfprotov5.ImportResourceStateRequest{
+		TypeName: rc.typeName,
+		ID:       id,
+	}
+
+	if identity != nil {
+		is, err := p.identitySchema(rc.typeName)
+		if err != nil {
+			return err
+		}
+
+		ty := is.block.ImpliedType()
+
+		src, err := json.Marshal(identity)
+		if err != nil {
+			return fmt.Errorf("%s: error encoding import identity: %w", addr, err)
+		}
+
+		val, err := ctyjson.Unmarshal(src, ty)
+		if err != nil {
+			return fmt.Errorf("%s: invalid import identity: %w", addr, err)
+		}
+
+		req.Identity = &tfprotov5.ResourceIdentityData{
+			IdentityData: dynamicValue(ty, val),
+		}
+	}
+
+	resp, err := p.server.ImportResourceState(ctx, req)
+	if err != nil {
+		return fmt.Errorf("%s: error importing: %w", addr, err)
+	}
+
+	if err := diagnosticsError(resp.Diagnostics, addr); err != nil {
+		return err
+	}
+
+	if len(resp.ImportedResources) == 0 {
+		return fmt.Errorf("Error: Cannot import non-existent remote object\n\nWhile attempting to import an existing object to %q, the provider detected that no object exists with the given id. Only pre-existing objects can be imported; check that the id is correct and that it is associated with the provider's configured region or endpoint, or use \"terraform apply\" to create a new remote object for this resource.", addr)
+	}
+
+	for i, imported := range resp.ImportedResources {
+		s, err := p.resourceSchema(tfjson.ManagedResourceMode, imported.TypeName)
+		if err != nil {
+			return err
+		}
+
+		val, err := valueFromDynamic(s.block.ImpliedType(), imported.State)
+		if err != nil {
+			return fmt.Errorf("%s: error decoding imported state: %w", addr, err)
+		}
+
+		ri := &resourceInstance{
+			mode:          tfjson.ManagedResourceMode,
+			typeName:      imported.TypeName,
+			name:          rc.name,
+			index:         index,
+			provider:      rc.provider,
+			schemaVersion: s.version,
+			value:         val,
+			private:       imported.Private,
+			identity:      imported.Identity,
+		}
+
+		// Additional imported objects are named after the import
+		// target with a numeric suffix, as done by Terraform.
+		if i > 0 {
+			ri.name = fmt.Sprintf("%s-%d", rc.name, i)
+		}
+
+		if err := w.readResource(ctx, ri); err != nil {
+			return err
+		}
+
+		if _, ok := w.state.resources[ri.addr()]; !ok {
+			return fmt.Errorf("Error: Cannot import non-existent remote object\n\nWhile attempting to import an existing object to %q, the provider detected that no object exists with the given id. Only pre-existing objects can be imported; check that the id is correct and that it is associated with the provider's configured region or endpoint, or use \"terraform apply\" to create a new remote object for this resource.", ri.addr())
+		}
+	}
+
+	c.state = w.state
+
+	return nil
+}
+
+// Taint marks the given managed resource instance as tainted, so that it is
+// replaced by the next apply.
+func (c *Core) Taint(addr string) error {
+	if resAddr, index, err := parseInstanceAddr(addr); err == nil {
+		addr = instanceAddr(resAddr, index)
+	}
+
+	ri, ok := c.state.resources[addr]
+	if !ok || ri.mode != tfjson.ManagedResourceMode {
+		return fmt.Errorf("Error: No such resource instance\n\nResource instance %s was not found, so it cannot be tainted.", addr)
+	}
+
+	ri.tainted = true
+
+	return nil
+}
+
+// parseInstanceAddr parses a managed resource instance address such as
+// examplecloud_thing.foo or examplecloud_thing.foo[1], or with the legacy
+// index format of examplecloud_thing.foo.1, which the Terraform CLI also
+// accepts.
+func parseInstanceAddr(addr string) (string, *int, error) {
+	resAddr, key, hasKey := strings.Cut(addr, "[")
+
+	if !hasKey {
+		if parts := strings.Split(addr, "."); len(parts) == 3 {
+			i, err := strconv.Atoi(parts[2])
+			if err != nil || i < 0 {
+				return "", nil, fmt.Errorf("Error: Invalid address\n\n%q is not a valid managed resource instance address.", addr)
+			}
+
+			return parts[0] + "." + parts[1], &i, nil
+		}
+	}
+
+	if strings.Count(resAddr, ".") != 1 {
+		return "", nil, fmt.Errorf("Error: Invalid address\n\n%q is not a valid managed resource instance address.", addr)
+	}
+
+	if !hasKey {
+		return resAddr, nil, nil
+	}
+
+	i, err := strconv.Atoi(strings.TrimSuffix(key, "]"))
+	if err != nil || !strings.HasSuffix(key, "]") {
+		return "", nil, fmt.Errorf("Error: Invalid address\n\n%q is not a valid managed resource instance address.", addr)
+	}
+
+	return resAddr, &i, nil
+}
@@ -0,0 +1,730 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package inprocess
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testProvider returns a provider whose remote objects are stored in the
+// given map, keyed by ID.
+func testProvider(objects map[string]map[string]interface{}) *schema.Provider {
+	var nextID int
+
+	readThing := func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+		obj, ok := objects[d.Id()]
+		if !ok {
+			d.SetId("")
+
+			return nil
+		}
+
+		for k, v := range obj {
+			if err := d.Set(k, v); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		identity, err := d.Identity()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		return diag.FromErr(identity.Set("id", d.Id()))
+	}
+
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+		ConfigureContextFunc: func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return d.Get("prefix").(string), nil
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"test_thing": {
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"zone": {
+						Type:     schema.TypeString,
+						Optional: true,
+						ForceNew: true,
+					},
+					"label": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+				CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+					nextID++
+					d.SetId(fmt.Sprintf("thing-%d", nextID))
+
+					objects[d.Id()] = map[string]interface{}{
+						"name":  d.Get("name"),
+						"zone":  d.Get("zone"),
+						"label": meta.(string) + d.Get("name").(string),
+					}
+
+					return readThing(ctx, d, meta)
+				},
+				ReadContext: readThing,
+				UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+					objects[d.Id()]["name"] = d.Get("name")
+					objects[d.Id()]["label"] = meta.(string) + d.Get("name").(string)
+
+					return readThing(ctx, d, meta)
+				},
+				DeleteContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+					delete(objects, d.Id())
+
+					return nil
+				},
+				Importer: &schema.ResourceImporter{
+					StateContext: schema.ImportStatePassthroughWithIdentity("id"),
+				},
+				Identity: &schema.ResourceIdentity{
+					Version: 1,
+					SchemaFunc: func() map[string]*schema.Schema {
+						return map[string]*schema.Schema{
+							"id": {
+								Type:              schema.TypeString,
+								RequiredForImport: true,
+							},
+						}
+					},
+				},
+			},
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"test_lookup": {
+				Schema: map[string]*schema.Schema{
+					"id_in": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+				ReadContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+					obj, ok := objects[d.Get("id_in").(string)]
+					if !ok {
+						return diag.Errorf("object %s not found", d.Get("id_in"))
+					}
+
+					d.SetId(d.Get("id_in").(string))
+
+					return diag.FromErr(d.Set("name", obj["name"]))
+				},
+			},
+		},
+	}
+}
+
+func testCore(t *testing.T, objects map[string]map[string]interface{}) *Core {
+	t.Helper()
+
+	core, err := NewCore(context.Background(), map[string]tfprotov5.ProviderServer{
+		"test": schema.NewGRPCProviderServer(testProvider(objects)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return core
+}
+
+func testPlanActions(t *testing.T, core *Core, destroy bool) map[string]tfjson.Actions {
+	t.Helper()
+
+	var plan *tfjson.Plan
+	var err error
+
+	if destroy {
+		plan, err = core.DestroyPlan(context.Background())
+	} else {
+		plan, err = core.Plan(context.Background())
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected plan error: %s", err)
+	}
+
+	actions := make(map[string]tfjson.Actions)
+
+	for _, rc := range plan.ResourceChanges {
+		actions[rc.Address] = rc.Change.Actions
+	}
+
+	return actions
+}
+
+func testStateValues(t *testing.T, core *Core) map[string]map[string]interface{} {
+	t.Helper()
+
+	state, err := core.State()
+	if err != nil {
+		t.Fatalf("unexpected state error: %s", err)
+	}
+
+	values := make(map[string]map[string]interface{})
+
+	if state.Values == nil {
+		return values
+	}
+
+	for _, r := range state.Values.RootModule.Resources {
+		values[r.Address] = r.AttributeValues
+	}
+
+	return values
+}
+
+func TestCore_lifecycle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	objects := make(map[string]map[string]interface{})
+	core := testCore(t, objects)
+
+	config := `
+provider "test" {
+  prefix = var.prefix
+}
+
+variable "prefix" {
+  default = "p-"
+}
+
+locals {
+  names = ["a", "b"]
+}
+
+resource "test_thing" "foo" {
+  count = length(local.names)
+  name  = local.names[count.index]
+  zone  = "one"
+}
+
+data "test_lookup" "foo" {
+  id_in = test_thing.foo[1].id
+}
+
+output "label" {
+  value = "${test_thing.foo[0].label}/${data.test_lookup.foo.name}"
+}
+`
+
+	if err := core.SetConfig(config); err != nil {
+		t.Fatalf("unexpected config error: %s", err)
+	}
+
+	expectedActions := map[string]tfjson.Actions{
+		"test_thing.foo[0]":    {tfjson.ActionCreate},
+		"test_thing.foo[1]":    {tfjson.ActionCreate},
+		"data.test_lookup.foo": {tfjson.ActionRead},
+	}
+
+	if diff := cmp.Diff(expectedActions, testPlanActions(t, core, false)); diff != "" {
+		t.Fatalf("unexpected plan difference: %s", diff)
+	}
+
+	if err := core.Apply(ctx); err != nil {
+		t.Fatalf("unexpected apply error: %s", err)
+	}
+
+	if len(objects) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(objects))
+	}
+
+	state, err := core.State()
+	if err != nil {
+		t.Fatalf("unexpected state error: %s", err)
+	}
+
+	if got, want := state.Values.Outputs["label"].Value, "p-a/b"; got != want {
+		t.Fatalf("expected output %q, got %q", want, got)
+	}
+
+	for _, r := range state.Values.RootModule.Resources {
+		if r.Address == "data.test_lookup.foo" && !cmp.Equal(r.DependsOn, []string{"test_thing.foo"}) {
+			t.Fatalf("unexpected dependencies: %v", r.DependsOn)
+		}
+	}
+
+	expectedActions = map[string]tfjson.Actions{
+		"test_thing.foo[0]": {tfjson.ActionNoop},
+		"test_thing.foo[1]": {tfjson.ActionNoop},
+	}
+
+	if diff := cmp.Diff(expectedActions, testPlanActions(t, core, false)); diff != "" {
+		t.Fatalf("unexpected plan difference after apply: %s", diff)
+	}
+
+	// Changes outside of Terraform are detected by the refresh.
+	objects["thing-1"]["name"] = "changed"
+
+	// The data resource depends on a resource with a pending change, so it is
+	// read during apply.
+	expectedActions["test_thing.foo[0]"] = tfjson.Actions{tfjson.ActionUpdate}
+	expectedActions["data.test_lookup.foo"] = tfjson.Actions{tfjson.ActionRead}
+
+	if diff := cmp.Diff(expectedActions, testPlanActions(t, core, false)); diff != "" {
+		t.Fatalf("unexpected plan difference after remote change: %s", diff)
+	}
+
+	if err := core.Apply(ctx); err != nil {
+		t.Fatalf("unexpected apply error: %s", err)
+	}
+
+	// Changing a ForceNew attribute replaces the resources and removing an
+	// instance destroys it.
+	config = strings.Replace(config, `["a", "b"]`, `["a"]`, 1)
+	config = strings.Replace(config, `"one"`, `"two"`, 1)
+	config = strings.Replace(config, `test_thing.foo[1].id`, `test_thing.foo[0].id`, 1)
+
+	if err := core.SetConfig(config); err != nil {
+		t.Fatalf("unexpected config error: %s", err)
+	}
+
+	expectedActions = map[string]tfjson.Actions{
+		"test_thing.foo[0]":    {tfjson.ActionDelete, tfjson.ActionCreate},
+		"test_thing.foo[1]":    {tfjson.ActionDelete},
+		"data.test_lookup.foo": {tfjson.ActionRead},
+	}
+
+	if diff := cmp.Diff(expectedActions, testPlanActions(t, core, false)); diff != "" {
+		t.Fatalf("unexpected plan difference after config change: %s", diff)
+	}
+
+	if err := core.Apply(ctx); err != nil {
+		t.Fatalf("unexpected apply error: %s", err)
+	}
+
+	expectedObjects := map[string]map[string]interface{}{
+		"thing-3": {
+			"name":  "a",
+			"zone":  "two",
+			"label": "p-a",
+		},
+	}
+
+	if diff := cmp.Diff(expectedObjects, objects); diff != "" {
+		t.Fatalf("unexpected objects difference: %s", diff)
+	}
+
+	if err := core.Taint("test_thing.foo[0]"); err != nil {
+		t.Fatalf("unexpected taint error: %s", err)
+	}
+
+	expectedActions = map[string]tfjson.Actions{
+		"test_thing.foo[0]":    {tfjson.ActionDelete, tfjson.ActionCreate},
+		"data.test_lookup.foo": {tfjson.ActionRead},
+	}
+
+	if diff := cmp.Diff(expectedActions, testPlanActions(t, core, false)); diff != "" {
+		t.Fatalf("unexpected plan difference after taint: %s", diff)
+	}
+
+	expectedActions = map[string]tfjson.Actions{
+		"test_thing.foo[0]": {tfjson.ActionDelete},
+	}
+
+	if diff := cmp.Diff(expectedActions, testPlanActions(t, core, true)); diff != "" {
+		t.Fatalf("unexpected destroy plan difference: %s", diff)
+	}
+
+	if err := core.Destroy(ctx); err != nil {
+		t.Fatalf("unexpected destroy error: %s", err)
+	}
+
+	if len(objects) != 0 {
+		t.Fatalf("expected no objects, got %v", objects)
+	}
+
+	if values := testStateValues(t, core); len(values) != 0 {
+		t.Fatalf("expected empty state, got %v", values)
+	}
+}
+
+func TestCore_Import(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	objects := map[string]map[string]interface{}{
+		"existing": {
+			"name":  "imported",
+			"zone":  "one",
+			"label": "imported",
+		},
+	}
+	core := testCore(t, objects)
+
+	config := `
+resource "test_thing" "foo" {
+  name = "imported"
+  zone = "one"
+}
+`
+
+	if err := core.SetConfig(config); err != nil {
+		t.Fatalf("unexpected config error: %s", err)
+	}
+
+	err := core.Import(ctx, "test_thing.foo", "missing")
+	if err == nil || !strings.Contains(err.Error(), "Cannot import non-existent remote object") {
+		t.Fatalf("expected non-existent object error, got: %v", err)
+	}
+
+	if err := core.Import(ctx, "test_thing.foo", "existing"); err != nil {
+		t.Fatalf("unexpected import error: %s", err)
+	}
+
+	expectedValues := map[string]map[string]interface{}{
+		"test_thing.foo": {
+			"id":    "existing",
+			"name":  "imported",
+			"zone":  "one",
+			"label": "imported",
+		},
+	}
+
+	if diff := cmp.Diff(expectedValues, testStateValues(t, core)); diff != "" {
+		t.Fatalf("unexpected state difference: %s", diff)
+	}
+
+	expectedActions := map[string]tfjson.Actions{
+		"test_thing.foo": {tfjson.ActionNoop},
+	}
+
+	if diff := cmp.Diff(expectedActions, testPlanActions(t, core, false)); diff != "" {
+		t.Fatalf("unexpected plan difference: %s", diff)
+	}
+
+	err = core.Import(ctx, "test_thing.foo", "existing")
+	if err == nil || !strings.Contains(err.Error(), "Resource already managed by Terraform") {
+		t.Fatalf("expected already managed error, got: %v", err)
+	}
+}
+
+func TestCore_ImportCount(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	objects := map[string]map[string]interface{}{
+		"first": {
+			"name":  "imported",
+			"zone":  "one",
+			"label": "imported",
+		},
+		"second": {
+			"name":  "imported",
+			"zone":  "one",
+			"label": "imported",
+		},
+	}
+	core := testCore(t, objects)
+
+	config := `
+resource "test_thing" "foo" {
+  count = 2
+
+  name = "imported"
+  zone = "one"
+}
+`
+
+	if err := core.SetConfig(config); err != nil {
+		t.Fatalf("unexpected config error: %s", err)
+	}
+
+	if err := core.Import(ctx, "test_thing.foo[0]", "first"); err != nil {
+		t.Fatalf("unexpected import error: %s", err)
+	}
+
+	// The legacy index format is also accepted.
+	if err := core.Import(ctx, "test_thing.foo.1", "second"); err != nil {
+		t.Fatalf("unexpected import error: %s", err)
+	}
+
+	expectedValues := map[string]map[string]interface{}{
+		"test_thing.foo[0]": {
+			"id":    "first",
+			"name":  "imported",
+			"zone":  "one",
+			"label": "imported",
+		},
+		"test_thing.foo[1]": {
+			"id":    "second",
+			"name":  "imported",
+			"zone":  "one",
+			"label": "imported",
+		},
+	}
+
+	if diff := cmp.Diff(expectedValues, testStateValues(t, core)); diff != "" {
+		t.Fatalf("unexpected state difference: %s", diff)
+	}
+
+	err := core.Import(ctx, "test_thing.foo.0", "first")
+	if err == nil || !strings.Contains(err.Error(), "Resource already managed by Terraform") {
+		t.Fatalf("expected already managed error, got: %v", err)
+	}
+}
+
+func TestParseInstanceAddr(t *testing.T) {
+	t.Parallel()
+
+	index := 1
+
+	testCases := map[string]struct {
+		addr          string
+		expectedAddr  string
+		expectedIndex *int
+		expectError   bool
+	}{
+		"no-index": {
+			addr:         "test_thing.foo",
+			expectedAddr: "test_thing.foo",
+		},
+		"index": {
+			addr:          "test_thing.foo[1]",
+			expectedAddr:  "test_thing.foo",
+			expectedIndex: &index,
+		},
+		"legacy-index": {
+			addr:          "test_thing.foo.1",
+			expectedAddr:  "test_thing.foo",
+			expectedIndex: &index,
+		},
+		"invalid-index": {
+			addr:        "test_thing.foo[a]",
+			expectError: true,
+		},
+		"invalid-legacy-index": {
+			addr:        "test_thing.foo.a",
+			expectError: true,
+		},
+		"data-source": {
+			addr:        "data.test_thing.foo",
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			addr, index, err := parseInstanceAddr(testCase.addr)
+			if err != nil {
+				if !testCase.expectError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectError {
+				t.Fatal("expected error, got none")
+			}
+
+			if addr != testCase.expectedAddr {
+				t.Errorf("expected address %q, got %q", testCase.expectedAddr, addr)
+			}
+
+			if diff := cmp.Diff(testCase.expectedIndex, index); diff != "" {
+				t.Errorf("unexpected index difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestCore_ImportIdentity(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	objects := map[string]map[string]interface{}{
+		"existing": {
+			"name":  "imported",
+			"zone":  "one",
+			"label": "imported",
+		},
+	}
+	core := testCore(t, objects)
+
+	config := `
+resource "test_thing" "foo" {
+  name = "imported"
+  zone = "one"
+}
+`
+
+	if err := core.SetConfig(config); err != nil {
+		t.Fatalf("unexpected config error: %s", err)
+	}
+
+	err := core.ImportIdentity(ctx, "test_thing.foo", map[string]interface{}{"id": true, "other": "value"})
+	if err == nil || !strings.Contains(err.Error(), "invalid import identity") {
+		t.Fatalf("expected invalid identity error, got: %v", err)
+	}
+
+	if err := core.ImportIdentity(ctx, "test_thing.foo", map[string]interface{}{"id": "existing"}); err != nil {
+		t.Fatalf("unexpected import error: %s", err)
+	}
+
+	state, err := core.State()
+	if err != nil {
+		t.Fatalf("unexpected state error: %s", err)
+	}
+
+	r := state.Values.RootModule.Resources[0]
+
+	if got, want := r.AttributeValues["name"], "imported"; got != want {
+		t.Fatalf("expected name %q, got %q", want, got)
+	}
+
+	if diff := cmp.Diff(map[string]interface{}{"id": "existing"}, r.IdentityValues); diff != "" {
+		t.Fatalf("unexpected identity difference: %s", diff)
+	}
+
+	if r.IdentitySchemaVersion == nil || *r.IdentitySchemaVersion != 1 {
+		t.Fatalf("expected identity schema version 1, got: %v", r.IdentitySchemaVersion)
+	}
+
+	expectedActions := map[string]tfjson.Actions{
+		"test_thing.foo": {tfjson.ActionNoop},
+	}
+
+	if diff := cmp.Diff(expectedActions, testPlanActions(t, core, false)); diff != "" {
+		t.Fatalf("unexpected plan difference: %s", diff)
+	}
+}
+
+func TestCore_SetConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Config   string
+		Expected string
+	}{
+		"syntax": {
+			Config:   `resource "test_thing" {}`,
+			Expected: "Missing name for resource",
+		},
+		"for_each": {
+			Config: `
+resource "test_thing" "foo" {
+  for_each = toset(["a"])
+  name     = each.key
+}
+`,
+			Expected: "for_each meta-arguments are not supported",
+		},
+		"module": {
+			Config: `
+module "foo" {
+  source = "./foo"
+}
+`,
+			Expected: `Blocks of type "module" are not expected here`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCore(t, nil).SetConfig(tc.Config)
+			if err == nil || !strings.Contains(err.Error(), tc.Expected) {
+				t.Fatalf("expected error containing %q, got: %v", tc.Expected, err)
+			}
+		})
+	}
+}
+
+func TestCore_missingProvider(t *testing.T) {
+	t.Parallel()
+
+	core := testCore(t, nil)
+
+	if err := core.SetConfig(`resource "other_thing" "foo" {}`); err != nil {
+		t.Fatalf("unexpected config error: %s", err)
+	}
+
+	_, err := core.Plan(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `provider "other" is not available`) {
+		t.Fatalf("expected missing provider error, got: %v", err)
+	}
+}
+
+func TestCore_SetConfigFiles(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	objects := make(map[string]map[string]interface{})
+	core := testCore(t, objects)
+
+	files := map[string][]byte{
+		"main.tf": []byte(`
+resource "test_thing" "foo" {
+  name = var.names[1]
+}
+`),
+		"variables.tf.json": []byte(`{"variable": {"names": {"type": "list(string)"}}}`),
+		"README.md":         []byte(`# not configuration`),
+	}
+
+	if err := core.SetConfigFiles(files); err != nil {
+		t.Fatalf("unexpected config error: %s", err)
+	}
+
+	if _, err := core.Plan(ctx); err == nil || !strings.Contains(err.Error(), `input variable "names" is not set`) {
+		t.Fatalf("expected missing variable error, got: %v", err)
+	}
+
+	// Values of undeclared variables are ignored, as they are by Terraform.
+	if err := core.SetVariables([]byte(`{"names": ["a", "b"], "undeclared": true}`)); err != nil {
+		t.Fatalf("unexpected variables error: %s", err)
+	}
+
+	if err := core.Apply(ctx); err != nil {
+		t.Fatalf("unexpected apply error: %s", err)
+	}
+
+	state, err := core.State()
+	if err != nil {
+		t.Fatalf("unexpected state error: %s", err)
+	}
+
+	if got, want := state.Values.RootModule.Resources[0].AttributeValues["name"], "b"; got != want {
+		t.Fatalf("expected name %q, got %q", want, got)
+	}
+
+	files["other.tf"] = []byte(`
+resource "test_thing" "foo" {
+  name = "other"
+}
+`)
+
+	err = core.SetConfigFiles(files)
+	if err == nil || !strings.Contains(err.Error(), "Duplicate managed resource configuration") {
+		t.Fatalf("expected duplicate resource error, got: %v", err)
+	}
+}
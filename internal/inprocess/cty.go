@@ -0,0 +1,238 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package inprocess
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	zcty "github.com/zclconf/go-cty/cty"
+)
+
+// The HCL parser evaluates expressions with github.com/zclconf/go-cty values,
+// while the SDK and its provider servers use the github.com/hashicorp/go-cty
+// fork. The functions in this file convert types and values between the two,
+// including unknown values, which cannot be round tripped through JSON.
+
+// toCtyType converts a zclconf/go-cty type into a hashicorp/go-cty type.
+func toCtyType(ty zcty.Type) cty.Type {
+	switch {
+	case ty == zcty.DynamicPseudoType:
+		return cty.DynamicPseudoType
+	case ty == zcty.String:
+		return cty.String
+	case ty == zcty.Number:
+		return cty.Number
+	case ty == zcty.Bool:
+		return cty.Bool
+	case ty.IsListType():
+		return cty.List(toCtyType(ty.ElementType()))
+	case ty.IsSetType():
+		return cty.Set(toCtyType(ty.ElementType()))
+	case ty.IsMapType():
+		return cty.Map(toCtyType(ty.ElementType()))
+	case ty.IsObjectType():
+		atys := make(map[string]cty.Type, len(ty.AttributeTypes()))
+		for name, aty := range ty.AttributeTypes() {
+			atys[name] = toCtyType(aty)
+		}
+
+		return cty.Object(atys)
+	case ty.IsTupleType():
+		etys := make([]cty.Type, 0, len(ty.TupleElementTypes()))
+		for _, ety := range ty.TupleElementTypes() {
+			etys = append(etys, toCtyType(ety))
+		}
+
+		return cty.Tuple(etys)
+	default:
+		panic(fmt.Sprintf("unsupported type %s", ty.FriendlyName()))
+	}
+}
+
+// fromCtyType converts a hashicorp/go-cty type into a zclconf/go-cty type.
+func fromCtyType(ty cty.Type) zcty.Type {
+	switch {
+	case ty == cty.DynamicPseudoType:
+		return zcty.DynamicPseudoType
+	case ty == cty.String:
+		return zcty.String
+	case ty == cty.Number:
+		return zcty.Number
+	case ty == cty.Bool:
+		return zcty.Bool
+	case ty.IsListType():
+		return zcty.List(fromCtyType(ty.ElementType()))
+	case ty.IsSetType():
+		return zcty.Set(fromCtyType(ty.ElementType()))
+	case ty.IsMapType():
+		return zcty.Map(fromCtyType(ty.ElementType()))
+	case ty.IsObjectType():
+		atys := make(map[string]zcty.Type, len(ty.AttributeTypes()))
+		for name, aty := range ty.AttributeTypes() {
+			atys[name] = fromCtyType(aty)
+		}
+
+		return zcty.Object(atys)
+	case ty.IsTupleType():
+		etys := make([]zcty.Type, 0, len(ty.TupleElementTypes()))
+		for _, ety := range ty.TupleElementTypes() {
+			etys = append(etys, fromCtyType(ety))
+		}
+
+		return zcty.Tuple(etys)
+	default:
+		panic(fmt.Sprintf("unsupported type %s", ty.FriendlyName()))
+	}
+}
+
+// toCtyValue converts a zclconf/go-cty value into a hashicorp/go-cty value.
+// Marks and refinements are discarded.
+func toCtyValue(val zcty.Value) cty.Value {
+	val, _ = val.UnmarkDeep()
+	ty := toCtyType(val.Type())
+
+	switch {
+	case !val.IsKnown():
+		return cty.UnknownVal(ty)
+	case val.IsNull():
+		return cty.NullVal(ty)
+	}
+
+	vty := val.Type()
+
+	switch {
+	case vty == zcty.String:
+		return cty.StringVal(val.AsString())
+	case vty == zcty.Number:
+		return cty.NumberVal(val.AsBigFloat())
+	case vty == zcty.Bool:
+		return cty.BoolVal(val.True())
+	case vty.IsListType(), vty.IsSetType(), vty.IsTupleType():
+		if val.LengthInt() == 0 {
+			switch {
+			case vty.IsListType():
+				return cty.ListValEmpty(ty.ElementType())
+			case vty.IsSetType():
+				return cty.SetValEmpty(ty.ElementType())
+			default:
+				return cty.EmptyTupleVal
+			}
+		}
+
+		elems := make([]cty.Value, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			elems = append(elems, toCtyValue(ev))
+		}
+
+		switch {
+		case vty.IsListType():
+			return cty.ListVal(elems)
+		case vty.IsSetType():
+			return cty.SetVal(elems)
+		default:
+			return cty.TupleVal(elems)
+		}
+	case vty.IsMapType():
+		if val.LengthInt() == 0 {
+			return cty.MapValEmpty(ty.ElementType())
+		}
+
+		elems := make(map[string]cty.Value, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			elems[k.AsString()] = toCtyValue(ev)
+		}
+
+		return cty.MapVal(elems)
+	case vty.IsObjectType():
+		if len(vty.AttributeTypes()) == 0 {
+			return cty.EmptyObjectVal
+		}
+
+		attrs := make(map[string]cty.Value, len(vty.AttributeTypes()))
+		for name := range vty.AttributeTypes() {
+			attrs[name] = toCtyValue(val.GetAttr(name))
+		}
+
+		return cty.ObjectVal(attrs)
+	default:
+		panic(fmt.Sprintf("unsupported value type %s", vty.FriendlyName()))
+	}
+}
+
+// fromCtyValue converts a hashicorp/go-cty value into a zclconf/go-cty value.
+func fromCtyValue(val cty.Value) zcty.Value {
+	ty := fromCtyType(val.Type())
+
+	switch {
+	case !val.IsKnown():
+		return zcty.UnknownVal(ty)
+	case val.IsNull():
+		return zcty.NullVal(ty)
+	}
+
+	vty := val.Type()
+
+	switch {
+	case vty == cty.String:
+		return zcty.StringVal(val.AsString())
+	case vty == cty.Number:
+		return zcty.NumberVal(val.AsBigFloat())
+	case vty == cty.Bool:
+		return zcty.BoolVal(val.True())
+	case vty.IsListType(), vty.IsSetType(), vty.IsTupleType():
+		if val.LengthInt() == 0 {
+			switch {
+			case vty.IsListType():
+				return zcty.ListValEmpty(ty.ElementType())
+			case vty.IsSetType():
+				return zcty.SetValEmpty(ty.ElementType())
+			default:
+				return zcty.EmptyTupleVal
+			}
+		}
+
+		elems := make([]zcty.Value, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			elems = append(elems, fromCtyValue(ev))
+		}
+
+		switch {
+		case vty.IsListType():
+			return zcty.ListVal(elems)
+		case vty.IsSetType():
+			return zcty.SetVal(elems)
+		default:
+			return zcty.TupleVal(elems)
+		}
+	case vty.IsMapType():
+		if val.LengthInt() == 0 {
+			return zcty.MapValEmpty(ty.ElementType())
+		}
+
+		elems := make(map[string]zcty.Value, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			elems[k.AsString()] = fromCtyValue(ev)
+		}
+
+		return zcty.MapVal(elems)
+	case vty.IsObjectType():
+		if len(vty.AttributeTypes()) == 0 {
+			return zcty.EmptyObjectVal
+		}
+
+		attrs := make(map[string]zcty.Value, len(vty.AttributeTypes()))
+		for name := range vty.AttributeTypes() {
+			attrs[name] = fromCtyValue(val.GetAttr(name))
+		}
+
+		return zcty.ObjectVal(attrs)
+	default:
+		panic(fmt.Sprintf("unsupported value type %s", vty.FriendlyName()))
+	}
+}
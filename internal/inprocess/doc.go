@@ -0,0 +1,21 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package inprocess implements a minimal Terraform core, which plans,
+// applies, refreshes, imports and destroys the resources of a single
+// configuration file by calling the RPCs of protocol version 5 provider
+// servers running in the same process.
+//
+// It is used by the acceptance testing framework when a TestCase opts into
+// InProcessCore, so that tests do not require a Terraform CLI binary or
+// network access. Only the subset of the Terraform language used by typical
+// acceptance test configurations is supported: provider, resource, data,
+// variable, locals and output blocks in the root module, the count, provider
+// and depends_on meta-arguments, and the functions of the cty standard
+// library. Modules, for_each, provisioners and lifecycle customizations are
+// not supported.
+//
+// The state and plans of a Core are exposed with the terraform-json types
+// returned by the Terraform CLI, so the testing framework can check them in
+// the same way as when running Terraform.
+package inprocess
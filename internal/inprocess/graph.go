@@ -0,0 +1,260 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package inprocess
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hcldec"
+)
+
+// graph is the dependency graph of a configuration. Its nodes are named with
+// the addresses used to reference them, such as var.name, local.name,
+// examplecloud_thing.name and data.examplecloud_thing.name, plus
+// provider.name for provider configurations and output.name for outputs.
+type graph struct {
+	// dependencies contains the direct dependencies of every node.
+	dependencies map[string][]string
+}
+
+// buildGraph returns the dependency graph of the configuration of the core,
+// including the providers of the resources in the given state, which must all
+// be available.
+func (c *Core) buildGraph(st *state) (*graph, error) {
+	cfg := c.config
+	deps := make(map[string][]string)
+
+	for name := range cfg.variables {
+		deps["var."+name] = nil
+	}
+
+	for name, expr := range cfg.locals {
+		deps["local."+name] = referenceAddrs(expr.Variables())
+	}
+
+	for name := range cfg.providers {
+		deps["provider."+name] = nil
+	}
+
+	for _, r := range cfg.resources {
+		deps["provider."+r.provider] = nil
+	}
+
+	for _, ri := range st.resources {
+		deps["provider."+ri.provider] = nil
+	}
+
+	for node := range deps {
+		name, ok := strings.CutPrefix(node, "provider.")
+		if !ok {
+			continue
+		}
+
+		p, ok := c.providers[name]
+		if !ok {
+			return nil, fmt.Errorf("provider %q is not available: the configuration requires a provider which was not given to the test", name)
+		}
+
+		if pc, ok := cfg.providers[name]; ok {
+			deps[node] = referenceAddrs(hcldec.Variables(pc.body, specForBlock(p.schema)))
+		}
+	}
+
+	for addr, r := range cfg.resources {
+		s, err := c.providers[r.provider].resourceSchema(r.mode, r.typeName)
+		if err != nil {
+			return nil, err
+		}
+
+		refs := []string{"provider." + r.provider}
+
+		if r.count != nil {
+			refs = append(refs, referenceAddrs(r.count.Variables())...)
+		}
+
+		refs = append(refs, referenceAddrs(hcldec.Variables(r.body, specForBlock(s.block)))...)
+		refs = append(refs, referenceAddrs(r.dependsOn)...)
+
+		deps[addr] = refs
+	}
+
+	for name, o := range cfg.outputs {
+		refs := referenceAddrs(o.expr.Variables())
+		refs = append(refs, referenceAddrs(o.dependsOn)...)
+
+		deps["output."+name] = refs
+	}
+
+	// References to undeclared objects are reported when the expressions
+	// are evaluated.
+	for node, refs := range deps {
+		var known []string
+
+		for _, ref := range refs {
+			if _, ok := deps[ref]; ok && ref != node {
+				known = append(known, ref)
+			}
+		}
+
+		deps[node] = known
+	}
+
+	return &graph{
+		dependencies: deps,
+	}, nil
+}
+
+// order returns the nodes of the graph in an order where every node comes
+// after its dependencies.
+func (g *graph) order() ([]string, error) {
+	remaining := make(map[string]int, len(g.dependencies))
+	dependents := make(map[string][]string)
+
+	for node, deps := range g.dependencies {
+		remaining[node] = len(deps)
+
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], node)
+		}
+	}
+
+	var ready []string
+
+	for node, n := range remaining {
+		if n == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	order := make([]string, 0, len(g.dependencies))
+
+	for len(ready) > 0 {
+		sort.Strings(ready)
+
+		node := ready[0]
+		ready = ready[1:]
+		order = append(order, node)
+
+		for _, dependent := range dependents[node] {
+			remaining[dependent]--
+
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) < len(g.dependencies) {
+		var cycle []string
+
+		for node, n := range remaining {
+			if n > 0 {
+				cycle = append(cycle, node)
+			}
+		}
+
+		sort.Strings(cycle)
+
+		return nil, fmt.Errorf("Error: Cycle: %s", strings.Join(cycle, ", "))
+	}
+
+	return order, nil
+}
+
+// resourceDependencies returns the addresses of the resources the given node
+// depends on, either directly or through other nodes.
+func (g *graph) resourceDependencies(node string) []string {
+	seen := make(map[string]bool)
+	queue := append([]string(nil), g.dependencies[node]...)
+
+	var result []string
+
+	for len(queue) > 0 {
+		dep := queue[0]
+		queue = queue[1:]
+
+		if seen[dep] {
+			continue
+		}
+
+		seen[dep] = true
+
+		if isResourceAddr(dep) {
+			result = append(result, dep)
+		}
+
+		queue = append(queue, g.dependencies[dep]...)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// isResourceAddr returns whether the given node of the graph is a resource or
+// data resource.
+func isResourceAddr(node string) bool {
+	prefix, _, _ := strings.Cut(node, ".")
+
+	switch prefix {
+	case "var", "local", "provider", "output":
+		return false
+	default:
+		return true
+	}
+}
+
+// referenceAddrs returns the graph nodes referenced by the given traversals.
+func referenceAddrs(traversals []hcl.Traversal) []string {
+	var addrs []string
+
+	for _, t := range traversals {
+		if addr := referenceAddr(t); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs
+}
+
+// referenceAddr returns the graph node referenced by the given traversal, or
+// an empty string if it does not reference a node.
+func referenceAddr(t hcl.Traversal) string {
+	if t.IsRelative() || len(t) < 2 {
+		return ""
+	}
+
+	root := t.RootName()
+
+	switch root {
+	case "count", "each", "path", "self", "terraform":
+		return ""
+	case "data":
+		if len(t) < 3 {
+			return ""
+		}
+
+		typeName, ok := t[1].(hcl.TraverseAttr)
+		if !ok {
+			return ""
+		}
+
+		name, ok := t[2].(hcl.TraverseAttr)
+		if !ok {
+			return ""
+		}
+
+		return "data." + typeName.Name + "." + name.Name
+	default:
+		name, ok := t[1].(hcl.TraverseAttr)
+		if !ok {
+			return ""
+		}
+
+		return root + "." + name.Name
+	}
+}
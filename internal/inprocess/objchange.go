@@ -0,0 +1,212 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package inprocess
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+)
+
+// proposedNew returns the proposed new state of a resource, which is the
+// configuration with the prior values of computed attributes which are not
+// set in the configuration, in the same way as Terraform.
+func proposedNew(schema *configschema.Block, prior, config cty.Value) cty.Value {
+	if config.IsNull() || !config.IsKnown() {
+		return config
+	}
+
+	if prior.IsNull() || !prior.IsKnown() {
+		prior = schema.EmptyValue()
+	}
+
+	vals := make(map[string]cty.Value, len(schema.Attributes)+len(schema.BlockTypes))
+
+	for name, attr := range schema.Attributes {
+		configV := config.GetAttr(name)
+
+		if attr.Computed && configV.IsNull() {
+			vals[name] = prior.GetAttr(name)
+
+			continue
+		}
+
+		vals[name] = configV
+	}
+
+	for name, nb := range schema.BlockTypes {
+		vals[name] = proposedNewNestedBlock(nb, prior.GetAttr(name), config.GetAttr(name))
+	}
+
+	return cty.ObjectVal(vals)
+}
+
+func proposedNewNestedBlock(nb *configschema.NestedBlock, prior, config cty.Value) cty.Value {
+	if config.IsNull() || !config.IsKnown() {
+		return config
+	}
+
+	ty := config.Type()
+
+	switch nb.Nesting {
+	case configschema.NestingSingle, configschema.NestingGroup:
+		return proposedNew(&nb.Block, prior, config)
+	case configschema.NestingList:
+		if config.LengthInt() == 0 {
+			return config
+		}
+
+		var priorElems []cty.Value
+		if !prior.IsNull() && prior.IsKnown() {
+			priorElems = prior.AsValueSlice()
+		}
+
+		elems := make([]cty.Value, 0, config.LengthInt())
+
+		for i, cv := range config.AsValueSlice() {
+			pv := cty.NullVal(nb.Block.ImpliedType())
+			if i < len(priorElems) {
+				pv = priorElems[i]
+			}
+
+			elems = append(elems, proposedNew(&nb.Block, pv, cv))
+		}
+
+		if ty.IsTupleType() {
+			return cty.TupleVal(elems)
+		}
+
+		return cty.ListVal(elems)
+	case configschema.NestingSet:
+		if config.LengthInt() == 0 {
+			return config
+		}
+
+		var priorElems []cty.Value
+		if !prior.IsNull() && prior.IsKnown() {
+			priorElems = prior.AsValueSlice()
+		}
+
+		used := make([]bool, len(priorElems))
+		elems := make([]cty.Value, 0, config.LengthInt())
+
+		// Set elements have no identity, so a prior element is matched
+		// with a configured element when all of their attributes which
+		// are not computed are equal.
+		for _, cv := range config.AsValueSlice() {
+			pv := cty.NullVal(nb.Block.ImpliedType())
+
+			for i, candidate := range priorElems {
+				if !used[i] && nonComputedAttributesEqual(&nb.Block, candidate, cv) {
+					used[i] = true
+					pv = candidate
+
+					break
+				}
+			}
+
+			elems = append(elems, proposedNew(&nb.Block, pv, cv))
+		}
+
+		return cty.SetVal(elems)
+	case configschema.NestingMap:
+		if config.LengthInt() == 0 {
+			return config
+		}
+
+		elems := make(map[string]cty.Value, config.LengthInt())
+
+		for it := config.ElementIterator(); it.Next(); {
+			k, cv := it.Element()
+
+			pv := cty.NullVal(nb.Block.ImpliedType())
+			if !prior.IsNull() && prior.IsKnown() && prior.Type().IsMapType() && prior.HasIndex(k).True() {
+				pv = prior.Index(k)
+			}
+
+			elems[k.AsString()] = proposedNew(&nb.Block, pv, cv)
+		}
+
+		if ty.IsObjectType() {
+			return cty.ObjectVal(elems)
+		}
+
+		return cty.MapVal(elems)
+	default:
+		return config
+	}
+}
+
+func nonComputedAttributesEqual(schema *configschema.Block, a, b cty.Value) bool {
+	if a.IsNull() || b.IsNull() || !a.IsKnown() || !b.IsKnown() {
+		return false
+	}
+
+	for name, attr := range schema.Attributes {
+		if attr.Computed {
+			continue
+		}
+
+		if !a.GetAttr(name).RawEquals(b.GetAttr(name)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// plannedDataValue returns the planned value of a data resource whose read
+// is deferred until apply, which is the configuration where computed
+// attributes which are not set are unknown.
+func plannedDataValue(schema *configschema.Block, config cty.Value) cty.Value {
+	if config.IsNull() || !config.IsKnown() {
+		return config
+	}
+
+	vals := make(map[string]cty.Value, len(schema.Attributes)+len(schema.BlockTypes))
+
+	for name, attr := range schema.Attributes {
+		configV := config.GetAttr(name)
+
+		if attr.Computed && configV.IsNull() {
+			vals[name] = cty.UnknownVal(attr.Type)
+
+			continue
+		}
+
+		vals[name] = configV
+	}
+
+	for name, nb := range schema.BlockTypes {
+		configV := config.GetAttr(name)
+
+		if configV.IsNull() || !configV.IsKnown() {
+			vals[name] = configV
+
+			continue
+		}
+
+		switch nb.Nesting {
+		case configschema.NestingSingle, configschema.NestingGroup:
+			vals[name] = plannedDataValue(&nb.Block, configV)
+		default:
+			if configV.LengthInt() == 0 {
+				vals[name] = configV
+
+				continue
+			}
+
+			vals[name], _ = cty.Transform(configV, func(p cty.Path, v cty.Value) (cty.Value, error) {
+				// Only the elements of the collection are transformed.
+				if len(p) != 1 {
+					return v, nil
+				}
+
+				return plannedDataValue(&nb.Block, v), nil
+			})
+		}
+	}
+
+	return cty.ObjectVal(vals)
+}
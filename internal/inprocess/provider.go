@@ -0,0 +1,299 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package inprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugin/convert"
+)
+
+// provider is a provider server and the schemas it returned.
+type provider struct {
+	name   string
+	server tfprotov5.ProviderServer
+
+	schema      *configschema.Block
+	resources   map[string]*resourceSchema
+	dataSources map[string]*resourceSchema
+
+	// identities are the identity schemas of the resource types which
+	// support resource identity.
+	identities map[string]*resourceSchema
+
+	// config is the configuration the provider was last configured with,
+	// so that providers are only configured again when their configuration
+	// changes.
+	configured bool
+	config     cty.Value
+}
+
+type resourceSchema struct {
+	block   *configschema.Block
+	version int64
+}
+
+func newProvider(ctx context.Context, name string, server tfprotov5.ProviderServer) (*provider, error) {
+	resp, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("provider %q: error getting schema: %w", name, err)
+	}
+
+	if err := diagnosticsError(resp.Diagnostics, ""); err != nil {
+		return nil, fmt.Errorf("provider %q: error getting schema: %w", name, err)
+	}
+
+	p := &provider{
+		name:        name,
+		server:      server,
+		schema:      &configschema.Block{},
+		resources:   make(map[string]*resourceSchema, len(resp.ResourceSchemas)),
+		dataSources: make(map[string]*resourceSchema, len(resp.DataSourceSchemas)),
+		identities:  make(map[string]*resourceSchema),
+	}
+
+	if resp.Provider != nil && resp.Provider.Block != nil {
+		p.schema = convert.ProtoToConfigSchema(ctx, resp.Provider.Block)
+	}
+
+	for typeName, s := range resp.ResourceSchemas {
+		p.resources[typeName] = &resourceSchema{
+			block:   convert.ProtoToConfigSchema(ctx, s.Block),
+			version: s.Version,
+		}
+	}
+
+	for typeName, s := range resp.DataSourceSchemas {
+		p.dataSources[typeName] = &resourceSchema{
+			block:   convert.ProtoToConfigSchema(ctx, s.Block),
+			version: s.Version,
+		}
+	}
+
+	identityResp, err := server.GetResourceIdentitySchemas(ctx, &tfprotov5.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("provider %q: error getting identity schemas: %w", name, err)
+	}
+
+	if err := diagnosticsError(identityResp.Diagnostics, ""); err != nil {
+		return nil, fmt.Errorf("provider %q: error getting identity schemas: %w", name, err)
+	}
+
+	for typeName, s := range identityResp.IdentitySchemas {
+		p.identities[typeName] = &resourceSchema{
+			block:   convert.ProtoToIdentitySchema(ctx, s.IdentityAttributes),
+			version: s.Version,
+		}
+	}
+
+	return p, nil
+}
+
+// identitySchema returns the identity schema of the given resource type.
+func (p *provider) identitySchema(typeName string) (*resourceSchema, error) {
+	s, ok := p.identities[typeName]
+	if !ok {
+		return nil, fmt.Errorf("provider %q does not support resource identity for resource type %q", p.name, typeName)
+	}
+
+	return s, nil
+}
+
+// resourceSchema returns the schema of the given resource or data source
+// type.
+func (p *provider) resourceSchema(mode tfjson.ResourceMode, typeName string) (*resourceSchema, error) {
+	schemas := p.resources
+	kind := "resource"
+
+	if mode == tfjson.DataResourceMode {
+		schemas = p.dataSources
+		kind = "data source"
+	}
+
+	s, ok := schemas[typeName]
+	if !ok {
+		return nil, fmt.Errorf("provider %q does not support %s type %q", p.name, kind, typeName)
+	}
+
+	return s, nil
+}
+
+// configure validates the given provider configuration and configures the
+// provider with it, unless the provider was already configured with the same
+// configuration.
+func (p *provider) configure(ctx context.Context, config cty.Value) error {
+	if p.configured && p.config.RawEquals(config) {
+		return nil
+	}
+
+	ty := p.schema.ImpliedType()
+
+	prepareResp, err := p.server.PrepareProviderConfig(ctx, &tfprotov5.PrepareProviderConfigRequest{
+		Config: dynamicValue(ty, config),
+	})
+	if err != nil {
+		return fmt.Errorf("provider %q: error validating configuration: %w", p.name, err)
+	}
+
+	if err := diagnosticsError(prepareResp.Diagnostics, "provider["+p.name+"]"); err != nil {
+		return err
+	}
+
+	prepared := config
+
+	if prepareResp.PreparedConfig != nil {
+		prepared, err = valueFromDynamic(ty, prepareResp.PreparedConfig)
+		if err != nil {
+			return fmt.Errorf("provider %q: error decoding prepared configuration: %w", p.name, err)
+		}
+	}
+
+	configureResp, err := p.server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		TerraformVersion: TerraformVersion,
+		Config:           dynamicValue(ty, prepared),
+	})
+	if err != nil {
+		return fmt.Errorf("provider %q: error configuring: %w", p.name, err)
+	}
+
+	if err := diagnosticsError(configureResp.Diagnostics, "provider["+p.name+"]"); err != nil {
+		return err
+	}
+
+	p.configured = true
+	p.config = config
+
+	return nil
+}
+
+// specForBlock returns the specification used to decode the body of a
+// provider, resource or data block with the given schema.
+func specForBlock(b *configschema.Block) hcldec.ObjectSpec {
+	spec := make(hcldec.ObjectSpec, len(b.Attributes)+len(b.BlockTypes))
+
+	for name, attr := range b.Attributes {
+		spec[name] = &hcldec.AttrSpec{
+			Name:     name,
+			Type:     fromCtyType(attr.Type),
+			Required: attr.Required,
+		}
+	}
+
+	for name, nb := range b.BlockTypes {
+		nested := specForBlock(&nb.Block)
+
+		switch nb.Nesting {
+		case configschema.NestingSingle, configschema.NestingGroup:
+			spec[name] = &hcldec.BlockSpec{
+				TypeName: name,
+				Nested:   nested,
+				Required: nb.MinItems > 0,
+			}
+		case configschema.NestingList:
+			if nb.Block.ImpliedType().HasDynamicTypes() {
+				spec[name] = &hcldec.BlockTupleSpec{
+					TypeName: name,
+					Nested:   nested,
+					MinItems: nb.MinItems,
+					MaxItems: nb.MaxItems,
+				}
+
+				continue
+			}
+
+			spec[name] = &hcldec.BlockListSpec{
+				TypeName: name,
+				Nested:   nested,
+				MinItems: nb.MinItems,
+				MaxItems: nb.MaxItems,
+			}
+		case configschema.NestingSet:
+			spec[name] = &hcldec.BlockSetSpec{
+				TypeName: name,
+				Nested:   nested,
+				MinItems: nb.MinItems,
+				MaxItems: nb.MaxItems,
+			}
+		case configschema.NestingMap:
+			spec[name] = &hcldec.BlockMapSpec{
+				TypeName:   name,
+				LabelNames: []string{"key"},
+				Nested:     nested,
+			}
+		}
+	}
+
+	return spec
+}
+
+// dynamicValue encodes the given value for a provider server.
+func dynamicValue(ty cty.Type, val cty.Value) *tfprotov5.DynamicValue {
+	b, err := msgpack.Marshal(val, ty)
+	if err != nil {
+		// Values are always decoded using the schemas of the provider
+		// servers, so this cannot happen with valid schemas.
+		panic(fmt.Sprintf("error encoding value: %s", err))
+	}
+
+	return &tfprotov5.DynamicValue{
+		MsgPack: b,
+	}
+}
+
+// valueFromDynamic decodes a value returned by a provider server, which may be
+// either MessagePack or JSON encoded.
+func valueFromDynamic(ty cty.Type, dv *tfprotov5.DynamicValue) (cty.Value, error) {
+	if dv == nil {
+		return cty.NullVal(ty), nil
+	}
+
+	if len(dv.MsgPack) > 0 {
+		return msgpack.Unmarshal(dv.MsgPack, ty)
+	}
+
+	if len(dv.JSON) > 0 {
+		return ctyjson.Unmarshal(dv.JSON, ty)
+	}
+
+	return cty.NullVal(ty), nil
+}
+
+// diagnosticsError returns an error containing every error diagnostic, in the
+// format used by the Terraform CLI, or nil if there are no error diagnostics.
+func diagnosticsError(ds []*tfprotov5.Diagnostic, addr string) error {
+	var errs []error
+
+	for _, d := range ds {
+		if d == nil || d.Severity != tfprotov5.DiagnosticSeverityError {
+			continue
+		}
+
+		var msg strings.Builder
+
+		msg.WriteString("Error: " + d.Summary)
+
+		if addr != "" {
+			msg.WriteString("\n\n  with " + addr)
+		}
+
+		if d.Detail != "" {
+			msg.WriteString("\n\n" + d.Detail)
+		}
+
+		errs = append(errs, errors.New(msg.String()))
+	}
+
+	return errors.Join(errs...)
+}
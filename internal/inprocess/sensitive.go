@@ -0,0 +1,98 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package inprocess
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+)
+
+// sensitiveJSON returns the structure of the sensitive_values field of a
+// resource in a state, and of the before_sensitive and after_sensitive fields
+// of a planned change, for the given value of the given schema. Sensitive
+// attributes are true, and objects without sensitive attributes are omitted.
+func sensitiveJSON(block *configschema.Block, val cty.Value) map[string]interface{} {
+	result := make(map[string]interface{})
+
+	if val.IsNull() || !val.IsKnown() {
+		return result
+	}
+
+	for name, attr := range block.Attributes {
+		if attr.Sensitive {
+			result[name] = true
+
+			continue
+		}
+
+		if attr.NestedType == nil {
+			continue
+		}
+
+		nested := &configschema.Block{
+			Attributes: attr.NestedType.Attributes,
+		}
+
+		if s := nestedSensitiveJSON(nested, attr.NestedType.Nesting, val.GetAttr(name)); s != nil {
+			result[name] = s
+		}
+	}
+
+	for name, nb := range block.BlockTypes {
+		if s := nestedSensitiveJSON(&nb.Block, nb.Nesting, val.GetAttr(name)); s != nil {
+			result[name] = s
+		}
+	}
+
+	return result
+}
+
+// nestedSensitiveJSON returns the sensitive values structure of the given
+// nested object or collection of objects, or nil if none of the objects have
+// sensitive attributes.
+func nestedSensitiveJSON(block *configschema.Block, nesting configschema.NestingMode, val cty.Value) interface{} {
+	if val.IsNull() || !val.IsKnown() {
+		return nil
+	}
+
+	switch nesting {
+	case configschema.NestingSingle, configschema.NestingGroup:
+		if s := sensitiveJSON(block, val); len(s) > 0 {
+			return s
+		}
+	case configschema.NestingList, configschema.NestingSet:
+		var found bool
+
+		result := make([]interface{}, 0, val.LengthInt())
+
+		for it := val.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			s := sensitiveJSON(block, ev)
+
+			found = found || len(s) > 0
+			result = append(result, s)
+		}
+
+		if found {
+			return result
+		}
+	case configschema.NestingMap:
+		result := make(map[string]interface{})
+
+		for it := val.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+
+			if s := sensitiveJSON(block, ev); len(s) > 0 {
+				result[k.AsString()] = s
+			}
+		}
+
+		if len(result) > 0 {
+			return result
+		}
+	}
+
+	return nil
+}
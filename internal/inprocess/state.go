@@ -0,0 +1,315 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package inprocess
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// state is the state of a Core, which contains the resource instances it
+// manages and the values of the outputs of its configuration.
+type state struct {
+	resources map[string]*resourceInstance
+	outputs   map[string]*outputValue
+}
+
+type resourceInstance struct {
+	mode     tfjson.ResourceMode
+	typeName string
+	name     string
+
+	// index is the count index of the instance, or nil if the resource
+	// does not use count.
+	index *int
+
+	provider      string
+	schemaVersion int64
+	value         cty.Value
+	private       []byte
+	identity      *tfprotov5.ResourceIdentityData
+	tainted       bool
+	dependencies  []string
+}
+
+type outputValue struct {
+	value     cty.Value
+	sensitive bool
+}
+
+func newState() *state {
+	return &state{
+		resources: make(map[string]*resourceInstance),
+		outputs:   make(map[string]*outputValue),
+	}
+}
+
+// resourceAddr returns the address of the resource of the instance.
+func (ri *resourceInstance) resourceAddr() string {
+	return resourceAddr(ri.mode, ri.typeName, ri.name)
+}
+
+// addr returns the address of the instance, such as examplecloud_thing.foo
+// or examplecloud_thing.foo[1].
+func (ri *resourceInstance) addr() string {
+	return instanceAddr(ri.resourceAddr(), ri.index)
+}
+
+func instanceAddr(resourceAddr string, index *int) string {
+	if index == nil {
+		return resourceAddr
+	}
+
+	return resourceAddr + "[" + strconv.Itoa(*index) + "]"
+}
+
+// deepCopy returns a copy of the state which can be modified without
+// modifying the receiver.
+func (s *state) deepCopy() *state {
+	result := newState()
+
+	for addr, ri := range s.resources {
+		c := *ri
+		result.resources[addr] = &c
+	}
+
+	for name, o := range s.outputs {
+		c := *o
+		result.outputs[name] = &c
+	}
+
+	return result
+}
+
+// instances returns the instances of the given resource, ordered by address.
+func (s *state) instances(resourceAddr string) []*resourceInstance {
+	var result []*resourceInstance
+
+	for _, addr := range s.sortedAddrs() {
+		if ri := s.resources[addr]; ri.resourceAddr() == resourceAddr {
+			result = append(result, ri)
+		}
+	}
+
+	return result
+}
+
+func (s *state) sortedAddrs() []string {
+	addrs := make([]string, 0, len(s.resources))
+
+	for addr := range s.resources {
+		addrs = append(addrs, addr)
+	}
+
+	sort.Strings(addrs)
+
+	return addrs
+}
+
+// json returns the state in the format of the terraform show -json command.
+// The sensitive values of the resources are determined from the schemas of
+// the given providers.
+func (s *state) json(providers map[string]*provider) (*tfjson.State, error) {
+	result := &tfjson.State{
+		FormatVersion:    "1.0",
+		TerraformVersion: TerraformVersion,
+	}
+
+	if len(s.resources) == 0 && len(s.outputs) == 0 {
+		return result, nil
+	}
+
+	result.Values = &tfjson.StateValues{
+		Outputs:    make(map[string]*tfjson.StateOutput, len(s.outputs)),
+		RootModule: &tfjson.StateModule{},
+	}
+
+	for name, o := range s.outputs {
+		so, err := outputJSON(o)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Values.Outputs[name] = so
+	}
+
+	for _, addr := range s.sortedAddrs() {
+		ri := s.resources[addr]
+
+		values, err := valueJSON(ri.value)
+		if err != nil {
+			return nil, err
+		}
+
+		sr := &tfjson.StateResource{
+			Address:       ri.addr(),
+			Mode:          ri.mode,
+			Type:          ri.typeName,
+			Name:          ri.name,
+			ProviderName:  providerAddr(ri.provider),
+			SchemaVersion: uint64(ri.schemaVersion),
+			DependsOn:     ri.dependencies,
+			Tainted:       ri.tainted,
+		}
+
+		if ri.index != nil {
+			sr.Index = json.Number(strconv.Itoa(*ri.index))
+		}
+
+		if m, ok := values.(map[string]interface{}); ok {
+			sr.AttributeValues = m
+		}
+
+		if p, ok := providers[ri.provider]; ok {
+			if rs, err := p.resourceSchema(ri.mode, ri.typeName); err == nil {
+				sr.SensitiveValues, err = json.Marshal(sensitiveJSON(rs.block, ri.value))
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if err := identityJSON(p, ri, sr); err != nil {
+				return nil, err
+			}
+		}
+
+		result.Values.RootModule.Resources = append(result.Values.RootModule.Resources, sr)
+	}
+
+	return result, nil
+}
+
+// identityJSON sets the identity fields of the given state resource from the
+// identity of the given instance, if any, decoded with the identity schema of
+// the given provider.
+func identityJSON(p *provider, ri *resourceInstance, sr *tfjson.StateResource) error {
+	if ri.identity == nil || ri.identity.IdentityData == nil {
+		return nil
+	}
+
+	is, err := p.identitySchema(ri.typeName)
+	if err != nil {
+		return err
+	}
+
+	val, err := valueFromDynamic(is.block.ImpliedType(), ri.identity.IdentityData)
+	if err != nil {
+		return fmt.Errorf("%s: error decoding identity: %w", ri.addr(), err)
+	}
+
+	values, err := valueJSON(val)
+	if err != nil {
+		return err
+	}
+
+	if m, ok := values.(map[string]interface{}); ok {
+		version := uint64(is.version)
+
+		sr.IdentityValues = m
+		sr.IdentitySchemaVersion = &version
+	}
+
+	return nil
+}
+
+func outputJSON(o *outputValue) (*tfjson.StateOutput, error) {
+	value, err := valueJSON(o.value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfjson.StateOutput{
+		Sensitive: o.sensitive,
+		Value:     value,
+		Type:      fromCtyType(o.value.Type()),
+	}, nil
+}
+
+// providerAddr returns the address the Terraform CLI uses for the provider
+// with the given local name.
+func providerAddr(name string) string {
+	return "registry.terraform.io/hashicorp/" + name
+}
+
+// valueJSON returns the given wholly known value in the form decoded from the
+// JSON output of the Terraform CLI, where numbers are json.Number.
+func valueJSON(val cty.Value) (interface{}, error) {
+	if val.IsNull() {
+		return nil, nil
+	}
+
+	b, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var result interface{}
+
+	if err := dec.Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// unknownAsNull returns the given value with every unknown value replaced
+// with a null value, as unknown values cannot be encoded as JSON.
+func unknownAsNull(val cty.Value) cty.Value {
+	result, _ := cty.Transform(val, func(_ cty.Path, v cty.Value) (cty.Value, error) {
+		if !v.IsKnown() {
+			return cty.NullVal(v.Type()), nil
+		}
+
+		return v, nil
+	})
+
+	return result
+}
+
+// unknownJSON returns the structure of the after_unknown field of a planned
+// change for the given value, where unknown values are true.
+func unknownJSON(val cty.Value) interface{} {
+	if !val.IsKnown() {
+		return true
+	}
+
+	ty := val.Type()
+
+	switch {
+	case val.IsNull(), ty.IsPrimitiveType():
+		return false
+	case ty.IsObjectType(), ty.IsMapType():
+		result := make(map[string]interface{})
+
+		for it := val.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+
+			if u := unknownJSON(ev); u != false {
+				result[k.AsString()] = u
+			}
+		}
+
+		return result
+	default:
+		result := make([]interface{}, 0, val.LengthInt())
+
+		for it := val.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			result = append(result, unknownJSON(ev))
+		}
+
+		return result
+	}
+}
@@ -0,0 +1,1109 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package inprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	zcty "github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+	plugconvert "github.com/hashicorp/terraform-plugin-sdk/v2/internal/plugin/convert"
+)
+
+type walkOperation int
+
+const (
+	// walkRefresh refreshes the managed resources in the state and reads
+	// the data resources.
+	walkRefresh walkOperation = iota
+
+	// walkPlan refreshes the managed resources in the state, reads the
+	// data resources and plans the changes of the managed resources.
+	walkPlan
+
+	// walkApply is walkPlan followed by applying every planned change as
+	// soon as it is planned.
+	walkApply
+)
+
+// walker visits the nodes of the dependency graph of the configuration of a
+// core in dependency order, working on a copy of the state of the core.
+type walker struct {
+	core  *Core
+	op    walkOperation
+	graph *graph
+	state *state
+
+	changes       []*resourceChange
+	outputChanges []*outputChange
+
+	// pending contains the addresses of the resources with planned
+	// changes, whose dependent data resources are read during apply.
+	pending map[string]bool
+
+	// values contains the values of the variables, locals and resources
+	// visited so far, keyed by the address used to reference them.
+	values map[string]zcty.Value
+}
+
+type resourceChange struct {
+	instance *resourceInstance
+	actions  tfjson.Actions
+	reason   tfjson.ActionReason
+	prior    cty.Value
+	planned  cty.Value
+	config   cty.Value
+
+	plannedPrivate  []byte
+	plannedIdentity *tfprotov5.ResourceIdentityData
+}
+
+type outputChange struct {
+	name    string
+	actions tfjson.Actions
+	prior   *outputValue
+	planned *outputValue
+}
+
+func (c *Core) newWalker(op walkOperation) *walker {
+	return &walker{
+		core:    c,
+		op:      op,
+		state:   c.state.deepCopy(),
+		pending: make(map[string]bool),
+		values:  make(map[string]zcty.Value),
+	}
+}
+
+// walk visits every node of the graph of the configuration.
+func (w *walker) walk(ctx context.Context) error {
+	g, err := w.core.buildGraph(w.state)
+	if err != nil {
+		return err
+	}
+
+	w.graph = g
+
+	order, err := g.order()
+	if err != nil {
+		return err
+	}
+
+	cfg := w.core.config
+
+	// Managed resources which were removed from the configuration are
+	// destroyed after the configuration is applied.
+	orphans := make(map[string]*resourceInstance)
+
+	for addr, ri := range w.state.resources {
+		if _, ok := cfg.resources[ri.resourceAddr()]; !ok {
+			if ri.mode == tfjson.DataResourceMode {
+				delete(w.state.resources, addr)
+
+				continue
+			}
+
+			orphans[addr] = ri
+		}
+	}
+
+	for _, node := range order {
+		kind, name, _ := strings.Cut(node, ".")
+
+		switch kind {
+		case "var":
+			err = w.walkVariable(cfg.variables[name])
+		case "local":
+			err = w.walkLocal(name, cfg.locals[name])
+		case "provider":
+			err = w.walkProvider(ctx, name)
+		case "output":
+			err = w.walkOutput(cfg.outputs[name])
+		default:
+			rc := cfg.resources[node]
+
+			if rc.mode == tfjson.DataResourceMode {
+				err = w.walkDataResource(ctx, rc)
+			} else {
+				err = w.walkManagedResource(ctx, rc)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for name := range w.state.outputs {
+		if _, ok := cfg.outputs[name]; !ok {
+			w.outputChanges = append(w.outputChanges, &outputChange{
+				name:    name,
+				actions: tfjson.Actions{tfjson.ActionDelete},
+				prior:   w.state.outputs[name],
+			})
+
+			delete(w.state.outputs, name)
+		}
+	}
+
+	for _, ri := range destroyOrder(orphans) {
+		if err := w.readResource(ctx, ri); err != nil {
+			return err
+		}
+
+		if _, ok := w.state.resources[ri.addr()]; !ok {
+			continue
+		}
+
+		if err := w.handleChange(ctx, &resourceChange{
+			instance: ri,
+			actions:  tfjson.Actions{tfjson.ActionDelete},
+			reason:   tfjson.ActionReasonDeleteBecauseNoResourceConfig,
+			prior:    ri.value,
+			planned:  nullValue(ri.value),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// evalContext returns the context used to evaluate expressions, containing
+// the values of the nodes visited so far, and count.index when the given
+// index is not nil.
+func (w *walker) evalContext(index *int) *hcl.EvalContext {
+	vars := make(map[string]zcty.Value)
+	variables := make(map[string]zcty.Value)
+	locals := make(map[string]zcty.Value)
+	managed := make(map[string]map[string]zcty.Value)
+	data := make(map[string]map[string]zcty.Value)
+
+	for addr, val := range w.values {
+		kind, rest, _ := strings.Cut(addr, ".")
+
+		switch kind {
+		case "var":
+			variables[rest] = val
+		case "local":
+			locals[rest] = val
+		case "data":
+			typeName, name, _ := strings.Cut(rest, ".")
+
+			if data[typeName] == nil {
+				data[typeName] = make(map[string]zcty.Value)
+			}
+
+			data[typeName][name] = val
+		default:
+			if managed[kind] == nil {
+				managed[kind] = make(map[string]zcty.Value)
+			}
+
+			managed[kind][rest] = val
+		}
+	}
+
+	for typeName, names := range managed {
+		vars[typeName] = zcty.ObjectVal(names)
+	}
+
+	dataTypes := make(map[string]zcty.Value, len(data))
+	for typeName, names := range data {
+		dataTypes[typeName] = zcty.ObjectVal(names)
+	}
+
+	vars["data"] = zcty.ObjectVal(dataTypes)
+	vars["var"] = zcty.ObjectVal(variables)
+	vars["local"] = zcty.ObjectVal(locals)
+	vars["path"] = zcty.ObjectVal(map[string]zcty.Value{
+		"cwd":    zcty.StringVal("."),
+		"module": zcty.StringVal("."),
+		"root":   zcty.StringVal("."),
+	})
+	vars["terraform"] = zcty.ObjectVal(map[string]zcty.Value{
+		"workspace": zcty.StringVal("default"),
+	})
+
+	if index != nil {
+		vars["count"] = zcty.ObjectVal(map[string]zcty.Value{
+			"index": zcty.NumberIntVal(int64(*index)),
+		})
+	}
+
+	return &hcl.EvalContext{
+		Variables: vars,
+		Functions: functions,
+	}
+}
+
+func (w *walker) walkVariable(v *variableConfig) error {
+	val, ok := w.core.variables[v.name]
+
+	switch {
+	case ok:
+	case !v.required:
+		val = v.def
+	default:
+		return fmt.Errorf("Error: No value for required variable\n\nThe root module input variable %q is not set, and has no default value.", v.name)
+	}
+
+	val, err := convert.Convert(val, v.typ)
+	if err != nil {
+		return fmt.Errorf("Error: Invalid value for input variable\n\nThe given value is not suitable for var.%s: %s.", v.name, err)
+	}
+
+	w.values["var."+v.name] = val
+
+	return nil
+}
+
+func (w *walker) walkLocal(name string, expr hcl.Expression) error {
+	val, diags := expr.Value(w.evalContext(nil))
+	if diags.HasErrors() {
+		return diags
+	}
+
+	w.values["local."+name] = val
+
+	return nil
+}
+
+func (w *walker) walkProvider(ctx context.Context, name string) error {
+	p := w.core.providers[name]
+
+	var body hcl.Body = hcl.EmptyBody()
+	if pc, ok := w.core.config.providers[name]; ok {
+		body = pc.body
+	}
+
+	config, err := decodeBody(body, p.schema, w.evalContext(nil))
+	if err != nil {
+		return err
+	}
+
+	return p.configure(ctx, config)
+}
+
+func (w *walker) walkOutput(o *outputConfig) error {
+	val, diags := o.expr.Value(w.evalContext(nil))
+	if diags.HasErrors() {
+		return diags
+	}
+
+	planned := &outputValue{
+		value:     toCtyValue(val),
+		sensitive: o.sensitive,
+	}
+
+	// Only plans can contain unknown output values.
+	if w.op != walkPlan && !planned.value.IsWhollyKnown() {
+		return nil
+	}
+
+	prior := w.state.outputs[o.name]
+
+	actions := tfjson.Actions{tfjson.ActionUpdate}
+
+	switch {
+	case prior == nil:
+		actions = tfjson.Actions{tfjson.ActionCreate}
+	case prior.value.RawEquals(planned.value):
+		actions = tfjson.Actions{tfjson.ActionNoop}
+	}
+
+	w.outputChanges = append(w.outputChanges, &outputChange{
+		name:    o.name,
+		actions: actions,
+		prior:   prior,
+		planned: planned,
+	})
+
+	w.state.outputs[o.name] = planned
+
+	return nil
+}
+
+// expand returns the instance indexes of the given resource, which are nil
+// when the resource does not use count.
+func (w *walker) expand(rc *resourceConfig) ([]*int, error) {
+	if rc.count == nil {
+		return []*int{nil}, nil
+	}
+
+	val, diags := rc.count.Value(w.evalContext(nil))
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	val, err := convert.Convert(val, zcty.Number)
+
+	switch {
+	case err != nil, val.IsNull():
+		return nil, fmt.Errorf("Error: Invalid count argument\n\n  on %s:\n\nThe given \"count\" argument value is unsuitable: a number is required.", rc.addr())
+	case !val.IsKnown():
+		return nil, fmt.Errorf("Error: Invalid count argument\n\n  on %s:\n\nThe \"count\" value depends on resource attributes that cannot be determined until apply, so Terraform cannot predict how many instances will be created.", rc.addr())
+	}
+
+	n, _ := val.AsBigFloat().Int64()
+	if n < 0 {
+		return nil, fmt.Errorf("Error: Invalid count argument\n\n  on %s:\n\nThe given \"count\" argument value is unsuitable: must be greater than or equal to zero.", rc.addr())
+	}
+
+	indexes := make([]*int, 0, n)
+
+	for i := 0; i < int(n); i++ {
+		index := i
+		indexes = append(indexes, &index)
+	}
+
+	return indexes, nil
+}
+
+// setResourceValue records the values of the instances of the given resource
+// for references from other nodes.
+func (w *walker) setResourceValue(rc *resourceConfig, instances map[string]cty.Value, indexes []*int) {
+	if rc.count == nil {
+		w.values[rc.addr()] = fromCtyValue(instances[rc.addr()])
+
+		return
+	}
+
+	elems := make([]zcty.Value, 0, len(indexes))
+
+	for _, index := range indexes {
+		elems = append(elems, fromCtyValue(instances[instanceAddr(rc.addr(), index)]))
+	}
+
+	w.values[rc.addr()] = zcty.TupleVal(elems)
+}
+
+func (w *walker) walkManagedResource(ctx context.Context, rc *resourceConfig) error {
+	p := w.core.providers[rc.provider]
+
+	s, err := p.resourceSchema(rc.mode, rc.typeName)
+	if err != nil {
+		return err
+	}
+
+	indexes, err := w.expand(rc)
+	if err != nil {
+		return err
+	}
+
+	dependencies := w.graph.resourceDependencies(rc.addr())
+	instances := make(map[string]cty.Value, len(indexes))
+
+	for _, index := range indexes {
+		addr := instanceAddr(rc.addr(), index)
+
+		config, err := decodeBody(rc.body, s.block, w.evalContext(index))
+		if err != nil {
+			return err
+		}
+
+		ri, ok := w.state.resources[addr]
+		if ok {
+			if err := w.readResource(ctx, ri); err != nil {
+				return err
+			}
+
+			ri = w.state.resources[addr]
+		}
+
+		if w.op == walkRefresh {
+			instances[addr] = cty.UnknownVal(s.block.ImpliedType())
+
+			if ri != nil {
+				instances[addr] = ri.value
+			}
+
+			continue
+		}
+
+		if ri == nil {
+			ri = &resourceInstance{
+				mode:     rc.mode,
+				typeName: rc.typeName,
+				name:     rc.name,
+				index:    index,
+				provider: rc.provider,
+				value:    cty.NullVal(s.block.ImpliedType()),
+			}
+		}
+
+		ri.dependencies = dependencies
+
+		change, err := w.planResourceInstance(ctx, ri, config)
+		if err != nil {
+			return err
+		}
+
+		if err := w.handleChange(ctx, change); err != nil {
+			return err
+		}
+
+		instances[addr] = change.planned
+
+		if w.op == walkApply {
+			instances[addr] = cty.NullVal(s.block.ImpliedType())
+
+			if ri, ok := w.state.resources[addr]; ok {
+				instances[addr] = ri.value
+			}
+		}
+	}
+
+	// Instances beyond the count of the resource are destroyed.
+	for _, ri := range w.state.instances(rc.addr()) {
+		if _, ok := instances[ri.addr()]; ok || w.op == walkRefresh {
+			continue
+		}
+
+		reason := tfjson.ActionReasonDeleteBecauseCountIndex
+		if (ri.index == nil) != (rc.count == nil) {
+			reason = tfjson.ActionReasonDeleteBecauseWrongRepetition
+		}
+
+		if err := w.readResource(ctx, ri); err != nil {
+			return err
+		}
+
+		if _, ok := w.state.resources[ri.addr()]; !ok {
+			continue
+		}
+
+		if err := w.handleChange(ctx, &resourceChange{
+			instance: ri,
+			actions:  tfjson.Actions{tfjson.ActionDelete},
+			reason:   reason,
+			prior:    ri.value,
+			planned:  nullValue(ri.value),
+		}); err != nil {
+			return err
+		}
+	}
+
+	w.setResourceValue(rc, instances, indexes)
+
+	return nil
+}
+
+// planResourceInstance validates the configuration of the given resource
+// instance and plans its change.
+func (w *walker) planResourceInstance(ctx context.Context, ri *resourceInstance, config cty.Value) (*resourceChange, error) {
+	p := w.core.providers[ri.provider]
+	addr := ri.addr()
+
+	s, err := p.resourceSchema(ri.mode, ri.typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	ty := s.block.ImpliedType()
+
+	validateResp, err := p.server.ValidateResourceTypeConfig(ctx, &tfprotov5.ValidateResourceTypeConfigRequest{
+		TypeName: ri.typeName,
+		Config:   dynamicValue(ty, config),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: error validating configuration: %w", addr, err)
+	}
+
+	if err := diagnosticsError(validateResp.Diagnostics, addr); err != nil {
+		return nil, err
+	}
+
+	change := &resourceChange{
+		instance: ri,
+		prior:    ri.value,
+		config:   config,
+	}
+
+	// Tainted objects are planned as if they did not exist, so they are
+	// replaced.
+	prior := ri.value
+	if ri.tainted {
+		prior = cty.NullVal(ty)
+	}
+
+	planResp, planned, err := w.planResourceChange(ctx, ri, prior, config)
+	if err != nil {
+		return nil, err
+	}
+
+	change.planned = planned
+	change.plannedPrivate = planResp.PlannedPrivate
+	change.plannedIdentity = planResp.PlannedIdentity
+
+	switch {
+	case ri.value.IsNull():
+		change.actions = tfjson.Actions{tfjson.ActionCreate}
+	case ri.tainted:
+		change.actions = tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}
+		change.reason = tfjson.ActionReasonReplaceBecauseTainted
+	case requiresReplace(planResp.RequiresReplace, ri.value, planned):
+		change.actions = tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}
+		change.reason = tfjson.ActionReasonReplaceBecauseCannotUpdate
+
+		// The replacement object is planned as a new object.
+		createResp, planned, err := w.planResourceChange(ctx, ri, cty.NullVal(ty), config)
+		if err != nil {
+			return nil, err
+		}
+
+		change.planned = planned
+		change.plannedPrivate = createResp.PlannedPrivate
+		change.plannedIdentity = createResp.PlannedIdentity
+	case planned.RawEquals(ri.value):
+		change.actions = tfjson.Actions{tfjson.ActionNoop}
+	default:
+		change.actions = tfjson.Actions{tfjson.ActionUpdate}
+	}
+
+	return change, nil
+}
+
+func (w *walker) planResourceChange(ctx context.Context, ri *resourceInstance, prior, config cty.Value) (*tfprotov5.PlanResourceChangeResponse, cty.Value, error) {
+	p := w.core.providers[ri.provider]
+	addr := ri.addr()
+	s, _ := p.resourceSchema(ri.mode, ri.typeName)
+	ty := s.block.ImpliedType()
+
+	req := &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         ri.typeName,
+		PriorState:       dynamicValue(ty, prior),
+		ProposedNewState: dynamicValue(ty, proposedNew(s.block, prior, config)),
+		Config:           dynamicValue(ty, config),
+	}
+
+	if !prior.IsNull() {
+		req.PriorPrivate = ri.private
+		req.PriorIdentity = ri.identity
+	}
+
+	resp, err := p.server.PlanResourceChange(ctx, req)
+	if err != nil {
+		return nil, cty.NilVal, fmt.Errorf("%s: error planning: %w", addr, err)
+	}
+
+	if err := diagnosticsError(resp.Diagnostics, addr); err != nil {
+		return nil, cty.NilVal, err
+	}
+
+	planned, err := valueFromDynamic(ty, resp.PlannedState)
+	if err != nil {
+		return nil, cty.NilVal, fmt.Errorf("%s: error decoding planned state: %w", addr, err)
+	}
+
+	return resp, planned, nil
+}
+
+// requiresReplace returns whether any of the given paths, which the provider
+// requires to replace the resource when changed, differs between the prior
+// and planned values.
+func requiresReplace(paths []*tftypes.AttributePath, prior, planned cty.Value) bool {
+	for _, ap := range paths {
+		path := plugconvert.AttributePathToPath(ap)
+
+		priorV, priorErr := path.Apply(prior)
+		plannedV, plannedErr := path.Apply(planned)
+
+		if priorErr != nil || plannedErr != nil || !priorV.RawEquals(plannedV) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// handleChange records the given planned change, and applies it when
+// walking to apply.
+func (w *walker) handleChange(ctx context.Context, change *resourceChange) error {
+	if w.op == walkRefresh {
+		return nil
+	}
+
+	w.changes = append(w.changes, change)
+
+	if !change.actions.NoOp() {
+		w.pending[change.instance.resourceAddr()] = true
+	}
+
+	if w.op != walkApply {
+		return nil
+	}
+
+	ri := change.instance
+
+	switch {
+	case change.actions.NoOp():
+		w.state.resources[ri.addr()] = ri
+
+		return nil
+	case change.actions.Delete():
+		return w.destroyInstance(ctx, ri)
+	case change.actions.Replace():
+		if err := w.destroyInstance(ctx, ri); err != nil {
+			return err
+		}
+
+		return w.applyResourceChange(ctx, ri, cty.NullVal(ri.value.Type()), change)
+	default:
+		return w.applyResourceChange(ctx, ri, ri.value, change)
+	}
+}
+
+func (w *walker) applyResourceChange(ctx context.Context, ri *resourceInstance, prior cty.Value, change *resourceChange) error {
+	p := w.core.providers[ri.provider]
+	addr := ri.addr()
+	s, _ := p.resourceSchema(ri.mode, ri.typeName)
+	ty := s.block.ImpliedType()
+
+	req := &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:        ri.typeName,
+		PriorState:      dynamicValue(ty, prior),
+		PlannedState:    dynamicValue(ty, change.planned),
+		Config:          dynamicValue(ty, change.config),
+		PlannedPrivate:  change.plannedPrivate,
+		PlannedIdentity: change.plannedIdentity,
+	}
+
+	resp, err := p.server.ApplyResourceChange(ctx, req)
+	if err != nil {
+		return fmt.Errorf("%s: error applying: %w", addr, err)
+	}
+
+	newVal, decodeErr := valueFromDynamic(ty, resp.NewState)
+	if decodeErr != nil {
+		return fmt.Errorf("%s: error decoding new state: %w", addr, decodeErr)
+	}
+
+	// Objects are saved in the state even when the provider returns an
+	// error, as partially created objects must still be destroyed.
+	if newVal.IsNull() {
+		delete(w.state.resources, addr)
+	} else {
+		applied := *ri
+		applied.value = newVal
+		applied.private = resp.Private
+		applied.identity = resp.NewIdentity
+		applied.schemaVersion = s.version
+		applied.tainted = false
+
+		w.state.resources[addr] = &applied
+	}
+
+	if err := diagnosticsError(resp.Diagnostics, addr); err != nil {
+		return err
+	}
+
+	if !newVal.IsWhollyKnown() {
+		return fmt.Errorf("Error: Provider returned invalid result object after apply\n\nAfter the apply operation, the provider still indicated an unknown value for %s. All values must be known after apply, so this is always a bug in the provider and should be reported in the provider's own repository.", addr)
+	}
+
+	return nil
+}
+
+// destroyInstance destroys the remote object of the given managed resource
+// instance and removes it from the state.
+func (w *walker) destroyInstance(ctx context.Context, ri *resourceInstance) error {
+	p := w.core.providers[ri.provider]
+	addr := ri.addr()
+
+	if p == nil {
+		return fmt.Errorf("%s: provider %q is not available", addr, ri.provider)
+	}
+
+	s, err := p.resourceSchema(ri.mode, ri.typeName)
+	if err != nil {
+		return err
+	}
+
+	ty := s.block.ImpliedType()
+
+	resp, err := p.server.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:       ri.typeName,
+		PriorState:     dynamicValue(ty, ri.value),
+		PlannedState:   dynamicValue(ty, cty.NullVal(ty)),
+		Config:         dynamicValue(ty, cty.NullVal(ty)),
+		PlannedPrivate: ri.private,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: error destroying: %w", addr, err)
+	}
+
+	if err := diagnosticsError(resp.Diagnostics, addr); err != nil {
+		return err
+	}
+
+	delete(w.state.resources, addr)
+
+	return nil
+}
+
+// readResource upgrades and refreshes the given managed resource instance,
+// storing the result in the state, or removing the instance from the state
+// when its remote object no longer exists.
+func (w *walker) readResource(ctx context.Context, ri *resourceInstance) error {
+	p := w.core.providers[ri.provider]
+	addr := ri.addr()
+
+	if p == nil {
+		return fmt.Errorf("%s: provider %q is not available", addr, ri.provider)
+	}
+
+	s, err := p.resourceSchema(ri.mode, ri.typeName)
+	if err != nil {
+		return err
+	}
+
+	ty := s.block.ImpliedType()
+
+	raw, err := ctyjson.Marshal(ri.value, ri.value.Type())
+	if err != nil {
+		return fmt.Errorf("%s: error encoding state: %w", addr, err)
+	}
+
+	upgradeResp, err := p.server.UpgradeResourceState(ctx, &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: ri.typeName,
+		Version:  ri.schemaVersion,
+		RawState: &tfprotov5.RawState{
+			JSON: raw,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: error upgrading state: %w", addr, err)
+	}
+
+	if err := diagnosticsError(upgradeResp.Diagnostics, addr); err != nil {
+		return err
+	}
+
+	current := ri.value
+
+	if upgradeResp.UpgradedState != nil {
+		current, err = valueFromDynamic(ty, upgradeResp.UpgradedState)
+		if err != nil {
+			return fmt.Errorf("%s: error decoding upgraded state: %w", addr, err)
+		}
+	}
+
+	resp, err := p.server.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		TypeName:        ri.typeName,
+		CurrentState:    dynamicValue(ty, current),
+		Private:         ri.private,
+		CurrentIdentity: ri.identity,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: error reading: %w", addr, err)
+	}
+
+	if err := diagnosticsError(resp.Diagnostics, addr); err != nil {
+		return err
+	}
+
+	newVal, err := valueFromDynamic(ty, resp.NewState)
+	if err != nil {
+		return fmt.Errorf("%s: error decoding refreshed state: %w", addr, err)
+	}
+
+	if newVal.IsNull() {
+		delete(w.state.resources, addr)
+
+		return nil
+	}
+
+	refreshed := *ri
+	refreshed.value = newVal
+	refreshed.private = resp.Private
+	refreshed.schemaVersion = s.version
+
+	if resp.NewIdentity != nil {
+		refreshed.identity = resp.NewIdentity
+	}
+
+	w.state.resources[addr] = &refreshed
+
+	return nil
+}
+
+func (w *walker) walkDataResource(ctx context.Context, rc *resourceConfig) error {
+	p := w.core.providers[rc.provider]
+
+	s, err := p.resourceSchema(rc.mode, rc.typeName)
+	if err != nil {
+		return err
+	}
+
+	indexes, err := w.expand(rc)
+	if err != nil {
+		return err
+	}
+
+	ty := s.block.ImpliedType()
+	dependencies := w.graph.resourceDependencies(rc.addr())
+
+	// Data resources depending on managed resources with planned changes
+	// are read during apply, when the changes have been applied.
+	var dependencyPending bool
+
+	for _, dep := range dependencies {
+		if w.pending[dep] && w.op == walkPlan {
+			dependencyPending = true
+		}
+	}
+
+	instances := make(map[string]cty.Value, len(indexes))
+
+	for _, index := range indexes {
+		addr := instanceAddr(rc.addr(), index)
+
+		config, err := decodeBody(rc.body, s.block, w.evalContext(index))
+		if err != nil {
+			return err
+		}
+
+		validateResp, err := p.server.ValidateDataSourceConfig(ctx, &tfprotov5.ValidateDataSourceConfigRequest{
+			TypeName: rc.typeName,
+			Config:   dynamicValue(ty, config),
+		})
+		if err != nil {
+			return fmt.Errorf("%s: error validating configuration: %w", addr, err)
+		}
+
+		if err := diagnosticsError(validateResp.Diagnostics, addr); err != nil {
+			return err
+		}
+
+		ri := &resourceInstance{
+			mode:          rc.mode,
+			typeName:      rc.typeName,
+			name:          rc.name,
+			index:         index,
+			provider:      rc.provider,
+			schemaVersion: s.version,
+			dependencies:  dependencies,
+		}
+
+		if !config.IsWhollyKnown() || dependencyPending {
+			ri.value = plannedDataValue(s.block, config)
+			instances[addr] = ri.value
+
+			reason := tfjson.ActionReasonReadBecauseConfigUnknown
+			if dependencyPending {
+				reason = tfjson.ActionReasonReadBecauseDependencyPending
+			}
+
+			if w.op == walkPlan {
+				w.changes = append(w.changes, &resourceChange{
+					instance: ri,
+					actions:  tfjson.Actions{tfjson.ActionRead},
+					reason:   reason,
+					prior:    cty.NullVal(ty),
+					planned:  ri.value,
+				})
+			}
+
+			continue
+		}
+
+		resp, err := p.server.ReadDataSource(ctx, &tfprotov5.ReadDataSourceRequest{
+			TypeName: rc.typeName,
+			Config:   dynamicValue(ty, config),
+		})
+		if err != nil {
+			return fmt.Errorf("%s: error reading: %w", addr, err)
+		}
+
+		if err := diagnosticsError(resp.Diagnostics, addr); err != nil {
+			return err
+		}
+
+		ri.value, err = valueFromDynamic(ty, resp.State)
+		if err != nil {
+			return fmt.Errorf("%s: error decoding state: %w", addr, err)
+		}
+
+		w.state.resources[addr] = ri
+		instances[addr] = ri.value
+	}
+
+	for _, ri := range w.state.instances(rc.addr()) {
+		if _, ok := instances[ri.addr()]; !ok {
+			delete(w.state.resources, ri.addr())
+		}
+	}
+
+	w.setResourceValue(rc, instances, indexes)
+
+	return nil
+}
+
+// decodeBody decodes the given provider, resource or data block body with the
+// given schema.
+func decodeBody(body hcl.Body, schema *configschema.Block, evalCtx *hcl.EvalContext) (cty.Value, error) {
+	val, diags := hcldec.Decode(body, specForBlock(schema), evalCtx)
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	result, err := schema.CoerceValue(toCtyValue(val))
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return result, nil
+}
+
+// destroyOrder returns the managed resource instances of the given map in the
+// order they must be destroyed, where instances are destroyed before the
+// instances they depend on.
+func destroyOrder(resources map[string]*resourceInstance) []*resourceInstance {
+	var addrs []string
+
+	for addr, ri := range resources {
+		if ri.mode == tfjson.ManagedResourceMode {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	sort.Strings(addrs)
+
+	visited := make(map[string]bool, len(addrs))
+	order := make([]*resourceInstance, 0, len(addrs))
+
+	// Visit every instance after the instances depending on it.
+	var visit func(addr string)
+	visit = func(addr string) {
+		if visited[addr] {
+			return
+		}
+
+		visited[addr] = true
+		ri := resources[addr]
+
+		for _, other := range addrs {
+			for _, dep := range resources[other].dependencies {
+				if dep == ri.resourceAddr() {
+					visit(other)
+				}
+			}
+		}
+
+		order = append(order, ri)
+	}
+
+	for _, addr := range addrs {
+		visit(addr)
+	}
+
+	return order
+}
+
+func nullValue(val cty.Value) cty.Value {
+	return cty.NullVal(val.Type())
+}
+
+// plan returns the changes planned by the walk in the format of the
+// terraform show -json command.
+func (w *walker) plan() (*tfjson.Plan, error) {
+	plan := &tfjson.Plan{
+		FormatVersion:    "1.2",
+		TerraformVersion: TerraformVersion,
+		OutputChanges:    make(map[string]*tfjson.Change, len(w.outputChanges)),
+	}
+
+	sort.SliceStable(w.changes, func(i, j int) bool {
+		return w.changes[i].instance.addr() < w.changes[j].instance.addr()
+	})
+
+	for _, change := range w.changes {
+		ri := change.instance
+
+		before, err := valueJSON(change.prior)
+		if err != nil {
+			return nil, err
+		}
+
+		after, err := valueJSON(unknownAsNull(change.planned))
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := w.core.providers[ri.provider].resourceSchema(ri.mode, ri.typeName)
+		if err != nil {
+			return nil, err
+		}
+
+		rc := &tfjson.ResourceChange{
+			Address:      ri.addr(),
+			Mode:         ri.mode,
+			Type:         ri.typeName,
+			Name:         ri.name,
+			ProviderName: providerAddr(ri.provider),
+			Change: &tfjson.Change{
+				Actions:         change.actions,
+				Before:          before,
+				After:           after,
+				AfterUnknown:    unknownJSON(change.planned),
+				BeforeSensitive: sensitiveJSON(s.block, change.prior),
+				AfterSensitive:  sensitiveJSON(s.block, change.planned),
+			},
+			ActionReason: change.reason,
+		}
+
+		if ri.index != nil {
+			rc.Index = json.Number(strconv.Itoa(*ri.index))
+		}
+
+		plan.ResourceChanges = append(plan.ResourceChanges, rc)
+	}
+
+	for _, change := range w.outputChanges {
+		oc := &tfjson.Change{
+			Actions:      change.actions,
+			AfterUnknown: false,
+		}
+
+		if change.prior != nil {
+			before, err := valueJSON(change.prior.value)
+			if err != nil {
+				return nil, err
+			}
+
+			oc.Before = before
+			oc.BeforeSensitive = change.prior.sensitive
+		}
+
+		if change.planned != nil {
+			after, err := valueJSON(unknownAsNull(change.planned.value))
+			if err != nil {
+				return nil, err
+			}
+
+			oc.After = after
+			oc.AfterUnknown = unknownJSON(change.planned.value)
+			oc.AfterSensitive = change.planned.sensitive
+		}
+
+		plan.OutputChanges[change.name] = oc
+	}
+
+	return plan, nil
+}
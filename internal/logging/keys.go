@@ -11,6 +11,9 @@ package logging
 // Refer to the terraform-plugin-go logging keys as well, which should be
 // equivalent to these when possible.
 const (
+	// The type of action being operated on, such as "aws_lambda_invoke"
+	KeyActionType = "tf_action_type"
+
 	// Attribute path representation, which is typically in flatmap form such
 	// as parent.0.child in this project.
 	KeyAttributePath = "tf_attribute_path"
@@ -21,6 +24,10 @@ const (
 	// Underlying Go error string when logging an error.
 	KeyError = "error"
 
+	// The name of the provider-defined function being operated on, such as
+	// "parse_arn"
+	KeyFunctionName = "tf_function_name"
+
 	// The full address of the provider, such as
 	// registry.terraform.io/hashicorp/random
 	KeyProviderAddress = "tf_provider_addr"
@@ -28,6 +35,10 @@ const (
 	// The type of resource being operated on, such as "random_pet"
 	KeyResourceType = "tf_resource_type"
 
+	// The type of ephemeral resource being operated on, such as
+	// "vault_token"
+	KeyEphemeralResourceType = "tf_ephemeral_resource_type"
+
 	// The Deferred reason for an RPC response
 	KeyDeferredReason = "tf_deferred_reason"
 
@@ -40,6 +51,14 @@ const (
 	// Terraform configuration used during acceptance testing Terraform operations.
 	KeyTestTerraformConfiguration = "test_terraform_configuration"
 
+	// The directory of the Terraform configuration used during acceptance
+	// testing Terraform operations, if it is not a string.
+	KeyTestTerraformConfigurationDirectory = "test_terraform_configuration_directory"
+
+	// The file of the Terraform configuration used during acceptance testing
+	// Terraform operations, if it is not a string.
+	KeyTestTerraformConfigurationFile = "test_terraform_configuration_file"
+
 	// The Terraform CLI logging level (TF_LOG) used for an acceptance test.
 	KeyTestTerraformLogLevel = "test_terraform_log_level"
 
@@ -58,6 +77,18 @@ const (
 	// The path to the Terraform CLI used for an acceptance test.
 	KeyTestTerraformPath = "test_terraform_path"
 
+	// The filesystem mirror directory of a previous provider version used
+	// for an acceptance test.
+	KeyTestTerraformProviderMirror = "test_terraform_provider_mirror"
+
+	// The source address of a provider installed from a filesystem mirror
+	// for an acceptance test.
+	KeyTestTerraformProviderSource = "test_terraform_provider_source"
+
+	// The version of a provider installed from a filesystem mirror for an
+	// acceptance test.
+	KeyTestTerraformProviderVersion = "test_terraform_provider_version"
+
 	// Terraform plan output generated during a TestStep.
 	KeyTestTerraformPlan = "test_terraform_plan"
 
@@ -0,0 +1,7 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package planrender renders Terraform plans in the JSON format of the
+// terraform show -json command as human readable text, for use in test
+// failure messages.
+package planrender
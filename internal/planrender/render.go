@@ -0,0 +1,175 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package planrender
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// Plan returns a human readable summary of the resource and output changes of
+// the given plan, similar to the output of the terraform plan command. Only
+// the top level attributes which differ are shown for each change.
+func Plan(plan *tfjson.Plan) string {
+	var b strings.Builder
+	var add, change, destroy int
+
+	for _, rc := range plan.ResourceChanges {
+		if rc.Change == nil {
+			continue
+		}
+
+		actions := rc.Change.Actions
+
+		var symbol, description string
+
+		switch {
+		case actions.NoOp():
+			continue
+		case actions.Create():
+			symbol, description = "+", "will be created"
+			add++
+		case actions.Read():
+			symbol, description = "<=", "will be read during apply"
+		case actions.Update():
+			symbol, description = "~", "will be updated in-place"
+			change++
+		case actions.Replace():
+			symbol, description = "-/+", "must be replaced"
+			add++
+			destroy++
+		case actions.Delete():
+			symbol, description = "-", "will be destroyed"
+			destroy++
+		default:
+			symbol, description = "?", fmt.Sprintf("has unexpected actions %v", actions)
+		}
+
+		fmt.Fprintf(&b, "  # %s %s\n", rc.Address, description)
+
+		if rc.ActionReason != "" {
+			fmt.Fprintf(&b, "  # (reason: %s)\n", rc.ActionReason)
+		}
+
+		keyword := "resource"
+
+		if rc.Mode == tfjson.DataResourceMode {
+			keyword = "data"
+		}
+
+		fmt.Fprintf(&b, "  %s %s %q %q {\n", symbol, keyword, rc.Type, rc.Name)
+
+		for _, line := range renderAttributeChanges(rc.Change) {
+			fmt.Fprintf(&b, "      %s\n", line)
+		}
+
+		b.WriteString("    }\n\n")
+	}
+
+	for _, name := range sortedOutputNames(plan.OutputChanges) {
+		oc := plan.OutputChanges[name]
+
+		if oc.Actions.NoOp() {
+			continue
+		}
+
+		fmt.Fprintf(&b, "  output %q: %s -> %s\n", name, renderValue(oc.Before, oc.BeforeSensitive, false), renderValue(oc.After, oc.AfterSensitive, isUnknown(oc.AfterUnknown)))
+	}
+
+	if b.Len() == 0 {
+		return "No changes."
+	}
+
+	fmt.Fprintf(&b, "\nPlan: %d to add, %d to change, %d to destroy.", add, change, destroy)
+
+	return b.String()
+}
+
+// renderAttributeChanges returns a line for every top level attribute which
+// differs between the prior and planned values of the given change.
+func renderAttributeChanges(c *tfjson.Change) []string {
+	before, _ := c.Before.(map[string]interface{})
+	after, _ := c.After.(map[string]interface{})
+	afterUnknown, _ := c.AfterUnknown.(map[string]interface{})
+	beforeSensitive, _ := c.BeforeSensitive.(map[string]interface{})
+	afterSensitive, _ := c.AfterSensitive.(map[string]interface{})
+
+	names := make(map[string]bool)
+
+	for name := range before {
+		names[name] = true
+	}
+
+	for name := range after {
+		names[name] = true
+	}
+
+	for name := range afterUnknown {
+		names[name] = true
+	}
+
+	sorted := make([]string, 0, len(names))
+
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+
+	sort.Strings(sorted)
+
+	var lines []string
+
+	for _, name := range sorted {
+		unknown := isUnknown(afterUnknown[name])
+
+		if !unknown && reflect.DeepEqual(before[name], after[name]) {
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%s: %s -> %s", name,
+			renderValue(before[name], beforeSensitive[name], false),
+			renderValue(after[name], afterSensitive[name], unknown)))
+	}
+
+	return lines
+}
+
+// renderValue returns the given JSON value as it is shown in a rendered plan.
+func renderValue(val interface{}, sensitive interface{}, unknown bool) string {
+	switch {
+	case unknown:
+		return "(known after apply)"
+	case sensitive == true:
+		return "(sensitive value)"
+	case val == nil:
+		return "null"
+	}
+
+	b, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+
+	return string(b)
+}
+
+func isUnknown(afterUnknown interface{}) bool {
+	return afterUnknown == true
+}
+
+func sortedOutputNames(m map[string]*tfjson.Change) []string {
+	names := make([]string, 0, len(m))
+
+	for name := range m {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -17,6 +17,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
 )
 
+// CtyTypeToTFType converts a cty.Type into the equivalent tftypes.Type for
+// protocol types which are not part of a schema block, such as function
+// parameters.
+func CtyTypeToTFType(in cty.Type) (tftypes.Type, error) {
+	return tftypeFromCtyType(in)
+}
+
 func tftypeFromCtyType(in cty.Type) (tftypes.Type, error) {
 	switch {
 	case in.Equals(cty.String):
@@ -277,6 +284,33 @@ func ProtoToConfigSchema(ctx context.Context, b *tfprotov5.SchemaBlock) *configs
 	return block
 }
 
+// ProtoToIdentitySchema takes the identity attributes of a
+// ResourceIdentitySchema from a grpc response and converts them to a
+// terraform *configschema.Block.
+func ProtoToIdentitySchema(ctx context.Context, attrs []*tfprotov5.ResourceIdentitySchemaAttribute) *configschema.Block {
+	block := &configschema.Block{
+		Attributes: make(map[string]*configschema.Attribute),
+	}
+
+	for _, a := range attrs {
+		attr := &configschema.Attribute{
+			Description:       a.Description,
+			OptionalForImport: a.OptionalForImport,
+			RequiredForImport: a.RequiredForImport,
+		}
+
+		var err error
+		attr.Type, err = ctyTypeFromTFType(a.Type)
+		if err != nil {
+			panic(err)
+		}
+
+		block.Attributes[a.Name] = attr
+	}
+
+	return block
+}
+
 func schemaStringKind(ctx context.Context, k tfprotov5.StringKind) configschema.StringKind {
 	switch k {
 	default:
@@ -481,3 +481,63 @@ func TestConvertProtoSchemaBlocks(t *testing.T) {
 		})
 	}
 }
+
+func TestProtoToIdentitySchema(t *testing.T) {
+	tests := map[string]struct {
+		Attributes []*tfprotov5.ResourceIdentitySchemaAttribute
+		Want       *configschema.Block
+	}{
+		"empty": {
+			[]*tfprotov5.ResourceIdentitySchemaAttribute{},
+			&configschema.Block{
+				Attributes: map[string]*configschema.Attribute{},
+			},
+		},
+		"attributes": {
+			[]*tfprotov5.ResourceIdentitySchemaAttribute{
+				{
+					Name:              "id",
+					Type:              tftypes.String,
+					RequiredForImport: true,
+					Description:       "the identifier",
+				},
+				{
+					Name:              "region",
+					Type:              tftypes.String,
+					OptionalForImport: true,
+				},
+				{
+					Name:              "zones",
+					Type:              tftypes.List{ElementType: tftypes.String},
+					OptionalForImport: true,
+				},
+			},
+			&configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"id": {
+						Type:              cty.String,
+						RequiredForImport: true,
+						Description:       "the identifier",
+					},
+					"region": {
+						Type:              cty.String,
+						OptionalForImport: true,
+					},
+					"zones": {
+						Type:              cty.List(cty.String),
+						OptionalForImport: true,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			converted := ProtoToIdentitySchema(context.Background(), tc.Attributes)
+			if !cmp.Equal(converted, tc.Want, typeComparer, valueComparer, equateEmpty) {
+				t.Fatal(cmp.Diff(converted, tc.Want, typeComparer, valueComparer, equateEmpty))
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package convert
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
+)
+
+// ConfigSchemaToProtoV6 takes a *configschema.Block and converts it to a
+// tfprotov6.SchemaBlock for a grpc response. Unlike protocol version 5,
+// nested attributes are sent with their nested type.
+func ConfigSchemaToProtoV6(ctx context.Context, b *configschema.Block) *tfprotov6.SchemaBlock {
+	block := &tfprotov6.SchemaBlock{
+		Description:        b.Description,
+		DescriptionKind:    protoV6StringKind(ctx, b.DescriptionKind),
+		Deprecated:         b.Deprecated,
+		DeprecationMessage: b.DeprecationMessage,
+	}
+
+	block.Attributes = protoV6SchemaAttributes(ctx, b.Attributes)
+
+	for _, name := range sortedKeys(b.BlockTypes) {
+		b := b.BlockTypes[name]
+		block.BlockTypes = append(block.BlockTypes, protoV6SchemaNestedBlock(ctx, name, b))
+	}
+
+	return block
+}
+
+// ConfigIdentitySchemaToProtoV6 is the protocol version 6 equivalent of
+// ConfigIdentitySchemaToProto.
+func ConfigIdentitySchemaToProtoV6(ctx context.Context, identitySchema *configschema.Block) []*tfprotov6.ResourceIdentitySchemaAttribute {
+	output := make([]*tfprotov6.ResourceIdentitySchemaAttribute, 0)
+
+	for _, name := range sortedKeys(identitySchema.Attributes) {
+		a := identitySchema.Attributes[name]
+
+		attr := &tfprotov6.ResourceIdentitySchemaAttribute{
+			Name:              name,
+			Description:       a.Description,
+			OptionalForImport: a.OptionalForImport,
+			RequiredForImport: a.RequiredForImport,
+		}
+
+		var err error
+		attr.Type, err = tftypeFromCtyType(a.Type)
+		if err != nil {
+			panic(err)
+		}
+
+		output = append(output, attr)
+	}
+
+	return output
+}
+
+func protoV6SchemaAttributes(ctx context.Context, attrs map[string]*configschema.Attribute) []*tfprotov6.SchemaAttribute {
+	var ret []*tfprotov6.SchemaAttribute
+
+	for _, name := range sortedKeys(attrs) {
+		a := attrs[name]
+
+		attr := &tfprotov6.SchemaAttribute{
+			Name:               name,
+			Description:        a.Description,
+			DescriptionKind:    protoV6StringKind(ctx, a.DescriptionKind),
+			Optional:           a.Optional,
+			Computed:           a.Computed,
+			Required:           a.Required,
+			Sensitive:          a.Sensitive,
+			Deprecated:         a.Deprecated,
+			DeprecationMessage: a.DeprecationMessage,
+			WriteOnly:          a.WriteOnly,
+		}
+
+		if a.NestedType != nil {
+			attr.NestedType = &tfprotov6.SchemaObject{
+				Attributes: protoV6SchemaAttributes(ctx, a.NestedType.Attributes),
+				Nesting:    protoV6SchemaObjectNesting(ctx, a.NestedType.Nesting),
+			}
+
+			ret = append(ret, attr)
+			continue
+		}
+
+		var err error
+		attr.Type, err = tftypeFromCtyType(a.Type)
+		if err != nil {
+			panic(err)
+		}
+
+		ret = append(ret, attr)
+	}
+
+	return ret
+}
+
+func protoV6StringKind(ctx context.Context, k configschema.StringKind) tfprotov6.StringKind {
+	switch k {
+	default:
+		logging.HelperSchemaTrace(ctx, fmt.Sprintf("Unexpected configschema.StringKind: %d", k))
+		return tfprotov6.StringKindPlain
+	case configschema.StringPlain:
+		return tfprotov6.StringKindPlain
+	case configschema.StringMarkdown:
+		return tfprotov6.StringKindMarkdown
+	}
+}
+
+func protoV6SchemaObjectNesting(ctx context.Context, n configschema.NestingMode) tfprotov6.SchemaObjectNestingMode {
+	switch n {
+	case configschema.NestingSingle:
+		return tfprotov6.SchemaObjectNestingModeSingle
+	case configschema.NestingList:
+		return tfprotov6.SchemaObjectNestingModeList
+	case configschema.NestingSet:
+		return tfprotov6.SchemaObjectNestingModeSet
+	case configschema.NestingMap:
+		return tfprotov6.SchemaObjectNestingModeMap
+	default:
+		logging.HelperSchemaTrace(ctx, fmt.Sprintf("Unexpected configschema.NestingMode for nested attribute: %s", n))
+		return tfprotov6.SchemaObjectNestingModeInvalid
+	}
+}
+
+func protoV6SchemaNestedBlock(ctx context.Context, name string, b *configschema.NestedBlock) *tfprotov6.SchemaNestedBlock {
+	var nesting tfprotov6.SchemaNestedBlockNestingMode
+	switch b.Nesting {
+	case configschema.NestingSingle:
+		nesting = tfprotov6.SchemaNestedBlockNestingModeSingle
+	case configschema.NestingGroup:
+		nesting = tfprotov6.SchemaNestedBlockNestingModeGroup
+	case configschema.NestingList:
+		nesting = tfprotov6.SchemaNestedBlockNestingModeList
+	case configschema.NestingSet:
+		nesting = tfprotov6.SchemaNestedBlockNestingModeSet
+	case configschema.NestingMap:
+		nesting = tfprotov6.SchemaNestedBlockNestingModeMap
+	default:
+		nesting = tfprotov6.SchemaNestedBlockNestingModeInvalid
+	}
+	return &tfprotov6.SchemaNestedBlock{
+		TypeName: name,
+		Block:    ConfigSchemaToProtoV6(ctx, &b.Block),
+		Nesting:  nesting,
+		MinItems: int64(b.MinItems),
+		MaxItems: int64(b.MaxItems),
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package convert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
+)
+
+func TestConfigSchemaToProtoV6(t *testing.T) {
+	tests := map[string]struct {
+		Block *configschema.Block
+		Want  *tfprotov6.SchemaBlock
+	}{
+		"attributes": {
+			&configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"computed": {
+						Type:     cty.List(cty.Bool),
+						Computed: true,
+					},
+					"optional": {
+						Type:        cty.String,
+						Optional:    true,
+						Description: "optional attribute",
+					},
+				},
+			},
+			&tfprotov6.SchemaBlock{
+				Attributes: []*tfprotov6.SchemaAttribute{
+					{
+						Name: "computed",
+						Type: tftypes.List{
+							ElementType: tftypes.Bool,
+						},
+						Computed: true,
+					},
+					{
+						Name:        "optional",
+						Type:        tftypes.String,
+						Optional:    true,
+						Description: "optional attribute",
+					},
+				},
+			},
+		},
+		"nested attributes": {
+			&configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"list": {
+						Type: cty.List(cty.Object(map[string]cty.Type{
+							"name": cty.String,
+						})),
+						NestedType: &configschema.Object{
+							Attributes: map[string]*configschema.Attribute{
+								"name": {
+									Type:     cty.String,
+									Required: true,
+								},
+							},
+							Nesting: configschema.NestingList,
+						},
+						Optional: true,
+					},
+					"single": {
+						Type: cty.Object(map[string]cty.Type{
+							"set": cty.Set(cty.Object(map[string]cty.Type{
+								"value": cty.Number,
+							})),
+						}),
+						NestedType: &configschema.Object{
+							Attributes: map[string]*configschema.Attribute{
+								"set": {
+									Type: cty.Set(cty.Object(map[string]cty.Type{
+										"value": cty.Number,
+									})),
+									NestedType: &configschema.Object{
+										Attributes: map[string]*configschema.Attribute{
+											"value": {
+												Type:     cty.Number,
+												Computed: true,
+											},
+										},
+										Nesting: configschema.NestingSet,
+									},
+									Optional: true,
+								},
+							},
+							Nesting: configschema.NestingSingle,
+						},
+						Required: true,
+					},
+				},
+			},
+			&tfprotov6.SchemaBlock{
+				Attributes: []*tfprotov6.SchemaAttribute{
+					{
+						Name: "list",
+						NestedType: &tfprotov6.SchemaObject{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "name",
+									Type:     tftypes.String,
+									Required: true,
+								},
+							},
+							Nesting: tfprotov6.SchemaObjectNestingModeList,
+						},
+						Optional: true,
+					},
+					{
+						Name: "single",
+						NestedType: &tfprotov6.SchemaObject{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name: "set",
+									NestedType: &tfprotov6.SchemaObject{
+										Attributes: []*tfprotov6.SchemaAttribute{
+											{
+												Name:     "value",
+												Type:     tftypes.Number,
+												Computed: true,
+											},
+										},
+										Nesting: tfprotov6.SchemaObjectNestingModeSet,
+									},
+									Optional: true,
+								},
+							},
+							Nesting: tfprotov6.SchemaObjectNestingModeSingle,
+						},
+						Required: true,
+					},
+				},
+			},
+		},
+		"blocks": {
+			&configschema.Block{
+				BlockTypes: map[string]*configschema.NestedBlock{
+					"list": {
+						Nesting:  configschema.NestingList,
+						MaxItems: 1,
+						Block: configschema.Block{
+							Attributes: map[string]*configschema.Attribute{
+								"foo": {
+									Type:     cty.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+					"set": {
+						Nesting: configschema.NestingSet,
+					},
+				},
+			},
+			&tfprotov6.SchemaBlock{
+				BlockTypes: []*tfprotov6.SchemaNestedBlock{
+					{
+						TypeName: "list",
+						Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+						MaxItems: 1,
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "foo",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+					{
+						TypeName: "set",
+						Nesting:  tfprotov6.SchemaNestedBlockNestingModeSet,
+						Block:    &tfprotov6.SchemaBlock{},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			converted := ConfigSchemaToProtoV6(context.Background(), tc.Block)
+			if !cmp.Equal(converted, tc.Want, typeComparer, equateEmpty) {
+				t.Fatal(cmp.Diff(converted, tc.Want, typeComparer, equateEmpty))
+			}
+		})
+	}
+}
@@ -5,10 +5,10 @@ package plugintest
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/terraform-exec/tfexec"
 	tfjson "github.com/hashicorp/terraform-json"
@@ -32,9 +32,10 @@ type WorkingDir struct {
 	// baseDir is the root of the working directory tree
 	baseDir string
 
-	// configFilename is the full filename where the latest configuration
-	// was stored; empty until SetConfig is called.
-	configFilename string
+	// configEntries are the top-level files and directories of the
+	// latest configuration, relative to baseDir; empty until SetConfig is
+	// called.
+	configEntries []string
 
 	// tf is the instance of tfexec.Terraform used for running Terraform commands
 	tf *tfexec.Terraform
@@ -45,6 +46,10 @@ type WorkingDir struct {
 	// reattachInfo stores the gRPC socket info required for Terraform's
 	// plugin reattach functionality
 	reattachInfo tfexec.ReattachInfo
+
+	// cliConfigFile is the path of the Terraform CLI configuration file
+	// written by SetProviderMirror; empty unless a provider mirror is set.
+	cliConfigFile string
 }
 
 // Close deletes the directories and files created to represent the receiving
@@ -68,28 +73,61 @@ func (wd *WorkingDir) GetHelper() *Helper {
 	return wd.h
 }
 
-// SetConfig sets a new configuration for the working directory.
+// SetConfig sets a new configuration for the working directory, writing the
+// string or copying every file from the directory or file of the given
+// configuration, along with its variable definitions file.
 //
 // This must be called at least once before any call to Init, Plan, Apply, or
 // Destroy to establish the configuration. Any previously-set configuration is
 // discarded and any saved plan is cleared.
-func (wd *WorkingDir) SetConfig(ctx context.Context, cfg string) error {
-	logging.HelperResourceTrace(ctx, "Setting Terraform configuration", map[string]any{logging.KeyTestTerraformConfiguration: cfg})
+func (wd *WorkingDir) SetConfig(ctx context.Context, cfg WorkingDirConfig) error {
+	logging.HelperResourceTrace(ctx, "Setting Terraform configuration", map[string]any{
+		logging.KeyTestTerraformConfiguration:          cfg.Raw,
+		logging.KeyTestTerraformConfigurationDirectory: cfg.Directory,
+		logging.KeyTestTerraformConfigurationFile:      cfg.File,
+	})
+
+	files, err := cfg.Files()
+	if err != nil {
+		return err
+	}
 
-	outFilename := filepath.Join(wd.baseDir, ConfigFileName)
-	rmFilename := filepath.Join(wd.baseDir, ConfigFileNameJSON)
-	bCfg := []byte(cfg)
-	if json.Valid(bCfg) {
-		outFilename, rmFilename = rmFilename, outFilename
+	if len(cfg.Variables) > 0 {
+		files[VariablesFileName] = cfg.Variables
 	}
-	if err := os.Remove(rmFilename); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("unable to remove %q: %w", rmFilename, err)
+
+	if cfg.ImportBlock != "" {
+		files[ImportBlockFileName] = []byte(cfg.ImportBlock)
 	}
-	err := os.WriteFile(outFilename, bCfg, 0700)
-	if err != nil {
-		return err
+
+	for _, entry := range wd.configEntries {
+		rmPath := filepath.Join(wd.baseDir, entry)
+		if err := os.RemoveAll(rmPath); err != nil {
+			return fmt.Errorf("unable to remove %q: %w", rmPath, err)
+		}
+	}
+
+	wd.configEntries = nil
+	entries := make(map[string]struct{})
+
+	for name, b := range files {
+		outFilename := filepath.Join(wd.baseDir, name)
+
+		if err := os.MkdirAll(filepath.Dir(outFilename), 0700); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(outFilename, b, 0700); err != nil {
+			return err
+		}
+
+		entry := strings.SplitN(filepath.ToSlash(name), "/", 2)[0]
+
+		if _, ok := entries[entry]; !ok {
+			entries[entry] = struct{}{}
+			wd.configEntries = append(wd.configEntries, entry)
+		}
 	}
-	wd.configFilename = outFilename
 
 	// Changing configuration invalidates any saved plan.
 	err = wd.ClearPlan(ctx)
@@ -147,15 +185,16 @@ var errWorkingDirSetConfigNotCalled = fmt.Errorf("must call SetConfig before Ini
 // Init runs "terraform init" for the given working directory, forcing Terraform
 // to use the current version of the plugin under test.
 func (wd *WorkingDir) Init(ctx context.Context) error {
-	if wd.configFilename == "" {
-		return errWorkingDirSetConfigNotCalled
-	}
-	if _, err := os.Stat(wd.configFilename); err != nil {
+	if len(wd.configEntries) == 0 {
 		return errWorkingDirSetConfigNotCalled
 	}
 
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI init command")
 
+	if err := wd.setEnv(); err != nil {
+		return err
+	}
+
 	// -upgrade=true is required for per-TestStep provider version changes
 	// e.g. TestTest_TestStep_ExternalProviders_DifferentVersions
 	err := wd.tf.Init(context.Background(), tfexec.Reattach(wd.reattachInfo), tfexec.Upgrade(true))
@@ -174,6 +213,10 @@ func (wd *WorkingDir) planFilename() string {
 func (wd *WorkingDir) CreatePlan(ctx context.Context) error {
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI plan command")
 
+	if err := wd.setEnv(); err != nil {
+		return err
+	}
+
 	hasChanges, err := wd.tf.Plan(context.Background(), tfexec.Reattach(wd.reattachInfo), tfexec.Refresh(false), tfexec.Out(PlanFileName))
 
 	logging.HelperResourceTrace(ctx, "Called Terraform CLI plan command")
@@ -204,6 +247,10 @@ func (wd *WorkingDir) CreatePlan(ctx context.Context) error {
 func (wd *WorkingDir) CreateDestroyPlan(ctx context.Context) error {
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI plan -destroy command")
 
+	if err := wd.setEnv(); err != nil {
+		return err
+	}
+
 	hasChanges, err := wd.tf.Plan(context.Background(), tfexec.Reattach(wd.reattachInfo), tfexec.Refresh(false), tfexec.Out(PlanFileName), tfexec.Destroy(true))
 
 	logging.HelperResourceTrace(ctx, "Called Terraform CLI plan -destroy command")
@@ -241,6 +288,10 @@ func (wd *WorkingDir) Apply(ctx context.Context) error {
 
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI apply command")
 
+	if err := wd.setEnv(); err != nil {
+		return err
+	}
+
 	err := wd.tf.Apply(context.Background(), args...)
 
 	logging.HelperResourceTrace(ctx, "Called Terraform CLI apply command")
@@ -256,6 +307,10 @@ func (wd *WorkingDir) Apply(ctx context.Context) error {
 func (wd *WorkingDir) Destroy(ctx context.Context) error {
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI destroy command")
 
+	if err := wd.setEnv(); err != nil {
+		return err
+	}
+
 	err := wd.tf.Destroy(context.Background(), tfexec.Reattach(wd.reattachInfo), tfexec.Refresh(false))
 
 	logging.HelperResourceTrace(ctx, "Called Terraform CLI destroy command")
@@ -281,6 +336,10 @@ func (wd *WorkingDir) SavedPlan(ctx context.Context) (*tfjson.Plan, error) {
 
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI show command for JSON plan")
 
+	if err := wd.setEnv(); err != nil {
+		return nil, err
+	}
+
 	plan, err := wd.tf.ShowPlanFile(context.Background(), wd.planFilename(), tfexec.Reattach(wd.reattachInfo))
 
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI show command for JSON plan")
@@ -299,6 +358,10 @@ func (wd *WorkingDir) SavedPlanRawStdout(ctx context.Context) (string, error) {
 
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI show command for stdout plan")
 
+	if err := wd.setEnv(); err != nil {
+		return "", err
+	}
+
 	stdout, err := wd.tf.ShowPlanFileRaw(context.Background(), wd.planFilename(), tfexec.Reattach(wd.reattachInfo))
 
 	logging.HelperResourceTrace(ctx, "Called Terraform CLI show command for stdout plan")
@@ -317,6 +380,10 @@ func (wd *WorkingDir) SavedPlanRawStdout(ctx context.Context) (string, error) {
 func (wd *WorkingDir) State(ctx context.Context) (*tfjson.State, error) {
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI show command for JSON state")
 
+	if err := wd.setEnv(); err != nil {
+		return nil, err
+	}
+
 	state, err := wd.tf.Show(context.Background(), tfexec.Reattach(wd.reattachInfo))
 
 	logging.HelperResourceTrace(ctx, "Called Terraform CLI show command for JSON state")
@@ -328,6 +395,10 @@ func (wd *WorkingDir) State(ctx context.Context) (*tfjson.State, error) {
 func (wd *WorkingDir) Import(ctx context.Context, resource, id string) error {
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI import command")
 
+	if err := wd.setEnv(); err != nil {
+		return err
+	}
+
 	err := wd.tf.Import(context.Background(), resource, id, tfexec.Config(wd.baseDir), tfexec.Reattach(wd.reattachInfo))
 
 	logging.HelperResourceTrace(ctx, "Called Terraform CLI import command")
@@ -339,6 +410,10 @@ func (wd *WorkingDir) Import(ctx context.Context, resource, id string) error {
 func (wd *WorkingDir) Taint(ctx context.Context, address string) error {
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI taint command")
 
+	if err := wd.setEnv(); err != nil {
+		return err
+	}
+
 	err := wd.tf.Taint(context.Background(), address)
 
 	logging.HelperResourceTrace(ctx, "Called Terraform CLI taint command")
@@ -350,6 +425,10 @@ func (wd *WorkingDir) Taint(ctx context.Context, address string) error {
 func (wd *WorkingDir) Refresh(ctx context.Context) error {
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI refresh command")
 
+	if err := wd.setEnv(); err != nil {
+		return err
+	}
+
 	err := wd.tf.Refresh(context.Background(), tfexec.Reattach(wd.reattachInfo))
 
 	logging.HelperResourceTrace(ctx, "Called Terraform CLI refresh command")
@@ -363,6 +442,10 @@ func (wd *WorkingDir) Refresh(ctx context.Context) error {
 func (wd *WorkingDir) Schemas(ctx context.Context) (*tfjson.ProviderSchemas, error) {
 	logging.HelperResourceTrace(ctx, "Calling Terraform CLI providers schema command")
 
+	if err := wd.setEnv(); err != nil {
+		return nil, err
+	}
+
 	providerSchemas, err := wd.tf.ProvidersSchema(context.Background())
 
 	logging.HelperResourceTrace(ctx, "Called Terraform CLI providers schema command")
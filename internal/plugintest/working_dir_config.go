@@ -0,0 +1,120 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plugintest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// VariablesFileName is the name of the variable definitions file written
+// for the Variables of a WorkingDirConfig. Terraform loads it automatically
+// in every command.
+const VariablesFileName = "terraform_plugin_test.auto.tfvars.json"
+
+// ImportBlockFileName is the name of the configuration file written for the
+// ImportBlock of a WorkingDirConfig.
+const ImportBlockFileName = "terraform_plugin_test_import.tf"
+
+// WorkingDirConfig is the configuration of a working directory, which is
+// either a string or copied from a directory or a file, along with the values
+// of its input variables. Only one of Raw, Directory and File is set.
+type WorkingDirConfig struct {
+	// Raw is a configuration in the native or JSON syntax, which is written
+	// to a single file.
+	Raw string
+
+	// Directory is the path of a directory whose files and subdirectories,
+	// such as those of local modules, are copied into the working directory.
+	Directory string
+
+	// File is the path of a single configuration file, which is copied into
+	// the working directory.
+	File string
+
+	// Variables is the content of a variable definitions file in the JSON
+	// syntax, or nil if the configuration has no variable values.
+	Variables []byte
+
+	// ImportBlock is a configuration in the native syntax containing import
+	// blocks, which is written alongside the configuration, or empty.
+	ImportBlock string
+}
+
+// IsEmpty returns true if none of Raw, Directory and File are set.
+func (c WorkingDirConfig) IsEmpty() bool {
+	return c.Raw == "" && c.Directory == "" && c.File == ""
+}
+
+// Files returns the content of the files of the configuration, excluding
+// the variable definitions and import block files, keyed by their paths
+// relative to the working directory.
+func (c WorkingDirConfig) Files() (map[string][]byte, error) {
+	switch {
+	case c.Directory != "":
+		return directoryFiles(c.Directory)
+	case c.File != "":
+		b, err := os.ReadFile(c.File)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read configuration file: %w", err)
+		}
+
+		return map[string][]byte{filepath.Base(c.File): b}, nil
+	case json.Valid([]byte(c.Raw)):
+		return map[string][]byte{ConfigFileNameJSON: []byte(c.Raw)}, nil
+	default:
+		return map[string][]byte{ConfigFileName: []byte(c.Raw)}, nil
+	}
+}
+
+// directoryFiles returns the content of every file within the given
+// directory and its subdirectories, except for the .terraform directories
+// and state files of any prior runs of Terraform within them.
+func directoryFiles(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if matched, _ := filepath.Match("*.tfstate*", d.Name()); matched {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		files[rel] = b
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration directory: %w", err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("configuration directory %q contains no files", dir)
+	}
+
+	return files, nil
+}
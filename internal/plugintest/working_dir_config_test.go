@@ -0,0 +1,119 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plugintest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWorkingDirConfig_Files(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	testFiles := map[string]string{
+		"main.tf":      `module "child" { source = "./modules/child" }`,
+		"variables.tf": `variable "name" {}`,
+		filepath.Join("modules", "child", "main.tf"):           `output "id" { value = "test" }`,
+		filepath.Join(".terraform", "modules", "modules.json"): `{}`,
+		"terraform.tfstate":        `{}`,
+		"terraform.tfstate.backup": `{}`,
+	}
+
+	for name, content := range testFiles {
+		path := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	emptyDir := t.TempDir()
+
+	tests := map[string]struct {
+		config        WorkingDirConfig
+		expected      map[string]string
+		expectedError string
+	}{
+		"raw": {
+			config: WorkingDirConfig{
+				Raw: `resource "test_thing" "test" {}`,
+			},
+			expected: map[string]string{
+				ConfigFileName: `resource "test_thing" "test" {}`,
+			},
+		},
+		"raw-json": {
+			config: WorkingDirConfig{
+				Raw: `{"resource": {}}`,
+			},
+			expected: map[string]string{
+				ConfigFileNameJSON: `{"resource": {}}`,
+			},
+		},
+		"directory": {
+			config: WorkingDirConfig{
+				Directory: dir,
+				Variables: []byte(`{"name":"test"}`),
+			},
+			expected: map[string]string{
+				"main.tf":      `module "child" { source = "./modules/child" }`,
+				"variables.tf": `variable "name" {}`,
+				filepath.Join("modules", "child", "main.tf"): `output "id" { value = "test" }`,
+			},
+		},
+		"directory-empty": {
+			config: WorkingDirConfig{
+				Directory: emptyDir,
+			},
+			expectedError: "configuration directory \"" + emptyDir + "\" contains no files",
+		},
+		"file": {
+			config: WorkingDirConfig{
+				File: filepath.Join(dir, "variables.tf"),
+			},
+			expected: map[string]string{
+				"variables.tf": `variable "name" {}`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			files, err := test.config.Files()
+
+			if test.expectedError != "" {
+				if err == nil || err.Error() != test.expectedError {
+					t.Fatalf("expected error %q, got: %v", test.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := make(map[string]string, len(files))
+
+			for name, b := range files {
+				got[name] = string(b)
+			}
+
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plugintest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/logging"
+)
+
+const (
+	// CLIConfigFileName is the name of the Terraform CLI configuration file
+	// written by SetProviderMirror.
+	CLIConfigFileName = "terraform_plugin_test.tfrc"
+
+	// providerMirrorDirName is the name of the directory of the pinned
+	// provider mirror written by SetProviderMirror.
+	providerMirrorDirName = ".terraform-plugin-test-mirror"
+
+	// envTfCLIConfigFile is the environment variable of the Terraform CLI
+	// configuration file.
+	envTfCLIConfigFile = "TF_CLI_CONFIG_FILE"
+)
+
+// SetProviderMirror configures Terraform to install the given version of the
+// provider with the given source address, such as
+// registry.terraform.io/hashicorp/examplecloud, from a local filesystem
+// mirror directory, rather than its origin registry. Other providers are
+// installed directly from their origin registries, so implied local mirror
+// directories are not used. The mirror directory must contain the provider
+// package in the packed or unpacked layout written by the terraform
+// providers mirror command. Init must be called afterwards to install the
+// provider.
+//
+// The Terraform CLI configuration file of the TF_CLI_CONFIG_FILE
+// environment variable, or the default location, is extended with the
+// mirror, so it must not configure provider installation itself and must be
+// written in the native syntax.
+func (wd *WorkingDir) SetProviderMirror(ctx context.Context, mirrorDir string, source string, version string) error {
+	logging.HelperResourceTrace(ctx, "Setting Terraform CLI provider mirror", map[string]interface{}{
+		logging.KeyTestTerraformProviderMirror:  mirrorDir,
+		logging.KeyTestTerraformProviderSource:  source,
+		logging.KeyTestTerraformProviderVersion: version,
+	})
+
+	source, err := normalizeProviderSource(source)
+	if err != nil {
+		return err
+	}
+
+	userCLIConfigFile, err := defaultCLIConfigFile()
+	if err != nil {
+		return err
+	}
+
+	pinnedDir := filepath.Join(wd.baseDir, providerMirrorDirName)
+
+	if err := os.RemoveAll(pinnedDir); err != nil {
+		return fmt.Errorf("unable to remove provider mirror: %w", err)
+	}
+
+	if err := pinProviderMirror(mirrorDir, pinnedDir, source, version); err != nil {
+		return err
+	}
+
+	cliConfig, err := providerMirrorCLIConfig(userCLIConfigFile, pinnedDir, source)
+	if err != nil {
+		return err
+	}
+
+	cliConfigPath := filepath.Join(wd.baseDir, CLIConfigFileName)
+
+	if err := os.WriteFile(cliConfigPath, cliConfig, 0600); err != nil {
+		return fmt.Errorf("unable to write Terraform CLI configuration: %w", err)
+	}
+
+	wd.cliConfigFile = cliConfigPath
+
+	return nil
+}
+
+// UnsetProviderMirror reverts the configuration of SetProviderMirror, so
+// that Terraform installs every provider as before. Init must be called
+// afterwards to reinstall the provider.
+func (wd *WorkingDir) UnsetProviderMirror(ctx context.Context) error {
+	logging.HelperResourceTrace(ctx, "Unsetting Terraform CLI provider mirror")
+
+	wd.cliConfigFile = ""
+
+	return nil
+}
+
+// setEnv sets the environment of the following Terraform commands to the
+// current environment of the process, along with the Terraform CLI
+// configuration file written by SetProviderMirror, if any.
+func (wd *WorkingDir) setEnv() error {
+	if wd.cliConfigFile == "" {
+		return wd.tf.SetEnv(nil)
+	}
+
+	env := make(map[string]string)
+
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		env[k] = v
+	}
+
+	env = tfexec.CleanEnv(env)
+	env[envTfCLIConfigFile] = wd.cliConfigFile
+
+	return wd.tf.SetEnv(env)
+}
+
+// defaultCLIConfigFile returns the path of the Terraform CLI configuration
+// file Terraform uses without SetProviderMirror, or an empty string if there
+// is none.
+func defaultCLIConfigFile() (string, error) {
+	if path := os.Getenv(envTfCLIConfigFile); path != "" {
+		return path, nil
+	}
+
+	if runtime.GOOS == "windows" {
+		appData := os.Getenv("APPDATA")
+
+		if appData == "" {
+			return "", nil
+		}
+
+		return filepath.Join(appData, "terraform.rc"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nil
+	}
+
+	return filepath.Join(home, ".terraformrc"), nil
+}
+
+// providerMirrorCLIConfig returns the Terraform CLI configuration which
+// installs the provider with the given source address from the given mirror
+// directory, extending the given configuration file if it exists.
+func providerMirrorCLIConfig(userCLIConfigFile string, pinnedDir string, source string) ([]byte, error) {
+	var cliConfig []byte
+
+	if userCLIConfigFile != "" {
+		b, err := os.ReadFile(userCLIConfigFile)
+
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("unable to read Terraform CLI configuration: %w", err)
+		case strings.HasSuffix(userCLIConfigFile, ".json"):
+			return nil, fmt.Errorf("unable to use Terraform CLI configuration %s with a provider mirror, as it is written in the JSON syntax", userCLIConfigFile)
+		case bytes.Contains(b, []byte("provider_installation")):
+			return nil, fmt.Errorf("unable to use Terraform CLI configuration %s with a provider mirror, as it already configures provider installation", userCLIConfigFile)
+		default:
+			cliConfig = append(b, '\n')
+		}
+	}
+
+	cliConfig = append(cliConfig, fmt.Sprintf(`provider_installation {
+  filesystem_mirror {
+    path    = %q
+    include = [%q]
+  }
+
+  direct {
+    exclude = [%q]
+  }
+}
+`, filepath.ToSlash(pinnedDir), source, source)...)
+
+	return cliConfig, nil
+}
+
+// normalizeProviderSource returns the given provider source address with
+// the default registry.terraform.io hostname if it has none.
+func normalizeProviderSource(source string) (string, error) {
+	parts := strings.Split(source, "/")
+
+	switch {
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return "registry.terraform.io/" + source, nil
+	case len(parts) == 3 && parts[0] != "" && parts[1] != "" && parts[2] != "":
+		return source, nil
+	default:
+		return "", fmt.Errorf("invalid provider source address %q, expected [HOSTNAME/]NAMESPACE/TYPE", source)
+	}
+}
+
+// pinProviderMirror links the packages of the given version of a provider
+// within a filesystem mirror directory into a new mirror directory, so that
+// Terraform cannot install any other version of it.
+func pinProviderMirror(mirrorDir string, pinnedDir string, source string, version string) error {
+	absMirrorDir, err := filepath.Abs(mirrorDir)
+	if err != nil {
+		return fmt.Errorf("unable to find provider mirror directory: %w", err)
+	}
+
+	providerDir := filepath.Join(absMirrorDir, filepath.FromSlash(source))
+	pinnedProviderDir := filepath.Join(pinnedDir, filepath.FromSlash(source))
+
+	entries, err := os.ReadDir(providerDir)
+	if err != nil {
+		return fmt.Errorf("unable to read provider %s in filesystem mirror %s: %w", source, mirrorDir, err)
+	}
+
+	providerType := filepath.Base(providerDir)
+	packedPrefix := fmt.Sprintf("terraform-provider-%s_%s_", providerType, version)
+
+	var links []string
+
+	for _, entry := range entries {
+		// Unpacked layout: HOSTNAME/NAMESPACE/TYPE/VERSION/TARGET/
+		// Packed layout: HOSTNAME/NAMESPACE/TYPE/terraform-provider-TYPE_VERSION_TARGET.zip
+		if (entry.IsDir() && entry.Name() == version) || (!entry.IsDir() && strings.HasPrefix(entry.Name(), packedPrefix) && strings.HasSuffix(entry.Name(), ".zip")) {
+			links = append(links, entry.Name())
+		}
+	}
+
+	if len(links) == 0 {
+		return fmt.Errorf("provider %s version %s not found in filesystem mirror %s", source, version, mirrorDir)
+	}
+
+	if err := os.MkdirAll(pinnedProviderDir, 0700); err != nil {
+		return fmt.Errorf("unable to create provider mirror: %w", err)
+	}
+
+	for _, link := range links {
+		if err := os.Symlink(filepath.Join(providerDir, link), filepath.Join(pinnedProviderDir, link)); err != nil {
+			return fmt.Errorf("unable to create provider mirror: %w", err)
+		}
+	}
+
+	return nil
+}
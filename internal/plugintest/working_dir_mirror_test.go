@@ -0,0 +1,201 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package plugintest
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeProviderSource(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		source        string
+		expected      string
+		expectedError string
+	}{
+		"namespace-type": {
+			source:   "hashicorp/examplecloud",
+			expected: "registry.terraform.io/hashicorp/examplecloud",
+		},
+		"hostname-namespace-type": {
+			source:   "example.com/hashicorp/examplecloud",
+			expected: "example.com/hashicorp/examplecloud",
+		},
+		"type": {
+			source:        "examplecloud",
+			expectedError: `invalid provider source address "examplecloud"`,
+		},
+		"empty-part": {
+			source:        "example.com//examplecloud",
+			expectedError: `invalid provider source address "example.com//examplecloud"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := normalizeProviderSource(testCase.source)
+
+			if testCase.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("expected error containing %q, got: %v", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestPinProviderMirror(t *testing.T) {
+	t.Parallel()
+
+	mirrorDir := t.TempDir()
+	providerDir := filepath.Join(mirrorDir, "registry.terraform.io", "hashicorp", "examplecloud")
+
+	for _, dir := range []string{"1.2.0/linux_amd64", "1.3.0/linux_amd64"} {
+		if err := os.MkdirAll(filepath.Join(providerDir, dir), 0700); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	for _, file := range []string{
+		"terraform-provider-examplecloud_1.2.0_darwin_arm64.zip",
+		"terraform-provider-examplecloud_1.2.0_linux_amd64.zip",
+		"terraform-provider-examplecloud_1.2.01_linux_amd64.zip",
+		"terraform-provider-examplecloud_1.3.0_linux_amd64.zip",
+	} {
+		if err := os.WriteFile(filepath.Join(providerDir, file), nil, 0600); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	pinnedDir := filepath.Join(t.TempDir(), "pinned")
+
+	if err := pinProviderMirror(mirrorDir, pinnedDir, "registry.terraform.io/hashicorp/examplecloud", "1.2.0"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(pinnedDir, "registry.terraform.io", "hashicorp", "examplecloud"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+	sort.Strings(got)
+
+	expected := []string{
+		"1.2.0",
+		"terraform-provider-examplecloud_1.2.0_darwin_arm64.zip",
+		"terraform-provider-examplecloud_1.2.0_linux_amd64.zip",
+	}
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected difference: %s", diff)
+	}
+
+	err = pinProviderMirror(mirrorDir, filepath.Join(t.TempDir(), "pinned"), "registry.terraform.io/hashicorp/examplecloud", "2.0.0")
+	if err == nil || !strings.Contains(err.Error(), "provider registry.terraform.io/hashicorp/examplecloud version 2.0.0 not found in filesystem mirror") {
+		t.Errorf("expected version not found error, got: %v", err)
+	}
+}
+
+func TestProviderMirrorCLIConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"credentials.tfrc":  "plugin_cache_dir = \"/tmp/cache\"\n",
+		"installation.tfrc": "provider_installation {\n  direct {}\n}\n",
+		"config.tfrc.json":  "{}\n",
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	mirrorConfig := `provider_installation {
+  filesystem_mirror {
+    path    = "/mirror"
+    include = ["registry.terraform.io/hashicorp/examplecloud"]
+  }
+
+  direct {
+    exclude = ["registry.terraform.io/hashicorp/examplecloud"]
+  }
+}
+`
+
+	testCases := map[string]struct {
+		userCLIConfigFile string
+		expected          string
+		expectedError     string
+	}{
+		"none": {
+			expected: mirrorConfig,
+		},
+		"not-found": {
+			userCLIConfigFile: filepath.Join(dir, "missing.tfrc"),
+			expected:          mirrorConfig,
+		},
+		"extended": {
+			userCLIConfigFile: filepath.Join(dir, "credentials.tfrc"),
+			expected:          "plugin_cache_dir = \"/tmp/cache\"\n\n" + mirrorConfig,
+		},
+		"provider-installation": {
+			userCLIConfigFile: filepath.Join(dir, "installation.tfrc"),
+			expectedError:     "as it already configures provider installation",
+		},
+		"json": {
+			userCLIConfigFile: filepath.Join(dir, "config.tfrc.json"),
+			expectedError:     "as it is written in the JSON syntax",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := providerMirrorCLIConfig(testCase.userCLIConfigFile, "/mirror", "registry.terraform.io/hashicorp/examplecloud")
+
+			if testCase.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("expected error containing %q, got: %v", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(testCase.expected, string(got)); diff != "" {
+				t.Errorf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
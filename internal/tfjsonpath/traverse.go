@@ -0,0 +1,121 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package tfjsonpath traverses values decoded from the JSON plan and state
+// formats of Terraform with cty paths.
+package tfjsonpath
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+// Traverse returns the value at the given path within the given JSON value,
+// which is an object, array or primitive decoded from the JSON plan or state
+// formats.
+//
+// GetAttrStep steps and IndexStep steps with string keys select object
+// attributes and map elements, while IndexStep steps with number keys select
+// list, set and tuple elements. Sets are encoded as arrays in the JSON
+// formats, so their elements are selected by position.
+func Traverse(value interface{}, path cty.Path) (interface{}, error) {
+	result := value
+
+	for i, step := range path {
+		var err error
+
+		result, err = traverseStep(result, step)
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", String(path[:i+1]), err)
+		}
+	}
+
+	return result, nil
+}
+
+func traverseStep(value interface{}, step cty.PathStep) (interface{}, error) {
+	switch step := step.(type) {
+	case cty.GetAttrStep:
+		return traverseKey(value, step.Name)
+	case cty.IndexStep:
+		switch {
+		case step.Key.Type() == cty.String && step.Key.IsKnown() && !step.Key.IsNull():
+			return traverseKey(value, step.Key.AsString())
+		case step.Key.Type() == cty.Number && step.Key.IsKnown() && !step.Key.IsNull():
+			arr, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot index %s with a number", describe(value))
+			}
+
+			index, accuracy := step.Key.AsBigFloat().Int64()
+			if accuracy != 0 || index < 0 || index >= int64(len(arr)) {
+				return nil, fmt.Errorf("index %s out of range for %d elements", step.Key.AsBigFloat().String(), len(arr))
+			}
+
+			return arr[index], nil
+		default:
+			return nil, fmt.Errorf("unsupported index key of type %s", step.Key.Type().FriendlyName())
+		}
+	default:
+		return nil, fmt.Errorf("unsupported path step %T", step)
+	}
+}
+
+func traverseKey(value interface{}, key string) (interface{}, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot get %q from %s", key, describe(value))
+	}
+
+	result, ok := obj[key]
+	if !ok {
+		return nil, fmt.Errorf("%q not found", key)
+	}
+
+	return result, nil
+}
+
+func describe(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null value"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return fmt.Sprintf("%T value", value)
+	}
+}
+
+// String returns the given path in the syntax of Terraform references, such
+// as name, tags["env"] or rule[0].port.
+func String(path cty.Path) string {
+	var b strings.Builder
+
+	for _, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+
+			b.WriteString(step.Name)
+		case cty.IndexStep:
+			switch {
+			case !step.Key.IsKnown() || step.Key.IsNull():
+				b.WriteString("[?]")
+			case step.Key.Type() == cty.String:
+				fmt.Fprintf(&b, "[%q]", step.Key.AsString())
+			case step.Key.Type() == cty.Number:
+				fmt.Fprintf(&b, "[%s]", step.Key.AsBigFloat().Text('f', -1))
+			default:
+				b.WriteString("[?]")
+			}
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,97 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjsonpath
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestTraverse(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"name": "test",
+		"tags": map[string]interface{}{
+			"env": "prod",
+		},
+		"rule": []interface{}{
+			map[string]interface{}{
+				"port": json.Number("80"),
+			},
+		},
+		"empty": nil,
+	}
+
+	cases := map[string]struct {
+		Path          cty.Path
+		Expected      interface{}
+		ExpectedError string
+	}{
+		"empty": {
+			Path:     nil,
+			Expected: value,
+		},
+		"attribute": {
+			Path:     cty.GetAttrPath("name"),
+			Expected: "test",
+		},
+		"map-element": {
+			Path:     cty.GetAttrPath("tags").IndexString("env"),
+			Expected: "prod",
+		},
+		"list-element-attribute": {
+			Path:     cty.GetAttrPath("rule").IndexInt(0).GetAttr("port"),
+			Expected: json.Number("80"),
+		},
+		"null": {
+			Path:     cty.GetAttrPath("empty"),
+			Expected: nil,
+		},
+		"missing-attribute": {
+			Path:          cty.GetAttrPath("missing"),
+			ExpectedError: `path missing: "missing" not found`,
+		},
+		"index-out-of-range": {
+			Path:          cty.GetAttrPath("rule").IndexInt(1),
+			ExpectedError: "path rule[1]: index 1 out of range for 1 elements",
+		},
+		"index-object": {
+			Path:          cty.GetAttrPath("tags").IndexInt(0),
+			ExpectedError: "path tags[0]: cannot index object with a number",
+		},
+		"attribute-of-null": {
+			Path:          cty.GetAttrPath("empty").GetAttr("name"),
+			ExpectedError: `path empty.name: cannot get "name" from null value`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Traverse(value, tc.Path)
+
+			if tc.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.ExpectedError) {
+					t.Fatalf("expected error %q, got: %v", tc.ExpectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.Expected, got); diff != "" {
+				t.Errorf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
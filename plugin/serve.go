@@ -51,6 +51,18 @@ type ServeOpts struct {
 
 	GRPCProviderV6Func GRPCProviderV6Func
 
+	// ProtocolV6 serves the provider returned by ProviderFunc over version 6
+	// of the plugin protocol, using schema.NewGRPCProviderServerV6, instead
+	// of version 5. This is required for Terraform to receive attributes with
+	// a ConfigMode of schema.SchemaConfigModeNestedAttr as nested attributes,
+	// and allows muxing the provider with other protocol version 6 providers
+	// without wrapping it with the tf5to6server package of
+	// terraform-plugin-mux.
+	//
+	// Terraform 1.0 or later is required to use protocol version 6. This
+	// option has no effect when GRPCProviderFunc or GRPCProviderV6Func is set.
+	ProtocolV6 bool
+
 	// Logger is the logger that go-plugin will use.
 	Logger hclog.Logger
 
@@ -121,6 +133,11 @@ func Serve(opts *ServeOpts) {
 	var err error
 
 	switch {
+	case opts.ProviderFunc != nil && opts.GRPCProviderFunc == nil && opts.GRPCProviderV6Func == nil && opts.ProtocolV6:
+		opts.GRPCProviderV6Func = func() tfprotov6.ProviderServer {
+			return schema.NewGRPCProviderServerV6(opts.ProviderFunc())
+		}
+		err = tf6serverServe(opts)
 	case opts.ProviderFunc != nil && opts.GRPCProviderFunc == nil:
 		opts.GRPCProviderFunc = func() tfprotov5.ProviderServer {
 			return schema.NewGRPCProviderServer(opts.ProviderFunc())
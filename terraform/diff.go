@@ -69,13 +69,18 @@ func (d *InstanceDiff) Unlock() { d.mu.Unlock() }
 func (d *InstanceDiff) ApplyToValue(base cty.Value, schema *configschema.Block) (cty.Value, error) {
 	// Create an InstanceState attributes from our existing state.
 	// We can use this to more easily apply the diff changes.
-	attrs := hcl2shim.FlatmapValueFromHCL2(base)
-	applied, err := d.Apply(attrs, schema)
+	legacyBase, err := hcl2shim.LegacyValueFromHCL2Block(base, schema)
 	if err != nil {
 		return base, err
 	}
 
-	val, err := hcl2shim.HCL2ValueFromFlatmap(applied, schema.ImpliedType())
+	attrs := hcl2shim.FlatmapValueFromHCL2(legacyBase)
+	applied, err := d.Apply(attrs, hcl2shim.LegacySchema(schema))
+	if err != nil {
+		return base, err
+	}
+
+	val, err := hcl2shim.HCL2ValueFromFlatmapBlock(applied, schema)
 	if err != nil {
 		return base, err
 	}
@@ -92,14 +92,38 @@ func NewResourceConfigRaw(raw map[string]interface{}) *ResourceConfig {
 // an already-populated ResourceConfig which they then treat as read-only.
 //
 // If the given value is not of an object type that conforms to the given
-// schema then this function will panic.
+// schema, or cannot be represented in the legacy config, such as a
+// dynamically-typed attribute whose value cannot be encoded, then this
+// function will panic. Use ShimResourceConfigFromValue to get an error
+// instead.
 func NewResourceConfigShimmed(val cty.Value, schema *configschema.Block) *ResourceConfig {
+	ret, err := ShimResourceConfigFromValue(val, schema)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
+
+// ShimResourceConfigFromValue is like NewResourceConfigShimmed, but returns
+// an error if the given value cannot be represented in the legacy config.
+//
+// If the given value is not of an object type that conforms to the given
+// schema then this function will panic.
+func ShimResourceConfigFromValue(val cty.Value, schema *configschema.Block) (*ResourceConfig, error) {
 	if !val.Type().IsObjectType() {
 		panic(fmt.Errorf("NewResourceConfigShimmed given %#v; an object type is required", val.Type()))
 	}
 	ret := &ResourceConfig{}
 
-	legacyVal := hcl2shim.ConfigValueFromHCL2Block(val, schema)
+	// Dynamically-typed and single nested attributes have a different
+	// representation in the legacy config.
+	val, err := hcl2shim.LegacyValueFromHCL2Block(val, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	legacyVal := hcl2shim.ConfigValueFromHCL2Block(val, hcl2shim.LegacySchema(schema))
 	if legacyVal != nil {
 		ret.Config = legacyVal
 
@@ -114,7 +138,7 @@ func NewResourceConfigShimmed(val cty.Value, schema *configschema.Block) *Resour
 	}
 	ret.Raw = ret.Config
 
-	return ret
+	return ret, nil
 }
 
 // Record the any config values in ComputedKeys. This field had been unused in
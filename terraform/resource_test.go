@@ -707,3 +707,31 @@ func TestNewResourceConfigShimmed(t *testing.T) {
 		})
 	}
 }
+
+func TestShimResourceConfigFromValue_invalidDynamic(t *testing.T) {
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"foo": {
+				Type:     cty.DynamicPseudoType,
+				Optional: true,
+			},
+		},
+	}
+
+	// Capsule values cannot be encoded as JSON.
+	val := cty.ObjectVal(map[string]cty.Value{
+		"foo": cty.CapsuleVal(cty.Capsule("test", reflect.TypeOf("")), new(string)),
+	})
+
+	if _, err := ShimResourceConfigFromValue(val, schema); err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected NewResourceConfigShimmed to panic")
+		}
+	}()
+
+	NewResourceConfigShimmed(val, schema)
+}
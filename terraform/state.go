@@ -22,6 +22,7 @@ import (
 	"github.com/mitchellh/copystructure"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/addrs"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/configschema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/internal/configs/hcl2shim"
 )
 
@@ -1373,6 +1374,20 @@ func NewInstanceStateShimmedFromValue(state cty.Value, schemaVersion int) *Insta
 // This is for shimming from old components only and should not be used in
 // new code.
 func (s *InstanceState) AttrsAsObjectValue(ty cty.Type) (cty.Value, error) {
+	return hcl2shim.HCL2ValueFromFlatmap(s.attrsWithID(), ty)
+}
+
+// AttrsAsObjectValueBlock is like AttrsAsObjectValue, but uses the given
+// schema for guidance, which is required to convert attributes whose legacy
+// representation differs from their type, such as single nested attributes.
+//
+// This is for shimming from old components only and should not be used in
+// new code.
+func (s *InstanceState) AttrsAsObjectValueBlock(schema *configschema.Block) (cty.Value, error) {
+	return hcl2shim.HCL2ValueFromFlatmapBlock(s.attrsWithID(), schema)
+}
+
+func (s *InstanceState) attrsWithID() map[string]string {
 	if s == nil {
 		// if the state is nil, we need to construct a complete cty.Value with
 		// null attributes, rather than a single cty.NullVal(ty)
@@ -1389,7 +1404,7 @@ func (s *InstanceState) AttrsAsObjectValue(ty cty.Type) (cty.Value, error) {
 		s.Attributes["id"] = s.ID
 	}
 
-	return hcl2shim.HCL2ValueFromFlatmap(s.Attributes, ty)
+	return s.Attributes
 }
 
 // Copy all the Fields from another InstanceState